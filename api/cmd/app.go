@@ -2,8 +2,12 @@ package main
 
 import (
 	"context"
+	"errors"
+	"net/http"
 	"os"
+	"os/signal"
 	"reflect"
+	"syscall"
 	"time"
 
 	"github.com/heiytor/invenda/api/pkg/cache"
@@ -19,7 +23,8 @@ import (
 )
 
 func main() {
-	ctx := context.Background()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 	log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stdout, TimeFormat: time.RFC3339})
 
 	if err := secretkeys.Load(); err != nil {
@@ -34,6 +39,12 @@ func main() {
 			Msg("Unable to parse the environment variables")
 	}
 
+	if env.E().ReservationSweep <= 0 {
+		log.Panic().
+			Dur("value", env.E().ReservationSweep).
+			Msg("INVENDA_RESERVATION_SWEEP must be a positive duration")
+	}
+
 	v := reflect.ValueOf(env.E())
 	for i := 0; i < v.NumField(); i++ {
 		log.Info().
@@ -65,16 +76,61 @@ func main() {
 			Msg("Unable to create the store")
 	}
 
-	routes := route.New(service.New(store, cache), cache)
+	svc := service.New(store, cache)
+	go expireReservations(ctx, svc, env.E().ReservationSweep)
+
+	routes := route.New(svc, cache)
 
 	// Configure logger
 	logger := lecho.From(log.Logger)
 	routes.E.Logger = logger
 	routes.E.Use(middleware.Logger(logger))
 
-	if err := routes.E.Start(":8080"); err != nil {
+	go func() {
+		<-ctx.Done()
+
+		shutdown, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		if err := routes.E.Shutdown(shutdown); err != nil {
+			log.Error().
+				Err(err).
+				Msg("Unable to shut down Echo")
+		}
+	}()
+
+	if err := routes.E.Start(":8080"); err != nil && !errors.Is(err, http.ErrServerClosed) {
 		log.Panic().
 			Err(err).
 			Msg("Echo panicked.")
 	}
 }
+
+// expireReservations releases the expired stock reservations every interval until ctx is done.
+func expireReservations(ctx context.Context, svc service.Service, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		count, err := svc.ExpireReservations(ctx)
+		if err != nil {
+			log.Error().
+				Err(err).
+				Msg("Unable to expire the reservations")
+
+			continue
+		}
+
+		if count > 0 {
+			log.Info().
+				Int("count", count).
+				Msg("Expired reservations released")
+		}
+	}
+}
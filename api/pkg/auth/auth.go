@@ -40,6 +40,79 @@ const (
 	NamespaceRead   Permission = "namespace:read"
 	NamespaceWrite  Permission = "namespace:write"
 	NamespaceDelete Permission = "namespace:delete"
+
+	ProductRead   Permission = "product:read"
+	ProductWrite  Permission = "product:write"
+	ProductDelete Permission = "product:delete"
+
+	WarehouseRead   Permission = "warehouse:read"
+	WarehouseWrite  Permission = "warehouse:write"
+	WarehouseDelete Permission = "warehouse:delete"
+
+	StockRead     Permission = "stock:read"
+	StockWrite    Permission = "stock:write"
+	StockTransfer Permission = "stock:transfer"
+
+	// StockIssueExpired allows issuing stock from expired lots.
+	StockIssueExpired Permission = "stock:issue_expired"
+
+	StockReserve Permission = "stock:reserve"
+
+	SupplierRead   Permission = "supplier:read"
+	SupplierWrite  Permission = "supplier:write"
+	SupplierDelete Permission = "supplier:delete"
+
+	PurchaseRead  Permission = "purchase:read"
+	PurchaseWrite Permission = "purchase:write"
+
+	// PurchaseReceive allows receiving the goods of purchase orders into the stock.
+	PurchaseReceive Permission = "purchase:receive"
+
+	CustomerRead   Permission = "customer:read"
+	CustomerWrite  Permission = "customer:write"
+	CustomerDelete Permission = "customer:delete"
+
+	SalesRead  Permission = "sales:read"
+	SalesWrite Permission = "sales:write"
+
+	// SalesFulfill allows picking and shipping sales orders, which issues their stock.
+	SalesFulfill Permission = "sales:fulfill"
+
+	RegisterWrite  Permission = "register:write"
+	RegisterDelete Permission = "register:delete"
+
+	// PosRead allows reading registers and counter sales and looking up items at the counter.
+	PosRead Permission = "pos:read"
+
+	// PosSell allows making counter sales, which issues their stock.
+	PosSell Permission = "pos:sell"
+
+	// PosShift allows opening and closing register shifts and moving cash in and out of their drawers.
+	PosShift Permission = "pos:shift"
+
+	// PosReturn allows returning counter sales, which restocks their goods and refunds the customer.
+	PosReturn Permission = "pos:return"
+
+	// PricingRead allows reading price lists and quoting the prices they resolve.
+	PricingRead   Permission = "pricing:read"
+	PricingWrite  Permission = "pricing:write"
+	PricingDelete Permission = "pricing:delete"
+
+	// PromotionRead allows reading promotions and evaluating them on carts.
+	PromotionRead   Permission = "promotion:read"
+	PromotionWrite  Permission = "promotion:write"
+	PromotionDelete Permission = "promotion:delete"
+
+	// TaxRead allows reading the namespace's tax rates.
+	TaxRead   Permission = "tax:read"
+	TaxWrite  Permission = "tax:write"
+	TaxDelete Permission = "tax:delete"
+
+	// ExchangeRead allows reading the namespace's exchange rates. ExchangeWrite allows loading them, through
+	// the API or from CSV files.
+	ExchangeRead   Permission = "exchange:read"
+	ExchangeWrite  Permission = "exchange:write"
+	ExchangeDelete Permission = "exchange:delete"
 )
 
 // All returns an array with all [Permission] values.
@@ -48,6 +121,47 @@ func All() []Permission {
 		NamespaceRead,
 		NamespaceWrite,
 		NamespaceDelete,
+		ProductRead,
+		ProductWrite,
+		ProductDelete,
+		WarehouseRead,
+		WarehouseWrite,
+		WarehouseDelete,
+		StockRead,
+		StockWrite,
+		StockTransfer,
+		StockIssueExpired,
+		StockReserve,
+		SupplierRead,
+		SupplierWrite,
+		SupplierDelete,
+		PurchaseRead,
+		PurchaseWrite,
+		PurchaseReceive,
+		CustomerRead,
+		CustomerWrite,
+		CustomerDelete,
+		SalesRead,
+		SalesWrite,
+		SalesFulfill,
+		RegisterWrite,
+		RegisterDelete,
+		PosRead,
+		PosSell,
+		PosShift,
+		PosReturn,
+		PricingRead,
+		PricingWrite,
+		PricingDelete,
+		PromotionRead,
+		PromotionWrite,
+		PromotionDelete,
+		TaxRead,
+		TaxWrite,
+		TaxDelete,
+		ExchangeRead,
+		ExchangeWrite,
+		ExchangeDelete,
 	}
 }
 
@@ -0,0 +1,18 @@
+package auth_test
+
+import (
+	"testing"
+
+	"github.com/heiytor/invenda/api/pkg/auth"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPermissionsFromString(t *testing.T) {
+	// Sessions keep the member's permissions as a string, so each of them must be read back unchanged.
+	for _, p := range auth.All() {
+		assert.Equal(t, auth.Permissions{p}, auth.Permissions{}.FromString(auth.Permissions{p}.String()), p)
+	}
+
+	all := auth.Permissions(auth.All())
+	assert.Equal(t, all, auth.Permissions{}.FromString(all.String()))
+}
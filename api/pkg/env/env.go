@@ -2,6 +2,7 @@ package env
 
 import (
 	"context"
+	"time"
 
 	"github.com/heiytor/invenda/api/pkg/validator"
 	"github.com/sethvargo/go-envconfig"
@@ -16,6 +17,8 @@ type spec struct {
 	MongoURI string `env:"INVENDA_MONGO_URI" validate:"required"`
 	// RedisURI stores the connection URI for MongoDB.
 	RedisURI string `env:"INVENDA_REDIS_URI" validate:"required"`
+	// ReservationSweep specifies how often the expired stock reservations are released.
+	ReservationSweep time.Duration `env:"INVENDA_RESERVATION_SWEEP, default=1m"`
 }
 
 var s = new(spec)
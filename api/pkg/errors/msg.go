@@ -2,7 +2,10 @@ package errors
 
 const (
 	MsgUnexpected             = "unexpected error"
+	MsgBadRequest             = "bad request"
 	MsgConflict               = "conflicts found"
+	MsgInsufficientStock      = "insufficient stock"
+	MsgIllegalTransition      = "illegal status transition"
 	MsgNotFound               = "entity not found"
 	MsgInvalidAuthtorization  = "missing or invalid Authorization header"
 	MsgMemberNotFound         = "member not found"
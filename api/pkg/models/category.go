@@ -0,0 +1,37 @@
+package models
+
+import (
+	"slices"
+	"time"
+)
+
+// Category represents a node of the namespace's category tree.
+type Category struct {
+	ID          string    `json:"id" bson:"_id"`
+	NamespaceID string    `json:"namespace_id" bson:"namespace_id"`
+	CreatedAt   time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" bson:"updated_at"`
+	Name        string    `json:"name" bson:"name"`
+
+	// ParentID is the ID of the parent category. It is empty for root categories.
+	ParentID string `json:"parent_id" bson:"parent_id"`
+
+	// Ancestors holds the IDs of the category's ancestors, from the root to the parent. It is a
+	// materialized path used to query whole subtrees.
+	Ancestors []string `json:"ancestors" bson:"ancestors"`
+}
+
+// Path returns the ancestors of a child of the category.
+func (c *Category) Path() []string {
+	return append(slices.Clone(c.Ancestors), c.ID)
+}
+
+// IsDescendantOf reports whether the category is id or is in the subtree of id.
+func (c *Category) IsDescendantOf(id string) bool {
+	return c.ID == id || slices.Contains(c.Ancestors, id)
+}
+
+type CategoryChanges struct {
+	UpdatedAt time.Time `bson:"updated_at"`
+	Name      string    `bson:"name,omitempty"`
+}
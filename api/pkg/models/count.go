@@ -0,0 +1,104 @@
+package models
+
+import "time"
+
+// CountStatus represents the stage of a stock count's lifecycle.
+type CountStatus string
+
+const (
+	CountOpen      CountStatus = "open"
+	CountApproved  CountStatus = "approved"
+	CountCancelled CountStatus = "cancelled"
+)
+
+// StockCount represents a physical count of a warehouse's stock. Opening a count snapshots the expected
+// quantity and the version of each balance, which later tells whether a balance was changed by other
+// movements while the count was in progress.
+type StockCount struct {
+	ID          string      `json:"id" bson:"_id"`
+	NamespaceID string      `json:"namespace_id" bson:"namespace_id"`
+	CreatedAt   time.Time   `json:"created_at" bson:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at" bson:"updated_at"`
+	Status      CountStatus `json:"status" bson:"status"`
+	WarehouseID string      `json:"warehouse_id" bson:"warehouse_id"`
+
+	// Location restricts the count to a single location of the warehouse when not empty.
+	Location string      `json:"location" bson:"location"`
+	Items    []CountItem `json:"items" bson:"items"`
+
+	// CreatedBy is the ID of the user that opened the count.
+	CreatedBy string `json:"created_by" bson:"created_by"`
+	// ApprovedBy is the ID of the user that approved the count.
+	ApprovedBy string     `json:"approved_by,omitempty" bson:"approved_by,omitempty"`
+	ApprovedAt *time.Time `json:"approved_at,omitempty" bson:"approved_at,omitempty"`
+}
+
+type CountItem struct {
+	Location  string `json:"location" bson:"location"`
+	ProductID string `json:"product_id" bson:"product_id"`
+	VariantID string `json:"variant_id" bson:"variant_id"`
+
+	// Expected is the on-hand quantity when the count was opened.
+	Expected int64 `json:"expected" bson:"expected"`
+	// Version is the balance's version when the count was opened.
+	Version int64 `json:"version" bson:"version"`
+
+	// Counted is the quantity submitted by the counter. It is nil until the item is counted.
+	Counted   *int64     `json:"counted" bson:"counted"`
+	CountedBy string     `json:"counted_by,omitempty" bson:"counted_by,omitempty"`
+	CountedAt *time.Time `json:"counted_at,omitempty" bson:"counted_at,omitempty"`
+
+	// Flagged reports whether the item was skipped at approval because its balance changed during
+	// the count.
+	Flagged bool `json:"flagged" bson:"flagged"`
+}
+
+// Key returns the stock key of an item of the count.
+func (c *StockCount) Key(item CountItem) StockKey {
+	return StockKey{WarehouseID: c.WarehouseID, Location: item.Location, ProductID: item.ProductID, VariantID: item.VariantID}
+}
+
+// Variance represents the difference between the expected and the counted quantities of an item.
+type Variance struct {
+	StockKey
+	Expected int64 `json:"expected"`
+	Counted  int64 `json:"counted"`
+
+	// Variance is the counted minus the expected quantity.
+	Variance int64 `json:"variance"`
+
+	// Flagged reports whether the balance changed since the count was opened, in which case the
+	// variance cannot be trusted and the item must be recounted.
+	Flagged bool `json:"flagged"`
+}
+
+// Variances returns the variances of the counted items. The balances, indexed by key, are the current
+// ones and are used to flag the items changed during the count; a missing balance is treated as one
+// with version zero.
+func (c *StockCount) Variances(balances map[StockKey]Balance) []Variance {
+	variances := make([]Variance, 0)
+	for _, item := range c.Items {
+		if item.Counted == nil {
+			continue
+		}
+
+		key := c.Key(item)
+		variances = append(variances, Variance{
+			StockKey: key,
+			Expected: item.Expected,
+			Counted:  *item.Counted,
+			Variance: *item.Counted - item.Expected,
+			Flagged:  balances[key].Version != item.Version,
+		})
+	}
+
+	return variances
+}
+
+type StockCountChanges struct {
+	UpdatedAt  time.Time   `bson:"updated_at"`
+	Status     CountStatus `bson:"status,omitempty"`
+	Items      []CountItem `bson:"items,omitempty"`
+	ApprovedBy string      `bson:"approved_by,omitempty"`
+	ApprovedAt *time.Time  `bson:"approved_at,omitempty"`
+}
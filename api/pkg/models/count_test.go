@@ -0,0 +1,33 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStockCountVariances(t *testing.T) {
+	counted := func(v int64) *int64 { return &v }
+
+	count := &StockCount{
+		WarehouseID: "wh_1",
+		Items: []CountItem{
+			{ProductID: "prd_1", Expected: 10, Version: 3, Counted: counted(8)},
+			{ProductID: "prd_2", Expected: 5, Version: 1, Counted: counted(5)},
+			{ProductID: "prd_3", Expected: 2, Version: 1},
+			{ProductID: "prd_4", Expected: 0, Version: 0, Counted: counted(1)},
+		},
+	}
+
+	balances := map[StockKey]Balance{
+		{WarehouseID: "wh_1", ProductID: "prd_1"}: {OnHand: 10, Version: 3},
+		{WarehouseID: "wh_1", ProductID: "prd_2"}: {OnHand: 4, Version: 2},
+		{WarehouseID: "wh_1", ProductID: "prd_3"}: {OnHand: 2, Version: 1},
+	}
+
+	assert.Equal(t, []Variance{
+		{StockKey: StockKey{WarehouseID: "wh_1", ProductID: "prd_1"}, Expected: 10, Counted: 8, Variance: -2, Flagged: false},
+		{StockKey: StockKey{WarehouseID: "wh_1", ProductID: "prd_2"}, Expected: 5, Counted: 5, Variance: 0, Flagged: true},
+		{StockKey: StockKey{WarehouseID: "wh_1", ProductID: "prd_4"}, Expected: 0, Counted: 1, Variance: 1, Flagged: false},
+	}, count.Variances(balances))
+}
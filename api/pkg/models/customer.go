@@ -0,0 +1,112 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/heiytor/invenda/api/pkg/money"
+)
+
+// Customer represents a person or business the namespace sells to.
+type Customer struct {
+	ID          string    `json:"id" bson:"_id"`
+	NamespaceID string    `json:"namespace_id" bson:"namespace_id"`
+	CreatedAt   time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" bson:"updated_at"`
+	Name        string    `json:"name" bson:"name"`
+	Email       string    `json:"email,omitempty" bson:"email,omitempty"`
+	Phone       string    `json:"phone" bson:"phone"`
+
+	// TaxID is the customer's tax identification number, such as a VAT number. Like the email, it
+	// identifies a single customer within the namespace.
+	TaxID string `json:"tax_id,omitempty" bson:"tax_id,omitempty"`
+
+	Addresses []Address `json:"addresses" bson:"addresses"`
+	Notes     string    `json:"notes" bson:"notes"`
+	Tags      []string  `json:"tags" bson:"tags"`
+
+	// Group is the customer group, such as "wholesale", which decides the price lists that apply to the
+	// customer.
+	Group string `json:"group" bson:"group"`
+
+	// StoreCredit is the amount the customer can spend in the namespace's stores, credited by refunds, in
+	// the namespace's base currency.
+	StoreCredit money.Money `json:"store_credit" bson:"store_credit"`
+}
+
+// Address represents a postal address. Label names the address for the customer, e.g. "billing".
+type Address struct {
+	Label      string `json:"label" bson:"label"`
+	Line1      string `json:"line1" bson:"line1" validate:"required"`
+	Line2      string `json:"line2" bson:"line2"`
+	City       string `json:"city" bson:"city" validate:"required"`
+	Region     string `json:"region" bson:"region"`
+	PostalCode string `json:"postal_code" bson:"postal_code"`
+	Country    string `json:"country" bson:"country" validate:"required|len:2"`
+
+	// Default reports whether the address is used when a document does not specify one.
+	Default bool `json:"default" bson:"default"`
+}
+
+// CheckAddresses reports whether the addresses have a first line, a city and a country, and whether at
+// most one of them is the default.
+func CheckAddresses(addresses []Address) error {
+	defaults := 0
+	for _, a := range addresses {
+		if a.Line1 == "" || a.City == "" || a.Country == "" {
+			return errors.New("addresses must have a line1, a city and a country")
+		}
+
+		if a.Default {
+			defaults++
+		}
+	}
+
+	if defaults > 1 {
+		return errors.New("only one address can be the default")
+	}
+
+	return nil
+}
+
+// DefaultAddress returns the customer's default address, or its first one when none is the default. It
+// returns nil when the customer has no addresses.
+func (c *Customer) DefaultAddress() *Address {
+	for i, a := range c.Addresses {
+		if a.Default {
+			return &c.Addresses[i]
+		}
+	}
+
+	if len(c.Addresses) > 0 {
+		return &c.Addresses[0]
+	}
+
+	return nil
+}
+
+// CustomerHistory summarizes the purchases of a customer over its lifetime.
+type CustomerHistory struct {
+	CustomerID string `json:"customer_id" bson:"customer_id"`
+
+	// Orders is the number of orders and counter sales placed by the customer and Spend the sum of their
+	// totals in the base currency. Cancelled orders and drafts are not purchases.
+	Orders int64       `json:"orders" bson:"orders"`
+	Spend  money.Money `json:"spend" bson:"spend"`
+
+	// LastPurchaseAt is when the customer's last order was placed. It is nil for customers that never
+	// purchased anything.
+	LastPurchaseAt *time.Time `json:"last_purchase_at" bson:"last_purchase_at"`
+}
+
+type CustomerChanges struct {
+	UpdatedAt time.Time `bson:"updated_at"`
+	Name      string    `bson:"name,omitempty"`
+	Email     *string   `bson:"email,omitempty"`
+	Phone     *string   `bson:"phone,omitempty"`
+	TaxID     *string   `bson:"tax_id,omitempty"`
+	Addresses []Address `bson:"addresses,omitempty"`
+	Notes     *string   `bson:"notes,omitempty"`
+	Tags      []string  `bson:"tags,omitempty"`
+	Group     *string   `bson:"group,omitempty"`
+}
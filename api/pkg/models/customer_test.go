@@ -0,0 +1,60 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckAddresses(t *testing.T) {
+	valid := Address{Line1: "1 Market St", City: "Springfield", Country: "US"}
+
+	cases := []struct {
+		description string
+		addresses   []Address
+		expected    string
+	}{
+		{
+			description: "succeeds when there are no addresses",
+			addresses:   []Address{},
+			expected:    "",
+		},
+		{
+			description: "fails when the country is missing",
+			addresses:   []Address{{Line1: "1 Market St", City: "Springfield"}},
+			expected:    "addresses must have a line1, a city and a country",
+		},
+		{
+			description: "fails when more than one address is the default",
+			addresses:   []Address{{Line1: "1 Market St", City: "Springfield", Country: "US", Default: true}, {Line1: "2 Elm St", City: "Springfield", Country: "US", Default: true}},
+			expected:    "only one address can be the default",
+		},
+		{
+			description: "succeeds with a single default",
+			addresses:   []Address{valid, {Line1: "2 Elm St", City: "Springfield", Country: "US", Default: true}},
+			expected:    "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			err := CheckAddresses(tc.addresses)
+			if tc.expected == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.EqualError(t, err, tc.expected)
+		})
+	}
+}
+
+func TestCustomerDefaultAddress(t *testing.T) {
+	assert.Nil(t, (&Customer{}).DefaultAddress())
+
+	c := &Customer{Addresses: []Address{{Label: "billing"}, {Label: "shipping", Default: true}}}
+	assert.Equal(t, "shipping", c.DefaultAddress().Label)
+
+	c.Addresses[1].Default = false
+	assert.Equal(t, "billing", c.DefaultAddress().Label)
+}
@@ -0,0 +1,184 @@
+package models
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/heiytor/invenda/api/pkg/money"
+)
+
+// ExchangeRate represents what one unit of a currency is worth in the namespace's base currency from a
+// date on. A rate is effective until the next rate of its currency takes over.
+type ExchangeRate struct {
+	ID          string         `json:"id" bson:"_id"`
+	NamespaceID string         `json:"namespace_id" bson:"namespace_id"`
+	CreatedAt   time.Time      `json:"created_at" bson:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at" bson:"updated_at"`
+	Currency    money.Currency `json:"currency" bson:"currency"`
+	Rate        money.Rate     `json:"rate" bson:"rate"`
+	ValidFrom   time.Time      `json:"valid_from" bson:"valid_from"`
+}
+
+// ExchangeRateLoad reports how many rates a load created and how many it replaced.
+type ExchangeRateLoad struct {
+	Created int64 `json:"created"`
+	Updated int64 `json:"updated"`
+}
+
+// exchangeRateColumns are the columns of exchange rate CSV files.
+var exchangeRateColumns = []string{"currency", "rate", "valid_from"}
+
+// ParseExchangeRates reads exchange rates from a CSV file. The file starts with a header naming the
+// currency, rate and valid_from columns, in any order, and valid_from is either a date (2006-01-02),
+// taken as midnight UTC, or an RFC 3339 time. Errors name the line they were found at.
+func ParseExchangeRates(r io.Reader) ([]ExchangeRate, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("the file is empty")
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, h := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))] = i
+	}
+
+	for _, c := range exchangeRateColumns {
+		if _, ok := columns[c]; !ok {
+			return nil, fmt.Errorf("the header has no %s column", c)
+		}
+	}
+
+	rates := make([]ExchangeRate, 0)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		line, _ := reader.FieldPos(0)
+
+		currency, err := money.ParseCurrency(record[columns["currency"]])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		rate, err := money.ParseRate(record[columns["rate"]])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		from, err := parseDate(record[columns["valid_from"]])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		rates = append(rates, ExchangeRate{Currency: currency, Rate: rate, ValidFrom: from})
+	}
+
+	return rates, nil
+}
+
+// parseDate parses a date or an RFC 3339 time.
+func parseDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if t, err := time.Parse(time.DateOnly, s); err == nil {
+		return t, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("valid_from %q is neither a date nor an RFC 3339 time", s)
+	}
+
+	return t.UTC(), nil
+}
+
+// CheckExchangeRates reports whether the rates quote valid currencies other than the base currency at
+// positive rates, and whether each currency has at most one rate from each time.
+func CheckExchangeRates(rates []ExchangeRate, base money.Currency) error {
+	if len(rates) == 0 {
+		return errors.New("at least one rate is required")
+	}
+
+	seen := make(map[string]bool, len(rates))
+	for _, r := range rates {
+		if !r.Currency.Valid() {
+			return fmt.Errorf("currency %q is not a valid ISO 4217 code", r.Currency)
+		}
+
+		if r.Currency == base {
+			return fmt.Errorf("%s is the base currency", r.Currency)
+		}
+
+		if _, err := money.ParseRate(string(r.Rate)); err != nil {
+			return err
+		}
+
+		if r.ValidFrom.IsZero() {
+			return fmt.Errorf("rate of %s must have a valid_from", r.Currency)
+		}
+
+		k := string(r.Currency) + "/" + r.ValidFrom.UTC().Format(time.RFC3339Nano)
+		if seen[k] {
+			return fmt.Errorf("%s has more than one rate from %s", r.Currency, r.ValidFrom.UTC().Format(time.RFC3339))
+		}
+
+		seen[k] = true
+	}
+
+	return nil
+}
+
+// Conversion converts the amounts of a document between its currency and the namespace's base currency,
+// at the exchange rate the document was priced with. The zero value converts nothing.
+type Conversion struct {
+	Currency money.Currency
+	Base     money.Currency
+	Rate     money.Rate
+}
+
+// NewConversion returns the conversion of a document of the currency priced at the rate. Documents
+// without a currency are in the base currency.
+func NewConversion(currency, base money.Currency, rate money.Rate) Conversion {
+	if currency == "" {
+		currency = base
+	}
+
+	return Conversion{Currency: currency, Base: base, Rate: rate}
+}
+
+// ToBase converts an amount of the document's currency to the base currency, rounding half up.
+func (c Conversion) ToBase(amount money.Money) money.Money {
+	return money.New(amount.Amount, c.Currency).Convert(c.Base, c.Rate.Rat(), money.RoundHalfUp)
+}
+
+// FromBase converts an amount of the base currency to the document's currency, rounding half up.
+func (c Conversion) FromBase(amount money.Money) money.Money {
+	return money.New(amount.Amount, c.Base).Convert(c.Currency, new(big.Rat).Inv(c.Rate.Rat()), money.RoundHalfUp)
+}
+
+// Conversion returns how the order's amounts convert to the base currency.
+func (so *SalesOrder) Conversion(base money.Currency) Conversion {
+	return NewConversion(so.Currency, base, so.ExchangeRate)
+}
+
+// Conversion returns how the order's costs convert to the base currency.
+func (po *PurchaseOrder) Conversion(base money.Currency) Conversion {
+	return NewConversion(po.Currency, base, po.ExchangeRate)
+}
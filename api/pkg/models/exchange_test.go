@@ -0,0 +1,73 @@
+package models
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/heiytor/invenda/api/pkg/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseExchangeRates(t *testing.T) {
+	csv := "\ufeffValid_From,Currency,Rate\n2026-01-01,usd,5.1234\n2026-01-02T12:00:00-03:00,EUR,5.9\n"
+
+	rates, err := ParseExchangeRates(strings.NewReader(csv))
+	assert.NoError(t, err)
+	assert.Equal(t, []ExchangeRate{
+		{Currency: "USD", Rate: "5.1234", ValidFrom: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{Currency: "EUR", Rate: "5.9", ValidFrom: time.Date(2026, 1, 2, 15, 0, 0, 0, time.UTC)},
+	}, rates)
+
+	cases := []struct {
+		description string
+		csv         string
+		err         string
+	}{
+		{description: "empty file", csv: "", err: "the file is empty"},
+		{description: "missing column", csv: "currency,rate\nUSD,5\n", err: "the header has no valid_from column"},
+		{description: "invalid currency", csv: "currency,rate,valid_from\nUSD,5,2026-01-01\nXYZ,5,2026-01-01\n", err: "line 3"},
+		{description: "invalid rate", csv: "currency,rate,valid_from\nUSD,-5,2026-01-01\n", err: "line 2"},
+		{description: "invalid date", csv: "currency,rate,valid_from\nUSD,5,01/01/2026\n", err: "line 2"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			_, err := ParseExchangeRates(strings.NewReader(tc.csv))
+			assert.ErrorContains(t, err, tc.err)
+		})
+	}
+}
+
+func TestCheckExchangeRates(t *testing.T) {
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.NoError(t, CheckExchangeRates([]ExchangeRate{
+		{Currency: "USD", Rate: "5.1", ValidFrom: from},
+		{Currency: "USD", Rate: "5.2", ValidFrom: from.AddDate(0, 0, 1)},
+		{Currency: "EUR", Rate: "5.9", ValidFrom: from},
+	}, "BRL"))
+
+	assert.Error(t, CheckExchangeRates(nil, "BRL"))
+	assert.Error(t, CheckExchangeRates([]ExchangeRate{{Currency: "BRL", Rate: "1", ValidFrom: from}}, "BRL"))
+	assert.Error(t, CheckExchangeRates([]ExchangeRate{{Currency: "usd", Rate: "5", ValidFrom: from}}, "BRL"))
+	assert.Error(t, CheckExchangeRates([]ExchangeRate{{Currency: "USD", Rate: "0", ValidFrom: from}}, "BRL"))
+	assert.Error(t, CheckExchangeRates([]ExchangeRate{{Currency: "USD", Rate: "5"}}, "BRL"))
+	assert.Error(t, CheckExchangeRates([]ExchangeRate{
+		{Currency: "USD", Rate: "5.1", ValidFrom: from},
+		{Currency: "USD", Rate: "5.2", ValidFrom: from.In(time.FixedZone("BRT", -3*60*60))},
+	}, "BRL"))
+}
+
+func TestConversion(t *testing.T) {
+	usd := NewConversion("USD", "BRL", "5")
+
+	assert.Equal(t, money.New(5000, "BRL"), usd.ToBase(money.New(1000, "USD")))
+	assert.Equal(t, money.New(1000, "USD"), usd.FromBase(money.New(5000, "BRL")))
+	assert.Equal(t, money.New(67, "USD"), usd.FromBase(money.New(333, "BRL")))
+
+	// Documents without a currency and the zero value convert nothing.
+	assert.Equal(t, Conversion{Currency: "BRL", Base: "BRL"}, NewConversion("", "BRL", ""))
+	assert.Equal(t, money.New(1234, ""), Conversion{}.ToBase(money.New(1234, "")))
+	assert.Equal(t, money.New(1234, ""), Conversion{}.FromBase(money.New(1234, "")))
+}
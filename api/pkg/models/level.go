@@ -0,0 +1,66 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// StockLevel holds the replenishment thresholds of a product, or of one of its variants, in a warehouse.
+type StockLevel struct {
+	ID          string    `json:"id" bson:"_id"`
+	NamespaceID string    `json:"namespace_id" bson:"namespace_id"`
+	CreatedAt   time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" bson:"updated_at"`
+	WarehouseID string    `json:"warehouse_id" bson:"warehouse_id"`
+	ProductID   string    `json:"product_id" bson:"product_id"`
+	VariantID   string    `json:"variant_id" bson:"variant_id"`
+
+	// Min is the safety stock, the level the stock should never fall below.
+	Min int64 `json:"min" bson:"min"`
+	// Max is the level the stock is brought back to when reordered.
+	Max int64 `json:"max" bson:"max"`
+	// ReorderPoint is the level at which the item must be reordered.
+	ReorderPoint int64 `json:"reorder_point" bson:"reorder_point"`
+}
+
+// CheckThresholds reports whether 0 <= min <= reorder point < max.
+func (l *StockLevel) CheckThresholds() error {
+	switch {
+	case l.Min < 0:
+		return errors.New("min must be zero or positive")
+	case l.ReorderPoint < l.Min:
+		return errors.New("reorder point must be greater than or equal to min")
+	case l.Max <= l.ReorderPoint:
+		return errors.New("max must be greater than the reorder point")
+	}
+
+	return nil
+}
+
+// ReorderLine is an item at or below its reorder point.
+type ReorderLine struct {
+	WarehouseID string `json:"warehouse_id" bson:"warehouse_id"`
+	ProductID   string `json:"product_id" bson:"product_id"`
+	VariantID   string `json:"variant_id" bson:"variant_id"`
+	SKU         string `json:"sku" bson:"sku"`
+	Name        string `json:"name" bson:"name"`
+
+	OnHand       int64 `json:"on_hand" bson:"on_hand"`
+	InTransit    int64 `json:"in_transit" bson:"in_transit"`
+	Min          int64 `json:"min" bson:"min"`
+	Max          int64 `json:"max" bson:"max"`
+	ReorderPoint int64 `json:"reorder_point" bson:"reorder_point"`
+
+	// Position is the stock expected to be available, i.e. on hand plus in transit.
+	Position int64 `json:"position" bson:"position"`
+	// Quantity is the suggested quantity to order, bringing the position back to max.
+	Quantity int64 `json:"quantity" bson:"quantity"`
+}
+
+// ReorderSuggestion groups the reorder lines of a supplier. SupplierID is empty for products
+// without a preferred supplier.
+type ReorderSuggestion struct {
+	SupplierID string        `json:"supplier_id" bson:"_id"`
+	Quantity   int64         `json:"quantity" bson:"quantity"`
+	Lines      []ReorderLine `json:"lines" bson:"lines"`
+}
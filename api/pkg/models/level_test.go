@@ -0,0 +1,48 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStockLevelCheckThresholds(t *testing.T) {
+	cases := []struct {
+		description string
+		level       *StockLevel
+		expected    string
+	}{
+		{
+			description: "fails when min is negative",
+			level:       &StockLevel{Min: -1, ReorderPoint: 0, Max: 1},
+			expected:    "min must be zero or positive",
+		},
+		{
+			description: "fails when the reorder point is below min",
+			level:       &StockLevel{Min: 5, ReorderPoint: 4, Max: 10},
+			expected:    "reorder point must be greater than or equal to min",
+		},
+		{
+			description: "fails when max is not above the reorder point",
+			level:       &StockLevel{Min: 5, ReorderPoint: 10, Max: 10},
+			expected:    "max must be greater than the reorder point",
+		},
+		{
+			description: "succeeds when the thresholds are ordered",
+			level:       &StockLevel{Min: 5, ReorderPoint: 8, Max: 20},
+			expected:    "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			err := tc.level.CheckThresholds()
+			if tc.expected == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.EqualError(t, err, tc.expected)
+		})
+	}
+}
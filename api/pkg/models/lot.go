@@ -0,0 +1,118 @@
+package models
+
+import (
+	"cmp"
+	"errors"
+	"slices"
+	"time"
+)
+
+// IssueStrategy defines which lots of a lot-tracked product are issued first.
+type IssueStrategy string
+
+const (
+	// IssueFEFO issues the lots that expire first. Lots without expiry are issued last.
+	IssueFEFO IssueStrategy = "fefo"
+	// IssueFIFO issues the lots that were received first.
+	IssueFIFO IssueStrategy = "fifo"
+	// IssueSpecific requires every issue to name the lot it is taken from.
+	IssueSpecific IssueStrategy = "specific"
+)
+
+// UnassignedLot holds the stock of a lot-tracked product that entered without a lot, e.g. surplus found
+// in a stock count.
+const UnassignedLot = "UNASSIGNED"
+
+var (
+	ErrLotRequired      = errors.New("lot is required")
+	ErrLotExpired       = errors.New("lot is expired")
+	ErrInsufficientLots = errors.New("insufficient stock in lots")
+	ErrUnknownStrategy  = errors.New("unknown issue strategy")
+)
+
+// StockLot is the balance of a lot of a [StockKey].
+type StockLot struct {
+	ID          string    `json:"id" bson:"_id"`
+	NamespaceID string    `json:"namespace_id" bson:"namespace_id"`
+	UpdatedAt   time.Time `json:"updated_at" bson:"updated_at"`
+	StockKey    `bson:",inline"`
+	Lot         string     `json:"lot" bson:"lot"`
+	ExpiresAt   *time.Time `json:"expires_at" bson:"expires_at"`
+
+	// ReceivedAt is when the lot first entered the key.
+	ReceivedAt time.Time `json:"received_at" bson:"received_at"`
+	OnHand     int64     `json:"on_hand" bson:"on_hand"`
+}
+
+// Expired reports whether the lot is expired at now.
+func (l *StockLot) Expired(now time.Time) bool {
+	return l.ExpiresAt != nil && !l.ExpiresAt.After(now)
+}
+
+// LotAllocation is the quantity taken from a lot.
+type LotAllocation struct {
+	Lot       string     `json:"lot" bson:"lot"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty" bson:"expires_at,omitempty"`
+	Quantity  int64      `json:"quantity" bson:"quantity"`
+}
+
+// AllocateLots splits quantity across the lots following the strategy. The specific strategy takes
+// everything from the lot named by specific, which may also be used with the other strategies to bypass
+// them. Expired lots are skipped, or rejected when named, unless allowExpired is true.
+func AllocateLots(lots []StockLot, quantity int64, strategy IssueStrategy, specific string, now time.Time, allowExpired bool) ([]LotAllocation, error) {
+	if specific != "" {
+		idx := slices.IndexFunc(lots, func(l StockLot) bool { return l.Lot == specific })
+		switch {
+		case idx < 0 || lots[idx].OnHand < quantity:
+			return nil, ErrInsufficientLots
+		case lots[idx].Expired(now) && !allowExpired:
+			return nil, ErrLotExpired
+		}
+
+		return []LotAllocation{{Lot: specific, ExpiresAt: lots[idx].ExpiresAt, Quantity: quantity}}, nil
+	}
+
+	sorted := slices.Clone(lots)
+	switch strategy {
+	case IssueFEFO:
+		slices.SortStableFunc(sorted, func(a, b StockLot) int {
+			switch {
+			case a.ExpiresAt == nil && b.ExpiresAt == nil:
+				return a.ReceivedAt.Compare(b.ReceivedAt)
+			case a.ExpiresAt == nil:
+				return 1
+			case b.ExpiresAt == nil:
+				return -1
+			}
+
+			return cmp.Or(a.ExpiresAt.Compare(*b.ExpiresAt), a.ReceivedAt.Compare(b.ReceivedAt))
+		})
+	case IssueFIFO:
+		slices.SortStableFunc(sorted, func(a, b StockLot) int { return a.ReceivedAt.Compare(b.ReceivedAt) })
+	case IssueSpecific:
+		return nil, ErrLotRequired
+	default:
+		return nil, ErrUnknownStrategy
+	}
+
+	allocations := make([]LotAllocation, 0)
+	for _, l := range sorted {
+		if quantity == 0 {
+			break
+		}
+
+		if l.OnHand <= 0 || (l.Expired(now) && !allowExpired) {
+			continue
+		}
+
+		q := min(l.OnHand, quantity)
+		allocations = append(allocations, LotAllocation{Lot: l.Lot, ExpiresAt: l.ExpiresAt, Quantity: q})
+		quantity -= q
+	}
+
+	if quantity > 0 {
+		return nil, ErrInsufficientLots
+	}
+
+	return allocations, nil
+}
@@ -0,0 +1,121 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllocateLots(t *testing.T) {
+	type Expected struct {
+		allocations []LotAllocation
+		err         error
+	}
+
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	date := func(month time.Month) *time.Time {
+		d := time.Date(2024, month, 1, 0, 0, 0, 0, time.UTC)
+		return &d
+	}
+
+	lots := []StockLot{
+		{Lot: "EXPIRED", ExpiresAt: date(time.May), ReceivedAt: now.AddDate(0, -3, 0), OnHand: 5},
+		{Lot: "LATE", ExpiresAt: date(time.December), ReceivedAt: now.AddDate(0, -2, 0), OnHand: 5},
+		{Lot: "NONE", ExpiresAt: nil, ReceivedAt: now.AddDate(0, -4, 0), OnHand: 5},
+		{Lot: "SOON", ExpiresAt: date(time.July), ReceivedAt: now.AddDate(0, -1, 0), OnHand: 5},
+	}
+
+	cases := []struct {
+		description  string
+		quantity     int64
+		strategy     IssueStrategy
+		specific     string
+		allowExpired bool
+		expected     Expected
+	}{
+		{
+			description: "fefo issues the lots that expire first, skipping expired ones",
+			quantity:    7,
+			strategy:    IssueFEFO,
+			expected: Expected{
+				allocations: []LotAllocation{
+					{Lot: "SOON", ExpiresAt: date(time.July), Quantity: 5},
+					{Lot: "LATE", ExpiresAt: date(time.December), Quantity: 2},
+				},
+			},
+		},
+		{
+			description: "fefo issues lots without expiry last",
+			quantity:    12,
+			strategy:    IssueFEFO,
+			expected: Expected{
+				allocations: []LotAllocation{
+					{Lot: "SOON", ExpiresAt: date(time.July), Quantity: 5},
+					{Lot: "LATE", ExpiresAt: date(time.December), Quantity: 5},
+					{Lot: "NONE", Quantity: 2},
+				},
+			},
+		},
+		{
+			description:  "fifo issues the lots received first",
+			quantity:     7,
+			strategy:     IssueFIFO,
+			allowExpired: true,
+			expected: Expected{
+				allocations: []LotAllocation{
+					{Lot: "NONE", Quantity: 5},
+					{Lot: "EXPIRED", ExpiresAt: date(time.May), Quantity: 2},
+				},
+			},
+		},
+		{
+			description: "fails when the lots that can be issued are not enough",
+			quantity:    16,
+			strategy:    IssueFEFO,
+			expected:    Expected{err: ErrInsufficientLots},
+		},
+		{
+			description: "specific issues from the named lot",
+			quantity:    3,
+			strategy:    IssueSpecific,
+			specific:    "LATE",
+			expected:    Expected{allocations: []LotAllocation{{Lot: "LATE", ExpiresAt: date(time.December), Quantity: 3}}},
+		},
+		{
+			description: "specific fails when no lot is named",
+			quantity:    3,
+			strategy:    IssueSpecific,
+			expected:    Expected{err: ErrLotRequired},
+		},
+		{
+			description: "fails when the named lot is expired",
+			quantity:    3,
+			strategy:    IssueFEFO,
+			specific:    "EXPIRED",
+			expected:    Expected{err: ErrLotExpired},
+		},
+		{
+			description:  "succeeds to issue the named expired lot when allowed",
+			quantity:     3,
+			strategy:     IssueFEFO,
+			specific:     "EXPIRED",
+			allowExpired: true,
+			expected:     Expected{allocations: []LotAllocation{{Lot: "EXPIRED", ExpiresAt: date(time.May), Quantity: 3}}},
+		},
+		{
+			description: "fails when the named lot does not have enough stock",
+			quantity:    6,
+			strategy:    IssueFEFO,
+			specific:    "LATE",
+			expected:    Expected{err: ErrInsufficientLots},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			allocations, err := AllocateLots(lots, tc.quantity, tc.strategy, tc.specific, now, tc.allowExpired)
+			assert.Equal(t, tc.expected, Expected{allocations, err})
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/heiytor/invenda/api/pkg/auth"
 	"github.com/heiytor/invenda/api/pkg/errors"
+	"github.com/heiytor/invenda/api/pkg/money"
 )
 
 type Namespace struct {
@@ -15,6 +16,44 @@ type Namespace struct {
 	UpdatedAt time.Time `json:"updated_at,omitempty" bson:"updated_at"`
 	Name      string    `json:"name" bson:"name"`
 	Members   []Member  `json:"members,omitempty" bson:"members"`
+	Settings  Settings  `json:"settings" bson:"settings"`
+}
+
+// Settings holds the namespace-wide inventory settings.
+type Settings struct {
+	// AllowBackorders allows stock balances to go below zero.
+	AllowBackorders bool `json:"allow_backorders" bson:"allow_backorders"`
+
+	// ValuationMethod defines how the stock leaving the namespace is valued. It defaults to
+	// [ValuationAverage] and a change only applies to the movements posted after it.
+	ValuationMethod ValuationMethod `json:"valuation_method,omitempty" bson:"valuation_method,omitempty"`
+
+	// TaxInclusive reports whether sales prices include their taxes. TaxRounding defines where taxes are
+	// rounded and defaults to [TaxRoundingLine]. Both are copied to the documents when they are priced.
+	TaxInclusive bool        `json:"tax_inclusive" bson:"tax_inclusive"`
+	TaxRounding  TaxRounding `json:"tax_rounding,omitempty" bson:"tax_rounding,omitempty"`
+
+	// Currency is the base currency, in which the catalog is priced and stock is valued, and to which
+	// reports convert the documents of other currencies. Once set it cannot be changed.
+	Currency money.Currency `json:"currency,omitempty" bson:"currency,omitempty"`
+}
+
+// Valuation returns the namespace's valuation method, defaulting to [ValuationAverage].
+func (s *Settings) Valuation() ValuationMethod {
+	if s.ValuationMethod == "" {
+		return ValuationAverage
+	}
+
+	return s.ValuationMethod
+}
+
+// Rounding returns the namespace's tax rounding, defaulting to [TaxRoundingLine].
+func (s *Settings) Rounding() TaxRounding {
+	if s.TaxRounding == "" {
+		return TaxRoundingLine
+	}
+
+	return s.TaxRounding
 }
 
 // FindMember reports whether a member exists or not in the namespace.
@@ -47,12 +86,19 @@ func (ns *Namespace) WithoutPermissions() {
 
 type Member struct {
 	ID          string           `json:"id" bson:"_id"`
+	Name        string           `json:"name,omitempty" bson:"name,omitempty"`   // Name is a copy of the user's name, used for searching.
+	Email       string           `json:"email,omitempty" bson:"email,omitempty"` // Email is a copy of the user's email, used for searching.
 	AddedAt     time.Time        `json:"added_at" bson:"added_at"`
 	Owner       bool             `json:"owner" bson:"owner"`
 	Permissions auth.Permissions `json:"permissions,omitempty" bson:"permissions"`
 }
 
 type NamespaceChanges struct {
-	UpdatedAt time.Time `bson:"updated_at"`
-	Name      string    `bson:"name,omitempty"`
+	UpdatedAt       time.Time       `bson:"updated_at"`
+	Name            string          `bson:"name,omitempty"`
+	AllowBackorders *bool           `bson:"settings.allow_backorders,omitempty"`
+	ValuationMethod ValuationMethod `bson:"settings.valuation_method,omitempty"`
+	TaxInclusive    *bool           `bson:"settings.tax_inclusive,omitempty"`
+	TaxRounding     TaxRounding     `bson:"settings.tax_rounding,omitempty"`
+	Currency        money.Currency  `bson:"settings.currency,omitempty"`
 }
@@ -0,0 +1,220 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/heiytor/invenda/api/pkg/money"
+)
+
+// PriceList represents prices that replace the products' own prices for the customers of some groups, such
+// as a wholesale or a VIP list, while it is valid. Its prices are amounts of the namespace's base currency.
+type PriceList struct {
+	ID          string    `json:"id" bson:"_id"`
+	NamespaceID string    `json:"namespace_id" bson:"namespace_id"`
+	CreatedAt   time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" bson:"updated_at"`
+	Code        string    `json:"code" bson:"code"`
+	Name        string    `json:"name" bson:"name"`
+	Active      bool      `json:"active" bson:"active"`
+
+	// Groups are the customer groups the list applies to. A list without groups applies to every
+	// customer, including the anonymous customers of counter sales.
+	Groups []string `json:"groups" bson:"groups"`
+
+	// Priority decides which list prices an item when several lists apply: the highest priority wins and,
+	// among lists of the same priority, the lowest price.
+	Priority int `json:"priority" bson:"priority"`
+
+	// ValidFrom and ValidUntil bound when the list applies, ValidUntil being exclusive. A nil bound leaves
+	// the range open on that side.
+	ValidFrom  *time.Time `json:"valid_from,omitempty" bson:"valid_from,omitempty"`
+	ValidUntil *time.Time `json:"valid_until,omitempty" bson:"valid_until,omitempty"`
+
+	Prices []PriceListItem `json:"prices" bson:"prices"`
+}
+
+// PriceListItem prices a product, or one of its variants, in a price list. An empty VariantID applies to
+// every variant of the product without a price of its own.
+type PriceListItem struct {
+	ProductID string `json:"product_id" bson:"product_id" validate:"required|ulid"`
+	VariantID string `json:"variant_id" bson:"variant_id" validate:"ulid"`
+
+	// Tiers are the item's quantity breaks. A quantity takes the price of the tier with the highest minimum
+	// quantity it reaches and quantities below every tier are not priced by the list.
+	Tiers []PriceTier `json:"tiers" bson:"tiers"`
+}
+
+type PriceTier struct {
+	MinQuantity int64       `json:"min_quantity" bson:"min_quantity"`
+	Price       money.Money `json:"price" bson:"price"`
+}
+
+// PriceQuote represents the unit price of a product or variant for a customer buying a quantity of it at
+// some time.
+type PriceQuote struct {
+	ProductID  string    `json:"product_id"`
+	VariantID  string    `json:"variant_id"`
+	CustomerID string    `json:"customer_id,omitempty"`
+	Quantity   int64     `json:"quantity"`
+	At         time.Time `json:"at"`
+
+	// BasePrice is the price of the product or variant, which is the unit price when no price list applies.
+	BasePrice money.Money `json:"base_price"`
+	UnitPrice money.Money `json:"unit_price"`
+
+	// PriceListID is the ID of the price list the unit price comes from, if any.
+	PriceListID string `json:"price_list_id,omitempty"`
+}
+
+// CheckPriceListItems reports whether the items have at least one tier, whether the tiers have positive
+// and distinct minimum quantities and non-negative prices, and whether each product or variant appears
+// only once.
+func CheckPriceListItems(items []PriceListItem) error {
+	seen := make(map[string]bool, len(items))
+	for _, it := range items {
+		if it.ProductID == "" || len(it.Tiers) == 0 {
+			return errors.New("prices must have a product_id and at least one tier")
+		}
+
+		quantities := make(map[int64]bool, len(it.Tiers))
+		for _, t := range it.Tiers {
+			if t.MinQuantity < 1 {
+				return fmt.Errorf("minimum quantities of %q must be positive", it.ProductID)
+			}
+
+			if t.Price.IsNegative() {
+				return fmt.Errorf("prices of %q cannot be negative", it.ProductID)
+			}
+
+			if quantities[t.MinQuantity] {
+				return fmt.Errorf("tier %d of %q is duplicated", t.MinQuantity, it.ProductID)
+			}
+
+			quantities[t.MinQuantity] = true
+		}
+
+		k := it.ProductID + "/" + it.VariantID
+		if seen[k] {
+			return fmt.Errorf("product %q is duplicated", k)
+		}
+
+		seen[k] = true
+	}
+
+	return nil
+}
+
+// CheckValidity reports whether a validity range ends after it starts.
+func CheckValidity(from, until *time.Time) error {
+	if from != nil && until != nil && !until.After(*from) {
+		return errors.New("valid_until must be after valid_from")
+	}
+
+	return nil
+}
+
+// AppliesTo reports whether the list applies to the customers of a group at a time.
+func (pl *PriceList) AppliesTo(group string, at time.Time) bool {
+	if !pl.Active {
+		return false
+	}
+
+	if len(pl.Groups) > 0 && !slices.Contains(pl.Groups, group) {
+		return false
+	}
+
+	if pl.ValidFrom != nil && at.Before(*pl.ValidFrom) {
+		return false
+	}
+
+	if pl.ValidUntil != nil && !at.Before(*pl.ValidUntil) {
+		return false
+	}
+
+	return true
+}
+
+// Price returns the list's unit price of a quantity of a product or variant, falling back to the price of
+// the product when the variant has none. It reports whether the list prices the quantity.
+func (pl *PriceList) Price(productID, variantID string, quantity int64) (money.Money, bool) {
+	var item, fallback *PriceListItem
+	for i, it := range pl.Prices {
+		if it.ProductID != productID {
+			continue
+		}
+
+		if it.VariantID == variantID {
+			item = &pl.Prices[i]
+			break
+		}
+
+		if it.VariantID == "" {
+			fallback = &pl.Prices[i]
+		}
+	}
+
+	if item == nil {
+		item = fallback
+	}
+
+	if item == nil {
+		return money.Money{}, false
+	}
+
+	var tier *PriceTier
+	for i, t := range item.Tiers {
+		if t.MinQuantity <= quantity && (tier == nil || t.MinQuantity > tier.MinQuantity) {
+			tier = &item.Tiers[i]
+		}
+	}
+
+	if tier == nil {
+		return money.Money{}, false
+	}
+
+	return tier.Price, true
+}
+
+// ResolvePrice returns the unit price of a quantity of a product or variant for the customers of a group at
+// a time, taken from the applicable list with the highest priority and, among lists of the same priority,
+// the lowest price. It returns the price, the ID of its list and whether any list prices the quantity.
+func ResolvePrice(lists []PriceList, group string, at time.Time, productID, variantID string, quantity int64) (money.Money, string, bool) {
+	var best *PriceList
+	var price money.Money
+	for i := range lists {
+		pl := &lists[i]
+		if !pl.AppliesTo(group, at) {
+			continue
+		}
+
+		p, ok := pl.Price(productID, variantID, quantity)
+		if !ok {
+			continue
+		}
+
+		if best == nil || pl.Priority > best.Priority || (pl.Priority == best.Priority && p.Amount < price.Amount) {
+			best, price = pl, p
+		}
+	}
+
+	if best == nil {
+		return money.Money{}, "", false
+	}
+
+	return price, best.ID, true
+}
+
+type PriceListChanges struct {
+	UpdatedAt  time.Time       `bson:"updated_at"`
+	Code       string          `bson:"code,omitempty"`
+	Name       string          `bson:"name,omitempty"`
+	Active     *bool           `bson:"active,omitempty"`
+	Groups     []string        `bson:"groups,omitempty"`
+	Priority   *int            `bson:"priority,omitempty"`
+	ValidFrom  *time.Time      `bson:"valid_from,omitempty"`
+	ValidUntil *time.Time      `bson:"valid_until,omitempty"`
+	Prices     []PriceListItem `bson:"prices,omitempty"`
+}
@@ -0,0 +1,120 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/heiytor/invenda/api/pkg/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPriceListPrice(t *testing.T) {
+	pl := &PriceList{Prices: []PriceListItem{
+		{ProductID: "prd_1", Tiers: []PriceTier{{MinQuantity: 1, Price: money.New(1000, "BRL")}, {MinQuantity: 10, Price: money.New(900, "BRL")}, {MinQuantity: 50, Price: money.New(800, "BRL")}}},
+		{ProductID: "prd_1", VariantID: "var_1", Tiers: []PriceTier{{MinQuantity: 5, Price: money.New(1100, "BRL")}}},
+	}}
+
+	p, ok := pl.Price("prd_1", "", 9)
+	assert.True(t, ok)
+	assert.Equal(t, money.New(1000, "BRL"), p)
+
+	p, ok = pl.Price("prd_1", "", 10)
+	assert.True(t, ok)
+	assert.Equal(t, money.New(900, "BRL"), p)
+
+	p, ok = pl.Price("prd_1", "", 120)
+	assert.True(t, ok)
+	assert.Equal(t, money.New(800, "BRL"), p)
+
+	p, ok = pl.Price("prd_1", "var_2", 10)
+	assert.True(t, ok)
+	assert.Equal(t, money.New(900, "BRL"), p)
+
+	p, ok = pl.Price("prd_1", "var_1", 5)
+	assert.True(t, ok)
+	assert.Equal(t, money.New(1100, "BRL"), p)
+
+	_, ok = pl.Price("prd_1", "var_1", 4)
+	assert.False(t, ok)
+
+	_, ok = pl.Price("prd_2", "", 1)
+	assert.False(t, ok)
+}
+
+func TestPriceListAppliesTo(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	pl := &PriceList{Active: true, Groups: []string{"wholesale"}, ValidFrom: &from, ValidUntil: &until}
+
+	assert.True(t, pl.AppliesTo("wholesale", from))
+	assert.False(t, pl.AppliesTo("wholesale", from.Add(-time.Second)))
+	assert.False(t, pl.AppliesTo("wholesale", until))
+	assert.False(t, pl.AppliesTo("vip", from))
+	assert.False(t, pl.AppliesTo("", from))
+
+	pl.Groups = nil
+	assert.True(t, pl.AppliesTo("", from))
+	assert.True(t, pl.AppliesTo("vip", from))
+
+	pl.Active = false
+	assert.False(t, pl.AppliesTo("", from))
+}
+
+func TestResolvePrice(t *testing.T) {
+	now := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	expired := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	item := func(price int64) []PriceListItem {
+		return []PriceListItem{{ProductID: "prd_1", Tiers: []PriceTier{{MinQuantity: 1, Price: money.New(price, "BRL")}}}}
+	}
+
+	lists := []PriceList{
+		{ID: "prl_retail", Active: true, Prices: item(1000)},
+		{ID: "prl_wholesale", Active: true, Groups: []string{"wholesale"}, Priority: 10, Prices: item(850)},
+		{ID: "prl_clearance", Active: true, Groups: []string{"wholesale"}, Priority: 10, Prices: item(800)},
+		{ID: "prl_vip", Active: true, Groups: []string{"vip"}, Priority: 20, Prices: item(900)},
+		{ID: "prl_old", Active: true, Priority: 30, ValidUntil: &expired, Prices: item(500)},
+	}
+
+	price, listID, ok := ResolvePrice(lists, "", now, "prd_1", "", 1)
+	assert.True(t, ok)
+	assert.Equal(t, money.New(1000, "BRL"), price)
+	assert.Equal(t, "prl_retail", listID)
+
+	price, listID, ok = ResolvePrice(lists, "wholesale", now, "prd_1", "", 1)
+	assert.True(t, ok)
+	assert.Equal(t, money.New(800, "BRL"), price)
+	assert.Equal(t, "prl_clearance", listID)
+
+	// A higher priority wins even when its price is higher.
+	price, listID, ok = ResolvePrice(lists, "vip", now, "prd_1", "", 1)
+	assert.True(t, ok)
+	assert.Equal(t, money.New(900, "BRL"), price)
+	assert.Equal(t, "prl_vip", listID)
+
+	_, _, ok = ResolvePrice(lists, "", now, "prd_2", "", 1)
+	assert.False(t, ok)
+}
+
+func TestCheckPriceListItems(t *testing.T) {
+	tiers := []PriceTier{{MinQuantity: 1, Price: money.New(100, "BRL")}}
+
+	assert.NoError(t, CheckPriceListItems([]PriceListItem{{ProductID: "prd_1", Tiers: tiers}, {ProductID: "prd_1", VariantID: "var_1", Tiers: tiers}}))
+	assert.EqualError(t, CheckPriceListItems([]PriceListItem{{ProductID: "prd_1"}}), "prices must have a product_id and at least one tier")
+	assert.EqualError(t, CheckPriceListItems([]PriceListItem{{ProductID: "prd_1", Tiers: []PriceTier{{MinQuantity: 0, Price: money.New(100, "BRL")}}}}), `minimum quantities of "prd_1" must be positive`)
+	assert.EqualError(t, CheckPriceListItems([]PriceListItem{{ProductID: "prd_1", Tiers: []PriceTier{{MinQuantity: 1, Price: money.New(-1, "BRL")}}}}), `prices of "prd_1" cannot be negative`)
+	assert.EqualError(t, CheckPriceListItems([]PriceListItem{{ProductID: "prd_1", Tiers: []PriceTier{{MinQuantity: 1, Price: money.New(100, "BRL")}, {MinQuantity: 1, Price: money.New(90, "BRL")}}}}), `tier 1 of "prd_1" is duplicated`)
+	assert.EqualError(t, CheckPriceListItems([]PriceListItem{{ProductID: "prd_1", Tiers: tiers}, {ProductID: "prd_1", Tiers: tiers}}), `product "prd_1/" is duplicated`)
+}
+
+func TestCheckValidity(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	until := from.Add(24 * time.Hour)
+
+	assert.NoError(t, CheckValidity(nil, nil))
+	assert.NoError(t, CheckValidity(&from, nil))
+	assert.NoError(t, CheckValidity(&from, &until))
+	assert.EqualError(t, CheckValidity(&until, &from), "valid_until must be after valid_from")
+	assert.EqualError(t, CheckValidity(&from, &from), "valid_until must be after valid_from")
+}
@@ -0,0 +1,175 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/heiytor/invenda/api/pkg/money"
+)
+
+// Product represents an item of a namespace's catalog. Its price and cost are amounts of the namespace's
+// base currency.
+type Product struct {
+	ID          string      `json:"id" bson:"_id"`
+	NamespaceID string      `json:"namespace_id" bson:"namespace_id"`
+	CreatedAt   time.Time   `json:"created_at" bson:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at" bson:"updated_at"`
+	SKU         string      `json:"sku" bson:"sku"`
+	Name        string      `json:"name" bson:"name"`
+	Description string      `json:"description" bson:"description"`
+	Unit        string      `json:"unit" bson:"unit"`
+	Price       money.Money `json:"price" bson:"price"`
+	Cost        money.Money `json:"cost" bson:"cost"`
+	Barcode     string      `json:"barcode,omitempty" bson:"barcode,omitempty"`
+	Tags        []string    `json:"tags" bson:"tags"`
+	Active      bool        `json:"active" bson:"active"`
+	CategoryID  string      `json:"category_id,omitempty" bson:"category_id,omitempty"`
+
+	// TaxCategory selects the namespace's tax rates levied on the product's sales. Products without one
+	// are taxed at the rates given on the lines they are sold in.
+	TaxCategory string `json:"tax_category,omitempty" bson:"tax_category,omitempty"`
+
+	// PreferredSupplierID is the ID of the supplier the product is usually reordered from.
+	PreferredSupplierID string `json:"preferred_supplier_id,omitempty" bson:"preferred_supplier_id,omitempty"`
+
+	// LotTracked reports whether the product's stock is kept by lot. IssueStrategy defines which lots
+	// are issued first and defaults to [IssueFEFO].
+	LotTracked    bool          `json:"lot_tracked" bson:"lot_tracked"`
+	IssueStrategy IssueStrategy `json:"issue_strategy,omitempty" bson:"issue_strategy,omitempty"`
+
+	// Serialized reports whether each unit of the product is tracked by its serial number.
+	Serialized bool `json:"serialized" bson:"serialized"`
+
+	// Incoming is the quantity ordered from suppliers by sent purchase orders and not yet received.
+	Incoming int64 `json:"incoming" bson:"incoming"`
+
+	// Options defines the dimensions in which the product varies. Each combination of the options'
+	// values can be sold as a [Variant].
+	Options []ProductOption `json:"options" bson:"options"`
+}
+
+// ProductOption represents a dimension in which a product varies, e.g. Size: S/M/L.
+type ProductOption struct {
+	Name   string   `json:"name" bson:"name"`
+	Values []string `json:"values" bson:"values"`
+}
+
+// CheckOptions reports an error when an option has no name or values, or when option names or the
+// values of an option are repeated.
+func CheckOptions(opts []ProductOption) error {
+	names := make([]string, 0, len(opts))
+	for _, opt := range opts {
+		if opt.Name == "" || len(opt.Values) == 0 {
+			return fmt.Errorf("options must have a name and at least one value")
+		}
+
+		if slices.Contains(names, opt.Name) {
+			return fmt.Errorf("option %q is repeated", opt.Name)
+		}
+
+		names = append(names, opt.Name)
+
+		values := make([]string, 0, len(opt.Values))
+		for _, v := range opt.Values {
+			if v == "" || slices.Contains(values, v) {
+				return fmt.Errorf("option %q has an empty or repeated value", opt.Name)
+			}
+
+			values = append(values, v)
+		}
+	}
+
+	return nil
+}
+
+// Combinations returns every combination of the product's option values, in the order the options
+// and values are defined. It returns an empty list when the product has no options.
+func (p *Product) Combinations() []map[string]string {
+	if len(p.Options) == 0 {
+		return []map[string]string{}
+	}
+
+	combinations := []map[string]string{{}}
+	for _, opt := range p.Options {
+		next := make([]map[string]string, 0, len(combinations)*len(opt.Values))
+		for _, c := range combinations {
+			for _, v := range opt.Values {
+				attrs := make(map[string]string, len(c)+1)
+				for k, val := range c {
+					attrs[k] = val
+				}
+
+				attrs[opt.Name] = v
+				next = append(next, attrs)
+			}
+		}
+
+		combinations = next
+	}
+
+	return combinations
+}
+
+// CheckAttributes reports an error when attrs does not define exactly one valid value for each
+// of the product's options.
+func (p *Product) CheckAttributes(attrs map[string]string) error {
+	if len(attrs) != len(p.Options) {
+		return fmt.Errorf("attributes must define a value for each of the %d product's options", len(p.Options))
+	}
+
+	for _, opt := range p.Options {
+		v, ok := attrs[opt.Name]
+		if !ok {
+			return fmt.Errorf("attribute %q is missing", opt.Name)
+		}
+
+		if !slices.Contains(opt.Values, v) {
+			return fmt.Errorf("%q is not a valid value for attribute %q", v, opt.Name)
+		}
+	}
+
+	return nil
+}
+
+// VariantSKU returns the SKU of the variant with the specified attributes, built by appending the
+// values to the product's SKU in the order the options are defined.
+func (p *Product) VariantSKU(attrs map[string]string) string {
+	parts := []string{p.SKU}
+	for _, opt := range p.Options {
+		parts = append(parts, strings.ToUpper(strings.Join(strings.Fields(attrs[opt.Name]), "")))
+	}
+
+	return strings.Join(parts, "-")
+}
+
+// Strategy returns the product's issue strategy, defaulting to [IssueFEFO].
+func (p *Product) Strategy() IssueStrategy {
+	if p.IssueStrategy == "" {
+		return IssueFEFO
+	}
+
+	return p.IssueStrategy
+}
+
+type ProductChanges struct {
+	UpdatedAt   time.Time       `bson:"updated_at"`
+	SKU         string          `bson:"sku,omitempty"`
+	Name        string          `bson:"name,omitempty"`
+	Description *string         `bson:"description,omitempty"`
+	Unit        string          `bson:"unit,omitempty"`
+	Price       *money.Money    `bson:"price,omitempty"`
+	Cost        *money.Money    `bson:"cost,omitempty"`
+	Barcode     *string         `bson:"barcode,omitempty"`
+	Tags        []string        `bson:"tags,omitempty"`
+	Active      *bool           `bson:"active,omitempty"`
+	Options     []ProductOption `bson:"options,omitempty"`
+	CategoryID  string          `bson:"category_id,omitempty"`
+	TaxCategory *string         `bson:"tax_category,omitempty"`
+
+	PreferredSupplierID string        `bson:"preferred_supplier_id,omitempty"`
+	LotTracked          *bool         `bson:"lot_tracked,omitempty"`
+	IssueStrategy       IssueStrategy `bson:"issue_strategy,omitempty"`
+	Serialized          *bool         `bson:"serialized,omitempty"`
+}
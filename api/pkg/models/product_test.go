@@ -0,0 +1,154 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/heiytor/invenda/api/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestProductCombinations(t *testing.T) {
+	cases := []struct {
+		description string
+		product     *Product
+		expected    []map[string]string
+	}{
+		{
+			description: "returns an empty list when the product has no options",
+			product:     &Product{},
+			expected:    []map[string]string{},
+		},
+		{
+			description: "returns every combination of the options' values",
+			product: &Product{
+				Options: []ProductOption{
+					{Name: "Size", Values: []string{"S", "M"}},
+					{Name: "Colour", Values: []string{"Blue", "Red"}},
+				},
+			},
+			expected: []map[string]string{
+				{"Size": "S", "Colour": "Blue"},
+				{"Size": "S", "Colour": "Red"},
+				{"Size": "M", "Colour": "Blue"},
+				{"Size": "M", "Colour": "Red"},
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.product.Combinations())
+		})
+	}
+}
+
+func TestProductCheckAttributes(t *testing.T) {
+	product := &Product{
+		Options: []ProductOption{
+			{Name: "Size", Values: []string{"S", "M"}},
+			{Name: "Colour", Values: []string{"Blue", "Red"}},
+		},
+	}
+
+	cases := []struct {
+		description string
+		attrs       map[string]string
+		expected    string
+	}{
+		{
+			description: "fails when an option is missing",
+			attrs:       map[string]string{"Size": "S"},
+			expected:    "attributes must define a value for each of the 2 product's options",
+		},
+		{
+			description: "fails when an attribute is not an option",
+			attrs:       map[string]string{"Size": "S", "Material": "Cotton"},
+			expected:    `attribute "Colour" is missing`,
+		},
+		{
+			description: "fails when a value is not valid",
+			attrs:       map[string]string{"Size": "XL", "Colour": "Blue"},
+			expected:    `"XL" is not a valid value for attribute "Size"`,
+		},
+		{
+			description: "succeeds when all options have valid values",
+			attrs:       map[string]string{"Size": "M", "Colour": "Red"},
+			expected:    "",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			err := product.CheckAttributes(tc.attrs)
+			if tc.expected == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.EqualError(t, err, tc.expected)
+		})
+	}
+}
+
+func TestCheckOptions(t *testing.T) {
+	cases := []struct {
+		description string
+		options     []ProductOption
+		expected    string
+	}{
+		{
+			description: "fails when an option has no values",
+			options:     []ProductOption{{Name: "Size"}},
+			expected:    "options must have a name and at least one value",
+		},
+		{
+			description: "fails when an option is repeated",
+			options:     []ProductOption{{Name: "Size", Values: []string{"S"}}, {Name: "Size", Values: []string{"M"}}},
+			expected:    `option "Size" is repeated`,
+		},
+		{
+			description: "fails when a value is repeated",
+			options:     []ProductOption{{Name: "Size", Values: []string{"S", "S"}}},
+			expected:    `option "Size" has an empty or repeated value`,
+		},
+		{
+			description: "succeeds with valid options",
+			options:     []ProductOption{{Name: "Size", Values: []string{"S", "M"}}},
+			expected:    "",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			err := CheckOptions(tc.options)
+			if tc.expected == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.EqualError(t, err, tc.expected)
+		})
+	}
+}
+
+func TestProductVariantSKU(t *testing.T) {
+	product := &Product{
+		SKU: "TSHIRT",
+		Options: []ProductOption{
+			{Name: "Size", Values: []string{"S", "M"}},
+			{Name: "Colour", Values: []string{"Navy blue", "Red"}},
+		},
+	}
+
+	assert.Equal(t, "TSHIRT-M-NAVYBLUE", product.VariantSKU(map[string]string{"Colour": "Navy blue", "Size": "M"}))
+}
+
+func TestProductChangesBSON(t *testing.T) {
+	data, err := bson.Marshal(ProductChanges{Price: &money.Money{Currency: "BRL"}})
+	assert.NoError(t, err)
+
+	raw := bson.Raw(data)
+	assert.Equal(t, int64(0), raw.Lookup("price", "amount").Int64())
+	assert.Equal(t, "BRL", raw.Lookup("price", "currency").StringValue())
+
+	_, err = raw.LookupErr("cost")
+	assert.Error(t, err)
+}
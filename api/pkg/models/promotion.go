@@ -0,0 +1,391 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/heiytor/invenda/api/pkg/money"
+)
+
+// PromotionType represents how a promotion discounts the lines it applies to.
+type PromotionType string
+
+const (
+	// PromotionPercentage takes a rate off each eligible line.
+	PromotionPercentage PromotionType = "percentage"
+	// PromotionFixed takes an amount off the eligible lines, apportioned by their amounts.
+	PromotionFixed PromotionType = "fixed"
+	// PromotionBuyXGetY gives away some units of each eligible line for every units bought.
+	PromotionBuyXGetY PromotionType = "buy_x_get_y"
+	// PromotionBundle sells groups of units of the eligible lines, mixed as they come, at a fixed price.
+	PromotionBundle PromotionType = "bundle"
+)
+
+// Promotion represents a discount that sales orders and counter sales apply to their lines. Its amounts are
+// of the namespace's base currency.
+type Promotion struct {
+	ID          string        `json:"id" bson:"_id"`
+	NamespaceID string        `json:"namespace_id" bson:"namespace_id"`
+	CreatedAt   time.Time     `json:"created_at" bson:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at" bson:"updated_at"`
+	Name        string        `json:"name" bson:"name"`
+	Active      bool          `json:"active" bson:"active"`
+	Type        PromotionType `json:"type" bson:"type"`
+
+	// Value is the rate of percentage promotions, in basis points (e.g. 1000 for 10%). Amount is the amount
+	// of fixed promotions and the price of a bundle of bundle promotions.
+	Value  int64       `json:"value" bson:"value"`
+	Amount money.Money `json:"amount" bson:"amount"`
+
+	// Buy and Get are the quantities of buy_x_get_y promotions: Get units are free for every Buy units of a
+	// line. BundleSize is the number of units in a bundle of bundle promotions.
+	Buy        int64 `json:"buy,omitempty" bson:"buy,omitempty"`
+	Get        int64 `json:"get,omitempty" bson:"get,omitempty"`
+	BundleSize int64 `json:"bundle_size,omitempty" bson:"bundle_size,omitempty"`
+
+	Rules PromotionRules `json:"rules" bson:"rules"`
+
+	// Priority orders the evaluation of promotions, the highest first. Stackable reports whether the
+	// promotion combines with others: a line discounted by a promotion that is not stackable takes no other
+	// promotion, and such a promotion skips the lines that other promotions already discounted.
+	Priority  int  `json:"priority" bson:"priority"`
+	Stackable bool `json:"stackable" bson:"stackable"`
+
+	// Coupon is the code a cart must present for the promotion to apply. Promotions without a coupon apply
+	// automatically.
+	Coupon string `json:"coupon,omitempty" bson:"coupon,omitempty"`
+
+	// UsageLimit is how many documents can use the promotion, with zero meaning no limit, and Used how many
+	// did.
+	UsageLimit int64 `json:"usage_limit" bson:"usage_limit"`
+	Used       int64 `json:"used" bson:"used"`
+
+	// ValidFrom and ValidUntil bound when the promotion applies, as in price lists.
+	ValidFrom  *time.Time `json:"valid_from,omitempty" bson:"valid_from,omitempty"`
+	ValidUntil *time.Time `json:"valid_until,omitempty" bson:"valid_until,omitempty"`
+}
+
+// PromotionRules decide the carts and lines a promotion applies to. A promotion without products or
+// categories applies to every line, otherwise to the lines of its products and of its categories'
+// products. A promotion without customer groups applies to every customer.
+type PromotionRules struct {
+	ProductIDs     []string `json:"product_ids" bson:"product_ids"`
+	CategoryIDs    []string `json:"category_ids" bson:"category_ids"`
+	CustomerGroups []string `json:"customer_groups" bson:"customer_groups"`
+
+	// MinTotal is the amount the cart's lines must add up to, before promotions, for the promotion to apply.
+	MinTotal money.Money `json:"min_total" bson:"min_total"`
+}
+
+// AppliedPromotion records the amount a promotion took off a document line.
+type AppliedPromotion struct {
+	PromotionID string      `json:"promotion_id" bson:"promotion_id"`
+	Name        string      `json:"name" bson:"name"`
+	Coupon      string      `json:"coupon,omitempty" bson:"coupon,omitempty"`
+	Amount      money.Money `json:"amount" bson:"amount"`
+}
+
+type AppliedPromotions []AppliedPromotion
+
+// Amount returns the minor units the promotions took off the line.
+func (ps AppliedPromotions) Amount() int64 {
+	amount := int64(0)
+	for _, p := range ps {
+		amount += p.Amount.Amount
+	}
+
+	return amount
+}
+
+// Cart represents the lines of a document being priced, on which promotions are evaluated. Its amounts
+// are of its currency.
+type Cart struct {
+	CustomerGroup string         `json:"customer_group"`
+	Coupons       []string       `json:"coupons"`
+	At            time.Time      `json:"at"`
+	Currency      money.Currency `json:"currency"`
+	Lines         []CartLine     `json:"lines"`
+
+	// Discount is the amount the promotions took off the cart's lines.
+	Discount money.Money `json:"discount"`
+}
+
+// CartLine represents a line of a cart. UnitPrice defaults to the price resolved for the cart's customer
+// group when it is not given, and Discount is the amount taken off the line before promotions.
+type CartLine struct {
+	ProductID  string            `json:"product_id"`
+	VariantID  string            `json:"variant_id"`
+	CategoryID string            `json:"category_id"`
+	Quantity   int64             `json:"quantity"`
+	UnitPrice  *money.Money      `json:"unit_price"`
+	Discount   money.Money       `json:"discount"`
+	Promotions AppliedPromotions `json:"promotions"`
+}
+
+// Amount returns the minor units left to pay for the line after its discount and promotions.
+func (l *CartLine) Amount() int64 {
+	return l.Quantity*l.UnitPrice.Amount - l.Discount.Amount - l.Promotions.Amount()
+}
+
+// CheckCartLines reports whether the lines have a product and a price, positive quantities, non-negative
+// prices and discounts that do not exceed their amounts.
+func CheckCartLines(lines []CartLine) error {
+	for _, l := range lines {
+		if l.ProductID == "" {
+			return errors.New("lines must have a product_id")
+		}
+
+		if l.Quantity < 1 {
+			return fmt.Errorf("quantity of %q must be positive", l.ProductID)
+		}
+
+		if l.UnitPrice == nil {
+			return fmt.Errorf("unit price of %q is missing", l.ProductID)
+		}
+
+		if l.UnitPrice.IsNegative() {
+			return fmt.Errorf("unit price of %q cannot be negative", l.ProductID)
+		}
+
+		if l.Discount.IsNegative() || l.Discount.Amount > l.Quantity*l.UnitPrice.Amount {
+			return fmt.Errorf("discount of %q must be between 0 and the line's amount", l.ProductID)
+		}
+	}
+
+	return nil
+}
+
+// CheckPromotion reports whether the promotion's value, amount and quantities suit its type and whether its
+// usage limit and minimum total are not negative.
+func CheckPromotion(p *Promotion) error {
+	switch p.Type {
+	case PromotionPercentage:
+		if p.Value < 1 || p.Value > 10000 {
+			return errors.New("value of percentage promotions must be between 1 and 10000")
+		}
+	case PromotionFixed:
+		if p.Amount.Amount < 1 {
+			return errors.New("amount of fixed promotions must be positive")
+		}
+	case PromotionBuyXGetY:
+		if p.Buy < 1 || p.Get < 1 {
+			return errors.New("buy and get of buy_x_get_y promotions must be positive")
+		}
+	case PromotionBundle:
+		if p.BundleSize < 2 || p.Amount.IsNegative() {
+			return errors.New("bundle promotions must have a bundle_size of at least 2 and a non-negative amount")
+		}
+	default:
+		return fmt.Errorf("type %q is not valid", p.Type)
+	}
+
+	if p.UsageLimit < 0 || p.Rules.MinTotal.IsNegative() {
+		return errors.New("usage_limit and min_total cannot be negative")
+	}
+
+	return nil
+}
+
+// Convert converts the promotion's amounts with the function, such as from the base currency to the
+// currency of the document it is evaluated on: the amount of fixed promotions, the price of a bundle of
+// bundle promotions and the minimum total.
+func (p *Promotion) Convert(convert func(money.Money) money.Money) {
+	p.Amount = convert(p.Amount)
+	p.Rules.MinTotal = convert(p.Rules.MinTotal)
+}
+
+// AppliesTo reports whether the promotion applies to the cart, whose lines add up to total minor units
+// before promotions.
+func (p *Promotion) AppliesTo(c *Cart, total int64) bool {
+	switch {
+	case !p.Active:
+		return false
+	case p.ValidFrom != nil && c.At.Before(*p.ValidFrom):
+		return false
+	case p.ValidUntil != nil && !c.At.Before(*p.ValidUntil):
+		return false
+	case p.Coupon != "" && !slices.Contains(c.Coupons, p.Coupon):
+		return false
+	case len(p.Rules.CustomerGroups) > 0 && !slices.Contains(p.Rules.CustomerGroups, c.CustomerGroup):
+		return false
+	case p.UsageLimit > 0 && p.Used >= p.UsageLimit:
+		return false
+	}
+
+	return total >= p.Rules.MinTotal.Amount
+}
+
+// Matches reports whether the promotion's rules select the line.
+func (p *Promotion) Matches(l *CartLine) bool {
+	if len(p.Rules.ProductIDs) == 0 && len(p.Rules.CategoryIDs) == 0 {
+		return true
+	}
+
+	return slices.Contains(p.Rules.ProductIDs, l.ProductID) || (l.CategoryID != "" && slices.Contains(p.Rules.CategoryIDs, l.CategoryID))
+}
+
+// CheckCoupons reports whether each of the cart's coupons belongs to one of the promotions and can still
+// be used.
+func (c *Cart) CheckCoupons(promotions []Promotion) error {
+	for _, code := range c.Coupons {
+		valid := slices.ContainsFunc(promotions, func(p Promotion) bool {
+			return p.Coupon == code && (p.UsageLimit == 0 || p.Used < p.UsageLimit)
+		})
+
+		if !valid {
+			return fmt.Errorf("coupon %q is not valid", code)
+		}
+	}
+
+	return nil
+}
+
+// Apply evaluates the promotions on the cart, replacing the promotions of its lines with the ones that
+// apply. Promotions are evaluated by priority, each on what is left of the lines after the previous
+// ones, and never take more than that.
+func (c *Cart) Apply(promotions []Promotion) {
+	total := int64(0)
+	for i := range c.Lines {
+		c.Lines[i].Promotions = nil
+		total += c.Lines[i].Amount()
+	}
+
+	ordered := slices.Clone(promotions)
+	slices.SortStableFunc(ordered, func(a, b Promotion) int { return b.Priority - a.Priority })
+
+	// exclusive marks the lines discounted by a promotion that is not stackable.
+	exclusive := make([]bool, len(c.Lines))
+
+	for i := range ordered {
+		p := &ordered[i]
+		if !p.AppliesTo(c, total) {
+			continue
+		}
+
+		eligible := make([]int, 0, len(c.Lines))
+		for j := range c.Lines {
+			l := &c.Lines[j]
+			if !p.Matches(l) || l.Amount() <= 0 || exclusive[j] || (!p.Stackable && len(l.Promotions) > 0) {
+				continue
+			}
+
+			eligible = append(eligible, j)
+		}
+
+		for k, amount := range p.discounts(c.Lines, eligible) {
+			j := eligible[k]
+			l := &c.Lines[j]
+
+			amount = min(amount, l.Amount())
+			if amount <= 0 {
+				continue
+			}
+
+			l.Promotions = append(l.Promotions, AppliedPromotion{PromotionID: p.ID, Name: p.Name, Coupon: p.Coupon, Amount: money.New(amount, c.Currency)})
+			exclusive[j] = exclusive[j] || !p.Stackable
+		}
+	}
+
+	discount := int64(0)
+	for _, l := range c.Lines {
+		discount += l.Promotions.Amount()
+	}
+
+	c.Discount = money.New(discount, c.Currency)
+}
+
+// discounts returns the minor units the promotion takes off each of the eligible lines.
+func (p *Promotion) discounts(lines []CartLine, eligible []int) []int64 {
+	amounts := make([]int64, len(eligible))
+
+	switch p.Type {
+	case PromotionPercentage:
+		for k, j := range eligible {
+			amounts[k] = (lines[j].Amount()*p.Value + 5000) / 10000
+		}
+	case PromotionFixed:
+		weights := make([]int64, len(eligible))
+		sum := int64(0)
+		for k, j := range eligible {
+			weights[k] = lines[j].Amount()
+			sum += weights[k]
+		}
+
+		amounts = apportion(min(p.Amount.Amount, sum), weights)
+	case PromotionBuyXGetY:
+		for k, j := range eligible {
+			free := lines[j].Quantity / (p.Buy + p.Get) * p.Get
+			amounts[k] = free * lines[j].UnitPrice.Amount
+		}
+	case PromotionBundle:
+		units := int64(0)
+		for _, j := range eligible {
+			units += lines[j].Quantity
+		}
+
+		// Units are bundled in the order of the lines and the bundles' regular price is apportioned
+		// between the lines they came from.
+		bundles := units / p.BundleSize
+		left := bundles * p.BundleSize
+		weights := make([]int64, len(eligible))
+		regular := int64(0)
+		for k, j := range eligible {
+			taken := min(left, lines[j].Quantity)
+			left -= taken
+
+			weights[k] = taken * lines[j].UnitPrice.Amount
+			regular += weights[k]
+		}
+
+		if discount := regular - bundles*p.Amount.Amount; discount > 0 {
+			amounts = apportion(discount, weights)
+		}
+	}
+
+	return amounts
+}
+
+// apportion splits an amount proportionally to the weights without losing any minor unit: each share is
+// the difference between the cumulative shares, so the shares always add up to the amount.
+func apportion(amount int64, weights []int64) []int64 {
+	shares := make([]int64, len(weights))
+
+	sum := int64(0)
+	for _, w := range weights {
+		sum += w
+	}
+
+	if sum == 0 {
+		return shares
+	}
+
+	cumulative, allocated := int64(0), int64(0)
+	for i, w := range weights {
+		cumulative += w
+		share := amount*cumulative/sum - allocated
+		allocated += share
+		shares[i] = share
+	}
+
+	return shares
+}
+
+type PromotionChanges struct {
+	UpdatedAt  time.Time       `bson:"updated_at"`
+	Name       string          `bson:"name,omitempty"`
+	Active     *bool           `bson:"active,omitempty"`
+	Value      *int64          `bson:"value,omitempty"`
+	Amount     *money.Money    `bson:"amount,omitempty"`
+	Buy        *int64          `bson:"buy,omitempty"`
+	Get        *int64          `bson:"get,omitempty"`
+	BundleSize *int64          `bson:"bundle_size,omitempty"`
+	Rules      *PromotionRules `bson:"rules,omitempty"`
+	Priority   *int            `bson:"priority,omitempty"`
+	Stackable  *bool           `bson:"stackable,omitempty"`
+	Coupon     *string         `bson:"coupon,omitempty"`
+	UsageLimit *int64          `bson:"usage_limit,omitempty"`
+	ValidFrom  *time.Time      `bson:"valid_from,omitempty"`
+	ValidUntil *time.Time      `bson:"valid_until,omitempty"`
+}
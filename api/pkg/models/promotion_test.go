@@ -0,0 +1,196 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/heiytor/invenda/api/pkg/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCartApply(t *testing.T) {
+	now := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	expired := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	cart := func() *Cart {
+		return &Cart{
+			CustomerGroup: "wholesale",
+			At:            now,
+			Currency:      "BRL",
+			Lines: []CartLine{
+				{ProductID: "prd_1", CategoryID: "cat_1", Quantity: 3, UnitPrice: unitPrice(1000, "BRL")},
+				{ProductID: "prd_2", CategoryID: "cat_2", Quantity: 1, UnitPrice: unitPrice(500, "BRL"), Discount: money.New(100, "BRL")},
+			},
+		}
+	}
+
+	amounts := func(c *Cart) []int64 {
+		out := make([]int64, 0, len(c.Lines))
+		for _, l := range c.Lines {
+			out = append(out, l.Promotions.Amount())
+		}
+
+		return out
+	}
+
+	t.Run("percentage discounts each eligible line after its discount", func(t *testing.T) {
+		c := cart()
+		c.Apply([]Promotion{{ID: "prm_1", Active: true, Type: PromotionPercentage, Value: 1000}})
+
+		assert.Equal(t, []int64{300, 40}, amounts(c))
+		assert.Equal(t, money.New(340, "BRL"), c.Discount)
+		assert.Equal(t, AppliedPromotions{{PromotionID: "prm_1", Amount: money.New(300, "BRL")}}, c.Lines[0].Promotions)
+	})
+
+	t.Run("fixed amounts are apportioned without losing minor units", func(t *testing.T) {
+		c := cart()
+		c.Apply([]Promotion{{ID: "prm_1", Active: true, Type: PromotionFixed, Amount: money.New(1000, "BRL")}})
+
+		assert.Equal(t, []int64{882, 118}, amounts(c))
+		assert.Equal(t, money.New(1000, "BRL"), c.Discount)
+	})
+
+	t.Run("fixed amounts never exceed the lines' amounts", func(t *testing.T) {
+		c := cart()
+		c.Apply([]Promotion{{ID: "prm_1", Active: true, Type: PromotionFixed, Amount: money.New(10000, "BRL")}})
+
+		assert.Equal(t, []int64{3000, 400}, amounts(c))
+	})
+
+	t.Run("buy x get y gives away units of a line", func(t *testing.T) {
+		c := cart()
+		c.Lines[0].Quantity = 7
+		c.Apply([]Promotion{{ID: "prm_1", Active: true, Type: PromotionBuyXGetY, Buy: 2, Get: 1, Rules: PromotionRules{ProductIDs: []string{"prd_1"}}}})
+
+		assert.Equal(t, []int64{2000, 0}, amounts(c))
+		assert.Nil(t, c.Lines[1].Promotions)
+	})
+
+	t.Run("bundles mix the units of the eligible lines", func(t *testing.T) {
+		c := cart()
+		c.Lines[1].Discount = money.Money{}
+		c.Apply([]Promotion{{ID: "prm_1", Active: true, Type: PromotionBundle, BundleSize: 2, Amount: money.New(1200, "BRL")}})
+
+		// Two bundles of 1000+1000 and 1000+500 cost 2400 instead of 3500.
+		assert.Equal(t, []int64{942, 158}, amounts(c))
+		assert.Equal(t, money.New(1100, "BRL"), c.Discount)
+	})
+
+	t.Run("categories select lines", func(t *testing.T) {
+		c := cart()
+		c.Apply([]Promotion{{ID: "prm_1", Active: true, Type: PromotionPercentage, Value: 5000, Rules: PromotionRules{CategoryIDs: []string{"cat_2"}}}})
+
+		assert.Equal(t, []int64{0, 200}, amounts(c))
+	})
+
+	t.Run("ineligible carts take no promotion", func(t *testing.T) {
+		c := cart()
+		c.Apply([]Promotion{
+			{ID: "prm_1", Active: false, Type: PromotionPercentage, Value: 1000},
+			{ID: "prm_2", Active: true, Type: PromotionPercentage, Value: 1000, ValidUntil: &expired},
+			{ID: "prm_3", Active: true, Type: PromotionPercentage, Value: 1000, Coupon: "SAVE10"},
+			{ID: "prm_4", Active: true, Type: PromotionPercentage, Value: 1000, Rules: PromotionRules{CustomerGroups: []string{"vip"}}},
+			{ID: "prm_5", Active: true, Type: PromotionPercentage, Value: 1000, Rules: PromotionRules{MinTotal: money.New(5000, "BRL")}},
+			{ID: "prm_6", Active: true, Type: PromotionPercentage, Value: 1000, UsageLimit: 1, Used: 1},
+		})
+
+		assert.Equal(t, []int64{0, 0}, amounts(c))
+		assert.Equal(t, money.New(0, "BRL"), c.Discount)
+	})
+
+	t.Run("coupons unlock their promotions", func(t *testing.T) {
+		c := cart()
+		c.Coupons = []string{"SAVE10"}
+		c.Apply([]Promotion{{ID: "prm_1", Active: true, Type: PromotionPercentage, Value: 1000, Coupon: "SAVE10"}})
+
+		assert.Equal(t, []int64{300, 40}, amounts(c))
+		assert.Equal(t, "SAVE10", c.Lines[0].Promotions[0].Coupon)
+	})
+
+	t.Run("stackable promotions apply in priority order on what is left", func(t *testing.T) {
+		c := cart()
+		c.Apply([]Promotion{
+			{ID: "prm_1", Active: true, Type: PromotionFixed, Amount: money.New(300, "BRL"), Priority: 1, Stackable: true, Rules: PromotionRules{ProductIDs: []string{"prd_1"}}},
+			{ID: "prm_2", Active: true, Type: PromotionPercentage, Value: 1000, Priority: 2, Stackable: true},
+		})
+
+		assert.Equal(t, []int64{600, 40}, amounts(c))
+		assert.Equal(t, "prm_2", c.Lines[0].Promotions[0].PromotionID)
+		assert.Equal(t, "prm_1", c.Lines[0].Promotions[1].PromotionID)
+	})
+
+	t.Run("promotions that are not stackable exclude the others", func(t *testing.T) {
+		c := cart()
+		c.Apply([]Promotion{
+			{ID: "prm_1", Active: true, Type: PromotionPercentage, Value: 2000, Priority: 2, Rules: PromotionRules{ProductIDs: []string{"prd_1"}}},
+			{ID: "prm_2", Active: true, Type: PromotionPercentage, Value: 1000, Priority: 1, Stackable: true},
+			{ID: "prm_3", Active: true, Type: PromotionPercentage, Value: 1000, Priority: 0},
+		})
+
+		assert.Equal(t, []int64{600, 40}, amounts(c))
+		assert.Len(t, c.Lines[0].Promotions, 1)
+		assert.Equal(t, "prm_2", c.Lines[1].Promotions[0].PromotionID)
+	})
+
+	t.Run("evaluating again replaces the promotions", func(t *testing.T) {
+		c := cart()
+		c.Apply([]Promotion{{ID: "prm_1", Active: true, Type: PromotionPercentage, Value: 1000}})
+		c.Apply([]Promotion{{ID: "prm_1", Active: true, Type: PromotionPercentage, Value: 1000}})
+
+		assert.Equal(t, []int64{300, 40}, amounts(c))
+	})
+}
+
+func TestCartCheckCoupons(t *testing.T) {
+	promotions := []Promotion{
+		{Coupon: "SAVE10"},
+		{Coupon: "ONCE", UsageLimit: 1, Used: 1},
+	}
+
+	assert.NoError(t, (&Cart{}).CheckCoupons(promotions))
+	assert.NoError(t, (&Cart{Coupons: []string{"SAVE10"}}).CheckCoupons(promotions))
+	assert.EqualError(t, (&Cart{Coupons: []string{"ONCE"}}).CheckCoupons(promotions), `coupon "ONCE" is not valid`)
+	assert.EqualError(t, (&Cart{Coupons: []string{"UNKNOWN"}}).CheckCoupons(promotions), `coupon "UNKNOWN" is not valid`)
+}
+
+func TestCheckPromotion(t *testing.T) {
+	assert.NoError(t, CheckPromotion(&Promotion{Type: PromotionPercentage, Value: 10000}))
+	assert.NoError(t, CheckPromotion(&Promotion{Type: PromotionFixed, Amount: money.New(500, "BRL")}))
+	assert.NoError(t, CheckPromotion(&Promotion{Type: PromotionBuyXGetY, Buy: 2, Get: 1}))
+	assert.NoError(t, CheckPromotion(&Promotion{Type: PromotionBundle, BundleSize: 3, Amount: money.New(1000, "BRL")}))
+	assert.EqualError(t, CheckPromotion(&Promotion{Type: PromotionPercentage, Value: 10001}), "value of percentage promotions must be between 1 and 10000")
+	assert.EqualError(t, CheckPromotion(&Promotion{Type: PromotionFixed}), "amount of fixed promotions must be positive")
+	assert.EqualError(t, CheckPromotion(&Promotion{Type: PromotionBuyXGetY, Buy: 2}), "buy and get of buy_x_get_y promotions must be positive")
+	assert.EqualError(t, CheckPromotion(&Promotion{Type: PromotionBundle, BundleSize: 1}), "bundle promotions must have a bundle_size of at least 2 and a non-negative amount")
+	assert.EqualError(t, CheckPromotion(&Promotion{Type: "other"}), `type "other" is not valid`)
+	assert.EqualError(t, CheckPromotion(&Promotion{Type: PromotionFixed, Amount: money.New(1, "BRL"), UsageLimit: -1}), "usage_limit and min_total cannot be negative")
+}
+
+func TestPromotionConvert(t *testing.T) {
+	usd := NewConversion("USD", "BRL", "5")
+
+	fixed := Promotion{Type: PromotionFixed, Amount: money.New(1000, "BRL"), Rules: PromotionRules{MinTotal: money.New(5000, "BRL")}}
+	fixed.Convert(usd.FromBase)
+	assert.Equal(t, money.New(200, "USD"), fixed.Amount)
+	assert.Equal(t, money.New(1000, "USD"), fixed.Rules.MinTotal)
+
+	// Percentage rates are not amounts.
+	percentage := Promotion{Type: PromotionPercentage, Value: 1000}
+	percentage.Convert(usd.FromBase)
+	assert.Equal(t, int64(1000), percentage.Value)
+}
+
+func TestCheckCartLines(t *testing.T) {
+	assert.NoError(t, CheckCartLines([]CartLine{{ProductID: "prd_1", Quantity: 1, UnitPrice: unitPrice(100, "BRL"), Discount: money.New(100, "BRL")}}))
+	assert.EqualError(t, CheckCartLines([]CartLine{{Quantity: 1}}), "lines must have a product_id")
+	assert.EqualError(t, CheckCartLines([]CartLine{{ProductID: "prd_1"}}), `quantity of "prd_1" must be positive`)
+	assert.EqualError(t, CheckCartLines([]CartLine{{ProductID: "prd_1", Quantity: 1}}), `unit price of "prd_1" is missing`)
+	assert.EqualError(t, CheckCartLines([]CartLine{{ProductID: "prd_1", Quantity: 1, UnitPrice: unitPrice(-1, "BRL")}}), `unit price of "prd_1" cannot be negative`)
+	assert.EqualError(t, CheckCartLines([]CartLine{{ProductID: "prd_1", Quantity: 1, UnitPrice: unitPrice(100, "BRL"), Discount: money.New(101, "BRL")}}), `discount of "prd_1" must be between 0 and the line's amount`)
+}
+
+func TestApportion(t *testing.T) {
+	assert.Equal(t, []int64{333, 333, 334}, apportion(1000, []int64{1, 1, 1}))
+	assert.Equal(t, []int64{0, 0}, apportion(1000, []int64{0, 0}))
+	assert.Equal(t, []int64{0, 1}, apportion(1, []int64{1, 1}))
+}
@@ -0,0 +1,184 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/heiytor/invenda/api/pkg/money"
+)
+
+// PurchaseOrderStatus represents the stage of a purchase order's lifecycle.
+type PurchaseOrderStatus string
+
+const (
+	PurchaseOrderDraft             PurchaseOrderStatus = "draft"
+	PurchaseOrderSent              PurchaseOrderStatus = "sent"
+	PurchaseOrderPartiallyReceived PurchaseOrderStatus = "partially_received"
+	PurchaseOrderReceived          PurchaseOrderStatus = "received"
+	PurchaseOrderClosed            PurchaseOrderStatus = "closed"
+	PurchaseOrderCancelled         PurchaseOrderStatus = "cancelled"
+)
+
+// PurchaseOrder represents goods ordered from a supplier to be received at a warehouse. Once sent, the
+// quantities that are still to be received are expected to come in and are reported by the products as
+// incoming until received, or until the order is closed or cancelled.
+type PurchaseOrder struct {
+	ID          string              `json:"id" bson:"_id"`
+	NamespaceID string              `json:"namespace_id" bson:"namespace_id"`
+	CreatedAt   time.Time           `json:"created_at" bson:"created_at"`
+	UpdatedAt   time.Time           `json:"updated_at" bson:"updated_at"`
+	Status      PurchaseOrderStatus `json:"status" bson:"status"`
+
+	SupplierID  string `json:"supplier_id" bson:"supplier_id"`
+	WarehouseID string `json:"warehouse_id" bson:"warehouse_id"`
+	Location    string `json:"location" bson:"location"`
+
+	Lines []PurchaseOrderLine `json:"lines" bson:"lines"`
+	Notes string              `json:"notes" bson:"notes"`
+
+	// ExpectedAt is when the goods are expected to arrive. When not set, it defaults to the supplier's
+	// lead time once the order is sent.
+	ExpectedAt *time.Time `json:"expected_at,omitempty" bson:"expected_at,omitempty"`
+
+	// Currency is the currency of the lines' costs, which defaults to the supplier's currency and then to
+	// the namespace's base currency. ExchangeRate is what one unit of it was worth in the base currency when
+	// the order was priced, at which received goods are valued.
+	Currency     money.Currency `json:"currency,omitempty" bson:"currency,omitempty"`
+	ExchangeRate money.Rate     `json:"exchange_rate,omitempty" bson:"exchange_rate,omitempty"`
+
+	// CreatedBy is the ID of the user that created the purchase order.
+	CreatedBy   string     `json:"created_by" bson:"created_by"`
+	SentAt      *time.Time `json:"sent_at,omitempty" bson:"sent_at,omitempty"`
+	ReceivedAt  *time.Time `json:"received_at,omitempty" bson:"received_at,omitempty"`
+	ClosedAt    *time.Time `json:"closed_at,omitempty" bson:"closed_at,omitempty"`
+	CancelledAt *time.Time `json:"cancelled_at,omitempty" bson:"cancelled_at,omitempty"`
+}
+
+type PurchaseOrderLine struct {
+	ProductID string `json:"product_id" bson:"product_id" validate:"required|ulid"`
+	VariantID string `json:"variant_id" bson:"variant_id" validate:"ulid"`
+	Quantity  int64  `json:"quantity" bson:"quantity" validate:"required|min:1"`
+	Received  int64  `json:"received" bson:"received"`
+
+	// SupplierSKU and UnitCost default to the supplier's mapping of the product, falling back to the
+	// product's cost, when they are not given; a given cost, even zero, is kept. UnitCost is the cost at
+	// which the received goods enter the stock.
+	SupplierSKU string       `json:"supplier_sku" bson:"supplier_sku"`
+	UnitCost    *money.Money `json:"unit_cost" bson:"unit_cost" validate:"money_min:0"`
+}
+
+// Remaining returns the quantity of the line that is still to be received.
+func (l *PurchaseOrderLine) Remaining() int64 {
+	return l.Quantity - l.Received
+}
+
+// PurchaseOrderReceipt represents a quantity of a purchase order's line received at once. Lot and
+// ExpiresAt name the received lot of lot-tracked products and Serials the received units of serialized
+// ones.
+type PurchaseOrderReceipt struct {
+	ProductID string     `json:"product_id" validate:"required|ulid"`
+	VariantID string     `json:"variant_id" validate:"ulid"`
+	Quantity  int64      `json:"quantity" validate:"required|min:1"`
+	Lot       string     `json:"lot"`
+	ExpiresAt *time.Time `json:"expires_at"`
+	Serials   []string   `json:"serials"`
+}
+
+// Key returns the stock key at which a line of the purchase order is received.
+func (po *PurchaseOrder) Key(productID, variantID string) StockKey {
+	return StockKey{WarehouseID: po.WarehouseID, Location: po.Location, ProductID: productID, VariantID: variantID}
+}
+
+// CheckPurchaseOrderLines reports whether the lines have positive quantities, non-negative costs and
+// whether each product or variant appears only once.
+func CheckPurchaseOrderLines(lines []PurchaseOrderLine) error {
+	seen := make(map[string]bool, len(lines))
+	for _, l := range lines {
+		if l.Quantity < 1 {
+			return fmt.Errorf("quantity of %q must be positive", l.ProductID)
+		}
+
+		if l.UnitCost != nil && l.UnitCost.IsNegative() {
+			return fmt.Errorf("unit cost of %q cannot be negative", l.ProductID)
+		}
+
+		k := l.ProductID + "/" + l.VariantID
+		if seen[k] {
+			return fmt.Errorf("line %q is duplicated", k)
+		}
+
+		seen[k] = true
+	}
+
+	return nil
+}
+
+// Receive adds the received quantities to the purchase order's lines and updates its status. When
+// receipts is empty, every remaining quantity is received. It returns the receipts or an error if a
+// receipt does not belong to the order or exceeds the remaining quantity of its line.
+func (po *PurchaseOrder) Receive(receipts []PurchaseOrderReceipt) ([]PurchaseOrderReceipt, error) {
+	if len(receipts) == 0 {
+		for _, l := range po.Lines {
+			if l.Remaining() > 0 {
+				receipts = append(receipts, PurchaseOrderReceipt{ProductID: l.ProductID, VariantID: l.VariantID, Quantity: l.Remaining()})
+			}
+		}
+	}
+
+	for _, r := range receipts {
+		idx := po.line(r.ProductID, r.VariantID)
+		if idx < 0 {
+			return nil, fmt.Errorf("line %q does not belong to the purchase order", r.ProductID)
+		}
+
+		if r.Quantity < 1 || r.Quantity > po.Lines[idx].Remaining() {
+			return nil, fmt.Errorf("quantity of %q must be between 1 and %d", r.ProductID, po.Lines[idx].Remaining())
+		}
+
+		po.Lines[idx].Received += r.Quantity
+	}
+
+	po.Status = PurchaseOrderReceived
+	for _, l := range po.Lines {
+		if l.Remaining() > 0 {
+			po.Status = PurchaseOrderPartiallyReceived
+			break
+		}
+	}
+
+	return receipts, nil
+}
+
+// Line returns the line of a product or variant. It returns nil when the purchase order has no such line.
+func (po *PurchaseOrder) Line(productID, variantID string) *PurchaseOrderLine {
+	if idx := po.line(productID, variantID); idx >= 0 {
+		return &po.Lines[idx]
+	}
+
+	return nil
+}
+
+func (po *PurchaseOrder) line(productID, variantID string) int {
+	for i, l := range po.Lines {
+		if l.ProductID == productID && l.VariantID == variantID {
+			return i
+		}
+	}
+
+	return -1
+}
+
+type PurchaseOrderChanges struct {
+	UpdatedAt    time.Time           `bson:"updated_at"`
+	Status       PurchaseOrderStatus `bson:"status,omitempty"`
+	Location     *string             `bson:"location,omitempty"`
+	Lines        []PurchaseOrderLine `bson:"lines,omitempty"`
+	Notes        *string             `bson:"notes,omitempty"`
+	ExpectedAt   *time.Time          `bson:"expected_at,omitempty"`
+	Currency     money.Currency      `bson:"currency,omitempty"`
+	ExchangeRate money.Rate          `bson:"exchange_rate,omitempty"`
+	SentAt       *time.Time          `bson:"sent_at,omitempty"`
+	ReceivedAt   *time.Time          `bson:"received_at,omitempty"`
+	ClosedAt     *time.Time          `bson:"closed_at,omitempty"`
+	CancelledAt  *time.Time          `bson:"cancelled_at,omitempty"`
+}
@@ -0,0 +1,88 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/heiytor/invenda/api/pkg/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPurchaseOrderReceive(t *testing.T) {
+	type Expected struct {
+		receipts []PurchaseOrderReceipt
+		status   PurchaseOrderStatus
+		err      string
+	}
+
+	lines := func() []PurchaseOrderLine {
+		return []PurchaseOrderLine{
+			{ProductID: "prd_1", Quantity: 10, Received: 0, UnitCost: unitCost(250, "BRL")},
+			{ProductID: "prd_2", VariantID: "var_1", Quantity: 5, Received: 2, UnitCost: unitCost(100, "BRL")},
+		}
+	}
+
+	cases := []struct {
+		description string
+		receipts    []PurchaseOrderReceipt
+		expected    Expected
+	}{
+		{
+			description: "receives every remaining quantity when no line is provided",
+			receipts:    []PurchaseOrderReceipt{},
+			expected: Expected{
+				receipts: []PurchaseOrderReceipt{
+					{ProductID: "prd_1", Quantity: 10},
+					{ProductID: "prd_2", VariantID: "var_1", Quantity: 3},
+				},
+				status: PurchaseOrderReceived,
+			},
+		},
+		{
+			description: "partially receives the purchase order",
+			receipts:    []PurchaseOrderReceipt{{ProductID: "prd_1", Quantity: 4, Lot: "L1"}},
+			expected: Expected{
+				receipts: []PurchaseOrderReceipt{{ProductID: "prd_1", Quantity: 4, Lot: "L1"}},
+				status:   PurchaseOrderPartiallyReceived,
+			},
+		},
+		{
+			description: "fails when the line does not belong to the purchase order",
+			receipts:    []PurchaseOrderReceipt{{ProductID: "prd_2", Quantity: 1}},
+			expected:    Expected{err: `line "prd_2" does not belong to the purchase order`},
+		},
+		{
+			description: "fails when the quantity exceeds the remaining one",
+			receipts:    []PurchaseOrderReceipt{{ProductID: "prd_2", VariantID: "var_1", Quantity: 4}},
+			expected:    Expected{err: `quantity of "prd_2" must be between 1 and 3`},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			po := &PurchaseOrder{Status: PurchaseOrderSent, Lines: lines()}
+
+			receipts, err := po.Receive(tc.receipts)
+			if tc.expected.err != "" {
+				assert.EqualError(t, err, tc.expected.err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected.receipts, receipts)
+			assert.Equal(t, tc.expected.status, po.Status)
+		})
+	}
+}
+
+func TestCheckPurchaseOrderLines(t *testing.T) {
+	assert.NoError(t, CheckPurchaseOrderLines([]PurchaseOrderLine{{ProductID: "prd_1", Quantity: 1}, {ProductID: "prd_1", VariantID: "var_1", Quantity: 1}}))
+	assert.EqualError(t, CheckPurchaseOrderLines([]PurchaseOrderLine{{ProductID: "prd_1", Quantity: 0}}), `quantity of "prd_1" must be positive`)
+	assert.EqualError(t, CheckPurchaseOrderLines([]PurchaseOrderLine{{ProductID: "prd_1", Quantity: 1, UnitCost: unitCost(-1, "BRL")}}), `unit cost of "prd_1" cannot be negative`)
+	assert.EqualError(t, CheckPurchaseOrderLines([]PurchaseOrderLine{{ProductID: "prd_1", Quantity: 1}, {ProductID: "prd_1", Quantity: 2}}), `line "prd_1/" is duplicated`)
+}
+
+// unitCost returns the unit cost of a line, of the amount and currency.
+func unitCost(amount int64, currency money.Currency) *money.Money {
+	cost := money.New(amount, currency)
+	return &cost
+}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// Register represents a till at which counter sales are made. The register's sales issue their stock from
+// the register's warehouse location.
+type Register struct {
+	ID          string    `json:"id" bson:"_id"`
+	NamespaceID string    `json:"namespace_id" bson:"namespace_id"`
+	CreatedAt   time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" bson:"updated_at"`
+	Code        string    `json:"code" bson:"code"`
+	Name        string    `json:"name" bson:"name"`
+	WarehouseID string    `json:"warehouse_id" bson:"warehouse_id"`
+	Location    string    `json:"location" bson:"location"`
+	Active      bool      `json:"active" bson:"active"`
+}
+
+type RegisterChanges struct {
+	UpdatedAt   time.Time `bson:"updated_at"`
+	Code        string    `bson:"code,omitempty"`
+	Name        string    `bson:"name,omitempty"`
+	WarehouseID string    `bson:"warehouse_id,omitempty"`
+	Location    *string   `bson:"location,omitempty"`
+	Active      *bool     `bson:"active,omitempty"`
+}
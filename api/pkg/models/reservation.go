@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// ReservationStatus represents the stage of a reservation's lifecycle.
+type ReservationStatus string
+
+const (
+	ReservationActive ReservationStatus = "active"
+	// ReservationReleased is the status of reservations given up before expiring, e.g. a cancelled order.
+	ReservationReleased ReservationStatus = "released"
+	// ReservationConsumed is the status of reservations whose stock was issued, e.g. a shipped order.
+	ReservationConsumed ReservationStatus = "consumed"
+	ReservationExpired  ReservationStatus = "expired"
+)
+
+// Reservation holds a quantity of a key's on-hand stock so it cannot be issued or reserved by anyone
+// else until the reservation is released, consumed or expires.
+type Reservation struct {
+	ID          string    `json:"id" bson:"_id"`
+	NamespaceID string    `json:"namespace_id" bson:"namespace_id"`
+	CreatedAt   time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" bson:"updated_at"`
+	StockKey    `bson:",inline"`
+	Quantity    int64             `json:"quantity" bson:"quantity"`
+	Status      ReservationStatus `json:"status" bson:"status"`
+	ExpiresAt   time.Time         `json:"expires_at" bson:"expires_at"`
+
+	// Reference is the ID of the document the stock is reserved for, if any.
+	Reference string `json:"reference,omitempty" bson:"reference,omitempty"`
+
+	// CreatedBy is the ID of the user that created the reservation.
+	CreatedBy string `json:"created_by" bson:"created_by"`
+}
@@ -0,0 +1,171 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/heiytor/invenda/api/pkg/money"
+)
+
+// ReturnReason represents why a customer returned goods.
+type ReturnReason string
+
+const (
+	ReturnDamaged   ReturnReason = "damaged"
+	ReturnDefective ReturnReason = "defective"
+	ReturnWrongItem ReturnReason = "wrong_item"
+	ReturnUnwanted  ReturnReason = "unwanted"
+	ReturnOther     ReturnReason = "other"
+)
+
+// RefundMethod represents how a return is refunded.
+type RefundMethod string
+
+const (
+	// RefundOriginalTender gives the money back with the tenders the sale was paid with.
+	RefundOriginalTender RefundMethod = "original_tender"
+
+	// RefundStoreCredit adds the money to the customer's store credit.
+	RefundStoreCredit RefundMethod = "store_credit"
+)
+
+// Return represents goods of a counter sale returned by a customer (RMA). The returned goods are put back
+// into the stock, or into the warehouse's quarantine when damaged, and the customer is refunded. A
+// return is processed within the open shift of a register and never changed afterwards. Its amounts are of
+// the returned sale's currency.
+type Return struct {
+	ID          string    `json:"id" bson:"_id"`
+	NamespaceID string    `json:"namespace_id" bson:"namespace_id"`
+	CreatedAt   time.Time `json:"created_at" bson:"created_at"`
+
+	SaleID      string `json:"sale_id" bson:"sale_id"`
+	RegisterID  string `json:"register_id" bson:"register_id"`
+	ShiftID     string `json:"shift_id" bson:"shift_id"`
+	WarehouseID string `json:"warehouse_id" bson:"warehouse_id"`
+	CustomerID  string `json:"customer_id,omitempty" bson:"customer_id,omitempty"`
+
+	Lines  []ReturnLine `json:"lines" bson:"lines"`
+	Notes  string       `json:"notes" bson:"notes"`
+	Refund Refund       `json:"refund" bson:"refund"`
+
+	// CreatedBy is the ID of the user that processed the return.
+	CreatedBy string `json:"created_by" bson:"created_by"`
+}
+
+type ReturnLine struct {
+	// Line is the index of the returned line within the sale's lines.
+	Line      int    `json:"line" bson:"line" validate:"min:0"`
+	ProductID string `json:"product_id" bson:"product_id"`
+	VariantID string `json:"variant_id" bson:"variant_id"`
+	Quantity  int64  `json:"quantity" bson:"quantity" validate:"required|min:1"`
+
+	Reason ReturnReason `json:"reason" bson:"reason" validate:"required|in:damaged,defective,wrong_item,unwanted,other"`
+
+	// Damaged goods are put into the warehouse's quarantine instead of the location they were sold from,
+	// which is Location once restocked.
+	Damaged  bool   `json:"damaged" bson:"damaged"`
+	Location string `json:"location" bson:"location"`
+
+	// Lot and Serials name the lot or units returned of lot-tracked or serialized products.
+	Lot     string   `json:"lot,omitempty" bson:"lot,omitempty"`
+	Serials []string `json:"serials,omitempty" bson:"serials,omitempty"`
+
+	// Amount is the part of the sale line's total refunded for the returned quantity.
+	Amount money.Money `json:"amount" bson:"amount"`
+}
+
+// Refund represents the money given back to a customer for a return. Tenders are the tenders refunded
+// when the method is [RefundOriginalTender].
+type Refund struct {
+	Method  RefundMethod `json:"method" bson:"method"`
+	Amount  money.Money  `json:"amount" bson:"amount"`
+	Tenders []Tender     `json:"tenders,omitempty" bson:"tenders,omitempty"`
+}
+
+// CheckReturnLines reports whether the lines have a positive quantity and a known reason.
+func CheckReturnLines(lines []ReturnLine) error {
+	for _, l := range lines {
+		if l.Quantity < 1 {
+			return fmt.Errorf("quantity of line %d must be positive", l.Line)
+		}
+
+		switch l.Reason {
+		case ReturnDamaged, ReturnDefective, ReturnWrongItem, ReturnUnwanted, ReturnOther:
+		default:
+			return fmt.Errorf("reason %q of line %d is not supported", l.Reason, l.Line)
+		}
+	}
+
+	return nil
+}
+
+// Return applies the returned lines to the sale, adding their quantities to the quantities returned of
+// the sale's lines. The returned lines take the product, variant and amount of the sale lines they refer
+// to. It returns the total amount to refund or an error if a line does not belong to the sale, is
+// repeated or exceeds the quantity that is still returnable.
+func (s *Sale) Return(lines []ReturnLine) (money.Money, error) {
+	seen := make(map[int]bool, len(lines))
+
+	amount := money.Zero(s.Currency)
+	for i, l := range lines {
+		if l.Line < 0 || l.Line >= len(s.Lines) {
+			return money.Money{}, fmt.Errorf("line %d does not belong to the sale", l.Line)
+		}
+
+		if seen[l.Line] {
+			return money.Money{}, fmt.Errorf("line %d is duplicated", l.Line)
+		}
+
+		seen[l.Line] = true
+
+		sl := &s.Lines[l.Line]
+		if remaining := sl.Quantity - sl.Returned; l.Quantity < 1 || l.Quantity > remaining {
+			return money.Money{}, fmt.Errorf("quantity of line %d must be between 1 and %d", l.Line, remaining)
+		}
+
+		lines[i].ProductID, lines[i].VariantID = sl.ProductID, sl.VariantID
+		lines[i].Amount = money.New(sl.Refundable(l.Quantity).Amount, s.Currency)
+		amount.Amount += lines[i].Amount.Amount
+
+		sl.Returned += l.Quantity
+	}
+
+	return amount, nil
+}
+
+// RefundTenders splits an amount to refund among the tenders the sale was paid with, net of the change
+// and of what was already refunded. Other tenders are refunded before cash, which is refunded last. It
+// returns the tenders or an error if the amount exceeds what can still be refunded.
+func (s *Sale) RefundTenders(amount money.Money) ([]Tender, error) {
+	takings := s.Takings()
+
+	methods := make([]TenderMethod, 0, len(takings))
+	for _, p := range s.Payments {
+		if p.Method != TenderCash && !slices.Contains(methods, p.Method) {
+			methods = append(methods, p.Method)
+		}
+	}
+
+	methods = append(methods, TenderCash)
+
+	left := amount.Amount
+
+	tenders := make([]Tender, 0)
+	for _, m := range methods {
+		available := takings[m].Amount - s.Refunded[m].Amount
+		if left == 0 || available <= 0 {
+			continue
+		}
+
+		t := Tender{Method: m, Amount: money.New(min(left, available), s.Currency)}
+		tenders = append(tenders, t)
+		left -= t.Amount.Amount
+	}
+
+	if left > 0 {
+		return nil, fmt.Errorf("refund exceeds the sale's payments by %s", money.New(left, s.Currency))
+	}
+
+	return tenders, nil
+}
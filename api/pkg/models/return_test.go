@@ -0,0 +1,70 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/heiytor/invenda/api/pkg/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSaleLineRefundable(t *testing.T) {
+	l := &SaleLine{Quantity: 3, Total: money.New(1000, "BRL")}
+
+	// Refunding the units one by one adds up to the line's total.
+	var refunded int64
+	for i := 0; i < 3; i++ {
+		refunded += l.Refundable(1).Amount
+		l.Returned++
+	}
+
+	assert.Equal(t, int64(1000), refunded)
+	assert.Equal(t, money.New(666, "BRL"), (&SaleLine{Quantity: 3, Total: money.New(1000, "BRL")}).Refundable(2))
+}
+
+func TestSaleReturn(t *testing.T) {
+	newSale := func() *Sale {
+		return &Sale{Currency: "BRL", Lines: []SaleLine{
+			{ProductID: "prd_1", Quantity: 2, Total: money.New(1100, "BRL")},
+			{ProductID: "prd_2", VariantID: "var_1", Quantity: 1, Total: money.New(300, "BRL"), Returned: 1},
+		}}
+	}
+
+	s := newSale()
+	lines := []ReturnLine{{Line: 0, Quantity: 1, Reason: ReturnUnwanted}}
+	amount, err := s.Return(lines)
+	assert.NoError(t, err)
+	assert.Equal(t, money.New(550, "BRL"), amount)
+	assert.Equal(t, ReturnLine{Line: 0, ProductID: "prd_1", Quantity: 1, Reason: ReturnUnwanted, Amount: money.New(550, "BRL")}, lines[0])
+	assert.Equal(t, int64(1), s.Lines[0].Returned)
+
+	_, err = newSale().Return([]ReturnLine{{Line: 2, Quantity: 1}})
+	assert.EqualError(t, err, "line 2 does not belong to the sale")
+
+	_, err = newSale().Return([]ReturnLine{{Line: 0, Quantity: 1}, {Line: 0, Quantity: 1}})
+	assert.EqualError(t, err, "line 0 is duplicated")
+
+	_, err = newSale().Return([]ReturnLine{{Line: 1, Quantity: 1}})
+	assert.EqualError(t, err, "quantity of line 1 must be between 1 and 0")
+}
+
+func TestSaleRefundTenders(t *testing.T) {
+	s := &Sale{
+		Currency: "BRL",
+		Payments: []Tender{{Method: TenderCash, Amount: money.New(1000, "BRL")}, {Method: TenderCard, Amount: money.New(800, "BRL")}},
+		Change:   money.New(200, "BRL"),
+		Refunded: map[TenderMethod]money.Money{TenderCard: money.New(300, "BRL")},
+	}
+
+	tenders, err := s.RefundTenders(money.New(700, "BRL"))
+	assert.NoError(t, err)
+	assert.Equal(t, []Tender{{Method: TenderCard, Amount: money.New(500, "BRL")}, {Method: TenderCash, Amount: money.New(200, "BRL")}}, tenders)
+
+	_, err = s.RefundTenders(money.New(1400, "BRL"))
+	assert.EqualError(t, err, "refund exceeds the sale's payments by 1.00 BRL")
+}
+
+func TestCheckReturnLines(t *testing.T) {
+	assert.NoError(t, CheckReturnLines([]ReturnLine{{Line: 0, Quantity: 1, Reason: ReturnDamaged}}))
+	assert.EqualError(t, CheckReturnLines([]ReturnLine{{Line: 1, Quantity: 0, Reason: ReturnOther}}), "quantity of line 1 must be positive")
+	assert.EqualError(t, CheckReturnLines([]ReturnLine{{Line: 0, Quantity: 1, Reason: "changed mind"}}), `reason "changed mind" of line 0 is not supported`)
+}
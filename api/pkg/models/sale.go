@@ -0,0 +1,293 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/heiytor/invenda/api/pkg/money"
+)
+
+// Sale represents a counter sale made at a register. A sale is completed at once: its lines are paid and
+// issued from the register's warehouse location when it is created, which makes the sale its own receipt.
+// Its amounts, and those of its lines and payments, are of the sale's currency.
+type Sale struct {
+	ID          string    `json:"id" bson:"_id"`
+	NamespaceID string    `json:"namespace_id" bson:"namespace_id"`
+	CreatedAt   time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" bson:"updated_at"`
+
+	// IdempotencyKey identifies the request that created the sale. Repeating a request with the same key
+	// returns the sale it created instead of charging the customer again.
+	IdempotencyKey string `json:"idempotency_key" bson:"idempotency_key"`
+
+	// RequestHash is the hash of the payload of the request that created the sale. A request repeating
+	// the idempotency key with another payload is not a retry of it, so it is not replayed.
+	RequestHash string `json:"-" bson:"request_hash"`
+
+	RegisterID  string `json:"register_id" bson:"register_id"`
+	WarehouseID string `json:"warehouse_id" bson:"warehouse_id"`
+	Location    string `json:"location" bson:"location"`
+	CustomerID  string `json:"customer_id,omitempty" bson:"customer_id,omitempty"`
+
+	// ShiftID is the ID of the register's shift within which the sale was made.
+	ShiftID string `json:"shift_id" bson:"shift_id"`
+
+	// CashierID is the ID of the user that made the sale.
+	CashierID string `json:"cashier_id" bson:"cashier_id"`
+
+	Lines    []SaleLine `json:"lines" bson:"lines"`
+	Payments []Tender   `json:"payments" bson:"payments"`
+
+	// Coupons are the promotion coupons presented by the customer.
+	Coupons []string `json:"coupons,omitempty" bson:"coupons,omitempty"`
+
+	// Subtotal, Discount, Tax and Total are the sums of the lines' amounts, Discount including the amounts
+	// of the lines' promotions.
+	Subtotal money.Money `json:"subtotal" bson:"subtotal"`
+	Discount money.Money `json:"discount" bson:"discount"`
+	Tax      money.Money `json:"tax" bson:"tax"`
+	Total    money.Money `json:"total" bson:"total"`
+
+	// TaxInclusive and TaxRounding are copied from the namespace's settings when the sale is priced, and
+	// Taxes are the taxes of the lines summed by rate.
+	TaxInclusive bool         `json:"tax_inclusive" bson:"tax_inclusive"`
+	TaxRounding  TaxRounding  `json:"tax_rounding,omitempty" bson:"tax_rounding,omitempty"`
+	Taxes        AppliedTaxes `json:"taxes,omitempty" bson:"taxes,omitempty"`
+
+	// Currency is the namespace's base currency when the sale was made, as counter sales are always made in
+	// it.
+	Currency money.Currency `json:"currency,omitempty" bson:"currency,omitempty"`
+
+	// Paid is the sum of the payments and Change the amount given back to the customer in cash.
+	Paid   money.Money `json:"paid" bson:"paid"`
+	Change money.Money `json:"change" bson:"change"`
+
+	// Refunded is the amount refunded with each tender by the sale's returns.
+	Refunded map[TenderMethod]money.Money `json:"refunded,omitempty" bson:"refunded,omitempty"`
+}
+
+type SaleLine struct {
+	// Barcode identifies the line's product or variant when ProductID is empty.
+	Barcode   string `json:"barcode,omitempty" bson:"barcode,omitempty"`
+	ProductID string `json:"product_id" bson:"product_id" validate:"ulid"`
+	VariantID string `json:"variant_id" bson:"variant_id" validate:"ulid"`
+
+	// SKU and Name are copied from the product or variant so the receipt does not change with the catalog.
+	SKU      string `json:"sku" bson:"sku"`
+	Name     string `json:"name" bson:"name"`
+	Quantity int64  `json:"quantity" bson:"quantity" validate:"required|min:1"`
+
+	// UnitPrice defaults to the price resolved for the customer when it is not given, as in the lines of
+	// sales orders, and Discount and TaxRate are applied as in them too.
+	UnitPrice   *money.Money `json:"unit_price" bson:"unit_price" validate:"money_min:0"`
+	Discount    money.Money  `json:"discount" bson:"discount" validate:"money_min:0"`
+	TaxRate     int64        `json:"tax_rate" bson:"tax_rate" validate:"min:0"`
+	PriceListID string       `json:"price_list_id,omitempty" bson:"price_list_id,omitempty"`
+
+	// Promotions are the promotions applied to the line, as in the lines of sales orders.
+	Promotions AppliedPromotions `json:"promotions,omitempty" bson:"promotions,omitempty"`
+
+	// Lot and Serials name the lot or units sold of lot-tracked or serialized products. Lots are chosen by
+	// the product's issue strategy when Lot is empty.
+	Lot     string   `json:"lot,omitempty" bson:"lot,omitempty"`
+	Serials []string `json:"serials,omitempty" bson:"serials,omitempty"`
+
+	// Taxes are the taxes levied on the line, as in the lines of sales orders.
+	Taxes AppliedTaxes `json:"taxes,omitempty" bson:"taxes,omitempty"`
+
+	Subtotal money.Money `json:"subtotal" bson:"subtotal"`
+	Tax      money.Money `json:"tax" bson:"tax"`
+	Total    money.Money `json:"total" bson:"total"`
+
+	// Returned is the quantity of the line returned by the sale's returns.
+	Returned int64 `json:"returned" bson:"returned"`
+}
+
+// Refundable returns the part of the line's total refunded by returning quantity more units. The total
+// is apportioned by the units returned so far, so once every unit is returned exactly the line's total
+// has been refunded.
+func (l *SaleLine) Refundable(quantity int64) money.Money {
+	if l.Quantity == 0 {
+		return money.Zero(l.Total.Currency)
+	}
+
+	return money.New(l.Total.Amount*(l.Returned+quantity)/l.Quantity-l.Total.Amount*l.Returned/l.Quantity, l.Total.Currency)
+}
+
+// TenderMethod represents how a payment was made.
+type TenderMethod string
+
+const (
+	TenderCash    TenderMethod = "cash"
+	TenderCard    TenderMethod = "card"
+	TenderVoucher TenderMethod = "voucher"
+	TenderOther   TenderMethod = "other"
+)
+
+// Tender represents a payment of a sale. A sale can be paid with several tenders, e.g. part in cash and
+// part by card.
+type Tender struct {
+	Method TenderMethod `json:"method" bson:"method" validate:"required|in:cash,card,voucher,other"`
+	Amount money.Money  `json:"amount" bson:"amount" validate:"required|money_min:1"`
+
+	// Reference identifies the payment outside of the namespace, such as a card authorization code.
+	Reference string `json:"reference,omitempty" bson:"reference,omitempty"`
+}
+
+// SaleItem is a product, or one of its variants, identified at the counter along with its price.
+type SaleItem struct {
+	ProductID string      `json:"product_id"`
+	VariantID string      `json:"variant_id"`
+	SKU       string      `json:"sku"`
+	Name      string      `json:"name"`
+	Price     money.Money `json:"price"`
+}
+
+// Key returns the stock key from which a line of the sale is issued.
+func (s *Sale) Key(line SaleLine) StockKey {
+	return StockKey{WarehouseID: s.WarehouseID, Location: s.Location, ProductID: line.ProductID, VariantID: line.VariantID}
+}
+
+// PromotionIDs returns the IDs of the promotions applied to the sale's lines, each one once.
+func (s *Sale) PromotionIDs() []string {
+	ids := make([]string, 0)
+	for _, l := range s.Lines {
+		for _, p := range l.Promotions {
+			if !slices.Contains(ids, p.PromotionID) {
+				ids = append(ids, p.PromotionID)
+			}
+		}
+	}
+
+	return ids
+}
+
+// Compute computes the amounts of the sale's lines and the sale's totals, as in [SalesOrder.Compute].
+func (s *Sale) Compute() {
+	var subtotal, discount, tax, total int64
+
+	taxable := make([]TaxableLine, len(s.Lines))
+	for i := range s.Lines {
+		l := &s.Lines[i]
+
+		*l.UnitPrice, l.Discount = money.New(l.UnitPrice.Amount, s.Currency), money.New(l.Discount.Amount, s.Currency)
+		l.Subtotal = l.UnitPrice.Mul(l.Quantity)
+		l.Taxes = lineTaxes(l.Taxes, l.TaxRate)
+		taxable[i] = TaxableLine{Amount: money.New(l.Subtotal.Amount-l.Discount.Amount-l.Promotions.Amount(), s.Currency), Taxes: l.Taxes}
+	}
+
+	s.Taxes = ComputeTaxes(taxable, s.TaxInclusive, s.TaxRounding)
+
+	for i := range s.Lines {
+		l := &s.Lines[i]
+
+		l.Tax, l.Total = money.New(l.Taxes.Amount(), s.Currency), taxable[i].Amount
+		if !s.TaxInclusive {
+			l.Total.Amount += l.Tax.Amount
+		}
+
+		subtotal += l.Subtotal.Amount
+		discount += l.Subtotal.Amount - taxable[i].Amount.Amount
+		tax += l.Tax.Amount
+		total += l.Total.Amount
+	}
+
+	s.Subtotal, s.Discount = money.New(subtotal, s.Currency), money.New(discount, s.Currency)
+	s.Tax, s.Total = money.New(tax, s.Currency), money.New(total, s.Currency)
+}
+
+// Settle computes the amount paid and the change of the sale from its payments. The payments must cover
+// the sale's total and, as the change is given back in cash, the change cannot exceed the cash tendered.
+// It returns an error otherwise.
+func (s *Sale) Settle() error {
+	var paid, cash int64
+	for _, p := range s.Payments {
+		paid += p.Amount.Amount
+
+		if p.Method == TenderCash {
+			cash += p.Amount.Amount
+		}
+	}
+
+	s.Paid = money.New(paid, s.Currency)
+	if paid < s.Total.Amount {
+		return fmt.Errorf("payments of %s do not cover the total of %s", s.Paid, s.Total)
+	}
+
+	s.Change = money.New(paid-s.Total.Amount, s.Currency)
+	if s.Change.Amount > cash {
+		return fmt.Errorf("change of %s exceeds the cash tendered", s.Change)
+	}
+
+	return nil
+}
+
+// Takings returns the amount taken with each of the sale's tenders. The change is given back in cash, so it
+// is taken off the cash tendered.
+func (s *Sale) Takings() map[TenderMethod]money.Money {
+	takings := make(map[TenderMethod]money.Money)
+	for _, p := range s.Payments {
+		takings[p.Method] = money.New(takings[p.Method].Amount+p.Amount.Amount, s.Currency)
+	}
+
+	if s.Change.Amount > 0 {
+		takings[TenderCash] = money.New(takings[TenderCash].Amount-s.Change.Amount, s.Currency)
+	}
+
+	return takings
+}
+
+// CheckSaleLines reports whether the lines identify a product, are priced, have positive quantities,
+// non-negative prices, taxes and discounts that do not exceed their amounts, and whether each product,
+// variant and lot appears only once.
+func CheckSaleLines(lines []SaleLine) error {
+	seen := make(map[string]bool, len(lines))
+	for _, l := range lines {
+		if l.ProductID == "" {
+			return fmt.Errorf("lines must have a product_id or a barcode")
+		}
+
+		if l.Quantity < 1 {
+			return fmt.Errorf("quantity of %q must be positive", l.ProductID)
+		}
+
+		if l.UnitPrice == nil {
+			return fmt.Errorf("unit price of %q is missing", l.ProductID)
+		}
+
+		if l.UnitPrice.IsNegative() || l.TaxRate < 0 {
+			return fmt.Errorf("unit price and tax rate of %q cannot be negative", l.ProductID)
+		}
+
+		if l.Discount.IsNegative() || l.Discount.Amount > l.Quantity*l.UnitPrice.Amount {
+			return fmt.Errorf("discount of %q must be between 0 and the line's amount", l.ProductID)
+		}
+
+		k := l.ProductID + "/" + l.VariantID + "/" + l.Lot
+		if seen[k] {
+			return fmt.Errorf("line %q is duplicated", k)
+		}
+
+		seen[k] = true
+	}
+
+	return nil
+}
+
+// CheckTenders reports whether the tenders have a known method and a positive amount.
+func CheckTenders(tenders []Tender) error {
+	for _, t := range tenders {
+		switch t.Method {
+		case TenderCash, TenderCard, TenderVoucher, TenderOther:
+		default:
+			return fmt.Errorf("method %q is not supported", t.Method)
+		}
+
+		if t.Amount.Amount < 1 {
+			return fmt.Errorf("amount of %q payments must be positive", t.Method)
+		}
+	}
+
+	return nil
+}
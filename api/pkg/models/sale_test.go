@@ -0,0 +1,109 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/heiytor/invenda/api/pkg/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSaleSettle(t *testing.T) {
+	cases := []struct {
+		description string
+		payments    []Tender
+		paid        int64
+		change      int64
+		err         string
+	}{
+		{
+			description: "exact card payment",
+			payments:    []Tender{{Method: TenderCard, Amount: money.New(1250, "BRL")}},
+			paid:        1250,
+		},
+		{
+			description: "cash with change",
+			payments:    []Tender{{Method: TenderCash, Amount: money.New(2000, "BRL")}},
+			paid:        2000,
+			change:      750,
+		},
+		{
+			description: "mixed tenders with change from the cash",
+			payments:    []Tender{{Method: TenderCard, Amount: money.New(1000, "BRL")}, {Method: TenderCash, Amount: money.New(500, "BRL")}},
+			paid:        1500,
+			change:      250,
+		},
+		{
+			description: "underpaid",
+			payments:    []Tender{{Method: TenderCash, Amount: money.New(1000, "BRL")}},
+			paid:        1000,
+			err:         "payments of 10.00 BRL do not cover the total of 12.50 BRL",
+		},
+		{
+			description: "mixed tenders with change within the cash",
+			payments:    []Tender{{Method: TenderCard, Amount: money.New(1200, "BRL")}, {Method: TenderCash, Amount: money.New(100, "BRL")}},
+			paid:        1300,
+			change:      50,
+		},
+		{
+			description: "card overpays",
+			payments:    []Tender{{Method: TenderCard, Amount: money.New(1300, "BRL")}},
+			paid:        1300,
+			change:      50,
+			err:         "change of 0.50 BRL exceeds the cash tendered",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			s := &Sale{Currency: "BRL", Total: money.New(1250, "BRL"), Payments: tc.payments}
+
+			err := s.Settle()
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tc.paid, s.Paid.Amount)
+			assert.Equal(t, tc.change, s.Change.Amount)
+		})
+	}
+}
+
+func TestSaleCompute(t *testing.T) {
+	s := &Sale{Currency: "BRL", Lines: []SaleLine{
+		{ProductID: "prd_1", Quantity: 2, UnitPrice: unitPrice(550, "BRL"), Discount: money.New(100, "BRL"), TaxRate: 1000},
+		{ProductID: "prd_2", Quantity: 1, UnitPrice: unitPrice(200, "BRL")},
+	}}
+
+	s.Compute()
+
+	assert.Equal(t, money.New(1100, "BRL"), s.Lines[0].Subtotal)
+	assert.Equal(t, money.New(100, "BRL"), s.Lines[0].Tax)
+	assert.Equal(t, money.New(1100, "BRL"), s.Lines[0].Total)
+	assert.Equal(t, money.New(1300, "BRL"), s.Subtotal)
+	assert.Equal(t, money.New(100, "BRL"), s.Discount)
+	assert.Equal(t, money.New(100, "BRL"), s.Tax)
+	assert.Equal(t, money.New(1300, "BRL"), s.Total)
+}
+
+func TestCheckSaleLines(t *testing.T) {
+	assert.NoError(t, CheckSaleLines([]SaleLine{{ProductID: "prd_1", Quantity: 1, UnitPrice: unitPrice(0, "BRL"), Lot: "L1"}, {ProductID: "prd_1", Quantity: 1, UnitPrice: unitPrice(0, "BRL"), Lot: "L2"}}))
+	assert.EqualError(t, CheckSaleLines([]SaleLine{{Barcode: "7891000100103", Quantity: 1}}), "lines must have a product_id or a barcode")
+	assert.EqualError(t, CheckSaleLines([]SaleLine{{ProductID: "prd_1", Quantity: 0}}), `quantity of "prd_1" must be positive`)
+	assert.EqualError(t, CheckSaleLines([]SaleLine{{ProductID: "prd_1", Quantity: 1}}), `unit price of "prd_1" is missing`)
+	assert.EqualError(t, CheckSaleLines([]SaleLine{{ProductID: "prd_1", Quantity: 1, UnitPrice: unitPrice(10, "BRL"), Discount: money.New(11, "BRL")}}), `discount of "prd_1" must be between 0 and the line's amount`)
+	assert.EqualError(t, CheckSaleLines([]SaleLine{{ProductID: "prd_1", Quantity: 1, UnitPrice: unitPrice(0, "BRL")}, {ProductID: "prd_1", Quantity: 2, UnitPrice: unitPrice(0, "BRL")}}), `line "prd_1//" is duplicated`)
+}
+
+func TestCheckTenders(t *testing.T) {
+	assert.NoError(t, CheckTenders([]Tender{{Method: TenderCash, Amount: money.New(1, "BRL")}, {Method: TenderCard, Amount: money.New(1, "BRL"), Reference: "AUTH-1"}}))
+	assert.EqualError(t, CheckTenders([]Tender{{Method: "cheque", Amount: money.New(1, "BRL")}}), `method "cheque" is not supported`)
+	assert.EqualError(t, CheckTenders([]Tender{{Method: TenderCash, Amount: money.New(0, "BRL")}}), `amount of "cash" payments must be positive`)
+}
+
+func TestSaleTakings(t *testing.T) {
+	s := &Sale{Currency: "BRL", Payments: []Tender{{Method: TenderCard, Amount: money.New(1000, "BRL")}, {Method: TenderCash, Amount: money.New(500, "BRL")}, {Method: TenderCash, Amount: money.New(200, "BRL")}}, Change: money.New(250, "BRL")}
+
+	assert.Equal(t, map[TenderMethod]money.Money{TenderCard: money.New(1000, "BRL"), TenderCash: money.New(450, "BRL")}, s.Takings())
+}
@@ -0,0 +1,274 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/heiytor/invenda/api/pkg/money"
+)
+
+// SalesOrderStatus represents the stage of a sales order's lifecycle.
+type SalesOrderStatus string
+
+const (
+	SalesOrderDraft     SalesOrderStatus = "draft"
+	SalesOrderConfirmed SalesOrderStatus = "confirmed"
+	SalesOrderPicked    SalesOrderStatus = "picked"
+	SalesOrderShipped   SalesOrderStatus = "shipped"
+	SalesOrderInvoiced  SalesOrderStatus = "invoiced"
+	SalesOrderClosed    SalesOrderStatus = "closed"
+	SalesOrderCancelled SalesOrderStatus = "cancelled"
+)
+
+// SalesOrderAction represents an action that moves a sales order to another status.
+type SalesOrderAction string
+
+const (
+	SalesOrderConfirm SalesOrderAction = "confirm"
+	SalesOrderPick    SalesOrderAction = "pick"
+	SalesOrderShip    SalesOrderAction = "ship"
+	SalesOrderInvoice SalesOrderAction = "invoice"
+	SalesOrderClose   SalesOrderAction = "close"
+	SalesOrderCancel  SalesOrderAction = "cancel"
+)
+
+// salesOrderTransitions maps each action to the statuses it can be performed from and the status it
+// leads to.
+var salesOrderTransitions = map[SalesOrderAction]struct {
+	from []SalesOrderStatus
+	to   SalesOrderStatus
+}{
+	SalesOrderConfirm: {[]SalesOrderStatus{SalesOrderDraft}, SalesOrderConfirmed},
+	SalesOrderPick:    {[]SalesOrderStatus{SalesOrderConfirmed}, SalesOrderPicked},
+	SalesOrderShip:    {[]SalesOrderStatus{SalesOrderPicked}, SalesOrderShipped},
+	SalesOrderInvoice: {[]SalesOrderStatus{SalesOrderShipped}, SalesOrderInvoiced},
+	SalesOrderClose:   {[]SalesOrderStatus{SalesOrderInvoiced}, SalesOrderClosed},
+	SalesOrderCancel:  {[]SalesOrderStatus{SalesOrderDraft, SalesOrderConfirmed, SalesOrderPicked}, SalesOrderCancelled},
+}
+
+// Transition returns the statuses from which the action can be performed and the status it leads to.
+// It returns no statuses for unknown actions.
+func (a SalesOrderAction) Transition() (from []SalesOrderStatus, to SalesOrderStatus) {
+	t := salesOrderTransitions[a]
+
+	return t.from, t.to
+}
+
+// Can reports whether the action can be performed on an order in the status.
+func (s SalesOrderStatus) Can(action SalesOrderAction) bool {
+	from, _ := action.Transition()
+
+	return slices.Contains(from, s)
+}
+
+// SalesOrder represents goods sold to a customer and shipped from a warehouse. Confirming an order
+// reserves its lines' stock, which is issued when the order is shipped or released when it is cancelled.
+// Its amounts, and those of its lines, are of the order's currency.
+type SalesOrder struct {
+	ID          string           `json:"id" bson:"_id"`
+	NamespaceID string           `json:"namespace_id" bson:"namespace_id"`
+	CreatedAt   time.Time        `json:"created_at" bson:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at" bson:"updated_at"`
+	Status      SalesOrderStatus `json:"status" bson:"status"`
+
+	CustomerID      string   `json:"customer_id" bson:"customer_id"`
+	WarehouseID     string   `json:"warehouse_id" bson:"warehouse_id"`
+	Location        string   `json:"location" bson:"location"`
+	ShippingAddress *Address `json:"shipping_address,omitempty" bson:"shipping_address,omitempty"`
+
+	Lines []SalesOrderLine `json:"lines" bson:"lines"`
+	Notes string           `json:"notes" bson:"notes"`
+
+	// Coupons are the promotion coupons presented by the customer.
+	Coupons []string `json:"coupons,omitempty" bson:"coupons,omitempty"`
+
+	// Subtotal, Discount, Tax and Total are the sums of the lines' amounts, Discount including the amounts
+	// of the lines' promotions.
+	Subtotal money.Money `json:"subtotal" bson:"subtotal"`
+	Discount money.Money `json:"discount" bson:"discount"`
+	Tax      money.Money `json:"tax" bson:"tax"`
+	Total    money.Money `json:"total" bson:"total"`
+
+	// TaxInclusive and TaxRounding are copied from the namespace's settings when the order is priced, and
+	// Taxes are the taxes of the lines summed by rate.
+	TaxInclusive bool         `json:"tax_inclusive" bson:"tax_inclusive"`
+	TaxRounding  TaxRounding  `json:"tax_rounding,omitempty" bson:"tax_rounding,omitempty"`
+	Taxes        AppliedTaxes `json:"taxes,omitempty" bson:"taxes,omitempty"`
+
+	// Currency is the currency of the order's amounts, which defaults to the namespace's base currency.
+	// ExchangeRate is what one unit of it was worth in the base currency when the order was priced, and
+	// BaseTotal the order's total converted at it.
+	Currency     money.Currency `json:"currency,omitempty" bson:"currency,omitempty"`
+	ExchangeRate money.Rate     `json:"exchange_rate,omitempty" bson:"exchange_rate,omitempty"`
+	BaseTotal    money.Money    `json:"base_total" bson:"base_total,omitempty"`
+
+	// CreatedBy is the ID of the user that created the order.
+	CreatedBy string `json:"created_by" bson:"created_by"`
+
+	// PlacedAt is when the order was confirmed, which is when the customer purchased it.
+	PlacedAt    *time.Time `json:"placed_at,omitempty" bson:"placed_at,omitempty"`
+	PickedAt    *time.Time `json:"picked_at,omitempty" bson:"picked_at,omitempty"`
+	ShippedAt   *time.Time `json:"shipped_at,omitempty" bson:"shipped_at,omitempty"`
+	InvoicedAt  *time.Time `json:"invoiced_at,omitempty" bson:"invoiced_at,omitempty"`
+	ClosedAt    *time.Time `json:"closed_at,omitempty" bson:"closed_at,omitempty"`
+	CancelledAt *time.Time `json:"cancelled_at,omitempty" bson:"cancelled_at,omitempty"`
+}
+
+type SalesOrderLine struct {
+	ProductID string `json:"product_id" bson:"product_id" validate:"required|ulid"`
+	VariantID string `json:"variant_id" bson:"variant_id" validate:"ulid"`
+	Quantity  int64  `json:"quantity" bson:"quantity" validate:"required|min:1"`
+
+	// UnitPrice defaults to the price resolved for the customer from the price lists, falling back to the
+	// price of the product or variant, when it is not given; a given price, even zero, is kept. Discount
+	// is the amount taken off the line and TaxRate the rate
+	// applied to the discounted amount, in basis points (e.g. 1000 for 10%), when the namespace has no
+	// tax rates for the product's tax category.
+	UnitPrice *money.Money `json:"unit_price" bson:"unit_price" validate:"money_min:0"`
+	Discount  money.Money  `json:"discount" bson:"discount" validate:"money_min:0"`
+	TaxRate   int64        `json:"tax_rate" bson:"tax_rate" validate:"min:0"`
+
+	// PriceListID is the ID of the price list the unit price was taken from, if any.
+	PriceListID string `json:"price_list_id,omitempty" bson:"price_list_id,omitempty"`
+
+	// Promotions are the promotions applied to the line, whose amounts are taken off the line on top of
+	// its discount.
+	Promotions AppliedPromotions `json:"promotions,omitempty" bson:"promotions,omitempty"`
+
+	// Taxes are the taxes levied on the line, taken from the namespace's tax rates or, without them, from
+	// the line's tax rate.
+	Taxes AppliedTaxes `json:"taxes,omitempty" bson:"taxes,omitempty"`
+
+	// Subtotal, Tax and Total are computed from the line's quantity, price, discount and taxes.
+	Subtotal money.Money `json:"subtotal" bson:"subtotal"`
+	Tax      money.Money `json:"tax" bson:"tax"`
+	Total    money.Money `json:"total" bson:"total"`
+
+	// ReservationID is the ID of the reservation that holds the line's stock once the order is confirmed.
+	ReservationID string `json:"reservation_id,omitempty" bson:"reservation_id,omitempty"`
+}
+
+// SalesOrderShipment names the lot or serials shipped for a line of a lot-tracked or serialized product.
+// Lots are chosen by the product's issue strategy when Lot is empty.
+type SalesOrderShipment struct {
+	ProductID string   `json:"product_id" validate:"required|ulid"`
+	VariantID string   `json:"variant_id" validate:"ulid"`
+	Lot       string   `json:"lot"`
+	Serials   []string `json:"serials"`
+}
+
+// Key returns the stock key from which a line of the sales order is shipped.
+func (so *SalesOrder) Key(line SalesOrderLine) StockKey {
+	return StockKey{WarehouseID: so.WarehouseID, Location: so.Location, ProductID: line.ProductID, VariantID: line.VariantID}
+}
+
+// PromotionIDs returns the IDs of the promotions applied to the order's lines, each one once.
+func (so *SalesOrder) PromotionIDs() []string {
+	ids := make([]string, 0)
+	for _, l := range so.Lines {
+		for _, p := range l.Promotions {
+			if !slices.Contains(ids, p.PromotionID) {
+				ids = append(ids, p.PromotionID)
+			}
+		}
+	}
+
+	return ids
+}
+
+// Compute computes the amounts of the order's lines and the order's totals. The amounts of the lines'
+// promotions are discounted on top of their discounts and their taxes are computed as in [ComputeTaxes],
+// with the order's tax mode. The totals of tax-inclusive lines are their discounted amounts. The lines'
+// prices and discounts are taken as amounts of the order's currency.
+func (so *SalesOrder) Compute() {
+	var subtotal, discount, tax, total int64
+
+	taxable := make([]TaxableLine, len(so.Lines))
+	for i := range so.Lines {
+		l := &so.Lines[i]
+
+		*l.UnitPrice, l.Discount = money.New(l.UnitPrice.Amount, so.Currency), money.New(l.Discount.Amount, so.Currency)
+		l.Subtotal = l.UnitPrice.Mul(l.Quantity)
+		l.Taxes = lineTaxes(l.Taxes, l.TaxRate)
+		taxable[i] = TaxableLine{Amount: money.New(l.Subtotal.Amount-l.Discount.Amount-l.Promotions.Amount(), so.Currency), Taxes: l.Taxes}
+	}
+
+	so.Taxes = ComputeTaxes(taxable, so.TaxInclusive, so.TaxRounding)
+
+	for i := range so.Lines {
+		l := &so.Lines[i]
+
+		l.Tax, l.Total = money.New(l.Taxes.Amount(), so.Currency), taxable[i].Amount
+		if !so.TaxInclusive {
+			l.Total.Amount += l.Tax.Amount
+		}
+
+		subtotal += l.Subtotal.Amount
+		discount += l.Subtotal.Amount - taxable[i].Amount.Amount
+		tax += l.Tax.Amount
+		total += l.Total.Amount
+	}
+
+	so.Subtotal, so.Discount = money.New(subtotal, so.Currency), money.New(discount, so.Currency)
+	so.Tax, so.Total = money.New(tax, so.Currency), money.New(total, so.Currency)
+}
+
+// CheckSalesOrderLines reports whether the lines are priced, have positive quantities, non-negative prices,
+// taxes and discounts that do not exceed their amounts, and whether each product or variant appears only
+// once.
+func CheckSalesOrderLines(lines []SalesOrderLine) error {
+	seen := make(map[string]bool, len(lines))
+	for _, l := range lines {
+		if l.Quantity < 1 {
+			return fmt.Errorf("quantity of %q must be positive", l.ProductID)
+		}
+
+		if l.UnitPrice == nil {
+			return fmt.Errorf("unit price of %q is missing", l.ProductID)
+		}
+
+		if l.UnitPrice.IsNegative() || l.TaxRate < 0 {
+			return fmt.Errorf("unit price and tax rate of %q cannot be negative", l.ProductID)
+		}
+
+		if l.Discount.IsNegative() || l.Discount.Amount > l.Quantity*l.UnitPrice.Amount {
+			return fmt.Errorf("discount of %q must be between 0 and the line's amount", l.ProductID)
+		}
+
+		k := l.ProductID + "/" + l.VariantID
+		if seen[k] {
+			return fmt.Errorf("line %q is duplicated", k)
+		}
+
+		seen[k] = true
+	}
+
+	return nil
+}
+
+type SalesOrderChanges struct {
+	UpdatedAt       time.Time        `bson:"updated_at"`
+	Status          SalesOrderStatus `bson:"status,omitempty"`
+	Location        *string          `bson:"location,omitempty"`
+	ShippingAddress *Address         `bson:"shipping_address,omitempty"`
+	Lines           []SalesOrderLine `bson:"lines,omitempty"`
+	Notes           *string          `bson:"notes,omitempty"`
+	Coupons         *[]string        `bson:"coupons,omitempty"`
+	Subtotal        *money.Money     `bson:"subtotal,omitempty"`
+	Discount        *money.Money     `bson:"discount,omitempty"`
+	Tax             *money.Money     `bson:"tax,omitempty"`
+	Total           *money.Money     `bson:"total,omitempty"`
+	TaxInclusive    *bool            `bson:"tax_inclusive,omitempty"`
+	TaxRounding     TaxRounding      `bson:"tax_rounding,omitempty"`
+	Taxes           *AppliedTaxes    `bson:"taxes,omitempty"`
+	Currency        money.Currency   `bson:"currency,omitempty"`
+	ExchangeRate    money.Rate       `bson:"exchange_rate,omitempty"`
+	BaseTotal       *money.Money     `bson:"base_total,omitempty"`
+	PlacedAt        *time.Time       `bson:"placed_at,omitempty"`
+	PickedAt        *time.Time       `bson:"picked_at,omitempty"`
+	ShippedAt       *time.Time       `bson:"shipped_at,omitempty"`
+	InvoicedAt      *time.Time       `bson:"invoiced_at,omitempty"`
+	ClosedAt        *time.Time       `bson:"closed_at,omitempty"`
+	CancelledAt     *time.Time       `bson:"cancelled_at,omitempty"`
+}
@@ -0,0 +1,118 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/heiytor/invenda/api/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestSalesOrderStatusCan(t *testing.T) {
+	cases := []struct {
+		status   SalesOrderStatus
+		action   SalesOrderAction
+		expected bool
+	}{
+		{SalesOrderDraft, SalesOrderConfirm, true},
+		{SalesOrderDraft, SalesOrderShip, false},
+		{SalesOrderConfirmed, SalesOrderPick, true},
+		{SalesOrderPicked, SalesOrderShip, true},
+		{SalesOrderShipped, SalesOrderInvoice, true},
+		{SalesOrderInvoiced, SalesOrderClose, true},
+		{SalesOrderPicked, SalesOrderCancel, true},
+		{SalesOrderShipped, SalesOrderCancel, false},
+		{SalesOrderCancelled, SalesOrderConfirm, false},
+		{SalesOrderDraft, SalesOrderAction("refund"), false},
+	}
+
+	for _, tc := range cases {
+		t.Run(string(tc.status)+"/"+string(tc.action), func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.status.Can(tc.action))
+		})
+	}
+}
+
+func TestSalesOrderCompute(t *testing.T) {
+	so := &SalesOrder{Currency: "BRL", Lines: []SalesOrderLine{
+		{ProductID: "prd_1", Quantity: 3, UnitPrice: unitPrice(1000, "BRL"), Discount: money.New(500, "BRL"), TaxRate: 1000},
+		{ProductID: "prd_2", Quantity: 1, UnitPrice: unitPrice(333, "BRL"), TaxRate: 750},
+	}}
+
+	so.Compute()
+
+	assert.Equal(t, SalesOrderLine{ProductID: "prd_1", Quantity: 3, UnitPrice: unitPrice(1000, "BRL"), Discount: money.New(500, "BRL"), TaxRate: 1000, Taxes: AppliedTaxes{{Rate: 1000, Base: money.New(2500, "BRL"), Amount: money.New(250, "BRL")}}, Subtotal: money.New(3000, "BRL"), Tax: money.New(250, "BRL"), Total: money.New(2750, "BRL")}, so.Lines[0])
+	assert.Equal(t, SalesOrderLine{ProductID: "prd_2", Quantity: 1, UnitPrice: unitPrice(333, "BRL"), Discount: money.New(0, "BRL"), TaxRate: 750, Taxes: AppliedTaxes{{Rate: 750, Base: money.New(333, "BRL"), Amount: money.New(25, "BRL")}}, Subtotal: money.New(333, "BRL"), Tax: money.New(25, "BRL"), Total: money.New(358, "BRL")}, so.Lines[1])
+	assert.Equal(t, money.New(3333, "BRL"), so.Subtotal)
+	assert.Equal(t, money.New(500, "BRL"), so.Discount)
+	assert.Equal(t, money.New(275, "BRL"), so.Tax)
+	assert.Equal(t, money.New(3108, "BRL"), so.Total)
+	assert.Equal(t, AppliedTaxes{{Rate: 1000, Base: money.New(2500, "BRL"), Amount: money.New(250, "BRL")}, {Rate: 750, Base: money.New(333, "BRL"), Amount: money.New(25, "BRL")}}, so.Taxes)
+}
+
+func TestSalesOrderComputeWithTaxRates(t *testing.T) {
+	vat := AppliedTax{TaxRateID: "txr_1", Name: "VAT", Rate: 1000}
+
+	so := &SalesOrder{Currency: "BRL", TaxInclusive: true, Lines: []SalesOrderLine{
+		{ProductID: "prd_1", Quantity: 1, UnitPrice: unitPrice(1100, "BRL"), TaxRate: 500, Taxes: AppliedTaxes{vat}},
+		{ProductID: "prd_2", Quantity: 2, UnitPrice: unitPrice(550, "BRL"), Discount: money.New(100, "BRL")},
+	}}
+
+	so.Compute()
+
+	// The line's taxes take precedence over its tax rate and inclusive totals are not taxed again.
+	assert.Equal(t, AppliedTaxes{{TaxRateID: "txr_1", Name: "VAT", Rate: 1000, Base: money.New(1000, "BRL"), Amount: money.New(100, "BRL")}}, so.Lines[0].Taxes)
+	assert.Equal(t, money.New(1100, "BRL"), so.Lines[0].Total)
+	assert.Nil(t, so.Lines[1].Taxes)
+	assert.Equal(t, money.New(1000, "BRL"), so.Lines[1].Total)
+	assert.Equal(t, money.New(100, "BRL"), so.Tax)
+	assert.Equal(t, money.New(2100, "BRL"), so.Total)
+	assert.Equal(t, AppliedTaxes{{TaxRateID: "txr_1", Name: "VAT", Rate: 1000, Base: money.New(1000, "BRL"), Amount: money.New(100, "BRL")}}, so.Taxes)
+}
+
+func TestSalesOrderComputeWithPromotions(t *testing.T) {
+	so := &SalesOrder{Currency: "BRL", Lines: []SalesOrderLine{
+		{ProductID: "prd_1", Quantity: 3, UnitPrice: unitPrice(1000, "BRL"), Discount: money.New(500, "BRL"), TaxRate: 1000, Promotions: AppliedPromotions{{PromotionID: "prm_1", Amount: money.New(250, "BRL")}, {PromotionID: "prm_2", Amount: money.New(250, "BRL")}}},
+		{ProductID: "prd_2", Quantity: 1, UnitPrice: unitPrice(500, "BRL"), Promotions: AppliedPromotions{{PromotionID: "prm_1", Amount: money.New(100, "BRL")}}},
+	}}
+
+	so.Compute()
+
+	assert.Equal(t, money.New(200, "BRL"), so.Lines[0].Tax)
+	assert.Equal(t, money.New(2200, "BRL"), so.Lines[0].Total)
+	assert.Equal(t, money.New(400, "BRL"), so.Lines[1].Total)
+	assert.Equal(t, money.New(1100, "BRL"), so.Discount)
+	assert.Equal(t, money.New(2600, "BRL"), so.Total)
+	assert.Equal(t, []string{"prm_1", "prm_2"}, so.PromotionIDs())
+}
+
+func TestCheckSalesOrderLines(t *testing.T) {
+	assert.NoError(t, CheckSalesOrderLines([]SalesOrderLine{{ProductID: "prd_1", Quantity: 1, UnitPrice: unitPrice(10, "BRL"), Discount: money.New(10, "BRL")}, {ProductID: "prd_1", VariantID: "var_1", Quantity: 1, UnitPrice: unitPrice(0, "BRL")}}))
+	assert.EqualError(t, CheckSalesOrderLines([]SalesOrderLine{{ProductID: "prd_1", Quantity: 0}}), `quantity of "prd_1" must be positive`)
+	assert.EqualError(t, CheckSalesOrderLines([]SalesOrderLine{{ProductID: "prd_1", Quantity: 1}}), `unit price of "prd_1" is missing`)
+	assert.EqualError(t, CheckSalesOrderLines([]SalesOrderLine{{ProductID: "prd_1", Quantity: 1, UnitPrice: unitPrice(0, "BRL"), TaxRate: -1}}), `unit price and tax rate of "prd_1" cannot be negative`)
+	assert.EqualError(t, CheckSalesOrderLines([]SalesOrderLine{{ProductID: "prd_1", Quantity: 2, UnitPrice: unitPrice(10, "BRL"), Discount: money.New(21, "BRL")}}), `discount of "prd_1" must be between 0 and the line's amount`)
+	assert.EqualError(t, CheckSalesOrderLines([]SalesOrderLine{{ProductID: "prd_1", Quantity: 1, UnitPrice: unitPrice(0, "BRL")}, {ProductID: "prd_1", Quantity: 2, UnitPrice: unitPrice(0, "BRL")}}), `line "prd_1/" is duplicated`)
+}
+
+func TestSalesOrderChangesBSON(t *testing.T) {
+	// An order whose coupon was removed has its discount and tax reset to zero.
+	coupons, zero, total := []string{}, money.New(0, "USD"), money.New(1000, "USD")
+	data, err := bson.Marshal(SalesOrderChanges{Coupons: &coupons, Discount: &zero, Tax: &zero, Total: &total})
+	assert.NoError(t, err)
+
+	raw := bson.Raw(data)
+	assert.Equal(t, int64(0), raw.Lookup("discount", "amount").Int64())
+	assert.Equal(t, int64(0), raw.Lookup("tax", "amount").Int64())
+	assert.Equal(t, int64(1000), raw.Lookup("total", "amount").Int64())
+	assert.Equal(t, bson.TypeArray, raw.Lookup("coupons").Type)
+
+	_, err = raw.LookupErr("subtotal")
+	assert.Error(t, err)
+}
+
+// unitPrice returns the unit price of a line, of the amount and currency.
+func unitPrice(amount int64, currency money.Currency) *money.Money {
+	price := money.New(amount, currency)
+	return &price
+}
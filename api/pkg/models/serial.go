@@ -0,0 +1,69 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// SerialStatus represents where a serialized unit is.
+type SerialStatus string
+
+const (
+	SerialInStock   SerialStatus = "in_stock"
+	SerialInTransit SerialStatus = "in_transit"
+	// SerialOut is the status of units that left the stock, e.g. issued or sold.
+	SerialOut SerialStatus = "out"
+)
+
+// StockSerial is the current state of a serialized unit. Serials are unique per product within a
+// namespace; the unit's history is kept by the movements that name it.
+type StockSerial struct {
+	ID          string    `json:"id" bson:"_id"`
+	NamespaceID string    `json:"namespace_id" bson:"namespace_id"`
+	CreatedAt   time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" bson:"updated_at"`
+	StockKey    `bson:",inline"`
+	Serial      string       `json:"serial" bson:"serial"`
+	Status      SerialStatus `json:"status" bson:"status"`
+}
+
+// SerialHistory is a serialized unit along with every movement that named it, oldest first.
+type SerialHistory struct {
+	Serial    *StockSerial `json:"serial"`
+	Movements []Movement   `json:"movements"`
+}
+
+// CheckSerials reports an error when the serials are not exactly quantity distinct non-empty values.
+func CheckSerials(serials []string, quantity int64) error {
+	if int64(len(serials)) != quantity {
+		return fmt.Errorf("expected %d serials, one per unit, but got %d", quantity, len(serials))
+	}
+
+	seen := make(map[string]bool, len(serials))
+	for _, s := range serials {
+		if s == "" || seen[s] {
+			return fmt.Errorf("serial %q is empty or repeated", s)
+		}
+
+		seen[s] = true
+	}
+
+	return nil
+}
+
+// AssignSerials distributes the serials across the movements in order, one per unit moved. The serials
+// must have been checked against the total quantity of the movements.
+func AssignSerials(movements []*Movement, serials []string) {
+	for _, m := range movements {
+		n := min(int(abs(m.Quantity)), len(serials))
+		m.Serials, serials = serials[:n], serials[n:]
+	}
+}
+
+func abs(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}
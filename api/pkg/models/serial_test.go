@@ -0,0 +1,62 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckSerials(t *testing.T) {
+	cases := []struct {
+		description string
+		serials     []string
+		quantity    int64
+		expected    string
+	}{
+		{
+			description: "fails when the serials do not match the quantity",
+			serials:     []string{"SN-1"},
+			quantity:    2,
+			expected:    "expected 2 serials, one per unit, but got 1",
+		},
+		{
+			description: "fails when a serial is repeated",
+			serials:     []string{"SN-1", "SN-1"},
+			quantity:    2,
+			expected:    `serial "SN-1" is empty or repeated`,
+		},
+		{
+			description: "fails when a serial is empty",
+			serials:     []string{"SN-1", ""},
+			quantity:    2,
+			expected:    `serial "" is empty or repeated`,
+		},
+		{
+			description: "succeeds when there is one distinct serial per unit",
+			serials:     []string{"SN-1", "SN-2"},
+			quantity:    2,
+			expected:    "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			err := CheckSerials(tc.serials, tc.quantity)
+			if tc.expected == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.EqualError(t, err, tc.expected)
+		})
+	}
+}
+
+func TestAssignSerials(t *testing.T) {
+	movements := []*Movement{{Lot: "L-001", Quantity: -2}, {Lot: "L-002", Quantity: -1}}
+
+	AssignSerials(movements, []string{"SN-1", "SN-2", "SN-3"})
+
+	assert.Equal(t, []string{"SN-1", "SN-2"}, movements[0].Serials)
+	assert.Equal(t, []string{"SN-3"}, movements[1].Serials)
+}
@@ -0,0 +1,103 @@
+package models
+
+import (
+	"time"
+
+	"github.com/heiytor/invenda/api/pkg/money"
+)
+
+// ShiftStatus represents the stage of a register shift's lifecycle.
+type ShiftStatus string
+
+const (
+	ShiftOpen   ShiftStatus = "open"
+	ShiftClosed ShiftStatus = "closed"
+)
+
+// Shift represents a cashier's session at a register, from the moment the cash drawer is counted to the
+// moment it is counted again. A register has at most one open shift, within which every sale of the
+// register is made, and a closed shift is never changed again. Its amounts are of the currency of its float,
+// which is the namespace's base currency when the shift was opened.
+type Shift struct {
+	ID          string      `json:"id" bson:"_id"`
+	NamespaceID string      `json:"namespace_id" bson:"namespace_id"`
+	CreatedAt   time.Time   `json:"created_at" bson:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at" bson:"updated_at"`
+	Status      ShiftStatus `json:"status" bson:"status"`
+	RegisterID  string      `json:"register_id" bson:"register_id"`
+
+	// Float is the cash counted in the drawer when the shift was opened.
+	Float money.Money `json:"float" bson:"float"`
+
+	// Sales is the number of sales made during the shift and Takings the amount taken with each tender.
+	// Cash takings are net of the change given back.
+	Sales   int64                        `json:"sales" bson:"sales"`
+	Takings map[TenderMethod]money.Money `json:"takings" bson:"takings"`
+
+	// CashRefunds is the cash given back to customers by refunds. PayIns and PayOuts are the sums of the
+	// cash movements of each type.
+	CashRefunds   money.Money    `json:"cash_refunds" bson:"cash_refunds"`
+	PayIns        money.Money    `json:"pay_ins" bson:"pay_ins"`
+	PayOuts       money.Money    `json:"pay_outs" bson:"pay_outs"`
+	CashMovements []CashMovement `json:"cash_movements" bson:"cash_movements"`
+
+	// OpenedBy and ClosedBy are the IDs of the users that opened and closed the shift.
+	OpenedBy string     `json:"opened_by" bson:"opened_by"`
+	ClosedBy string     `json:"closed_by,omitempty" bson:"closed_by,omitempty"`
+	ClosedAt *time.Time `json:"closed_at,omitempty" bson:"closed_at,omitempty"`
+
+	// Report is the reconciliation of the drawer produced when the shift was closed.
+	Report *ShiftReport `json:"report,omitempty" bson:"report,omitempty"`
+}
+
+// CashMovementType represents the direction of cash put into or taken out of a drawer outside of sales,
+// e.g. change brought from the safe or a delivery paid in cash.
+type CashMovementType string
+
+const (
+	CashPayIn  CashMovementType = "pay_in"
+	CashPayOut CashMovementType = "pay_out"
+)
+
+type CashMovement struct {
+	Type      CashMovementType `json:"type" bson:"type"`
+	Amount    money.Money      `json:"amount" bson:"amount"`
+	Reason    string           `json:"reason" bson:"reason"`
+	UserID    string           `json:"user_id" bson:"user_id"`
+	CreatedAt time.Time        `json:"created_at" bson:"created_at"`
+}
+
+// ShiftReport reconciles the cash counted in a drawer with the cash the drawer was expected to hold.
+type ShiftReport struct {
+	Expected money.Money `json:"expected" bson:"expected"`
+	Counted  money.Money `json:"counted" bson:"counted"`
+
+	// Difference is the counted cash minus the expected cash: positive when the drawer is over and
+	// negative when it is short.
+	Difference money.Money `json:"difference" bson:"difference"`
+	Notes      string      `json:"notes" bson:"notes"`
+}
+
+// ShiftDelta represents what an operation adds to an open shift's totals.
+type ShiftDelta struct {
+	Sales        int64
+	Takings      map[TenderMethod]money.Money
+	CashRefunds  money.Money
+	CashMovement *CashMovement
+}
+
+// ExpectedCash returns the cash the shift's drawer is expected to hold: the float and the cash taken,
+// minus the cash refunded, plus the pay-ins and minus the pay-outs.
+func (s *Shift) ExpectedCash() money.Money {
+	cash := s.Float.Amount + s.Takings[TenderCash].Amount - s.CashRefunds.Amount + s.PayIns.Amount - s.PayOuts.Amount
+
+	return money.New(cash, s.Float.Currency)
+}
+
+// Reconcile returns the report of a drawer in which the counted cash was found.
+func (s *Shift) Reconcile(counted money.Money, notes string) *ShiftReport {
+	expected := s.ExpectedCash()
+	counted = money.New(counted.Amount, expected.Currency)
+
+	return &ShiftReport{Expected: expected, Counted: counted, Difference: money.New(counted.Amount-expected.Amount, expected.Currency), Notes: notes}
+}
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/heiytor/invenda/api/pkg/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShiftReconcile(t *testing.T) {
+	brl := func(amount int64) money.Money { return money.New(amount, "BRL") }
+
+	s := &Shift{
+		Float:       brl(10000),
+		Takings:     map[TenderMethod]money.Money{TenderCash: brl(4500), TenderCard: brl(9000)},
+		CashRefunds: brl(500),
+		PayIns:      brl(2000),
+		PayOuts:     brl(1200),
+	}
+
+	assert.Equal(t, brl(14800), s.ExpectedCash())
+	assert.Equal(t, &ShiftReport{Expected: brl(14800), Counted: brl(14750), Difference: brl(-50), Notes: "short"}, s.Reconcile(brl(14750), "short"))
+	assert.Equal(t, &ShiftReport{Expected: brl(14800), Counted: brl(14900), Difference: brl(100)}, s.Reconcile(brl(14900), ""))
+}
@@ -0,0 +1,101 @@
+package models
+
+import (
+	"time"
+
+	"github.com/heiytor/invenda/api/pkg/money"
+)
+
+// MovementType represents the kind of a stock movement.
+type MovementType string
+
+const (
+	MovementReceipt     MovementType = "receipt"
+	MovementIssue       MovementType = "issue"
+	MovementAdjustment  MovementType = "adjustment"
+	MovementTransferIn  MovementType = "transfer_in"
+	MovementTransferOut MovementType = "transfer_out"
+
+	// MovementReturn brings back goods returned by a customer.
+	MovementReturn MovementType = "return"
+)
+
+// Inbound reports whether movements of the type always increase the stock. Adjustments can
+// go in both directions.
+func (t MovementType) Inbound() bool {
+	return t == MovementReceipt || t == MovementTransferIn || t == MovementReturn
+}
+
+// Outbound reports whether movements of the type always decrease the stock. Adjustments can
+// go in both directions.
+func (t MovementType) Outbound() bool {
+	return t == MovementIssue || t == MovementTransferOut
+}
+
+// StockKey identifies where an item is stocked. VariantID is empty for products without
+// variants and Location is empty for stock that is not kept in a specific location.
+type StockKey struct {
+	WarehouseID string `json:"warehouse_id" bson:"warehouse_id"`
+	Location    string `json:"location" bson:"location"`
+	ProductID   string `json:"product_id" bson:"product_id"`
+	VariantID   string `json:"variant_id" bson:"variant_id"`
+}
+
+// Movement represents an entry of the stock ledger. Movements are append-only; a mistake is
+// fixed with a new movement, never by changing an existing one.
+type Movement struct {
+	ID          string    `json:"id" bson:"_id"`
+	NamespaceID string    `json:"namespace_id" bson:"namespace_id"`
+	CreatedAt   time.Time `json:"created_at" bson:"created_at"`
+	StockKey    `bson:",inline"`
+	Type        MovementType `json:"type" bson:"type"`
+
+	// Quantity is the signed quantity moved, positive when the stock increases and negative
+	// when it decreases.
+	Quantity int64  `json:"quantity" bson:"quantity"`
+	Reason   string `json:"reason" bson:"reason"`
+
+	// UserID is the ID of the user that posted the movement.
+	UserID string `json:"user_id" bson:"user_id"`
+
+	// Reference is the ID of the document that originated the movement, if any.
+	Reference string `json:"reference,omitempty" bson:"reference,omitempty"`
+
+	// Lot is the lot moved, for lot-tracked products. ExpiresAt is the lot's expiry date, which is only
+	// recorded by the movement that first brings the lot into a key.
+	Lot       string     `json:"lot,omitempty" bson:"lot,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty" bson:"expires_at,omitempty"`
+
+	// Serials are the serialized units moved, one per unit, for serialized products.
+	Serials []string `json:"serials,omitempty" bson:"serials,omitempty"`
+
+	// UnitCost and Value are the cost of each unit moved and the signed value of the movement, which is
+	// negative when the stock decreases, in the namespace's base currency. Inbound movements may be
+	// posted with either of them, which is kept even when zero; the others are computed when the movement
+	// is posted. COGS is the cost of the goods sold by issues.
+	UnitCost *money.Money `json:"unit_cost" bson:"unit_cost"`
+	Value    *money.Money `json:"value" bson:"value"`
+	COGS     *money.Money `json:"cogs,omitempty" bson:"cogs,omitempty"`
+}
+
+// Balance is the materialized sum of the movements of a [StockKey].
+type Balance struct {
+	ID          string    `json:"id" bson:"_id"`
+	NamespaceID string    `json:"namespace_id" bson:"namespace_id"`
+	UpdatedAt   time.Time `json:"updated_at" bson:"updated_at"`
+	StockKey    `bson:",inline"`
+	OnHand      int64 `json:"on_hand" bson:"on_hand"`
+
+	// InTransit is the quantity dispatched to the key by transfers and not yet received. It is not
+	// part of the on-hand quantity.
+	InTransit int64 `json:"in_transit" bson:"in_transit"`
+
+	// Reserved is the part of the on-hand quantity held by active reservations. Available is the
+	// remaining on-hand quantity, which is computed when the balance is read.
+	Reserved  int64 `json:"reserved" bson:"reserved"`
+	Available int64 `json:"available" bson:"-"`
+
+	// Version is incremented whenever the on-hand quantity changes. It allows detecting changes
+	// made between reading and writing a balance.
+	Version int64 `json:"version" bson:"version"`
+}
@@ -0,0 +1,112 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/heiytor/invenda/api/pkg/money"
+)
+
+// Supplier represents a business the namespace purchases goods from.
+type Supplier struct {
+	ID          string    `json:"id" bson:"_id"`
+	NamespaceID string    `json:"namespace_id" bson:"namespace_id"`
+	CreatedAt   time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" bson:"updated_at"`
+	Code        string    `json:"code" bson:"code"`
+	Name        string    `json:"name" bson:"name"`
+	Contact     Contact   `json:"contact" bson:"contact"`
+	Address     string    `json:"address" bson:"address"`
+	Active      bool      `json:"active" bson:"active"`
+
+	// PaymentTerms describes when the supplier's invoices are due, e.g. "net 30".
+	PaymentTerms string `json:"payment_terms" bson:"payment_terms"`
+
+	// LeadTimeDays is the usual number of days between sending a purchase order and receiving its goods.
+	LeadTimeDays int `json:"lead_time_days" bson:"lead_time_days"`
+
+	// Currency is the currency the supplier bills in, in which its products' costs are quoted and which its
+	// purchase orders default to. Suppliers without one bill in the namespace's base currency.
+	Currency money.Currency `json:"currency,omitempty" bson:"currency,omitempty"`
+
+	// Products are the products the supplier sells, with the supplier's own SKU and cost for each one.
+	Products []SupplierProduct `json:"products" bson:"products"`
+}
+
+// Contact represents the person to reach at a business.
+type Contact struct {
+	Name  string `json:"name" bson:"name"`
+	Email string `json:"email" bson:"email" validate:"email"`
+	Phone string `json:"phone" bson:"phone"`
+}
+
+// SupplierProduct maps a product, or one of its variants, to the supplier's catalog. An empty VariantID
+// applies to every variant of the product without a mapping of its own.
+type SupplierProduct struct {
+	ProductID string      `json:"product_id" bson:"product_id" validate:"required|ulid"`
+	VariantID string      `json:"variant_id" bson:"variant_id" validate:"ulid"`
+	SKU       string      `json:"sku" bson:"sku" validate:"required"`
+	Cost      money.Money `json:"cost" bson:"cost" validate:"money_min:0"`
+}
+
+// CheckSupplierProducts reports whether the products have a SKU and a non-negative cost and whether each
+// product or variant appears only once.
+func CheckSupplierProducts(products []SupplierProduct) error {
+	seen := make(map[string]bool, len(products))
+	for _, p := range products {
+		if p.ProductID == "" || p.SKU == "" {
+			return fmt.Errorf("products must have a product_id and a sku")
+		}
+
+		if p.Cost.IsNegative() {
+			return fmt.Errorf("cost of %q cannot be negative", p.ProductID)
+		}
+
+		k := p.ProductID + "/" + p.VariantID
+		if seen[k] {
+			return fmt.Errorf("product %q is duplicated", k)
+		}
+
+		seen[k] = true
+	}
+
+	return nil
+}
+
+// Product returns the supplier's mapping of a product or variant, falling back to the mapping of the
+// product when the variant has none. It reports whether a mapping was found.
+func (s *Supplier) Product(productID, variantID string) (SupplierProduct, bool) {
+	var fallback *SupplierProduct
+	for i, p := range s.Products {
+		if p.ProductID != productID {
+			continue
+		}
+
+		if p.VariantID == variantID {
+			return p, true
+		}
+
+		if p.VariantID == "" {
+			fallback = &s.Products[i]
+		}
+	}
+
+	if fallback != nil {
+		return *fallback, true
+	}
+
+	return SupplierProduct{}, false
+}
+
+type SupplierChanges struct {
+	UpdatedAt    time.Time         `bson:"updated_at"`
+	Code         string            `bson:"code,omitempty"`
+	Name         string            `bson:"name,omitempty"`
+	Contact      *Contact          `bson:"contact,omitempty"`
+	Address      *string           `bson:"address,omitempty"`
+	Active       *bool             `bson:"active,omitempty"`
+	PaymentTerms *string           `bson:"payment_terms,omitempty"`
+	LeadTimeDays *int              `bson:"lead_time_days,omitempty"`
+	Currency     money.Currency    `bson:"currency,omitempty"`
+	Products     []SupplierProduct `bson:"products,omitempty"`
+}
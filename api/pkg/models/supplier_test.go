@@ -0,0 +1,33 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/heiytor/invenda/api/pkg/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSupplierProduct(t *testing.T) {
+	sup := &Supplier{Products: []SupplierProduct{
+		{ProductID: "prd_1", SKU: "ACME-1", Cost: money.New(100, "BRL")},
+		{ProductID: "prd_1", VariantID: "var_1", SKU: "ACME-1-RED", Cost: money.New(120, "BRL")},
+	}}
+
+	p, ok := sup.Product("prd_1", "var_1")
+	assert.True(t, ok)
+	assert.Equal(t, "ACME-1-RED", p.SKU)
+
+	p, ok = sup.Product("prd_1", "var_2")
+	assert.True(t, ok)
+	assert.Equal(t, "ACME-1", p.SKU)
+
+	_, ok = sup.Product("prd_2", "")
+	assert.False(t, ok)
+}
+
+func TestCheckSupplierProducts(t *testing.T) {
+	assert.NoError(t, CheckSupplierProducts([]SupplierProduct{{ProductID: "prd_1", SKU: "A"}, {ProductID: "prd_1", VariantID: "var_1", SKU: "B"}}))
+	assert.EqualError(t, CheckSupplierProducts([]SupplierProduct{{ProductID: "prd_1"}}), "products must have a product_id and a sku")
+	assert.EqualError(t, CheckSupplierProducts([]SupplierProduct{{ProductID: "prd_1", SKU: "A", Cost: money.New(-1, "BRL")}}), `cost of "prd_1" cannot be negative`)
+	assert.EqualError(t, CheckSupplierProducts([]SupplierProduct{{ProductID: "prd_1", SKU: "A"}, {ProductID: "prd_1", SKU: "B"}}), `product "prd_1/" is duplicated`)
+}
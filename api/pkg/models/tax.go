@@ -0,0 +1,271 @@
+package models
+
+import (
+	"math/big"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/heiytor/invenda/api/pkg/money"
+)
+
+// TaxRounding defines where the taxes of a document are rounded to the minor unit.
+type TaxRounding string
+
+const (
+	// TaxRoundingLine rounds the taxes of each line.
+	TaxRoundingLine TaxRounding = "line"
+	// TaxRoundingDocument rounds the taxes of the whole document, apportioning the rounded amounts
+	// among the lines so they still add up to the document's taxes.
+	TaxRoundingDocument TaxRounding = "document"
+)
+
+// TaxRate represents a tax levied on the sales of the products of a tax category. Rates are effective
+// within their validity range, so a change of rate is recorded as a new rate that starts when the previous
+// one ends.
+type TaxRate struct {
+	ID          string    `json:"id" bson:"_id"`
+	NamespaceID string    `json:"namespace_id" bson:"namespace_id"`
+	CreatedAt   time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" bson:"updated_at"`
+	Name        string    `json:"name" bson:"name"`
+
+	// Category is the tax category of the products the rate applies to.
+	Category string `json:"category" bson:"category"`
+
+	// Rate is the rate in basis points (e.g. 1000 for 10%). A compound rate is levied on the amount plus
+	// the taxes applied before it, while other rates are levied on the amount alone.
+	Rate     int64 `json:"rate" bson:"rate"`
+	Compound bool  `json:"compound" bson:"compound"`
+
+	// Order is the order in which the rates of a category are applied, lowest first.
+	Order int `json:"order" bson:"order"`
+
+	ValidFrom  *time.Time `json:"valid_from,omitempty" bson:"valid_from,omitempty"`
+	ValidUntil *time.Time `json:"valid_until,omitempty" bson:"valid_until,omitempty"`
+}
+
+// AppliesAt reports whether the rate is effective at the given time.
+func (r *TaxRate) AppliesAt(at time.Time) bool {
+	return (r.ValidFrom == nil || !at.Before(*r.ValidFrom)) && (r.ValidUntil == nil || at.Before(*r.ValidUntil))
+}
+
+// AppliedTax is a tax levied on a document line or, summed by rate, on a whole document. It copies the
+// rate so the document does not change when the namespace's rates do.
+type AppliedTax struct {
+	// TaxRateID is the ID of the rate, empty for the rate given on the line itself.
+	TaxRateID string `json:"tax_rate_id,omitempty" bson:"tax_rate_id,omitempty"`
+	Name      string `json:"name,omitempty" bson:"name,omitempty"`
+	Rate      int64  `json:"rate" bson:"rate"`
+	Compound  bool   `json:"compound,omitempty" bson:"compound,omitempty"`
+
+	// Base is the amount on which the tax is levied and Amount the tax itself.
+	Base   money.Money `json:"base" bson:"base"`
+	Amount money.Money `json:"amount" bson:"amount"`
+}
+
+// key identifies the rate of the tax within a document.
+func (t *AppliedTax) key() string {
+	return t.TaxRateID + "/" + t.Name + "/" + strconv.FormatInt(t.Rate, 10) + "/" + strconv.FormatBool(t.Compound)
+}
+
+type AppliedTaxes []AppliedTax
+
+// Amount returns the sum of the taxes' amounts, in minor units.
+func (t AppliedTaxes) Amount() int64 {
+	var amount int64
+	for _, a := range t {
+		amount += a.Amount.Amount
+	}
+
+	return amount
+}
+
+// TaxesFor returns the rates, among the given ones, that apply to the products of the category at the
+// given time, in the order they are applied. Products without a category take no rates.
+func TaxesFor(rates []TaxRate, category string, at time.Time) AppliedTaxes {
+	if category == "" {
+		return nil
+	}
+
+	applicable := make([]TaxRate, 0)
+	for _, r := range rates {
+		if r.Category == category && r.AppliesAt(at) {
+			applicable = append(applicable, r)
+		}
+	}
+
+	slices.SortStableFunc(applicable, func(a, b TaxRate) int {
+		return a.Order - b.Order
+	})
+
+	taxes := make(AppliedTaxes, 0, len(applicable))
+	for _, r := range applicable {
+		taxes = append(taxes, AppliedTax{TaxRateID: r.ID, Name: r.Name, Rate: r.Rate, Compound: r.Compound})
+	}
+
+	if len(taxes) == 0 {
+		return nil
+	}
+
+	return taxes
+}
+
+// lineTaxes returns a copy of the taxes of a line or, when it has none, its own tax rate as the only tax.
+func lineTaxes(taxes AppliedTaxes, rate int64) AppliedTaxes {
+	if len(taxes) == 0 {
+		if rate == 0 {
+			return nil
+		}
+
+		return AppliedTaxes{{Rate: rate}}
+	}
+
+	return slices.Clone(taxes)
+}
+
+// TaxableLine is a document line whose taxes are computed by [ComputeTaxes].
+type TaxableLine struct {
+	// Amount is the line's amount after discounts, including its taxes when prices are tax-inclusive.
+	Amount money.Money
+	Taxes  AppliedTaxes
+}
+
+// ComputeTaxes computes the base and amount of the taxes of each line, which are applied in order, and
+// returns the taxes of the document summed by rate. Inclusive prices already contain their taxes, so the
+// net amount of the line is what, once taxed, adds up to its amount. Taxes are computed exactly and
+// rounded half up to the minor unit by line or by document, as in [TaxRounding].
+func ComputeTaxes(lines []TaxableLine, inclusive bool, rounding TaxRounding) AppliedTaxes {
+	document := make(AppliedTaxes, 0)
+	index := make(map[string]int)
+
+	// The exact and rounded cumulative amounts of each rate, when rounding by document.
+	exact := make(map[string]*big.Rat)
+	rounded := make(map[string]int64)
+
+	for _, l := range lines {
+		// Each tax is a coefficient of the net amount; compound ones apply to the net amount plus the
+		// coefficients of the taxes before them.
+		coefficients := make([]*big.Rat, len(l.Taxes))
+		bases := make([]*big.Rat, len(l.Taxes))
+		multiplier := big.NewRat(1, 1)
+
+		for i, t := range l.Taxes {
+			bases[i] = big.NewRat(1, 1)
+			if t.Compound {
+				bases[i].Set(multiplier)
+			}
+
+			coefficients[i] = new(big.Rat).Mul(bases[i], big.NewRat(t.Rate, 10000))
+			multiplier.Add(multiplier, coefficients[i])
+		}
+
+		net := new(big.Rat).SetInt64(l.Amount.Amount)
+		if inclusive {
+			net.Quo(net, multiplier)
+		}
+
+		for i := range l.Taxes {
+			t := &l.Taxes[i]
+			amount := new(big.Rat).Mul(coefficients[i], net)
+
+			t.Base = money.New(money.Round(new(big.Rat).Mul(bases[i], net), money.RoundHalfUp), l.Amount.Currency)
+			t.Amount = money.New(money.Round(amount, money.RoundHalfUp), l.Amount.Currency)
+
+			k := t.key()
+			if rounding == TaxRoundingDocument {
+				if exact[k] == nil {
+					exact[k] = new(big.Rat)
+				}
+
+				exact[k].Add(exact[k], amount)
+
+				cumulative := money.Round(exact[k], money.RoundHalfUp)
+				t.Amount.Amount = cumulative - rounded[k]
+				rounded[k] = cumulative
+			}
+
+			if j, ok := index[k]; ok {
+				document[j].Base.Amount += t.Base.Amount
+				document[j].Amount.Amount += t.Amount.Amount
+
+				continue
+			}
+
+			index[k] = len(document)
+			document = append(document, *t)
+		}
+	}
+
+	if len(document) == 0 {
+		return nil
+	}
+
+	return document
+}
+
+// SumTaxes sums the taxes by rate, keeping the order in which each rate first appears.
+func SumTaxes(taxes ...AppliedTaxes) AppliedTaxes {
+	sum := make(AppliedTaxes, 0)
+	index := make(map[string]int)
+
+	for _, t := range taxes {
+		for _, a := range t {
+			k := a.key()
+			if j, ok := index[k]; ok {
+				sum[j].Base.Amount += a.Base.Amount
+				sum[j].Amount.Amount += a.Amount.Amount
+
+				continue
+			}
+
+			index[k] = len(sum)
+			sum = append(sum, a)
+		}
+	}
+
+	return sum
+}
+
+// TaxTotal is a tax summed over the documents of a currency priced at the same exchange rate, which
+// reports convert to the base currency with [ConvertTaxes].
+type TaxTotal struct {
+	AppliedTax   `bson:",inline"`
+	Currency     money.Currency `json:"currency,omitempty" bson:"currency,omitempty"`
+	ExchangeRate money.Rate     `json:"exchange_rate,omitempty" bson:"exchange_rate,omitempty"`
+}
+
+// ConvertTaxes converts the totals to the base currency at the rates their documents were priced with and
+// sums them by rate as in [SumTaxes]. Totals without a currency are already in the base currency.
+func ConvertTaxes(totals []TaxTotal, base money.Currency) AppliedTaxes {
+	taxes := make(AppliedTaxes, len(totals))
+	for i, t := range totals {
+		c := NewConversion(t.Currency, base, t.ExchangeRate)
+
+		taxes[i] = t.AppliedTax
+		taxes[i].Base, taxes[i].Amount = c.ToBase(t.Base), c.ToBase(t.Amount)
+	}
+
+	return SumTaxes(taxes)
+}
+
+// TaxReport sums by rate the taxes of the counter sales made and the sales orders invoiced within a
+// period, as they were computed when the documents were priced, so later changes of rates do not change
+// it. Amounts are in the namespace's base currency, Currency, converted at the documents' exchange rates.
+type TaxReport struct {
+	From     time.Time      `json:"from"`
+	Until    time.Time      `json:"until"`
+	Currency money.Currency `json:"currency,omitempty"`
+	Taxes    AppliedTaxes   `json:"taxes"`
+	Tax      money.Money    `json:"tax"`
+}
+
+type TaxRateChanges struct {
+	UpdatedAt  time.Time  `bson:"updated_at"`
+	Name       string     `bson:"name,omitempty"`
+	Rate       *int64     `bson:"rate,omitempty"`
+	Compound   *bool      `bson:"compound,omitempty"`
+	Order      *int       `bson:"order,omitempty"`
+	ValidFrom  *time.Time `bson:"valid_from,omitempty"`
+	ValidUntil *time.Time `bson:"valid_until,omitempty"`
+}
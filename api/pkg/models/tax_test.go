@@ -0,0 +1,124 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/heiytor/invenda/api/pkg/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeTaxes(t *testing.T) {
+	vat := AppliedTax{TaxRateID: "txr_1", Name: "VAT", Rate: 1000}
+	pst := AppliedTax{TaxRateID: "txr_2", Name: "PST", Rate: 500, Compound: true}
+
+	amounts := func(lines []TaxableLine) [][]int64 {
+		out := make([][]int64, 0, len(lines))
+		for _, l := range lines {
+			a := make([]int64, 0, len(l.Taxes))
+			for _, t := range l.Taxes {
+				a = append(a, t.Amount.Amount)
+			}
+
+			out = append(out, a)
+		}
+
+		return out
+	}
+
+	t.Run("exclusive taxes are levied on the amount", func(t *testing.T) {
+		lines := []TaxableLine{{Amount: money.New(1000, "BRL"), Taxes: AppliedTaxes{vat}}}
+		document := ComputeTaxes(lines, false, TaxRoundingLine)
+
+		assert.Equal(t, AppliedTaxes{{TaxRateID: "txr_1", Name: "VAT", Rate: 1000, Base: money.New(1000, "BRL"), Amount: money.New(100, "BRL")}}, document)
+		assert.Equal(t, AppliedTaxes{{TaxRateID: "txr_1", Name: "VAT", Rate: 1000, Base: money.New(1000, "BRL"), Amount: money.New(100, "BRL")}}, lines[0].Taxes)
+	})
+
+	t.Run("compound taxes are levied on the amount plus the previous taxes", func(t *testing.T) {
+		lines := []TaxableLine{{Amount: money.New(1000, "BRL"), Taxes: AppliedTaxes{vat, pst}}}
+		document := ComputeTaxes(lines, false, TaxRoundingLine)
+
+		assert.Equal(t, [][]int64{{100, 55}}, amounts(lines))
+		assert.Equal(t, money.New(1100, "BRL"), document[1].Base)
+	})
+
+	t.Run("inclusive taxes are taken out of the amount", func(t *testing.T) {
+		lines := []TaxableLine{{Amount: money.New(1155, "BRL"), Taxes: AppliedTaxes{vat, pst}}}
+		document := ComputeTaxes(lines, true, TaxRoundingLine)
+
+		assert.Equal(t, [][]int64{{100, 55}}, amounts(lines))
+		assert.Equal(t, money.New(1000, "BRL"), document[0].Base)
+		assert.Equal(t, money.New(1100, "BRL"), document[1].Base)
+	})
+
+	t.Run("rounding by line rounds each line's taxes", func(t *testing.T) {
+		lines := []TaxableLine{{Amount: money.New(5, "BRL"), Taxes: AppliedTaxes{vat}}, {Amount: money.New(5, "BRL"), Taxes: AppliedTaxes{vat}}, {Amount: money.New(5, "BRL"), Taxes: AppliedTaxes{vat}}}
+		document := ComputeTaxes(lines, false, TaxRoundingLine)
+
+		assert.Equal(t, [][]int64{{1}, {1}, {1}}, amounts(lines))
+		assert.Equal(t, AppliedTaxes{{TaxRateID: "txr_1", Name: "VAT", Rate: 1000, Base: money.New(15, "BRL"), Amount: money.New(3, "BRL")}}, document)
+	})
+
+	t.Run("rounding by document rounds the sum of each rate", func(t *testing.T) {
+		lines := []TaxableLine{{Amount: money.New(5, "BRL"), Taxes: AppliedTaxes{vat}}, {Amount: money.New(5, "BRL"), Taxes: AppliedTaxes{vat}}, {Amount: money.New(5, "BRL"), Taxes: AppliedTaxes{vat}}}
+		document := ComputeTaxes(lines, false, TaxRoundingDocument)
+
+		assert.Equal(t, [][]int64{{1}, {0}, {1}}, amounts(lines))
+		assert.Equal(t, AppliedTaxes{{TaxRateID: "txr_1", Name: "VAT", Rate: 1000, Base: money.New(15, "BRL"), Amount: money.New(2, "BRL")}}, document)
+	})
+
+	t.Run("lines without taxes are not taxed", func(t *testing.T) {
+		assert.Nil(t, ComputeTaxes([]TaxableLine{{Amount: money.New(1000, "BRL")}}, false, TaxRoundingLine))
+	})
+}
+
+func TestTaxesFor(t *testing.T) {
+	now := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	old := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	rates := []TaxRate{
+		{ID: "txr_1", Name: "PST", Category: "standard", Rate: 500, Compound: true, Order: 2},
+		{ID: "txr_2", Name: "VAT", Category: "standard", Rate: 1000, Order: 1},
+		{ID: "txr_3", Name: "VAT", Category: "standard", Rate: 900, Order: 1, ValidUntil: &old},
+		{ID: "txr_4", Name: "VAT", Category: "reduced", Rate: 500},
+	}
+
+	assert.Equal(t, AppliedTaxes{
+		{TaxRateID: "txr_2", Name: "VAT", Rate: 1000},
+		{TaxRateID: "txr_1", Name: "PST", Rate: 500, Compound: true},
+	}, TaxesFor(rates, "standard", now))
+	assert.Equal(t, AppliedTaxes{{TaxRateID: "txr_4", Name: "VAT", Rate: 500}}, TaxesFor(rates, "reduced", now))
+	assert.Nil(t, TaxesFor(rates, "exempt", now))
+	assert.Nil(t, TaxesFor(rates, "", now))
+}
+
+func TestSumTaxes(t *testing.T) {
+	vat := AppliedTax{TaxRateID: "txr_1", Name: "VAT", Rate: 1000}
+
+	a := vat
+	a.Base, a.Amount = money.New(1000, "BRL"), money.New(100, "BRL")
+	b := vat
+	b.Base, b.Amount = money.New(500, "BRL"), money.New(50, "BRL")
+
+	assert.Equal(t, AppliedTaxes{
+		{TaxRateID: "txr_1", Name: "VAT", Rate: 1000, Base: money.New(1500, "BRL"), Amount: money.New(150, "BRL")},
+		{Rate: 500, Base: money.New(200, "BRL"), Amount: money.New(10, "BRL")},
+	}, SumTaxes(AppliedTaxes{a}, AppliedTaxes{b, {Rate: 500, Base: money.New(200, "BRL"), Amount: money.New(10, "BRL")}}))
+	assert.Equal(t, AppliedTaxes{}, SumTaxes())
+}
+
+func TestConvertTaxes(t *testing.T) {
+	vat := AppliedTax{TaxRateID: "txr_1", Name: "VAT", Rate: 1000}
+
+	usd := TaxTotal{AppliedTax: vat, Currency: "USD", ExchangeRate: "5.1234"}
+	usd.Base, usd.Amount = money.New(1000, "USD"), money.New(100, "USD")
+	brl := TaxTotal{AppliedTax: vat, Currency: "BRL", ExchangeRate: "1"}
+	brl.Base, brl.Amount = money.New(2000, "BRL"), money.New(200, "BRL")
+	legacy := TaxTotal{AppliedTax: vat}
+	legacy.Base, legacy.Amount = money.New(300, ""), money.New(30, "")
+
+	assert.Equal(t, AppliedTaxes{
+		{TaxRateID: "txr_1", Name: "VAT", Rate: 1000, Base: money.New(7423, "BRL"), Amount: money.New(742, "BRL")},
+	}, ConvertTaxes([]TaxTotal{usd, brl, legacy}, "BRL"))
+	assert.Equal(t, AppliedTaxes{}, ConvertTaxes(nil, "BRL"))
+}
@@ -0,0 +1,230 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/heiytor/invenda/api/pkg/money"
+)
+
+// TransferStatus represents the stage of a transfer's lifecycle.
+type TransferStatus string
+
+const (
+	TransferDraft             TransferStatus = "draft"
+	TransferDispatched        TransferStatus = "dispatched"
+	TransferPartiallyReceived TransferStatus = "partially_received"
+	TransferReceived          TransferStatus = "received"
+	TransferClosed            TransferStatus = "closed"
+)
+
+// Transfer represents goods moving from a source warehouse to a destination warehouse. Dispatching
+// a transfer takes the goods out of the source and puts them in transit to the destination, where they
+// stay until received or until the transfer is closed.
+type Transfer struct {
+	ID          string         `json:"id" bson:"_id"`
+	NamespaceID string         `json:"namespace_id" bson:"namespace_id"`
+	CreatedAt   time.Time      `json:"created_at" bson:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at" bson:"updated_at"`
+	Status      TransferStatus `json:"status" bson:"status"`
+
+	SourceID            string `json:"source_id" bson:"source_id"`
+	SourceLocation      string `json:"source_location" bson:"source_location"`
+	DestinationID       string `json:"destination_id" bson:"destination_id"`
+	DestinationLocation string `json:"destination_location" bson:"destination_location"`
+
+	Items []TransferItem `json:"items" bson:"items"`
+	Notes string         `json:"notes" bson:"notes"`
+
+	// CreatedBy is the ID of the user that created the transfer.
+	CreatedBy    string     `json:"created_by" bson:"created_by"`
+	DispatchedAt *time.Time `json:"dispatched_at,omitempty" bson:"dispatched_at,omitempty"`
+	ReceivedAt   *time.Time `json:"received_at,omitempty" bson:"received_at,omitempty"`
+	ClosedAt     *time.Time `json:"closed_at,omitempty" bson:"closed_at,omitempty"`
+}
+
+type TransferItem struct {
+	ProductID string `json:"product_id" bson:"product_id" validate:"required|ulid"`
+	VariantID string `json:"variant_id" bson:"variant_id" validate:"ulid"`
+	Quantity  int64  `json:"quantity" bson:"quantity" validate:"required|min:1"`
+	Received  int64  `json:"received" bson:"received"`
+
+	// Lots are the lots taken from the source when the transfer was dispatched, for lot-tracked products.
+	Lots []LotAllocation `json:"lots,omitempty" bson:"lots,omitempty"`
+
+	// Serials are the transferred units of serialized products, one per unit. ReceivedSerials are the
+	// ones already received at the destination.
+	Serials         []string `json:"serials,omitempty" bson:"serials,omitempty"`
+	ReceivedSerials []string `json:"received_serials,omitempty" bson:"received_serials,omitempty"`
+
+	// Value is the cost at which the item left the source, which is also the cost at which it enters the
+	// destination, in the namespace's base currency, and UnitCost is its cost per unit. Receipts split the
+	// value so nothing is lost to the unit cost.
+	Value    money.Money `json:"value" bson:"value"`
+	UnitCost money.Money `json:"unit_cost" bson:"unit_cost"`
+}
+
+// ReceiptValue returns the value of quantity units received after the first received ones.
+func (i *TransferItem) ReceiptValue(received, quantity int64) money.Money {
+	return ValueOfUnits(i.Value, i.Quantity, received, quantity)
+}
+
+// Remaining returns the quantity of the item that is still in transit.
+func (i *TransferItem) Remaining() int64 {
+	return i.Quantity - i.Received
+}
+
+// receiveSerials marks the named serials as received. When named is empty, the first quantity serials
+// still in transit are received. It returns the received serials or an error if a serial is not in transit.
+func (i *TransferItem) receiveSerials(named []string, quantity int64) ([]string, error) {
+	remaining := make([]string, 0, len(i.Serials))
+	for _, s := range i.Serials {
+		if !slices.Contains(i.ReceivedSerials, s) {
+			remaining = append(remaining, s)
+		}
+	}
+
+	if len(named) == 0 {
+		named = remaining[:min(quantity, int64(len(remaining)))]
+	}
+
+	if err := CheckSerials(named, quantity); err != nil {
+		return nil, err
+	}
+
+	for _, s := range named {
+		if !slices.Contains(remaining, s) {
+			return nil, fmt.Errorf("serial %q is not in transit", s)
+		}
+	}
+
+	i.ReceivedSerials = append(i.ReceivedSerials, named...)
+
+	return named, nil
+}
+
+// LotsOf splits the quantity received after the first received units across the item's lots, in the
+// order they were dispatched. It returns nil when the item has no lots.
+func (i *TransferItem) LotsOf(received, quantity int64) []LotAllocation {
+	if len(i.Lots) == 0 {
+		return nil
+	}
+
+	lots := make([]LotAllocation, 0)
+	for _, l := range i.Lots {
+		skip := min(received, l.Quantity)
+		received -= skip
+
+		q := min(l.Quantity-skip, quantity)
+		if q <= 0 {
+			continue
+		}
+
+		lots = append(lots, LotAllocation{Lot: l.Lot, ExpiresAt: l.ExpiresAt, Quantity: q})
+		quantity -= q
+	}
+
+	return lots
+}
+
+// SourceKey returns the stock key of an item at the transfer's source.
+func (t *Transfer) SourceKey(item TransferItem) StockKey {
+	return StockKey{WarehouseID: t.SourceID, Location: t.SourceLocation, ProductID: item.ProductID, VariantID: item.VariantID}
+}
+
+// DestinationKey returns the stock key of an item at the transfer's destination.
+func (t *Transfer) DestinationKey(item TransferItem) StockKey {
+	return StockKey{WarehouseID: t.DestinationID, Location: t.DestinationLocation, ProductID: item.ProductID, VariantID: item.VariantID}
+}
+
+// CheckTransferItems reports whether the items have positive quantities, whether their serials, if any,
+// match their quantities and whether each product or variant appears only once.
+func CheckTransferItems(items []TransferItem) error {
+	seen := make(map[string]bool, len(items))
+	for _, i := range items {
+		if i.Quantity < 1 {
+			return fmt.Errorf("quantity of %q must be positive", i.ProductID)
+		}
+
+		if len(i.Serials) > 0 {
+			if err := CheckSerials(i.Serials, i.Quantity); err != nil {
+				return fmt.Errorf("item %q: %w", i.ProductID, err)
+			}
+		}
+
+		k := i.ProductID + "/" + i.VariantID
+		if seen[k] {
+			return fmt.Errorf("item %q is duplicated", k)
+		}
+
+		seen[k] = true
+	}
+
+	return nil
+}
+
+// Receive adds the received quantities to the transfer's items and updates its status. When received
+// is empty, every remaining quantity is received. Items with serials receive the named serials, or the
+// first ones in transit when none is named. It returns the items with the quantities and serials received
+// now, or an error if an item does not belong to the transfer or exceeds its remaining quantity.
+func (t *Transfer) Receive(received []TransferItem) ([]TransferItem, error) {
+	if len(received) == 0 {
+		for _, i := range t.Items {
+			if i.Remaining() > 0 {
+				received = append(received, TransferItem{ProductID: i.ProductID, VariantID: i.VariantID, Quantity: i.Remaining()})
+			}
+		}
+	}
+
+	for k, r := range received {
+		idx := -1
+		for j, i := range t.Items {
+			if i.ProductID == r.ProductID && i.VariantID == r.VariantID {
+				idx = j
+				break
+			}
+		}
+
+		if idx < 0 {
+			return nil, fmt.Errorf("item %q does not belong to the transfer", r.ProductID)
+		}
+
+		if r.Quantity < 1 || r.Quantity > t.Items[idx].Remaining() {
+			return nil, fmt.Errorf("quantity of %q must be between 1 and %d", r.ProductID, t.Items[idx].Remaining())
+		}
+
+		if len(t.Items[idx].Serials) > 0 {
+			serials, err := t.Items[idx].receiveSerials(r.Serials, r.Quantity)
+			if err != nil {
+				return nil, fmt.Errorf("item %q: %w", r.ProductID, err)
+			}
+
+			received[k].Serials = serials
+		}
+
+		t.Items[idx].Received += r.Quantity
+	}
+
+	t.Status = TransferReceived
+	for _, i := range t.Items {
+		if i.Remaining() > 0 {
+			t.Status = TransferPartiallyReceived
+			break
+		}
+	}
+
+	return received, nil
+}
+
+type TransferChanges struct {
+	UpdatedAt           time.Time      `bson:"updated_at"`
+	Status              TransferStatus `bson:"status,omitempty"`
+	SourceLocation      *string        `bson:"source_location,omitempty"`
+	DestinationLocation *string        `bson:"destination_location,omitempty"`
+	Items               []TransferItem `bson:"items,omitempty"`
+	Notes               *string        `bson:"notes,omitempty"`
+	DispatchedAt        *time.Time     `bson:"dispatched_at,omitempty"`
+	ReceivedAt          *time.Time     `bson:"received_at,omitempty"`
+	ClosedAt            *time.Time     `bson:"closed_at,omitempty"`
+}
@@ -0,0 +1,197 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/heiytor/invenda/api/pkg/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransferReceive(t *testing.T) {
+	type Expected struct {
+		received []TransferItem
+		status   TransferStatus
+		err      string
+	}
+
+	items := func() []TransferItem {
+		return []TransferItem{
+			{ProductID: "prd_1", Quantity: 10, Received: 0},
+			{ProductID: "prd_2", VariantID: "var_1", Quantity: 5, Received: 2},
+		}
+	}
+
+	cases := []struct {
+		description string
+		received    []TransferItem
+		expected    Expected
+	}{
+		{
+			description: "receives every remaining quantity when no item is provided",
+			received:    []TransferItem{},
+			expected: Expected{
+				received: []TransferItem{
+					{ProductID: "prd_1", Quantity: 10},
+					{ProductID: "prd_2", VariantID: "var_1", Quantity: 3},
+				},
+				status: TransferReceived,
+			},
+		},
+		{
+			description: "partially receives the transfer",
+			received:    []TransferItem{{ProductID: "prd_1", Quantity: 4}},
+			expected: Expected{
+				received: []TransferItem{{ProductID: "prd_1", Quantity: 4}},
+				status:   TransferPartiallyReceived,
+			},
+		},
+		{
+			description: "fails when the item does not belong to the transfer",
+			received:    []TransferItem{{ProductID: "prd_2", Quantity: 1}},
+			expected:    Expected{err: `item "prd_2" does not belong to the transfer`},
+		},
+		{
+			description: "fails when the quantity exceeds the remaining one",
+			received:    []TransferItem{{ProductID: "prd_2", VariantID: "var_1", Quantity: 4}},
+			expected:    Expected{err: `quantity of "prd_2" must be between 1 and 3`},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			tr := &Transfer{Status: TransferDispatched, Items: items()}
+
+			received, err := tr.Receive(tc.received)
+			if tc.expected.err != "" {
+				assert.EqualError(t, err, tc.expected.err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected.received, received)
+			assert.Equal(t, tc.expected.status, tr.Status)
+		})
+	}
+}
+
+func TestCheckTransferItems(t *testing.T) {
+	assert.NoError(t, CheckTransferItems([]TransferItem{{ProductID: "prd_1", Quantity: 1}, {ProductID: "prd_1", VariantID: "var_1", Quantity: 1}}))
+	assert.EqualError(t, CheckTransferItems([]TransferItem{{ProductID: "prd_1", Quantity: 0}}), `quantity of "prd_1" must be positive`)
+	assert.EqualError(t, CheckTransferItems([]TransferItem{{ProductID: "prd_1", Quantity: 1}, {ProductID: "prd_1", Quantity: 2}}), `item "prd_1/" is duplicated`)
+}
+
+func TestTransferItemLotsOf(t *testing.T) {
+	item := &TransferItem{
+		Quantity: 8,
+		Lots: []LotAllocation{
+			{Lot: "L-001", Quantity: 5},
+			{Lot: "L-002", Quantity: 3},
+		},
+	}
+
+	cases := []struct {
+		description string
+		received    int64
+		quantity    int64
+		expected    []LotAllocation
+	}{
+		{
+			description: "splits the first receipt from the first lot",
+			received:    0,
+			quantity:    3,
+			expected:    []LotAllocation{{Lot: "L-001", Quantity: 3}},
+		},
+		{
+			description: "continues from where the previous receipts stopped",
+			received:    3,
+			quantity:    4,
+			expected:    []LotAllocation{{Lot: "L-001", Quantity: 2}, {Lot: "L-002", Quantity: 2}},
+		},
+		{
+			description: "returns the remaining lots",
+			received:    7,
+			quantity:    1,
+			expected:    []LotAllocation{{Lot: "L-002", Quantity: 1}},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			assert.Equal(t, tc.expected, item.LotsOf(tc.received, tc.quantity))
+		})
+	}
+
+	assert.Nil(t, (&TransferItem{Quantity: 8}).LotsOf(0, 8))
+}
+
+func TestTransferReceiveSerials(t *testing.T) {
+	type Expected struct {
+		serials  []string
+		received []string
+		err      string
+	}
+
+	cases := []struct {
+		description string
+		named       []string
+		quantity    int64
+		expected    Expected
+	}{
+		{
+			description: "receives the first serials in transit when none is named",
+			named:       []string{},
+			quantity:    2,
+			expected:    Expected{serials: []string{"SN-2", "SN-3"}, received: []string{"SN-1", "SN-2", "SN-3"}},
+		},
+		{
+			description: "receives the named serials",
+			named:       []string{"SN-4"},
+			quantity:    1,
+			expected:    Expected{serials: []string{"SN-4"}, received: []string{"SN-1", "SN-4"}},
+		},
+		{
+			description: "fails when a named serial was already received",
+			named:       []string{"SN-1"},
+			quantity:    1,
+			expected:    Expected{err: `item "prd_1": serial "SN-1" is not in transit`},
+		},
+		{
+			description: "fails when the named serials do not match the quantity",
+			named:       []string{"SN-2"},
+			quantity:    2,
+			expected:    Expected{err: `item "prd_1": expected 2 serials, one per unit, but got 1`},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			tr := &Transfer{
+				Status: TransferPartiallyReceived,
+				Items: []TransferItem{
+					{ProductID: "prd_1", Quantity: 4, Received: 1, Serials: []string{"SN-1", "SN-2", "SN-3", "SN-4"}, ReceivedSerials: []string{"SN-1"}},
+				},
+			}
+
+			received, err := tr.Receive([]TransferItem{{ProductID: "prd_1", Quantity: tc.quantity, Serials: tc.named}})
+			if tc.expected.err != "" {
+				assert.EqualError(t, err, tc.expected.err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected.serials, received[0].Serials)
+			assert.Equal(t, tc.expected.received, tr.Items[0].ReceivedSerials)
+		})
+	}
+}
+
+func TestTransferItemReceiptValue(t *testing.T) {
+	item := &TransferItem{Quantity: 3, Value: money.New(100, "BRL"), UnitCost: money.New(33, "BRL")}
+
+	value := money.New(0, "BRL")
+	for received := int64(0); received < item.Quantity; received++ {
+		value, _ = value.Add(item.ReceiptValue(received, 1))
+	}
+
+	assert.Equal(t, item.Value, value)
+}
@@ -0,0 +1,145 @@
+package models
+
+import (
+	"math"
+	"time"
+
+	"github.com/heiytor/invenda/api/pkg/money"
+)
+
+// ValuationMethod defines how the cost of the units leaving the stock is computed.
+type ValuationMethod string
+
+const (
+	// ValuationAverage values the units leaving the stock at the moving weighted-average cost of the
+	// product.
+	ValuationAverage ValuationMethod = "average"
+	// ValuationFIFO values the units leaving the stock at the cost of the oldest units received.
+	ValuationFIFO ValuationMethod = "fifo"
+)
+
+// CostPool is the quantity and value of a product's stock across the namespace, from which its moving
+// weighted-average cost is computed. Every movement changes the pool by its quantity and value, which, as
+// every amount of the ledger, is of the namespace's base currency.
+type CostPool struct {
+	ID          string      `json:"id" bson:"_id"`
+	NamespaceID string      `json:"namespace_id" bson:"namespace_id"`
+	UpdatedAt   time.Time   `json:"updated_at" bson:"updated_at"`
+	ProductID   string      `json:"product_id" bson:"product_id"`
+	VariantID   string      `json:"variant_id" bson:"variant_id"`
+	Quantity    int64       `json:"quantity" bson:"quantity"`
+	Value       money.Money `json:"value" bson:"value"`
+}
+
+// Cost returns the value of quantity units at the pool's average cost, of fallback's currency. When the
+// pool has no stock, the units are valued at fallback each.
+func (p *CostPool) Cost(quantity int64, fallback money.Money) money.Money {
+	if p.Quantity <= 0 {
+		return fallback.Mul(quantity)
+	}
+
+	return money.New(int64(math.Round(float64(quantity)*float64(p.Value.Amount)/float64(p.Quantity))), fallback.Currency)
+}
+
+// CostLayer is a quantity of a product received at the same unit cost. Layers are consumed oldest first
+// as the product leaves the stock, which is how FIFO values its issues.
+type CostLayer struct {
+	ID          string    `json:"id" bson:"_id"`
+	NamespaceID string    `json:"namespace_id" bson:"namespace_id"`
+	CreatedAt   time.Time `json:"created_at" bson:"created_at"`
+	ProductID   string    `json:"product_id" bson:"product_id"`
+	VariantID   string    `json:"variant_id" bson:"variant_id"`
+
+	// Quantity is the quantity of the layer that is still in stock.
+	Quantity int64       `json:"quantity" bson:"quantity"`
+	UnitCost money.Money `json:"unit_cost" bson:"unit_cost"`
+}
+
+// LayerConsumption is the quantity taken from a cost layer.
+type LayerConsumption struct {
+	LayerID  string
+	Quantity int64
+}
+
+// ConsumeLayers takes quantity units from the layers in order. Units not covered by the layers, e.g.
+// when backorders are allowed, are valued at the unit cost of the last layer or at fallback when there
+// are no layers. It returns the layers consumed and the value of the units, of fallback's currency.
+func ConsumeLayers(layers []CostLayer, quantity int64, fallback money.Money) ([]LayerConsumption, money.Money) {
+	consumed := make([]LayerConsumption, 0)
+	currency := fallback.Currency
+
+	var value int64
+	for _, l := range layers {
+		if quantity == 0 {
+			break
+		}
+
+		q := min(l.Quantity, quantity)
+		if q <= 0 {
+			continue
+		}
+
+		consumed = append(consumed, LayerConsumption{LayerID: l.ID, Quantity: q})
+		value += q * l.UnitCost.Amount
+		quantity -= q
+		fallback = l.UnitCost
+	}
+
+	return consumed, money.New(value+quantity*fallback.Amount, currency)
+}
+
+// ValueOfUnits returns the part of value taken by quantity units following the first offset of total
+// units. The value is allocated to the first units as in [money.Money.Allocate], so the parts of
+// consecutive units add up to the whole value once every unit is taken.
+func ValueOfUnits(value money.Money, total, offset, quantity int64) money.Money {
+	return money.New(valueUpTo(value, total, offset+quantity)-valueUpTo(value, total, offset), value.Currency)
+}
+
+// valueUpTo returns the minor units of value taken by the first n of total units.
+func valueUpTo(value money.Money, total, n int64) int64 {
+	n = min(max(n, 0), total)
+
+	shares, err := value.Allocate(n, total-n)
+	if err != nil {
+		return 0
+	}
+
+	return shares[0].Amount
+}
+
+// AllocateValue splits value among the movements in proportion to their quantities, as in
+// [money.Money.Allocate], and sets it as their value.
+func AllocateValue(movements []*Movement, value money.Money) {
+	ratios := make([]int64, len(movements))
+	for i, m := range movements {
+		ratios[i] = max(m.Quantity, 0)
+	}
+
+	shares, err := value.Allocate(ratios...)
+	if err != nil {
+		return
+	}
+
+	for i, m := range movements {
+		m.Value = &shares[i]
+	}
+}
+
+// ValuationLine is the on-hand quantity and value of a product at a point in time.
+type ValuationLine struct {
+	ProductID string      `json:"product_id" bson:"product_id"`
+	VariantID string      `json:"variant_id" bson:"variant_id"`
+	SKU       string      `json:"sku" bson:"sku"`
+	Name      string      `json:"name" bson:"name"`
+	Quantity  int64       `json:"quantity" bson:"quantity"`
+	Value     money.Money `json:"value" bson:"value"`
+}
+
+// Valuation is the worth of a namespace's stock at a point in time, computed from the values recorded
+// by the ledger in the namespace's base currency. Stock in transit between warehouses is not part of it.
+type Valuation struct {
+	AsOf   time.Time       `json:"as_of"`
+	Method ValuationMethod `json:"method"`
+	Value  money.Money     `json:"value"`
+	Lines  []ValuationLine `json:"lines"`
+}
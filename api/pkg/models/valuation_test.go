@@ -0,0 +1,114 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/heiytor/invenda/api/pkg/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCostPoolCost(t *testing.T) {
+	cases := []struct {
+		description string
+		pool        *CostPool
+		quantity    int64
+		expected    money.Money
+	}{
+		{
+			description: "values the units at the average cost",
+			pool:        &CostPool{Quantity: 3, Value: money.New(1000, "BRL")},
+			quantity:    2,
+			expected:    money.New(667, "BRL"),
+		},
+		{
+			description: "values the units at the fallback when the pool has no stock",
+			pool:        &CostPool{Quantity: 0, Value: money.New(0, "BRL")},
+			quantity:    2,
+			expected:    money.New(300, "BRL"),
+		},
+		{
+			description: "values the units at the fallback when the pool is negative",
+			pool:        &CostPool{Quantity: -1, Value: money.New(-150, "BRL")},
+			quantity:    2,
+			expected:    money.New(300, "BRL"),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.pool.Cost(tc.quantity, money.New(150, "BRL")))
+		})
+	}
+}
+
+func TestConsumeLayers(t *testing.T) {
+	type Expected struct {
+		consumed []LayerConsumption
+		value    money.Money
+	}
+
+	layers := []CostLayer{
+		{ID: "cly_1", Quantity: 2, UnitCost: money.New(100, "BRL")},
+		{ID: "cly_2", Quantity: 3, UnitCost: money.New(120, "BRL")},
+	}
+
+	cases := []struct {
+		description string
+		layers      []CostLayer
+		quantity    int64
+		expected    Expected
+	}{
+		{
+			description: "consumes the oldest layer first",
+			layers:      layers,
+			quantity:    2,
+			expected:    Expected{consumed: []LayerConsumption{{LayerID: "cly_1", Quantity: 2}}, value: money.New(200, "BRL")},
+		},
+		{
+			description: "consumes across layers",
+			layers:      layers,
+			quantity:    4,
+			expected:    Expected{consumed: []LayerConsumption{{LayerID: "cly_1", Quantity: 2}, {LayerID: "cly_2", Quantity: 2}}, value: money.New(440, "BRL")},
+		},
+		{
+			description: "values the units beyond the layers at the last layer's cost",
+			layers:      layers,
+			quantity:    6,
+			expected:    Expected{consumed: []LayerConsumption{{LayerID: "cly_1", Quantity: 2}, {LayerID: "cly_2", Quantity: 3}}, value: money.New(680, "BRL")},
+		},
+		{
+			description: "values the units at the fallback when there are no layers",
+			layers:      []CostLayer{},
+			quantity:    2,
+			expected:    Expected{consumed: []LayerConsumption{}, value: money.New(100, "BRL")},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			consumed, value := ConsumeLayers(tc.layers, tc.quantity, money.New(50, "BRL"))
+			assert.Equal(t, tc.expected, Expected{consumed, value})
+		})
+	}
+}
+
+func TestValueOfUnits(t *testing.T) {
+	value := money.New(100, "BRL")
+
+	// 100 minor units dispatched over 3 units arrive whole whichever way the receipts are split.
+	assert.Equal(t, value, ValueOfUnits(value, 3, 0, 3))
+	assert.Equal(t, int64(100), ValueOfUnits(value, 3, 0, 1).Amount+ValueOfUnits(value, 3, 1, 1).Amount+ValueOfUnits(value, 3, 2, 1).Amount)
+	assert.Equal(t, int64(100), ValueOfUnits(value, 3, 0, 2).Amount+ValueOfUnits(value, 3, 2, 1).Amount)
+	assert.Equal(t, money.New(33, "BRL"), ValueOfUnits(value, 3, 0, 1))
+
+	assert.Equal(t, money.New(0, "BRL"), ValueOfUnits(value, 0, 0, 1))
+	assert.Equal(t, money.New(0, "BRL"), ValueOfUnits(value, 3, 3, 1))
+}
+
+func TestAllocateValue(t *testing.T) {
+	movements := []*Movement{{Quantity: 1}, {Quantity: 2}}
+	AllocateValue(movements, money.New(100, "BRL"))
+
+	assert.Equal(t, money.New(33, "BRL"), *movements[0].Value)
+	assert.Equal(t, money.New(67, "BRL"), *movements[1].Value)
+}
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+
+	"github.com/heiytor/invenda/api/pkg/money"
+)
+
+// Variant represents a sellable combination of a product's options, e.g. a "Size M, Colour Blue"
+// t-shirt.
+type Variant struct {
+	ID          string    `json:"id" bson:"_id"`
+	NamespaceID string    `json:"namespace_id" bson:"namespace_id"`
+	ProductID   string    `json:"product_id" bson:"product_id"`
+	CreatedAt   time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" bson:"updated_at"`
+	SKU         string    `json:"sku" bson:"sku"`
+	Barcode     string    `json:"barcode,omitempty" bson:"barcode,omitempty"`
+
+	// Attributes maps each of the product's option names to the variant's value.
+	Attributes map[string]string `json:"attributes" bson:"attributes"`
+
+	// Price overrides the product's price when set.
+	Price *money.Money `json:"price,omitempty" bson:"price,omitempty"`
+}
+
+// EffectivePrice returns the variant's price or the product's price when the variant does not
+// override it.
+func (v *Variant) EffectivePrice(p *Product) money.Money {
+	if v.Price != nil {
+		return *v.Price
+	}
+
+	return p.Price
+}
+
+type VariantChanges struct {
+	UpdatedAt  time.Time         `bson:"updated_at"`
+	SKU        string            `bson:"sku,omitempty"`
+	Barcode    *string           `bson:"barcode,omitempty"`
+	Attributes map[string]string `bson:"attributes,omitempty"`
+	Price      *money.Money      `bson:"price,omitempty"`
+}
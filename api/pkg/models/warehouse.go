@@ -0,0 +1,65 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Warehouse represents a physical place where the namespace's stock is kept.
+type Warehouse struct {
+	ID          string     `json:"id" bson:"_id"`
+	NamespaceID string     `json:"namespace_id" bson:"namespace_id"`
+	CreatedAt   time.Time  `json:"created_at" bson:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at" bson:"updated_at"`
+	Code        string     `json:"code" bson:"code"`
+	Name        string     `json:"name" bson:"name"`
+	Address     string     `json:"address" bson:"address"`
+	Active      bool       `json:"active" bson:"active"`
+	Locations   []Location `json:"locations" bson:"locations"`
+
+	// Quarantine is the code of the location where damaged goods returned by customers are kept apart
+	// from the sellable stock. Damaged goods cannot be returned to warehouses without one.
+	Quarantine string `json:"quarantine" bson:"quarantine"`
+}
+
+// Location represents a place within a warehouse, such as an aisle, a shelf or a bin.
+type Location struct {
+	Code string `json:"code" bson:"code" validate:"required"`
+	Name string `json:"name" bson:"name"`
+}
+
+// HasLocation reports whether the warehouse has a location with the specified code. An empty code
+// refers to the warehouse itself and is always valid.
+func (w *Warehouse) HasLocation(code string) bool {
+	return code == "" || slices.ContainsFunc(w.Locations, func(l Location) bool { return l.Code == code })
+}
+
+// CheckLocations reports whether the locations have non-empty and unique codes.
+func CheckLocations(locations []Location) error {
+	seen := make(map[string]bool, len(locations))
+	for _, l := range locations {
+		if l.Code == "" {
+			return errors.New("location code cannot be empty")
+		}
+
+		if seen[l.Code] {
+			return fmt.Errorf("location %q is duplicated", l.Code)
+		}
+
+		seen[l.Code] = true
+	}
+
+	return nil
+}
+
+type WarehouseChanges struct {
+	UpdatedAt  time.Time  `bson:"updated_at"`
+	Code       string     `bson:"code,omitempty"`
+	Name       string     `bson:"name,omitempty"`
+	Address    *string    `bson:"address,omitempty"`
+	Active     *bool      `bson:"active,omitempty"`
+	Locations  []Location `bson:"locations,omitempty"`
+	Quarantine *string    `bson:"quarantine,omitempty"`
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckLocations(t *testing.T) {
+	cases := []struct {
+		description string
+		locations   []Location
+		expected    string
+	}{
+		{
+			description: "succeeds when there are no locations",
+			locations:   []Location{},
+			expected:    "",
+		},
+		{
+			description: "fails when a code is empty",
+			locations:   []Location{{Code: "", Name: "Aisle 1"}},
+			expected:    "location code cannot be empty",
+		},
+		{
+			description: "fails when a code is duplicated",
+			locations:   []Location{{Code: "A1"}, {Code: "A1"}},
+			expected:    `location "A1" is duplicated`,
+		},
+		{
+			description: "succeeds when codes are unique",
+			locations:   []Location{{Code: "A1"}, {Code: "A2"}},
+			expected:    "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			err := CheckLocations(tc.locations)
+			if tc.expected == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.EqualError(t, err, tc.expected)
+		})
+	}
+}
+
+func TestWarehouseHasLocation(t *testing.T) {
+	wh := &Warehouse{Locations: []Location{{Code: "A1"}}}
+
+	assert.True(t, wh.HasLocation(""))
+	assert.True(t, wh.HasLocation("A1"))
+	assert.False(t, wh.HasLocation("B1"))
+}
@@ -0,0 +1,73 @@
+package money
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+// document is how amounts are encoded, in JSON and in BSON.
+type document struct {
+	Amount   int64  `json:"amount" bson:"amount"`
+	Currency string `json:"currency" bson:"currency"`
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(document{Amount: m.Amount, Currency: string(m.Currency)})
+}
+
+// UnmarshalJSON decodes an object with the amount in minor units and the currency's code, which is
+// matched regardless of its case. Amounts without a currency are left for validation to report.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var doc document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+
+	return m.from(doc)
+}
+
+func (m Money) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	return bson.MarshalValue(document{Amount: m.Amount, Currency: string(m.Currency)})
+}
+
+// UnmarshalBSONValue decodes an embedded document with the amount and the currency.
+func (m *Money) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	raw := bson.RawValue{Type: t, Value: data}
+
+	switch t {
+	case bson.TypeNull, bson.TypeUndefined:
+		*m = Money{}
+
+		return nil
+	case bson.TypeEmbeddedDocument:
+		var doc document
+		if err := raw.Unmarshal(&doc); err != nil {
+			return err
+		}
+
+		return m.from(doc)
+	default:
+		return fmt.Errorf("cannot decode %s into an amount of money", t)
+	}
+}
+
+// from sets the amount from its encoded document, reporting currencies that are not valid.
+func (m *Money) from(doc document) error {
+	if doc.Currency == "" {
+		*m = Money{Amount: doc.Amount}
+
+		return nil
+	}
+
+	currency, err := ParseCurrency(doc.Currency)
+	if err != nil {
+		return err
+	}
+
+	*m = Money{Amount: doc.Amount, Currency: currency}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package money
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestMoneyJSON(t *testing.T) {
+	data, err := json.Marshal(New(1234, "BRL"))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"amount": 1234, "currency": "BRL"}`, string(data))
+
+	m := new(Money)
+	assert.NoError(t, json.Unmarshal([]byte(`{"amount": -50, "currency": "usd"}`), m))
+	assert.Equal(t, New(-50, "USD"), *m)
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"amount": 50}`), m))
+	assert.Equal(t, Money{Amount: 50}, *m)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"amount": 50, "currency": "XXX"}`), m))
+	assert.Error(t, json.Unmarshal([]byte(`{"amount": 0.5, "currency": "USD"}`), m))
+}
+
+func TestMoneyBSON(t *testing.T) {
+	type doc struct {
+		Price Money  `bson:"price"`
+		Cost  *Money `bson:"cost,omitempty"`
+		Tax   *Money `bson:"tax,omitempty"`
+	}
+
+	data, err := bson.Marshal(doc{Price: New(1234, "BRL"), Tax: &Money{Currency: "BRL"}})
+	assert.NoError(t, err)
+
+	raw := bson.Raw(data)
+	assert.Equal(t, int64(1234), raw.Lookup("price", "amount").Int64())
+	assert.Equal(t, "BRL", raw.Lookup("price", "currency").StringValue())
+
+	_, err = raw.LookupErr("cost")
+	assert.Error(t, err)
+
+	// Zero amounts are kept: only nil ones are omitted.
+	assert.Equal(t, int64(0), raw.Lookup("tax", "amount").Int64())
+	assert.Equal(t, "BRL", raw.Lookup("tax", "currency").StringValue())
+
+	decoded := new(doc)
+	assert.NoError(t, bson.Unmarshal(data, decoded))
+	assert.Equal(t, doc{Price: New(1234, "BRL"), Tax: &Money{Currency: "BRL"}}, *decoded)
+
+	data, err = bson.Marshal(bson.M{"price": "12.34"})
+	assert.NoError(t, err)
+	assert.Error(t, bson.Unmarshal(data, decoded))
+}
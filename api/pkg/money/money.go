@@ -0,0 +1,229 @@
+// Package money represents monetary amounts exactly, as an integer number of a currency's minor units
+// (e.g. cents), so arithmetic on them never loses or creates a fraction of a unit.
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrCurrencyMismatch is returned when operating on amounts of different currencies.
+	ErrCurrencyMismatch = errors.New("amounts are of different currencies")
+	// ErrInvalidRatios is returned when allocating by ratios that are negative or sum to zero.
+	ErrInvalidRatios = errors.New("ratios must not be negative and must not sum to zero")
+)
+
+// Money is an amount of a currency, in the currency's minor unit.
+type Money struct {
+	Amount   int64    `json:"amount" bson:"amount"`
+	Currency Currency `json:"currency" bson:"currency"`
+}
+
+// New returns an amount of minor units of the currency.
+func New(amount int64, currency Currency) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+// Zero returns no amount of the currency.
+func Zero(currency Currency) Money {
+	return Money{Currency: currency}
+}
+
+// Parse parses a decimal amount of the currency, such as "-12.34", into minor units. The amount cannot
+// have more decimal places than the currency's minor unit.
+func Parse(amount string, currency Currency) (Money, error) {
+	digits := currency.Digits()
+
+	s := strings.TrimSpace(amount)
+	negative := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "-"), "+")
+
+	whole, fraction, _ := strings.Cut(s, ".")
+	if whole == "" || len(fraction) > digits || strings.ContainsAny(whole+fraction, "+-") {
+		return Money{}, fmt.Errorf("amount %q is not a valid amount of %s", amount, currency)
+	}
+
+	units, err := strconv.ParseInt(whole+fraction+strings.Repeat("0", digits-len(fraction)), 10, 64)
+	if err != nil {
+		return Money{}, fmt.Errorf("amount %q is not a valid amount of %s", amount, currency)
+	}
+
+	if negative {
+		units = -units
+	}
+
+	return Money{Amount: units, Currency: currency}, nil
+}
+
+// IsZeroAmount reports whether the amount is zero. It is not named IsZero, which BSON would use to
+// omit zero amounts from the fields tagged omitempty, so that amounts can be set to zero.
+func (m Money) IsZeroAmount() bool {
+	return m.Amount == 0
+}
+
+// IsNegative reports whether the amount is below zero.
+func (m Money) IsNegative() bool {
+	return m.Amount < 0
+}
+
+// SameCurrency reports whether the amounts are of the same currency.
+func (m Money) SameCurrency(o Money) bool {
+	return m.Currency == o.Currency
+}
+
+// Add returns the sum of the amounts. It returns [ErrCurrencyMismatch] when their currencies differ.
+func (m Money) Add(o Money) (Money, error) {
+	if !m.SameCurrency(o) {
+		return Money{}, ErrCurrencyMismatch
+	}
+
+	return Money{Amount: m.Amount + o.Amount, Currency: m.Currency}, nil
+}
+
+// Sub returns the difference of the amounts. It returns [ErrCurrencyMismatch] when their currencies
+// differ.
+func (m Money) Sub(o Money) (Money, error) {
+	if !m.SameCurrency(o) {
+		return Money{}, ErrCurrencyMismatch
+	}
+
+	return Money{Amount: m.Amount - o.Amount, Currency: m.Currency}, nil
+}
+
+// Cmp compares the amounts, returning -1, 0 or 1 as m is less than, equal to or greater than o. It
+// returns [ErrCurrencyMismatch] when their currencies differ.
+func (m Money) Cmp(o Money) (int, error) {
+	if !m.SameCurrency(o) {
+		return 0, ErrCurrencyMismatch
+	}
+
+	switch {
+	case m.Amount < o.Amount:
+		return -1, nil
+	case m.Amount > o.Amount:
+		return 1, nil
+	default:
+		return 0, nil
+	}
+}
+
+// Neg returns the amount with its sign inverted.
+func (m Money) Neg() Money {
+	return Money{Amount: -m.Amount, Currency: m.Currency}
+}
+
+// Mul returns the amount multiplied by a quantity, such as the total of units at a unit price.
+func (m Money) Mul(quantity int64) Money {
+	return Money{Amount: m.Amount * quantity, Currency: m.Currency}
+}
+
+// Scale returns the amount multiplied by a factor, such as a rate or a percentage, rounded to the minor
+// unit with the rounding mode.
+func (m Money) Scale(factor *big.Rat, mode Rounding) Money {
+	x := new(big.Rat).Mul(new(big.Rat).SetInt64(m.Amount), factor)
+
+	return Money{Amount: Round(x, mode), Currency: m.Currency}
+}
+
+// Allocate splits the amount in shares proportional to the ratios without losing minor units: the
+// shares always add up to the amount. The units left by rounding the shares down are given one by one to
+// the shares with the largest remainders, the first ones winning ties. It returns [ErrInvalidRatios]
+// when a ratio is negative or they sum to zero.
+func (m Money) Allocate(ratios ...int64) ([]Money, error) {
+	total := new(big.Int)
+	for _, r := range ratios {
+		if r < 0 {
+			return nil, ErrInvalidRatios
+		}
+
+		total.Add(total, big.NewInt(r))
+	}
+
+	if total.Sign() == 0 {
+		return nil, ErrInvalidRatios
+	}
+
+	// The magnitude is allocated and the sign restored afterwards, so negative amounts are allocated
+	// as their positive counterparts are.
+	sign := int64(1)
+	if m.Amount < 0 {
+		sign = -1
+	}
+
+	amount := new(big.Int).Abs(big.NewInt(m.Amount))
+	shares := make([]Money, len(ratios))
+	remainders := make([]*big.Int, len(ratios))
+
+	left := new(big.Int).Set(amount)
+	for i, r := range ratios {
+		share, rem := new(big.Int).QuoRem(new(big.Int).Mul(amount, big.NewInt(r)), total, new(big.Int))
+
+		shares[i] = Money{Amount: share.Int64(), Currency: m.Currency}
+		remainders[i] = rem
+		left.Sub(left, share)
+	}
+
+	// Fewer units are left than there are shares, as each share lost less than one unit.
+	for n := left.Int64(); n > 0; n-- {
+		largest := 0
+		for i := range remainders {
+			if remainders[i].Cmp(remainders[largest]) > 0 {
+				largest = i
+			}
+		}
+
+		shares[largest].Amount++
+		remainders[largest] = big.NewInt(-1)
+	}
+
+	for i := range shares {
+		shares[i].Amount *= sign
+	}
+
+	return shares, nil
+}
+
+// Split splits the amount in n shares as even as possible, as in [Money.Allocate]. It returns
+// [ErrInvalidRatios] when n is not positive.
+func (m Money) Split(n int) ([]Money, error) {
+	if n < 1 {
+		return nil, ErrInvalidRatios
+	}
+
+	ratios := make([]int64, n)
+	for i := range ratios {
+		ratios[i] = 1
+	}
+
+	return m.Allocate(ratios...)
+}
+
+// Decimal returns the amount as a decimal in the currency's major unit, e.g. "-12.34".
+func (m Money) Decimal() string {
+	digits := m.Currency.Digits()
+
+	units := strconv.FormatInt(m.Amount, 10)
+	sign := ""
+	if m.Amount < 0 {
+		sign, units = "-", units[1:]
+	}
+
+	if digits == 0 {
+		return sign + units
+	}
+
+	if len(units) <= digits {
+		units = strings.Repeat("0", digits-len(units)+1) + units
+	}
+
+	return sign + units[:len(units)-digits] + "." + units[len(units)-digits:]
+}
+
+// String returns the amount followed by its currency, e.g. "12.34 BRL".
+func (m Money) String() string {
+	return m.Decimal() + " " + string(m.Currency)
+}
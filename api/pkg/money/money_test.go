@@ -0,0 +1,160 @@
+package money
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		amount   string
+		currency Currency
+		expected int64
+		err      bool
+	}{
+		{amount: "12.34", currency: "BRL", expected: 1234},
+		{amount: "-12.3", currency: "BRL", expected: -1230},
+		{amount: "+7", currency: "USD", expected: 700},
+		{amount: "0.05", currency: "USD", expected: 5},
+		{amount: "1500", currency: "JPY", expected: 1500},
+		{amount: "1.234", currency: "KWD", expected: 1234},
+		{amount: "1.234", currency: "USD", err: true},
+		{amount: "1.5", currency: "JPY", err: true},
+		{amount: ".5", currency: "USD", err: true},
+		{amount: "--1", currency: "USD", err: true},
+		{amount: "1,00", currency: "USD", err: true},
+		{amount: "", currency: "USD", err: true},
+	}
+
+	for _, tc := range cases {
+		m, err := Parse(tc.amount, tc.currency)
+		if tc.err {
+			assert.Error(t, err, tc.amount)
+
+			continue
+		}
+
+		assert.NoError(t, err, tc.amount)
+		assert.Equal(t, New(tc.expected, tc.currency), m, tc.amount)
+	}
+}
+
+func TestMoneyDecimal(t *testing.T) {
+	assert.Equal(t, "12.34 BRL", New(1234, "BRL").String())
+	assert.Equal(t, "-0.05", New(-5, "USD").Decimal())
+	assert.Equal(t, "0.00", New(0, "USD").Decimal())
+	assert.Equal(t, "1500", New(1500, "JPY").Decimal())
+	assert.Equal(t, "0.001", New(1, "KWD").Decimal())
+}
+
+func TestMoneyArithmetic(t *testing.T) {
+	a, b := New(1000, "BRL"), New(250, "BRL")
+
+	sum, err := a.Add(b)
+	assert.NoError(t, err)
+	assert.Equal(t, New(1250, "BRL"), sum)
+
+	diff, err := b.Sub(a)
+	assert.NoError(t, err)
+	assert.Equal(t, New(-750, "BRL"), diff)
+	assert.True(t, diff.IsNegative())
+
+	cmp, err := a.Cmp(b)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, cmp)
+
+	assert.Equal(t, New(3000, "BRL"), a.Mul(3))
+	assert.Equal(t, New(-1000, "BRL"), a.Neg())
+
+	_, err = a.Add(New(1, "USD"))
+	assert.ErrorIs(t, err, ErrCurrencyMismatch)
+
+	_, err = a.Cmp(New(1, "USD"))
+	assert.ErrorIs(t, err, ErrCurrencyMismatch)
+}
+
+func TestMoneyScale(t *testing.T) {
+	m := New(1005, "USD")
+
+	assert.Equal(t, New(101, "USD"), m.Scale(big.NewRat(1, 10), RoundHalfUp))
+	assert.Equal(t, New(100, "USD"), m.Scale(big.NewRat(1, 10), RoundHalfEven))
+	assert.Equal(t, New(100, "USD"), m.Scale(big.NewRat(1, 10), RoundDown))
+	assert.Equal(t, New(-101, "USD"), m.Neg().Scale(big.NewRat(1, 10), RoundHalfUp))
+}
+
+func TestRound(t *testing.T) {
+	cases := []struct {
+		x        *big.Rat
+		mode     Rounding
+		expected int64
+	}{
+		{x: big.NewRat(5, 2), mode: RoundHalfUp, expected: 3},
+		{x: big.NewRat(-5, 2), mode: RoundHalfUp, expected: -3},
+		{x: big.NewRat(5, 2), mode: RoundHalfEven, expected: 2},
+		{x: big.NewRat(7, 2), mode: RoundHalfEven, expected: 4},
+		{x: big.NewRat(-7, 2), mode: RoundHalfEven, expected: -4},
+		{x: big.NewRat(5, 2), mode: RoundHalfDown, expected: 2},
+		{x: big.NewRat(26, 10), mode: RoundHalfDown, expected: 3},
+		{x: big.NewRat(21, 10), mode: RoundUp, expected: 3},
+		{x: big.NewRat(-21, 10), mode: RoundUp, expected: -3},
+		{x: big.NewRat(29, 10), mode: RoundDown, expected: 2},
+		{x: big.NewRat(-29, 10), mode: RoundDown, expected: -2},
+		{x: big.NewRat(-21, 10), mode: RoundCeiling, expected: -2},
+		{x: big.NewRat(21, 10), mode: RoundCeiling, expected: 3},
+		{x: big.NewRat(-21, 10), mode: RoundFloor, expected: -3},
+		{x: big.NewRat(29, 10), mode: RoundFloor, expected: 2},
+		{x: big.NewRat(4, 1), mode: RoundUp, expected: 4},
+	}
+
+	for _, tc := range cases {
+		assert.Equal(t, tc.expected, Round(tc.x, tc.mode), "%s %s", tc.x, tc.mode)
+	}
+}
+
+func TestMoneyAllocate(t *testing.T) {
+	shares, err := New(100, "BRL").Allocate(1, 1, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []Money{New(34, "BRL"), New(33, "BRL"), New(33, "BRL")}, shares)
+
+	// The unit left goes to the largest remainder: 5*70/100 = 3.5 and 5*30/100 = 1.5 tie, so the first
+	// share wins.
+	shares, err = New(5, "USD").Allocate(70, 30)
+	assert.NoError(t, err)
+	assert.Equal(t, []Money{New(4, "USD"), New(1, "USD")}, shares)
+
+	shares, err = New(-100, "BRL").Allocate(1, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []Money{New(-33, "BRL"), New(-67, "BRL")}, shares)
+
+	shares, err = New(100, "BRL").Allocate(0, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []Money{New(0, "BRL"), New(100, "BRL")}, shares)
+
+	_, err = New(100, "BRL").Allocate(0, 0)
+	assert.ErrorIs(t, err, ErrInvalidRatios)
+
+	_, err = New(100, "BRL").Allocate(1, -1)
+	assert.ErrorIs(t, err, ErrInvalidRatios)
+}
+
+func TestMoneySplit(t *testing.T) {
+	shares, err := New(1001, "BRL").Split(4)
+	assert.NoError(t, err)
+	assert.Equal(t, []Money{New(251, "BRL"), New(250, "BRL"), New(250, "BRL"), New(250, "BRL")}, shares)
+
+	_, err = New(1001, "BRL").Split(0)
+	assert.ErrorIs(t, err, ErrInvalidRatios)
+}
+
+func TestParseCurrency(t *testing.T) {
+	c, err := ParseCurrency(" brl ")
+	assert.NoError(t, err)
+	assert.Equal(t, Currency("BRL"), c)
+	assert.Equal(t, 0, Currency("JPY").Digits())
+	assert.Equal(t, 3, Currency("BHD").Digits())
+
+	_, err = ParseCurrency("XXX")
+	assert.EqualError(t, err, `currency "XXX" is not a valid ISO 4217 code`)
+}
@@ -0,0 +1,93 @@
+package money
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// maxRateDecimals is the number of decimal places kept by exchange rates.
+const maxRateDecimals = 12
+
+// Rate is an exchange rate: the units of a currency that one unit of another is worth. It is kept as the
+// exact decimal it was quoted with, such as "5.1234", so amounts convert without floating point errors.
+// The empty rate is a rate of 1.
+type Rate string
+
+// ParseRate parses a positive decimal exchange rate with at most 12 decimal places.
+func ParseRate(rate string) (Rate, error) {
+	s := strings.TrimSpace(rate)
+
+	whole, fraction, _ := strings.Cut(s, ".")
+	if whole == "" || len(fraction) > maxRateDecimals || strings.Trim(whole+fraction, "0123456789") != "" {
+		return "", fmt.Errorf("rate %q is not a positive decimal with at most %d decimal places", rate, maxRateDecimals)
+	}
+
+	r := Rate(s)
+	if r.Rat().Sign() <= 0 {
+		return "", fmt.Errorf("rate %q is not a positive decimal with at most %d decimal places", rate, maxRateDecimals)
+	}
+
+	return r, nil
+}
+
+// Rat returns the rate as a rational. The empty rate and rates that are not valid decimals are 1.
+func (r Rate) Rat() *big.Rat {
+	if x, ok := new(big.Rat).SetString(string(r)); ok && r != "" {
+		return x
+	}
+
+	return big.NewRat(1, 1)
+}
+
+// UnmarshalJSON decodes a rate quoted either as a string or as a number, keeping the number's exact
+// decimal digits.
+func (r *Rate) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if strings.HasPrefix(s, `"`) {
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+	}
+
+	if s == "" || s == "null" {
+		*r = ""
+
+		return nil
+	}
+
+	rate, err := ParseRate(s)
+	if err != nil {
+		return err
+	}
+
+	*r = rate
+
+	return nil
+}
+
+// Convert converts the amount to another currency at the rate, the units of to that one unit of the
+// amount's currency is worth, rounding to the minor unit of to with the rounding mode.
+func (m Money) Convert(to Currency, rate *big.Rat, mode Rounding) Money {
+	x := new(big.Rat).Mul(new(big.Rat).SetInt64(m.Amount), rate)
+
+	// The amounts are in minor units, so the difference between the currencies' digits scales them too.
+	exp := to.Digits() - m.Currency.Digits()
+	scale := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(abs(exp))), nil))
+	if exp < 0 {
+		scale.Inv(scale)
+	}
+
+	x.Mul(x, scale)
+
+	return Money{Amount: Round(x, mode), Currency: to}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}
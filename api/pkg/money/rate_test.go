@@ -0,0 +1,67 @@
+package money
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRate(t *testing.T) {
+	cases := []struct {
+		rate     string
+		expected Rate
+		err      bool
+	}{
+		{rate: "5.1234", expected: "5.1234"},
+		{rate: " 0.0064 ", expected: "0.0064"},
+		{rate: "2", expected: "2"},
+		{rate: "0.000000000001", expected: "0.000000000001"},
+		{rate: "0.0000000000001", err: true},
+		{rate: "0", err: true},
+		{rate: "-1.5", err: true},
+		{rate: ".5", err: true},
+		{rate: "1e3", err: true},
+		{rate: "1,5", err: true},
+		{rate: "", err: true},
+	}
+
+	for _, tc := range cases {
+		r, err := ParseRate(tc.rate)
+		if tc.err {
+			assert.Error(t, err, tc.rate)
+
+			continue
+		}
+
+		assert.NoError(t, err, tc.rate)
+		assert.Equal(t, tc.expected, r, tc.rate)
+	}
+}
+
+func TestRateJSON(t *testing.T) {
+	var v struct {
+		Number Rate `json:"number"`
+		String Rate `json:"string"`
+	}
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"number": 5.10, "string": "0.1"}`), &v))
+	assert.Equal(t, Rate("5.10"), v.Number)
+	assert.Equal(t, Rate("0.1"), v.String)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"number": -1}`), &v))
+	assert.Equal(t, big.NewRat(1, 1), Rate("").Rat())
+}
+
+func TestMoneyConvert(t *testing.T) {
+	usd := New(1000, "USD")
+
+	assert.Equal(t, New(5123, "BRL"), usd.Convert("BRL", Rate("5.1234").Rat(), RoundHalfUp))
+	assert.Equal(t, New(5124, "BRL"), usd.Convert("BRL", Rate("5.1234").Rat(), RoundUp))
+
+	// The difference between the currencies' minor units scales the amount.
+	assert.Equal(t, New(1500, "JPY"), usd.Convert("JPY", Rate("150").Rat(), RoundHalfUp))
+	assert.Equal(t, New(1000, "USD"), New(1500, "JPY").Convert("USD", new(big.Rat).Inv(Rate("150").Rat()), RoundHalfUp))
+	assert.Equal(t, New(3070, "KWD"), usd.Convert("KWD", Rate("0.307").Rat(), RoundHalfUp))
+}
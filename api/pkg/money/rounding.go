@@ -0,0 +1,74 @@
+package money
+
+import (
+	"math/big"
+	"slices"
+)
+
+// Rounding defines how an amount that falls between two minor units is rounded.
+type Rounding string
+
+const (
+	// RoundHalfUp rounds to the nearest minor unit, and halves away from zero.
+	RoundHalfUp Rounding = "half_up"
+	// RoundHalfEven rounds to the nearest minor unit, and halves to the even one. It is also known as
+	// banker's rounding.
+	RoundHalfEven Rounding = "half_even"
+	// RoundHalfDown rounds to the nearest minor unit, and halves towards zero.
+	RoundHalfDown Rounding = "half_down"
+	// RoundUp rounds away from zero.
+	RoundUp Rounding = "up"
+	// RoundDown rounds towards zero, truncating the amount.
+	RoundDown Rounding = "down"
+	// RoundCeiling rounds towards positive infinity.
+	RoundCeiling Rounding = "ceiling"
+	// RoundFloor rounds towards negative infinity.
+	RoundFloor Rounding = "floor"
+)
+
+// Roundings lists the rounding modes.
+var Roundings = []Rounding{RoundHalfUp, RoundHalfEven, RoundHalfDown, RoundUp, RoundDown, RoundCeiling, RoundFloor}
+
+// Valid reports whether the rounding is one of [Roundings].
+func (r Rounding) Valid() bool {
+	return slices.Contains(Roundings, r)
+}
+
+// Round rounds the rational to an integer with the rounding mode. Unknown modes round half up.
+func Round(x *big.Rat, mode Rounding) int64 {
+	// quo is truncated towards zero and rem takes the sign of x.
+	quo, rem := new(big.Int).QuoRem(x.Num(), x.Denom(), new(big.Int))
+	if rem.Sign() == 0 {
+		return quo.Int64()
+	}
+
+	sign := int64(x.Sign())
+
+	// half compares twice the remainder's magnitude to the denominator: -1 below a half, 0 at a half and
+	// 1 above it.
+	half := new(big.Int).Mul(new(big.Int).Abs(rem), big.NewInt(2)).Cmp(x.Denom())
+
+	away := false
+	switch mode {
+	case RoundUp:
+		away = true
+	case RoundDown:
+		away = false
+	case RoundCeiling:
+		away = sign > 0
+	case RoundFloor:
+		away = sign < 0
+	case RoundHalfDown:
+		away = half > 0
+	case RoundHalfEven:
+		away = half > 0 || (half == 0 && quo.Bit(0) == 1)
+	default:
+		away = half >= 0
+	}
+
+	if away {
+		return quo.Int64() + sign
+	}
+
+	return quo.Int64()
+}
@@ -0,0 +1,38 @@
+package query
+
+// Kind represents the type of the values that a field holds.
+type Kind int
+
+const (
+	KindString Kind = iota + 1
+	KindNumber
+	KindTime
+	KindBool
+)
+
+// Field describes how clients are allowed to use an entity's attribute in a query.
+type Field struct {
+	Kind       Kind // Kind represents the type of the field's values.
+	Sortable   bool // Sortable reports whether the field can be used in [Sorter.By].
+	Filterable bool // Filterable reports whether the field can be used in [Filter] conditions.
+}
+
+// Fields is an allow-list of the attributes that clients can sort and filter by. The key is the
+// attribute name as stored in the database.
+type Fields map[string]Field
+
+// operators returns the operators that can be applied to a field of kind k.
+func (k Kind) operators() []Operator {
+	switch k {
+	case KindString:
+		return []Operator{OperatorEq, OperatorNe, OperatorContains, OperatorIn}
+	case KindNumber:
+		return []Operator{OperatorEq, OperatorNe, OperatorGt, OperatorGte, OperatorLt, OperatorLte, OperatorIn}
+	case KindTime:
+		return []Operator{OperatorEq, OperatorGt, OperatorGte, OperatorLt, OperatorLte}
+	case KindBool:
+		return []Operator{OperatorEq, OperatorNe}
+	default:
+		return []Operator{}
+	}
+}
@@ -0,0 +1,133 @@
+package query
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Operator string
+
+const (
+	OperatorEq       Operator = "eq"
+	OperatorNe       Operator = "ne"
+	OperatorGt       Operator = "gt"
+	OperatorGte      Operator = "gte"
+	OperatorLt       Operator = "lt"
+	OperatorLte      Operator = "lte"
+	OperatorContains Operator = "contains"
+	OperatorIn       Operator = "in"
+)
+
+const (
+	conditionSeparator = ","
+	partSeparator      = ":"
+	valueSeparator     = "|"
+)
+
+// Condition represents a single parsed filter expression.
+type Condition struct {
+	Field    string
+	Operator Operator
+	// Value holds the condition's value converted to the field's [Kind]. When the operator is
+	// [OperatorIn], Value is a slice with each converted value.
+	Value interface{}
+}
+
+// Filter represents the filter conditions in a query. Conditions are written as `field:operator:value`
+// and separated by commas, e.g. `name:contains:acme,created_at:gte:2024-01-01`. Values of the `in`
+// operator are separated by pipes, e.g. `status:in:open|closed`.
+type Filter struct {
+	Raw        string      `query:"filter"`
+	Conditions []Condition `json:"-"`
+}
+
+// Parse parses [Filter.Raw] into [Filter.Conditions], ensuring that every field is filterable
+// according to fields and that the operators and values match the field's kind.
+func (f *Filter) Parse(fields Fields) error {
+	f.Conditions = make([]Condition, 0)
+	if strings.TrimSpace(f.Raw) == "" {
+		return nil
+	}
+
+	for _, expr := range strings.Split(f.Raw, conditionSeparator) {
+		parts := strings.SplitN(strings.TrimSpace(expr), partSeparator, 3)
+		if len(parts) != 3 {
+			return fmt.Errorf("invalid condition %q: must be written as field:operator:value", expr)
+		}
+
+		name, op, raw := parts[0], Operator(parts[1]), parts[2]
+
+		field, ok := fields[name]
+		if !ok || !field.Filterable {
+			return fmt.Errorf("field %q is not filterable", name)
+		}
+
+		if !slices.Contains(field.Kind.operators(), op) {
+			return fmt.Errorf("operator %q cannot be applied to field %q", op, name)
+		}
+
+		var value interface{}
+		if op == OperatorIn {
+			values := make([]interface{}, 0)
+			for _, r := range strings.Split(raw, valueSeparator) {
+				v, err := convert(field.Kind, r)
+				if err != nil {
+					return fmt.Errorf("invalid value for field %q: %w", name, err)
+				}
+
+				values = append(values, v)
+			}
+
+			value = values
+		} else {
+			v, err := convert(field.Kind, raw)
+			if err != nil {
+				return fmt.Errorf("invalid value for field %q: %w", name, err)
+			}
+
+			value = v
+		}
+
+		f.Conditions = append(f.Conditions, Condition{Field: name, Operator: op, Value: value})
+	}
+
+	return nil
+}
+
+// Get returns the first condition applied to the field with the specified name.
+func (f *Filter) Get(field string) (*Condition, bool) {
+	for i := range f.Conditions {
+		if f.Conditions[i].Field == field {
+			return &f.Conditions[i], true
+		}
+	}
+
+	return nil, false
+}
+
+// convert converts a raw value to the go type that represents the kind k.
+func convert(k Kind, raw string) (interface{}, error) {
+	switch k {
+	case KindNumber:
+		return strconv.ParseFloat(raw, 64)
+	case KindBool:
+		return strconv.ParseBool(raw)
+	case KindTime:
+		for _, layout := range []string{time.RFC3339, time.DateOnly} {
+			if t, err := time.Parse(layout, raw); err == nil {
+				return t.UTC(), nil
+			}
+		}
+
+		return nil, fmt.Errorf("%q is not a RFC3339 or YYYY-MM-DD date", raw)
+	default:
+		if raw == "" {
+			return nil, fmt.Errorf("value cannot be empty")
+		}
+
+		return raw, nil
+	}
+}
@@ -0,0 +1,99 @@
+package query
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterParse(t *testing.T) {
+	fields := Fields{
+		"name":       {Kind: KindString, Sortable: true, Filterable: true},
+		"quantity":   {Kind: KindNumber, Sortable: true, Filterable: true},
+		"active":     {Kind: KindBool, Filterable: true},
+		"created_at": {Kind: KindTime, Sortable: true, Filterable: true},
+		"password":   {Kind: KindString},
+	}
+
+	type Expected struct {
+		conditions []Condition
+		err        error
+	}
+
+	cases := []struct {
+		description string
+		raw         string
+		expected    Expected
+	}{
+		{
+			description: "succeeds with an empty filter",
+			raw:         "",
+			expected:    Expected{conditions: []Condition{}, err: nil},
+		},
+		{
+			description: "fails when the condition is malformed",
+			raw:         "name:acme",
+			expected:    Expected{conditions: nil, err: errors.New(`invalid condition "name:acme": must be written as field:operator:value`)},
+		},
+		{
+			description: "fails when the field is not in the allow-list",
+			raw:         "email:eq:john.doe@test.com",
+			expected:    Expected{conditions: nil, err: errors.New(`field "email" is not filterable`)},
+		},
+		{
+			description: "fails when the field is not filterable",
+			raw:         "password:eq:secret",
+			expected:    Expected{conditions: nil, err: errors.New(`field "password" is not filterable`)},
+		},
+		{
+			description: "fails when the operator does not match the field's kind",
+			raw:         "active:gt:true",
+			expected:    Expected{conditions: nil, err: errors.New(`operator "gt" cannot be applied to field "active"`)},
+		},
+		{
+			description: "fails when the value does not match the field's kind",
+			raw:         "created_at:gte:yesterday",
+			expected:    Expected{conditions: nil, err: errors.New(`invalid value for field "created_at": "yesterday" is not a RFC3339 or YYYY-MM-DD date`)},
+		},
+		{
+			description: "succeeds to parse multiple conditions",
+			raw:         "name:contains:acme,created_at:gte:2024-01-01,quantity:in:1|2,active:eq:true",
+			expected: Expected{
+				conditions: []Condition{
+					{Field: "name", Operator: OperatorContains, Value: "acme"},
+					{Field: "created_at", Operator: OperatorGte, Value: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+					{Field: "quantity", Operator: OperatorIn, Value: []interface{}{float64(1), float64(2)}},
+					{Field: "active", Operator: OperatorEq, Value: true},
+				},
+				err: nil,
+			},
+		},
+		{
+			description: "succeeds to parse values containing the part separator",
+			raw:         "created_at:lt:2024-01-01T10:00:00Z",
+			expected: Expected{
+				conditions: []Condition{
+					{Field: "created_at", Operator: OperatorLt, Value: time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)},
+				},
+				err: nil,
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			f := &Filter{Raw: tc.raw}
+
+			err := f.Parse(fields)
+			if tc.expected.err != nil {
+				assert.EqualError(t, err, tc.expected.err.Error())
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected.conditions, f.Conditions)
+		})
+	}
+}
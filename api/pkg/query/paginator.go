@@ -1,9 +1,10 @@
 package query
 
-import "math"
+import "fmt"
 
 const (
 	MinPage        = 1   // MinPage represents the minimum allowed value for the pagination query's Page parameter.
+	DefaultPage    = 1   // DefaultPage represents the default value for the pagination query's Page parameter.
 	MinPerPage     = 1   // MinPerPage represents the minimum allowed value for the pagination query's Size parameter.
 	DefaultPerPage = 10  // DefaultPerPage represents the default value for the pagination query's Size parameter.
 	MaxPerPage     = 100 // MaxPerPage represents the maximum allowed value for the pagination query's Size parameter.
@@ -11,15 +12,50 @@ const (
 
 // Paginator represents the paginator parameters in a query.
 type Paginator struct {
-	Page uint `query:"page" validate:"min:1"`         // Page represents the current page number.
-	Size uint `query:"size" validate:"min:1|max:100"` // Size represents the number of items per page.
+	Page int `query:"page"` // Page represents the current page number.
+	Size int `query:"size"` // Size represents the number of items per page.
 }
 
-// Normalize ensures valid values for Page and Size in the pagination query.
-// If query.Size is less than one, it is set to `DefaultPerPage`.
-// If query.Page is less than one, it is set to `MinPage`.
-// The maximum allowed value for query.Size is `MaxPerPage`.
+// Normalize sets the default values for Page and Size when they are absent (zero). It does not
+// modify values provided by the client; use [Paginator.Check] to validate them.
 func (p *Paginator) Normalize() {
-	p.Size = uint(math.Max(math.Min(float64(p.Size), float64(MaxPerPage)), float64(DefaultPerPage)))
-	p.Page = uint(math.Max(float64(MinPage), float64(p.Page)))
+	if p.Page == 0 {
+		p.Page = DefaultPage
+	}
+
+	if p.Size == 0 {
+		p.Size = DefaultPerPage
+	}
+}
+
+// Check reports an error when Page is lower than [MinPage] or when Size is not between [MinPerPage]
+// and [MaxPerPage].
+func (p *Paginator) Check() error {
+	if p.Page < MinPage {
+		return fmt.Errorf("page must be greater than or equal to %d", MinPage)
+	}
+
+	if p.Size < MinPerPage || p.Size > MaxPerPage {
+		return fmt.Errorf("size must be between %d and %d", MinPerPage, MaxPerPage)
+	}
+
+	return nil
+}
+
+// Pagination represents the pagination metadata of a list response.
+type Pagination struct {
+	Total   int64 `json:"total"`
+	Page    int   `json:"page"`
+	Size    int   `json:"size"`
+	HasNext bool  `json:"has_next"`
+}
+
+// Pagination returns the pagination metadata of a page with total documents.
+func (p *Paginator) Pagination(total int64) Pagination {
+	return Pagination{
+		Total:   total,
+		Page:    p.Page,
+		Size:    p.Size,
+		HasNext: int64(p.Page)*int64(p.Size) < total,
+	}
 }
@@ -13,19 +13,24 @@ func TestPaginatorNormalize(t *testing.T) {
 		expected    *Paginator
 	}{
 		{
-			description: "set Page to MinParge when Page is lower than 1",
-			paginator:   &Paginator{Page: -2, Size: 100},
+			description: "set Page to DefaultPage when Page is absent",
+			paginator:   &Paginator{Page: 0, Size: 100},
 			expected:    &Paginator{Page: 1, Size: 100},
 		},
 		{
-			description: "set Size to DefaultPerParge when Size is lower than 1",
-			paginator:   &Paginator{Page: 1, Size: -2},
+			description: "set Size to DefaultPerPage when Size is absent",
+			paginator:   &Paginator{Page: 1, Size: 0},
 			expected:    &Paginator{Page: 1, Size: 10},
 		},
 		{
-			description: "set Size to MaxPerParge when Size is greather than 100",
-			paginator:   &Paginator{Page: 1, Size: 101},
-			expected:    &Paginator{Page: 1, Size: 100},
+			description: "keeps Size when Size is lower than DefaultPerPage",
+			paginator:   &Paginator{Page: 1, Size: 5},
+			expected:    &Paginator{Page: 1, Size: 5},
+		},
+		{
+			description: "keeps out of bounds values",
+			paginator:   &Paginator{Page: -2, Size: 101},
+			expected:    &Paginator{Page: -2, Size: 101},
 		},
 		{
 			description: "successfully parse query",
@@ -40,3 +45,76 @@ func TestPaginatorNormalize(t *testing.T) {
 		})
 	}
 }
+
+func TestPaginatorCheck(t *testing.T) {
+	cases := []struct {
+		description string
+		paginator   *Paginator
+		expected    string
+	}{
+		{
+			description: "fails when Page is lower than MinPage",
+			paginator:   &Paginator{Page: -2, Size: 10},
+			expected:    "page must be greater than or equal to 1",
+		},
+		{
+			description: "fails when Size is lower than MinPerPage",
+			paginator:   &Paginator{Page: 1, Size: -2},
+			expected:    "size must be between 1 and 100",
+		},
+		{
+			description: "fails when Size is greater than MaxPerPage",
+			paginator:   &Paginator{Page: 1, Size: 101},
+			expected:    "size must be between 1 and 100",
+		},
+		{
+			description: "succeeds when Page and Size are within bounds",
+			paginator:   &Paginator{Page: 1, Size: 1},
+			expected:    "",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			err := tc.paginator.Check()
+			if tc.expected == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.EqualError(t, err, tc.expected)
+		})
+	}
+}
+
+func TestPaginatorPagination(t *testing.T) {
+	cases := []struct {
+		description string
+		paginator   *Paginator
+		total       int64
+		expected    Pagination
+	}{
+		{
+			description: "has next when there are documents after the page",
+			paginator:   &Paginator{Page: 1, Size: 10},
+			total:       11,
+			expected:    Pagination{Total: 11, Page: 1, Size: 10, HasNext: true},
+		},
+		{
+			description: "does not have next at the last page",
+			paginator:   &Paginator{Page: 2, Size: 10},
+			total:       20,
+			expected:    Pagination{Total: 20, Page: 2, Size: 10, HasNext: false},
+		},
+		{
+			description: "does not have next when there are no documents",
+			paginator:   &Paginator{Page: 1, Size: 10},
+			total:       0,
+			expected:    Pagination{Total: 0, Page: 1, Size: 10, HasNext: false},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.paginator.Pagination(tc.total))
+		})
+	}
+}
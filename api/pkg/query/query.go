@@ -1,13 +1,58 @@
 package query
 
+import (
+	"net/http"
+
+	"github.com/heiytor/invenda/api/pkg/errors"
+)
+
 type Query struct {
 	Paginator
 	Sorter
+	Filter
+
+	// Search represents a free-text search term. Entities without a search index ignore it.
+	Search string `query:"q"`
 }
 
 func New() *Query {
 	return &Query{
 		Paginator: Paginator{},
 		Sorter:    Sorter{},
+		Filter:    Filter{},
+	}
+}
+
+// Resolve checks the pagination bounds and the query against the allow-list of fields, parsing the
+// filter conditions. It must be called after the paginator and the sorter were normalized. It returns
+// an [errors.Error] with code 400 if the query is out of bounds or uses a field that is not allowed.
+func (q *Query) Resolve(fields Fields) error {
+	if err := q.Paginator.Check(); err != nil {
+		return errors.
+			New().
+			Code(http.StatusBadRequest).
+			Layer(errors.LayerPkg).
+			Attr("pagination", []string{err.Error()}).
+			Msg(errors.MsgBadRequest)
 	}
+
+	if err := q.Sorter.Check(fields); err != nil {
+		return errors.
+			New().
+			Code(http.StatusBadRequest).
+			Layer(errors.LayerPkg).
+			Attr("sort", []string{err.Error()}).
+			Msg(errors.MsgBadRequest)
+	}
+
+	if err := q.Filter.Parse(fields); err != nil {
+		return errors.
+			New().
+			Code(http.StatusBadRequest).
+			Layer(errors.LayerPkg).
+			Attr("filter", []string{err.Error()}).
+			Msg(errors.MsgBadRequest)
+	}
+
+	return nil
 }
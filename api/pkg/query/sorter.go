@@ -1,5 +1,7 @@
 package query
 
+import "fmt"
+
 const (
 	OrderAsc  = "asc"
 	OrderDesc = "desc"
@@ -22,3 +24,12 @@ func (s *Sorter) NormalizeWith(by string) {
 		s.Order = OrderDesc
 	}
 }
+
+// Check reports an error when [Sorter.By] is not a sortable field according to fields.
+func (s *Sorter) Check(fields Fields) error {
+	if field, ok := fields[s.By]; !ok || !field.Sortable {
+		return fmt.Errorf("field %q is not sortable", s.By)
+	}
+
+	return nil
+}
@@ -30,3 +30,37 @@ func TestSortNormalize(t *testing.T) {
 		})
 	}
 }
+
+func TestSortCheck(t *testing.T) {
+	fields := Fields{
+		"name":     {Kind: KindString, Sortable: true, Filterable: true},
+		"password": {Kind: KindString},
+	}
+
+	cases := []struct {
+		description string
+		order       *Sorter
+		expected    bool
+	}{
+		{
+			description: "fails when By is not in the allow-list",
+			order:       &Sorter{By: "email", Order: "asc"},
+			expected:    false,
+		},
+		{
+			description: "fails when By is not sortable",
+			order:       &Sorter{By: "password", Order: "asc"},
+			expected:    false,
+		},
+		{
+			description: "succeeds when By is sortable",
+			order:       &Sorter{By: "name", Order: "asc"},
+			expected:    true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.order.Check(fields) == nil)
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package requests
+
+import "github.com/heiytor/invenda/api/pkg/query"
+
+// CategoryFields lists the category attributes that clients can sort and filter by.
+var CategoryFields = query.Fields{
+	"name":       {Kind: query.KindString, Sortable: true, Filterable: true},
+	"parent_id":  {Kind: query.KindString, Filterable: true},
+	"ancestors":  {Kind: query.KindString, Filterable: true},
+	"created_at": {Kind: query.KindTime, Sortable: true, Filterable: true},
+	"updated_at": {Kind: query.KindTime, Sortable: true, Filterable: true},
+}
+
+type ListCategory struct {
+	query.Query
+}
+
+type GetCategory struct {
+	ID string `param:"id" validate:"required|ulid"`
+}
+
+type CreateCategory struct {
+	Name     string `json:"name" validate:"required"`
+	ParentID string `json:"parent_id" validate:"ulid"` // ParentID creates a root category when empty.
+}
+
+type UpdateCategory struct {
+	ID   string `param:"id" validate:"required|ulid"`
+	Name string `json:"name"`
+}
+
+type MoveCategory struct {
+	ID       string `param:"id" validate:"required|ulid"`
+	ParentID string `json:"parent_id" validate:"ulid"` // ParentID moves the category to the root when empty.
+}
+
+type DeleteCategory struct {
+	ID string `param:"id" validate:"required|ulid"`
+}
+
+// CategoryProducts assigns or unassigns products of a category.
+type CategoryProducts struct {
+	ID         string   `param:"id" validate:"required|ulid"`
+	ProductIDs []string `json:"product_ids" validate:"required|min_len:1"`
+}
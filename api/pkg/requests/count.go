@@ -0,0 +1,51 @@
+package requests
+
+import "github.com/heiytor/invenda/api/pkg/query"
+
+// StockCountFields lists the stock count attributes that clients can sort and filter by.
+var StockCountFields = query.Fields{
+	"status":       {Kind: query.KindString, Filterable: true},
+	"warehouse_id": {Kind: query.KindString, Filterable: true},
+	"location":     {Kind: query.KindString, Filterable: true},
+	"created_by":   {Kind: query.KindString, Filterable: true},
+	"created_at":   {Kind: query.KindTime, Sortable: true, Filterable: true},
+	"updated_at":   {Kind: query.KindTime, Sortable: true, Filterable: true},
+}
+
+type ListStockCount struct {
+	query.Query
+}
+
+type GetStockCount struct {
+	ID string `param:"id" validate:"required|ulid"`
+}
+
+// CreateStockCount opens a count of a warehouse, or of one of its locations when Location is not empty.
+type CreateStockCount struct {
+	WarehouseID string `json:"warehouse_id" validate:"required|ulid"`
+	Location    string `json:"location"`
+}
+
+type countedItem struct {
+	Location  string `json:"location"`
+	ProductID string `json:"product_id"`
+	VariantID string `json:"variant_id"`
+	Counted   *int64 `json:"counted"`
+}
+
+type SubmitStockCount struct {
+	ID    string        `param:"id" validate:"required|ulid"`
+	Items []countedItem `json:"items" validate:"required|min_len:1"`
+}
+
+type GetStockCountVariance struct {
+	ID string `param:"id" validate:"required|ulid"`
+}
+
+type ApproveStockCount struct {
+	ID string `param:"id" validate:"required|ulid"`
+}
+
+type CancelStockCount struct {
+	ID string `param:"id" validate:"required|ulid"`
+}
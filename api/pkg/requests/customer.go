@@ -0,0 +1,56 @@
+package requests
+
+import (
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/query"
+)
+
+// CustomerFields lists the customer attributes that clients can sort and filter by.
+var CustomerFields = query.Fields{
+	"name":       {Kind: query.KindString, Sortable: true, Filterable: true},
+	"email":      {Kind: query.KindString, Sortable: true, Filterable: true},
+	"tax_id":     {Kind: query.KindString, Filterable: true},
+	"tags":       {Kind: query.KindString, Filterable: true},
+	"group":      {Kind: query.KindString, Sortable: true, Filterable: true},
+	"created_at": {Kind: query.KindTime, Sortable: true, Filterable: true},
+	"updated_at": {Kind: query.KindTime, Sortable: true, Filterable: true},
+}
+
+type ListCustomer struct {
+	query.Query
+}
+
+type GetCustomer struct {
+	ID string `param:"id" validate:"required|ulid"`
+}
+
+type CreateCustomer struct {
+	Name      string           `json:"name" validate:"required"`
+	Email     string           `json:"email" validate:"email"`
+	Phone     string           `json:"phone"`
+	TaxID     string           `json:"tax_id" validate:"max_len:32"`
+	Addresses []models.Address `json:"addresses"`
+	Notes     string           `json:"notes"`
+	Tags      []string         `json:"tags"`
+	Group     string           `json:"group" validate:"max_len:32"`
+}
+
+type UpdateCustomer struct {
+	ID        string           `param:"id" validate:"required|ulid"`
+	Name      string           `json:"name"`
+	Email     *string          `json:"email" validate:"email"`
+	Phone     *string          `json:"phone"`
+	TaxID     *string          `json:"tax_id" validate:"max_len:32"`
+	Addresses []models.Address `json:"addresses"`
+	Notes     *string          `json:"notes"`
+	Tags      []string         `json:"tags"`
+	Group     *string          `json:"group" validate:"max_len:32"`
+}
+
+type DeleteCustomer struct {
+	ID string `param:"id" validate:"required|ulid"`
+}
+
+type GetCustomerHistory struct {
+	ID string `param:"id" validate:"required|ulid"`
+}
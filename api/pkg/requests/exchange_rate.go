@@ -0,0 +1,33 @@
+package requests
+
+import (
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/query"
+)
+
+// ExchangeRateFields lists the exchange rate attributes that clients can sort and filter by.
+var ExchangeRateFields = query.Fields{
+	"currency":   {Kind: query.KindString, Sortable: true, Filterable: true},
+	"valid_from": {Kind: query.KindTime, Sortable: true, Filterable: true},
+	"created_at": {Kind: query.KindTime, Sortable: true, Filterable: true},
+	"updated_at": {Kind: query.KindTime, Sortable: true, Filterable: true},
+}
+
+type ListExchangeRate struct {
+	query.Query
+}
+
+type GetExchangeRate struct {
+	ID string `param:"id" validate:"required|ulid"`
+}
+
+// LoadExchangeRates creates the rates or replaces the ones of the same currency and start. Rates are
+// quoted in the namespace's base currency. Documents keep the rates they were priced with, so loads only
+// apply to the documents priced after them.
+type LoadExchangeRates struct {
+	Rates []models.ExchangeRate `json:"rates"`
+}
+
+type DeleteExchangeRate struct {
+	ID string `param:"id" validate:"required|ulid"`
+}
@@ -0,0 +1,49 @@
+package requests
+
+import "github.com/heiytor/invenda/api/pkg/query"
+
+// StockLevelFields lists the stock level attributes that clients can sort and filter by.
+var StockLevelFields = query.Fields{
+	"warehouse_id":  {Kind: query.KindString, Filterable: true},
+	"product_id":    {Kind: query.KindString, Filterable: true},
+	"variant_id":    {Kind: query.KindString, Filterable: true},
+	"min":           {Kind: query.KindNumber, Sortable: true, Filterable: true},
+	"max":           {Kind: query.KindNumber, Sortable: true, Filterable: true},
+	"reorder_point": {Kind: query.KindNumber, Sortable: true, Filterable: true},
+	"created_at":    {Kind: query.KindTime, Sortable: true, Filterable: true},
+	"updated_at":    {Kind: query.KindTime, Sortable: true, Filterable: true},
+}
+
+// ReorderSuggestionFields lists the reorder line attributes that clients can sort and filter by.
+var ReorderSuggestionFields = query.Fields{
+	"warehouse_id": {Kind: query.KindString, Filterable: true},
+	"product_id":   {Kind: query.KindString, Filterable: true},
+	"variant_id":   {Kind: query.KindString, Filterable: true},
+	"supplier_id":  {Kind: query.KindString, Filterable: true},
+	"sku":          {Kind: query.KindString, Sortable: true, Filterable: true},
+	"on_hand":      {Kind: query.KindNumber, Sortable: true, Filterable: true},
+	"position":     {Kind: query.KindNumber, Sortable: true, Filterable: true},
+	"quantity":     {Kind: query.KindNumber, Sortable: true, Filterable: true},
+}
+
+type ListStockLevel struct {
+	query.Query
+}
+
+// SetStockLevel creates or replaces the thresholds of an item in a warehouse.
+type SetStockLevel struct {
+	WarehouseID  string `json:"warehouse_id" validate:"required|ulid"`
+	ProductID    string `json:"product_id" validate:"required|ulid"`
+	VariantID    string `json:"variant_id" validate:"ulid"`
+	Min          int64  `json:"min" validate:"min:0"`
+	Max          int64  `json:"max" validate:"required|min:1"`
+	ReorderPoint int64  `json:"reorder_point" validate:"min:0"`
+}
+
+type DeleteStockLevel struct {
+	ID string `param:"id" validate:"required|ulid"`
+}
+
+type ListReorderSuggestion struct {
+	query.Query
+}
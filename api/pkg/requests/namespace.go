@@ -12,6 +12,13 @@ type member struct {
 	Permissions []auth.Permission `json:"permissions" validate:"permissions|required"`
 }
 
+// NamespaceFields lists the namespace attributes that clients can sort and filter by.
+var NamespaceFields = query.Fields{
+	"name":       {Kind: query.KindString, Sortable: true, Filterable: true},
+	"created_at": {Kind: query.KindTime, Sortable: true, Filterable: true},
+	"updated_at": {Kind: query.KindTime, Sortable: true, Filterable: true},
+}
+
 type ListNamespace struct {
 	query.Query
 }
@@ -21,7 +28,16 @@ type CreateNamespace struct {
 	Members []models.Member `json:"members" validate:"permissions"`
 }
 
+type settings struct {
+	AllowBackorders *bool  `json:"allow_backorders"`
+	ValuationMethod string `json:"valuation_method" validate:"in:average,fifo"`
+	TaxInclusive    *bool  `json:"tax_inclusive"`
+	TaxRounding     string `json:"tax_rounding" validate:"in:line,document"`
+	Currency        string `json:"currency" validate:"currency"`
+}
+
 type UpdateNamespace struct {
-	Name    string   `json:"name" validate:""`
-	Members []member `json:"members"`
+	Name     string    `json:"name" validate:""`
+	Members  []member  `json:"members"`
+	Settings *settings `json:"settings"`
 }
@@ -0,0 +1,67 @@
+package requests
+
+import (
+	"time"
+
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/query"
+)
+
+// PriceListFields lists the price list attributes that clients can sort and filter by.
+var PriceListFields = query.Fields{
+	"code":        {Kind: query.KindString, Sortable: true, Filterable: true},
+	"name":        {Kind: query.KindString, Sortable: true, Filterable: true},
+	"active":      {Kind: query.KindBool, Filterable: true},
+	"groups":      {Kind: query.KindString, Filterable: true},
+	"priority":    {Kind: query.KindNumber, Sortable: true, Filterable: true},
+	"valid_from":  {Kind: query.KindTime, Sortable: true, Filterable: true},
+	"valid_until": {Kind: query.KindTime, Sortable: true, Filterable: true},
+	"created_at":  {Kind: query.KindTime, Sortable: true, Filterable: true},
+	"updated_at":  {Kind: query.KindTime, Sortable: true, Filterable: true},
+}
+
+type ListPriceList struct {
+	query.Query
+}
+
+type GetPriceList struct {
+	ID string `param:"id" validate:"required|ulid"`
+}
+
+type CreatePriceList struct {
+	Code       string                 `json:"code" validate:"required|max_len:32"`
+	Name       string                 `json:"name" validate:"required"`
+	Active     *bool                  `json:"active"` // Active defaults to true when absent.
+	Groups     []string               `json:"groups"`
+	Priority   int                    `json:"priority"`
+	ValidFrom  *time.Time             `json:"valid_from"`
+	ValidUntil *time.Time             `json:"valid_until"`
+	Prices     []models.PriceListItem `json:"prices"`
+}
+
+type UpdatePriceList struct {
+	ID         string                 `param:"id" validate:"required|ulid"`
+	Code       string                 `json:"code" validate:"max_len:32"`
+	Name       string                 `json:"name"`
+	Active     *bool                  `json:"active"`
+	Groups     []string               `json:"groups"`
+	Priority   *int                   `json:"priority"`
+	ValidFrom  *time.Time             `json:"valid_from"`
+	ValidUntil *time.Time             `json:"valid_until"`
+	Prices     []models.PriceListItem `json:"prices"`
+}
+
+type DeletePriceList struct {
+	ID string `param:"id" validate:"required|ulid"`
+}
+
+// ResolvePrice quotes the unit price of a quantity of a product or variant for a customer at a time. The
+// quantity defaults to one, the time to now, and without a customer only the lists that apply to every
+// customer are considered.
+type ResolvePrice struct {
+	ProductID  string    `query:"product_id" validate:"required|ulid"`
+	VariantID  string    `query:"variant_id" validate:"ulid"`
+	CustomerID string    `query:"customer_id" validate:"ulid"`
+	Quantity   int64     `query:"quantity" validate:"min:0"`
+	At         time.Time `query:"at"`
+}
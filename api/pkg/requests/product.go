@@ -0,0 +1,127 @@
+package requests
+
+import (
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/money"
+	"github.com/heiytor/invenda/api/pkg/query"
+)
+
+// ProductFields lists the product attributes that clients can sort and filter by.
+var ProductFields = query.Fields{
+	"sku":                   {Kind: query.KindString, Sortable: true, Filterable: true},
+	"name":                  {Kind: query.KindString, Sortable: true, Filterable: true},
+	"unit":                  {Kind: query.KindString, Filterable: true},
+	"price.amount":          {Kind: query.KindNumber, Sortable: true, Filterable: true},
+	"cost.amount":           {Kind: query.KindNumber, Sortable: true, Filterable: true},
+	"barcode":               {Kind: query.KindString, Filterable: true},
+	"tags":                  {Kind: query.KindString, Filterable: true},
+	"active":                {Kind: query.KindBool, Filterable: true},
+	"category_id":           {Kind: query.KindString, Filterable: true}, // Also matches the products of the category's descendants.
+	"tax_category":          {Kind: query.KindString, Filterable: true},
+	"preferred_supplier_id": {Kind: query.KindString, Filterable: true},
+	"lot_tracked":           {Kind: query.KindBool, Filterable: true},
+	"serialized":            {Kind: query.KindBool, Filterable: true},
+	"created_at":            {Kind: query.KindTime, Sortable: true, Filterable: true},
+	"updated_at":            {Kind: query.KindTime, Sortable: true, Filterable: true},
+}
+
+type ListProduct struct {
+	query.Query
+}
+
+type GetProduct struct {
+	ID string `param:"id" validate:"required|ulid"`
+}
+
+type CreateProduct struct {
+	SKU         string      `json:"sku" validate:"required|max_len:64"`
+	Name        string      `json:"name" validate:"required"`
+	Description string      `json:"description"`
+	Unit        string      `json:"unit" validate:"required"`
+	Price       money.Money `json:"price" validate:"money_min:0"`
+	Cost        money.Money `json:"cost" validate:"money_min:0"`
+	Barcode     string      `json:"barcode" validate:"max_len:64"`
+	Tags        []string    `json:"tags"`
+	Active      *bool       `json:"active"` // Active defaults to true when absent.
+	CategoryID  string      `json:"category_id" validate:"ulid"`
+	TaxCategory string      `json:"tax_category" validate:"max_len:32"`
+
+	PreferredSupplierID string `json:"preferred_supplier_id" validate:"ulid"`
+	LotTracked          bool   `json:"lot_tracked"`
+	IssueStrategy       string `json:"issue_strategy" validate:"in:fefo,fifo,specific"`
+	Serialized          bool   `json:"serialized"`
+
+	Options []models.ProductOption `json:"options"`
+}
+
+type UpdateProduct struct {
+	ID          string       `param:"id" validate:"required|ulid"`
+	SKU         string       `json:"sku" validate:"max_len:64"`
+	Name        string       `json:"name"`
+	Description *string      `json:"description"`
+	Unit        string       `json:"unit"`
+	Price       *money.Money `json:"price" validate:"money_min:0"`
+	Cost        *money.Money `json:"cost" validate:"money_min:0"`
+	Barcode     *string      `json:"barcode" validate:"max_len:64"`
+	Tags        []string     `json:"tags"`
+	Active      *bool        `json:"active"`
+	CategoryID  string       `json:"category_id" validate:"ulid"`
+	TaxCategory *string      `json:"tax_category" validate:"max_len:32"`
+
+	PreferredSupplierID string `json:"preferred_supplier_id" validate:"ulid"`
+	LotTracked          *bool  `json:"lot_tracked"`
+	IssueStrategy       string `json:"issue_strategy" validate:"in:fefo,fifo,specific"`
+	Serialized          *bool  `json:"serialized"`
+
+	Options []models.ProductOption `json:"options"`
+}
+
+type DeleteProduct struct {
+	ID string `param:"id" validate:"required|ulid"`
+}
+
+// VariantFields lists the variant attributes that clients can sort and filter by.
+var VariantFields = query.Fields{
+	"sku":          {Kind: query.KindString, Sortable: true, Filterable: true},
+	"barcode":      {Kind: query.KindString, Filterable: true},
+	"price.amount": {Kind: query.KindNumber, Sortable: true, Filterable: true},
+	"created_at":   {Kind: query.KindTime, Sortable: true, Filterable: true},
+	"updated_at":   {Kind: query.KindTime, Sortable: true, Filterable: true},
+}
+
+type ListVariant struct {
+	ProductID string `param:"id" validate:"required|ulid"`
+	query.Query
+}
+
+type GetVariant struct {
+	ProductID string `param:"id" validate:"required|ulid"`
+	ID        string `param:"variant" validate:"required|ulid"`
+}
+
+type CreateVariant struct {
+	ProductID  string            `param:"id" validate:"required|ulid"`
+	SKU        string            `json:"sku" validate:"max_len:64"` // SKU is generated from the attributes when absent.
+	Barcode    string            `json:"barcode" validate:"max_len:64"`
+	Attributes map[string]string `json:"attributes" validate:"required"`
+	Price      *money.Money      `json:"price" validate:"money_min:0"`
+}
+
+// GenerateVariants creates a variant for each combination of the product's options that does not
+// have one yet.
+type GenerateVariants struct {
+	ProductID string `param:"id" validate:"required|ulid"`
+}
+
+type UpdateVariant struct {
+	ProductID string       `param:"id" validate:"required|ulid"`
+	ID        string       `param:"variant" validate:"required|ulid"`
+	SKU       string       `json:"sku" validate:"max_len:64"`
+	Barcode   *string      `json:"barcode" validate:"max_len:64"`
+	Price     *money.Money `json:"price" validate:"money_min:0"`
+}
+
+type DeleteVariant struct {
+	ProductID string `param:"id" validate:"required|ulid"`
+	ID        string `param:"variant" validate:"required|ulid"`
+}
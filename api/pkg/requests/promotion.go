@@ -0,0 +1,81 @@
+package requests
+
+import (
+	"time"
+
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/money"
+	"github.com/heiytor/invenda/api/pkg/query"
+)
+
+// PromotionFields lists the promotion attributes that clients can sort and filter by.
+var PromotionFields = query.Fields{
+	"name":        {Kind: query.KindString, Sortable: true, Filterable: true},
+	"type":        {Kind: query.KindString, Filterable: true},
+	"active":      {Kind: query.KindBool, Filterable: true},
+	"coupon":      {Kind: query.KindString, Filterable: true},
+	"priority":    {Kind: query.KindNumber, Sortable: true, Filterable: true},
+	"used":        {Kind: query.KindNumber, Sortable: true, Filterable: true},
+	"valid_from":  {Kind: query.KindTime, Sortable: true, Filterable: true},
+	"valid_until": {Kind: query.KindTime, Sortable: true, Filterable: true},
+	"created_at":  {Kind: query.KindTime, Sortable: true, Filterable: true},
+	"updated_at":  {Kind: query.KindTime, Sortable: true, Filterable: true},
+}
+
+type ListPromotion struct {
+	query.Query
+}
+
+type GetPromotion struct {
+	ID string `param:"id" validate:"required|ulid"`
+}
+
+type CreatePromotion struct {
+	Name       string                `json:"name" validate:"required"`
+	Active     *bool                 `json:"active"` // Active defaults to true when absent.
+	Type       models.PromotionType  `json:"type" validate:"required|in:percentage,fixed,buy_x_get_y,bundle"`
+	Value      int64                 `json:"value" validate:"min:0"`
+	Amount     money.Money           `json:"amount" validate:"money_min:0"`
+	Buy        int64                 `json:"buy" validate:"min:0"`
+	Get        int64                 `json:"get" validate:"min:0"`
+	BundleSize int64                 `json:"bundle_size" validate:"min:0"`
+	Rules      models.PromotionRules `json:"rules"`
+	Priority   int                   `json:"priority"`
+	Stackable  bool                  `json:"stackable"`
+	Coupon     string                `json:"coupon" validate:"max_len:32"`
+	UsageLimit int64                 `json:"usage_limit" validate:"min:0"`
+	ValidFrom  *time.Time            `json:"valid_from"`
+	ValidUntil *time.Time            `json:"valid_until"`
+}
+
+// UpdatePromotion changes a promotion. A promotion's type cannot be changed.
+type UpdatePromotion struct {
+	ID         string                 `param:"id" validate:"required|ulid"`
+	Name       string                 `json:"name"`
+	Active     *bool                  `json:"active"`
+	Value      *int64                 `json:"value" validate:"min:0"`
+	Amount     *money.Money           `json:"amount" validate:"money_min:0"`
+	Buy        *int64                 `json:"buy" validate:"min:0"`
+	Get        *int64                 `json:"get" validate:"min:0"`
+	BundleSize *int64                 `json:"bundle_size" validate:"min:0"`
+	Rules      *models.PromotionRules `json:"rules"`
+	Priority   *int                   `json:"priority"`
+	Stackable  *bool                  `json:"stackable"`
+	Coupon     *string                `json:"coupon" validate:"max_len:32"`
+	UsageLimit *int64                 `json:"usage_limit" validate:"min:0"`
+	ValidFrom  *time.Time             `json:"valid_from"`
+	ValidUntil *time.Time             `json:"valid_until"`
+}
+
+type DeletePromotion struct {
+	ID string `param:"id" validate:"required|ulid"`
+}
+
+// EvaluatePromotions prices a cart for a customer as a sales order or a counter sale would: lines without
+// a unit price take the price resolved from the price lists and the promotions that apply are returned
+// line by line. Without a customer, only the promotions for every customer apply.
+type EvaluatePromotions struct {
+	CustomerID string            `json:"customer_id" validate:"ulid"`
+	Coupons    []string          `json:"coupons"`
+	Lines      []models.CartLine `json:"lines" validate:"required|min_len:1"`
+}
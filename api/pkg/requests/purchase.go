@@ -0,0 +1,70 @@
+package requests
+
+import (
+	"time"
+
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/query"
+)
+
+// PurchaseOrderFields lists the purchase order attributes that clients can sort and filter by.
+var PurchaseOrderFields = query.Fields{
+	"status":       {Kind: query.KindString, Filterable: true},
+	"supplier_id":  {Kind: query.KindString, Filterable: true},
+	"warehouse_id": {Kind: query.KindString, Filterable: true},
+	"created_by":   {Kind: query.KindString, Filterable: true},
+	"created_at":   {Kind: query.KindTime, Sortable: true, Filterable: true},
+	"updated_at":   {Kind: query.KindTime, Sortable: true, Filterable: true},
+	"sent_at":      {Kind: query.KindTime, Sortable: true, Filterable: true},
+	"expected_at":  {Kind: query.KindTime, Sortable: true, Filterable: true},
+}
+
+type ListPurchaseOrder struct {
+	query.Query
+}
+
+type GetPurchaseOrder struct {
+	ID string `param:"id" validate:"required|ulid"`
+}
+
+type CreatePurchaseOrder struct {
+	SupplierID  string                     `json:"supplier_id" validate:"required|ulid"`
+	WarehouseID string                     `json:"warehouse_id" validate:"required|ulid"`
+	Location    string                     `json:"location"`
+	Lines       []models.PurchaseOrderLine `json:"lines" validate:"required|min_len:1"`
+	Notes       string                     `json:"notes"`
+	ExpectedAt  *time.Time                 `json:"expected_at"`
+	Currency    string                     `json:"currency" validate:"currency"` // Currency defaults to the supplier's currency.
+}
+
+// UpdatePurchaseOrder changes a draft purchase order.
+type UpdatePurchaseOrder struct {
+	ID         string                     `param:"id" validate:"required|ulid"`
+	Location   *string                    `json:"location"`
+	Lines      []models.PurchaseOrderLine `json:"lines"`
+	Notes      *string                    `json:"notes"`
+	ExpectedAt *time.Time                 `json:"expected_at"`
+}
+
+type DeletePurchaseOrder struct {
+	ID string `param:"id" validate:"required|ulid"`
+}
+
+type SendPurchaseOrder struct {
+	ID string `param:"id" validate:"required|ulid"`
+}
+
+// ReceivePurchaseOrder receives goods of the purchase order's lines at its warehouse. Every remaining
+// quantity is received when Lines is empty.
+type ReceivePurchaseOrder struct {
+	ID    string                        `param:"id" validate:"required|ulid"`
+	Lines []models.PurchaseOrderReceipt `json:"lines"`
+}
+
+type ClosePurchaseOrder struct {
+	ID string `param:"id" validate:"required|ulid"`
+}
+
+type CancelPurchaseOrder struct {
+	ID string `param:"id" validate:"required|ulid"`
+}
@@ -0,0 +1,42 @@
+package requests
+
+import "github.com/heiytor/invenda/api/pkg/query"
+
+// RegisterFields lists the register attributes that clients can sort and filter by.
+var RegisterFields = query.Fields{
+	"code":         {Kind: query.KindString, Sortable: true, Filterable: true},
+	"name":         {Kind: query.KindString, Sortable: true, Filterable: true},
+	"warehouse_id": {Kind: query.KindString, Filterable: true},
+	"active":       {Kind: query.KindBool, Filterable: true},
+	"created_at":   {Kind: query.KindTime, Sortable: true, Filterable: true},
+	"updated_at":   {Kind: query.KindTime, Sortable: true, Filterable: true},
+}
+
+type ListRegister struct {
+	query.Query
+}
+
+type GetRegister struct {
+	ID string `param:"id" validate:"required|ulid"`
+}
+
+type CreateRegister struct {
+	Code        string `json:"code" validate:"required|max_len:32"`
+	Name        string `json:"name" validate:"required"`
+	WarehouseID string `json:"warehouse_id" validate:"required|ulid"`
+	Location    string `json:"location"`
+	Active      *bool  `json:"active"` // Active defaults to true when absent.
+}
+
+type UpdateRegister struct {
+	ID          string  `param:"id" validate:"required|ulid"`
+	Code        string  `json:"code" validate:"max_len:32"`
+	Name        string  `json:"name"`
+	WarehouseID string  `json:"warehouse_id" validate:"ulid"`
+	Location    *string `json:"location"`
+	Active      *bool   `json:"active"`
+}
+
+type DeleteRegister struct {
+	ID string `param:"id" validate:"required|ulid"`
+}
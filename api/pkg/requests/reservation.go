@@ -0,0 +1,42 @@
+package requests
+
+import (
+	"time"
+
+	"github.com/heiytor/invenda/api/pkg/query"
+)
+
+// ReservationFields lists the reservation attributes that clients can sort and filter by.
+var ReservationFields = query.Fields{
+	"warehouse_id": {Kind: query.KindString, Filterable: true},
+	"location":     {Kind: query.KindString, Filterable: true},
+	"product_id":   {Kind: query.KindString, Filterable: true},
+	"variant_id":   {Kind: query.KindString, Filterable: true},
+	"status":       {Kind: query.KindString, Filterable: true},
+	"reference":    {Kind: query.KindString, Filterable: true},
+	"quantity":     {Kind: query.KindNumber, Sortable: true, Filterable: true},
+	"expires_at":   {Kind: query.KindTime, Sortable: true, Filterable: true},
+	"created_at":   {Kind: query.KindTime, Sortable: true, Filterable: true},
+}
+
+type ListReservation struct {
+	query.Query
+}
+
+type GetReservation struct {
+	ID string `param:"id" validate:"required|ulid"`
+}
+
+type CreateReservation struct {
+	WarehouseID string    `json:"warehouse_id" validate:"required|ulid"`
+	Location    string    `json:"location"`
+	ProductID   string    `json:"product_id" validate:"required|ulid"`
+	VariantID   string    `json:"variant_id" validate:"ulid"`
+	Quantity    int64     `json:"quantity" validate:"required|min:1"`
+	ExpiresAt   time.Time `json:"expires_at" validate:"required"`
+	Reference   string    `json:"reference"`
+}
+
+type ReleaseReservation struct {
+	ID string `param:"id" validate:"required|ulid"`
+}
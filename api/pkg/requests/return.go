@@ -0,0 +1,35 @@
+package requests
+
+import (
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/query"
+)
+
+// ReturnFields lists the return attributes that clients can sort and filter by.
+var ReturnFields = query.Fields{
+	"sale_id":              {Kind: query.KindString, Filterable: true},
+	"register_id":          {Kind: query.KindString, Filterable: true},
+	"customer_id":          {Kind: query.KindString, Filterable: true},
+	"created_by":           {Kind: query.KindString, Filterable: true},
+	"refund.method":        {Kind: query.KindString, Filterable: true},
+	"refund.amount.amount": {Kind: query.KindNumber, Sortable: true, Filterable: true},
+	"created_at":           {Kind: query.KindTime, Sortable: true, Filterable: true},
+}
+
+type ListReturn struct {
+	query.Query
+}
+
+type GetReturn struct {
+	ID string `param:"id" validate:"required|ulid"`
+}
+
+// CreateReturn returns lines of a counter sale. The return is processed at RegisterID, which defaults to
+// the register the sale was made at.
+type CreateReturn struct {
+	SaleID       string              `param:"id" validate:"required|ulid"`
+	RegisterID   string              `json:"register_id" validate:"ulid"`
+	Lines        []models.ReturnLine `json:"lines" validate:"required|min_len:1"`
+	RefundMethod models.RefundMethod `json:"refund_method" validate:"required|in:original_tender,store_credit"`
+	Notes        string              `json:"notes"`
+}
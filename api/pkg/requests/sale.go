@@ -0,0 +1,40 @@
+package requests
+
+import (
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/query"
+)
+
+// SaleFields lists the sale attributes that clients can sort and filter by.
+var SaleFields = query.Fields{
+	"register_id":  {Kind: query.KindString, Filterable: true},
+	"warehouse_id": {Kind: query.KindString, Filterable: true},
+	"customer_id":  {Kind: query.KindString, Filterable: true},
+	"cashier_id":   {Kind: query.KindString, Filterable: true},
+	"total.amount": {Kind: query.KindNumber, Sortable: true, Filterable: true},
+	"created_at":   {Kind: query.KindTime, Sortable: true, Filterable: true},
+}
+
+type ListSale struct {
+	query.Query
+}
+
+type GetSale struct {
+	ID string `param:"id" validate:"required|ulid"`
+}
+
+// CreateSale completes a counter sale at a register. IdempotencyKey is chosen by the client, once per
+// sale, so a repeated request returns the sale already made instead of making another one.
+type CreateSale struct {
+	IdempotencyKey string            `json:"idempotency_key" validate:"required|max_len:64"`
+	RegisterID     string            `json:"register_id" validate:"required|ulid"`
+	CustomerID     string            `json:"customer_id" validate:"ulid"`
+	Lines          []models.SaleLine `json:"lines" validate:"required|min_len:1"`
+	Payments       []models.Tender   `json:"payments" validate:"required|min_len:1"`
+	Coupons        []string          `json:"coupons"`
+}
+
+// LookupSaleItem identifies the product or variant with a barcode, as scanned at the counter.
+type LookupSaleItem struct {
+	Barcode string `param:"barcode" validate:"required"`
+}
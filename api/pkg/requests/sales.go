@@ -0,0 +1,78 @@
+package requests
+
+import (
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/query"
+)
+
+// SalesOrderFields lists the sales order attributes that clients can sort and filter by.
+var SalesOrderFields = query.Fields{
+	"status":       {Kind: query.KindString, Filterable: true},
+	"customer_id":  {Kind: query.KindString, Filterable: true},
+	"warehouse_id": {Kind: query.KindString, Filterable: true},
+	"created_by":   {Kind: query.KindString, Filterable: true},
+	"total.amount": {Kind: query.KindNumber, Sortable: true, Filterable: true},
+	"created_at":   {Kind: query.KindTime, Sortable: true, Filterable: true},
+	"updated_at":   {Kind: query.KindTime, Sortable: true, Filterable: true},
+	"placed_at":    {Kind: query.KindTime, Sortable: true, Filterable: true},
+}
+
+type ListSalesOrder struct {
+	query.Query
+}
+
+type GetSalesOrder struct {
+	ID string `param:"id" validate:"required|ulid"`
+}
+
+type CreateSalesOrder struct {
+	CustomerID      string                  `json:"customer_id" validate:"required|ulid"`
+	WarehouseID     string                  `json:"warehouse_id" validate:"required|ulid"`
+	Location        string                  `json:"location"`
+	ShippingAddress *models.Address         `json:"shipping_address"` // ShippingAddress defaults to the customer's default address.
+	Lines           []models.SalesOrderLine `json:"lines" validate:"required|min_len:1"`
+	Notes           string                  `json:"notes"`
+	Coupons         []string                `json:"coupons"`
+	Currency        string                  `json:"currency" validate:"currency"` // Currency defaults to the namespace's base currency.
+}
+
+// UpdateSalesOrder changes a draft sales order.
+type UpdateSalesOrder struct {
+	ID              string                  `param:"id" validate:"required|ulid"`
+	Location        *string                 `json:"location"`
+	ShippingAddress *models.Address         `json:"shipping_address"`
+	Lines           []models.SalesOrderLine `json:"lines"`
+	Notes           *string                 `json:"notes"`
+	Coupons         []string                `json:"coupons"`
+}
+
+type DeleteSalesOrder struct {
+	ID string `param:"id" validate:"required|ulid"`
+}
+
+type ConfirmSalesOrder struct {
+	ID string `param:"id" validate:"required|ulid"`
+}
+
+type PickSalesOrder struct {
+	ID string `param:"id" validate:"required|ulid"`
+}
+
+// ShipSalesOrder ships every line of the sales order. Shipments name the lots or serials shipped for the
+// lines of lot-tracked or serialized products.
+type ShipSalesOrder struct {
+	ID        string                      `param:"id" validate:"required|ulid"`
+	Shipments []models.SalesOrderShipment `json:"shipments"`
+}
+
+type InvoiceSalesOrder struct {
+	ID string `param:"id" validate:"required|ulid"`
+}
+
+type CloseSalesOrder struct {
+	ID string `param:"id" validate:"required|ulid"`
+}
+
+type CancelSalesOrder struct {
+	ID string `param:"id" validate:"required|ulid"`
+}
@@ -0,0 +1,46 @@
+package requests
+
+import (
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/money"
+	"github.com/heiytor/invenda/api/pkg/query"
+)
+
+// ShiftFields lists the shift attributes that clients can sort and filter by.
+var ShiftFields = query.Fields{
+	"status":      {Kind: query.KindString, Filterable: true},
+	"register_id": {Kind: query.KindString, Filterable: true},
+	"opened_by":   {Kind: query.KindString, Filterable: true},
+	"created_at":  {Kind: query.KindTime, Sortable: true, Filterable: true},
+	"closed_at":   {Kind: query.KindTime, Sortable: true, Filterable: true},
+}
+
+type ListShift struct {
+	query.Query
+}
+
+type GetShift struct {
+	ID string `param:"id" validate:"required|ulid"`
+}
+
+// OpenShift opens a shift at a register with the cash counted in its drawer.
+type OpenShift struct {
+	RegisterID string      `json:"register_id" validate:"required|ulid"`
+	Float      money.Money `json:"float" validate:"money_min:0"`
+}
+
+// RecordShiftCash records cash put into or taken out of the drawer of an open shift.
+type RecordShiftCash struct {
+	ID     string                  `param:"id" validate:"required|ulid"`
+	Type   models.CashMovementType `json:"type" validate:"required|in:pay_in,pay_out"`
+	Amount money.Money             `json:"amount" validate:"required|money_min:1"`
+	Reason string                  `json:"reason" validate:"required"`
+}
+
+// CloseShift closes an open shift with a blind count of its drawer, i.e. the cash counted without
+// knowing how much was expected.
+type CloseShift struct {
+	ID      string      `param:"id" validate:"required|ulid"`
+	Counted money.Money `json:"counted" validate:"money_min:0"`
+	Notes   string      `json:"notes"`
+}
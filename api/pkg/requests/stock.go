@@ -0,0 +1,113 @@
+package requests
+
+import (
+	"time"
+
+	"github.com/heiytor/invenda/api/pkg/money"
+	"github.com/heiytor/invenda/api/pkg/query"
+)
+
+// MovementFields lists the movement attributes that clients can sort and filter by.
+var MovementFields = query.Fields{
+	"warehouse_id": {Kind: query.KindString, Filterable: true},
+	"location":     {Kind: query.KindString, Filterable: true},
+	"product_id":   {Kind: query.KindString, Filterable: true},
+	"variant_id":   {Kind: query.KindString, Filterable: true},
+	"type":         {Kind: query.KindString, Filterable: true},
+	"quantity":     {Kind: query.KindNumber, Sortable: true, Filterable: true},
+	"user_id":      {Kind: query.KindString, Filterable: true},
+	"reference":    {Kind: query.KindString, Filterable: true},
+	"lot":          {Kind: query.KindString, Filterable: true},
+	"serials":      {Kind: query.KindString, Filterable: true},
+	"created_at":   {Kind: query.KindTime, Sortable: true, Filterable: true},
+}
+
+// BalanceFields lists the balance attributes that clients can sort and filter by.
+var BalanceFields = query.Fields{
+	"warehouse_id": {Kind: query.KindString, Filterable: true},
+	"location":     {Kind: query.KindString, Filterable: true},
+	"product_id":   {Kind: query.KindString, Filterable: true},
+	"variant_id":   {Kind: query.KindString, Filterable: true},
+	"on_hand":      {Kind: query.KindNumber, Sortable: true, Filterable: true},
+	"reserved":     {Kind: query.KindNumber, Sortable: true, Filterable: true},
+	"updated_at":   {Kind: query.KindTime, Sortable: true, Filterable: true},
+}
+
+type ListMovement struct {
+	query.Query
+}
+
+type ListBalance struct {
+	query.Query
+}
+
+// LotFields lists the lot attributes that clients can sort and filter by.
+var LotFields = query.Fields{
+	"warehouse_id": {Kind: query.KindString, Filterable: true},
+	"location":     {Kind: query.KindString, Filterable: true},
+	"product_id":   {Kind: query.KindString, Filterable: true},
+	"variant_id":   {Kind: query.KindString, Filterable: true},
+	"lot":          {Kind: query.KindString, Filterable: true},
+	"on_hand":      {Kind: query.KindNumber, Sortable: true, Filterable: true},
+	"expires_at":   {Kind: query.KindTime, Sortable: true, Filterable: true},
+	"received_at":  {Kind: query.KindTime, Sortable: true, Filterable: true},
+}
+
+type ListLot struct {
+	query.Query
+}
+
+// ListExpiringLot lists the lots expiring within the next Days days.
+type ListExpiringLot struct {
+	query.Query
+	Days int `query:"days" validate:"min:0"`
+}
+
+// SerialFields lists the serialized unit attributes that clients can sort and filter by.
+var SerialFields = query.Fields{
+	"warehouse_id": {Kind: query.KindString, Filterable: true},
+	"location":     {Kind: query.KindString, Filterable: true},
+	"product_id":   {Kind: query.KindString, Filterable: true},
+	"variant_id":   {Kind: query.KindString, Filterable: true},
+	"serial":       {Kind: query.KindString, Sortable: true, Filterable: true},
+	"status":       {Kind: query.KindString, Filterable: true},
+	"created_at":   {Kind: query.KindTime, Sortable: true, Filterable: true},
+	"updated_at":   {Kind: query.KindTime, Sortable: true, Filterable: true},
+}
+
+type ListSerial struct {
+	query.Query
+}
+
+type GetSerialHistory struct {
+	ProductID string `param:"id" validate:"required|ulid"`
+	Serial    string `param:"serial" validate:"required"`
+}
+
+// CreateMovement posts a movement to the ledger. Quantity is always positive for receipts and issues,
+// while adjustments use its sign to tell whether the stock increases or decreases. Movements of
+// lot-tracked products carry a lot, which receipts must name along with its expiry date; issues take it
+// from the product's issue strategy when it is not named. Movements of serialized products name one
+// serial per unit.
+type CreateMovement struct {
+	WarehouseID string `json:"warehouse_id" validate:"required|ulid"`
+	Location    string `json:"location"`
+	ProductID   string `json:"product_id" validate:"required|ulid"`
+	VariantID   string `json:"variant_id" validate:"ulid"`
+	Type        string `json:"type" validate:"required|in:receipt,issue,adjustment"`
+	Quantity    int64  `json:"quantity" validate:"required"`
+	Reason      string `json:"reason" validate:"required"`
+
+	// UnitCost is the cost of each unit entering the stock, in the namespace's base currency. When
+	// not given it defaults to the product's cost for receipts and to the current average cost for
+	// adjustments. It is ignored when the stock decreases.
+	UnitCost *money.Money `json:"unit_cost" validate:"money_min:0"`
+
+	Lot       string     `json:"lot"`
+	ExpiresAt *time.Time `json:"expires_at"`
+	Serials   []string   `json:"serials"`
+
+	// AllowExpired reports whether the issue may take stock from expired lots. It is set from the
+	// session's permissions.
+	AllowExpired bool `json:"-"`
+}
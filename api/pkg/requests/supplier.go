@@ -0,0 +1,54 @@
+package requests
+
+import (
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/query"
+)
+
+// SupplierFields lists the supplier attributes that clients can sort and filter by.
+var SupplierFields = query.Fields{
+	"code":           {Kind: query.KindString, Sortable: true, Filterable: true},
+	"name":           {Kind: query.KindString, Sortable: true, Filterable: true},
+	"active":         {Kind: query.KindBool, Filterable: true},
+	"lead_time_days": {Kind: query.KindNumber, Sortable: true, Filterable: true},
+	"currency":       {Kind: query.KindString, Sortable: true, Filterable: true},
+	"created_at":     {Kind: query.KindTime, Sortable: true, Filterable: true},
+	"updated_at":     {Kind: query.KindTime, Sortable: true, Filterable: true},
+}
+
+type ListSupplier struct {
+	query.Query
+}
+
+type GetSupplier struct {
+	ID string `param:"id" validate:"required|ulid"`
+}
+
+type CreateSupplier struct {
+	Code         string                   `json:"code" validate:"required|max_len:32"`
+	Name         string                   `json:"name" validate:"required"`
+	Contact      models.Contact           `json:"contact"`
+	Address      string                   `json:"address"`
+	PaymentTerms string                   `json:"payment_terms"`
+	LeadTimeDays int                      `json:"lead_time_days" validate:"min:0"`
+	Currency     string                   `json:"currency" validate:"currency"`
+	Active       *bool                    `json:"active"` // Active defaults to true when absent.
+	Products     []models.SupplierProduct `json:"products"`
+}
+
+type UpdateSupplier struct {
+	ID           string                   `param:"id" validate:"required|ulid"`
+	Code         string                   `json:"code" validate:"max_len:32"`
+	Name         string                   `json:"name"`
+	Contact      *models.Contact          `json:"contact"`
+	Address      *string                  `json:"address"`
+	PaymentTerms *string                  `json:"payment_terms"`
+	LeadTimeDays *int                     `json:"lead_time_days" validate:"min:0"`
+	Currency     string                   `json:"currency" validate:"currency"`
+	Active       *bool                    `json:"active"`
+	Products     []models.SupplierProduct `json:"products"`
+}
+
+type DeleteSupplier struct {
+	ID string `param:"id" validate:"required|ulid"`
+}
@@ -0,0 +1,61 @@
+package requests
+
+import (
+	"time"
+
+	"github.com/heiytor/invenda/api/pkg/query"
+)
+
+// TaxRateFields lists the tax rate attributes that clients can sort and filter by.
+var TaxRateFields = query.Fields{
+	"name":        {Kind: query.KindString, Sortable: true, Filterable: true},
+	"category":    {Kind: query.KindString, Sortable: true, Filterable: true},
+	"rate":        {Kind: query.KindNumber, Sortable: true, Filterable: true},
+	"compound":    {Kind: query.KindBool, Filterable: true},
+	"order":       {Kind: query.KindNumber, Sortable: true, Filterable: true},
+	"valid_from":  {Kind: query.KindTime, Sortable: true, Filterable: true},
+	"valid_until": {Kind: query.KindTime, Sortable: true, Filterable: true},
+	"created_at":  {Kind: query.KindTime, Sortable: true, Filterable: true},
+	"updated_at":  {Kind: query.KindTime, Sortable: true, Filterable: true},
+}
+
+type ListTaxRate struct {
+	query.Query
+}
+
+type GetTaxRate struct {
+	ID string `param:"id" validate:"required|ulid"`
+}
+
+type CreateTaxRate struct {
+	Name       string     `json:"name" validate:"required"`
+	Category   string     `json:"category" validate:"required|max_len:32"`
+	Rate       int64      `json:"rate" validate:"min:0"`
+	Compound   bool       `json:"compound"`
+	Order      int        `json:"order"`
+	ValidFrom  *time.Time `json:"valid_from"`
+	ValidUntil *time.Time `json:"valid_until"`
+}
+
+// UpdateTaxRate changes a tax rate. A rate's category cannot be changed. Documents keep the rates they
+// were priced with, so changes only apply to the documents priced after them.
+type UpdateTaxRate struct {
+	ID         string     `param:"id" validate:"required|ulid"`
+	Name       string     `json:"name"`
+	Rate       *int64     `json:"rate" validate:"min:0"`
+	Compound   *bool      `json:"compound"`
+	Order      *int       `json:"order"`
+	ValidFrom  *time.Time `json:"valid_from"`
+	ValidUntil *time.Time `json:"valid_until"`
+}
+
+type DeleteTaxRate struct {
+	ID string `param:"id" validate:"required|ulid"`
+}
+
+// GetTaxReport sums the taxes levied within [From, Until). Until defaults to now and From to the start of
+// Until's month.
+type GetTaxReport struct {
+	From  time.Time `query:"from"`
+	Until time.Time `query:"until"`
+}
@@ -0,0 +1,62 @@
+package requests
+
+import (
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/query"
+)
+
+// TransferFields lists the transfer attributes that clients can sort and filter by.
+var TransferFields = query.Fields{
+	"status":         {Kind: query.KindString, Filterable: true},
+	"source_id":      {Kind: query.KindString, Filterable: true},
+	"destination_id": {Kind: query.KindString, Filterable: true},
+	"created_by":     {Kind: query.KindString, Filterable: true},
+	"created_at":     {Kind: query.KindTime, Sortable: true, Filterable: true},
+	"updated_at":     {Kind: query.KindTime, Sortable: true, Filterable: true},
+	"dispatched_at":  {Kind: query.KindTime, Sortable: true, Filterable: true},
+}
+
+type ListTransfer struct {
+	query.Query
+}
+
+type GetTransfer struct {
+	ID string `param:"id" validate:"required|ulid"`
+}
+
+type CreateTransfer struct {
+	SourceID            string                `json:"source_id" validate:"required|ulid"`
+	SourceLocation      string                `json:"source_location"`
+	DestinationID       string                `json:"destination_id" validate:"required|ulid"`
+	DestinationLocation string                `json:"destination_location"`
+	Items               []models.TransferItem `json:"items" validate:"required|min_len:1"`
+	Notes               string                `json:"notes"`
+}
+
+// UpdateTransfer changes a draft transfer.
+type UpdateTransfer struct {
+	ID                  string                `param:"id" validate:"required|ulid"`
+	SourceLocation      *string               `json:"source_location"`
+	DestinationLocation *string               `json:"destination_location"`
+	Items               []models.TransferItem `json:"items"`
+	Notes               *string               `json:"notes"`
+}
+
+type DeleteTransfer struct {
+	ID string `param:"id" validate:"required|ulid"`
+}
+
+type DispatchTransfer struct {
+	ID string `param:"id" validate:"required|ulid"`
+}
+
+// ReceiveTransfer receives the quantities of the items at the transfer's destination. Every remaining
+// quantity is received when Items is empty.
+type ReceiveTransfer struct {
+	ID    string                `param:"id" validate:"required|ulid"`
+	Items []models.TransferItem `json:"items"`
+}
+
+type CloseTransfer struct {
+	ID string `param:"id" validate:"required|ulid"`
+}
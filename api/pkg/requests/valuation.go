@@ -0,0 +1,9 @@
+package requests
+
+import "time"
+
+// GetValuation values the stock as of AsOf, which defaults to now, optionally of a single warehouse.
+type GetValuation struct {
+	AsOf        time.Time `query:"as_of"`
+	WarehouseID string    `query:"warehouse_id" validate:"ulid"`
+}
@@ -0,0 +1,46 @@
+package requests
+
+import (
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/query"
+)
+
+// WarehouseFields lists the warehouse attributes that clients can sort and filter by.
+var WarehouseFields = query.Fields{
+	"code":       {Kind: query.KindString, Sortable: true, Filterable: true},
+	"name":       {Kind: query.KindString, Sortable: true, Filterable: true},
+	"active":     {Kind: query.KindBool, Filterable: true},
+	"created_at": {Kind: query.KindTime, Sortable: true, Filterable: true},
+	"updated_at": {Kind: query.KindTime, Sortable: true, Filterable: true},
+}
+
+type ListWarehouse struct {
+	query.Query
+}
+
+type GetWarehouse struct {
+	ID string `param:"id" validate:"required|ulid"`
+}
+
+type CreateWarehouse struct {
+	Code       string            `json:"code" validate:"required|max_len:32"`
+	Name       string            `json:"name" validate:"required"`
+	Address    string            `json:"address"`
+	Active     *bool             `json:"active"` // Active defaults to true when absent.
+	Locations  []models.Location `json:"locations"`
+	Quarantine string            `json:"quarantine"`
+}
+
+type UpdateWarehouse struct {
+	ID         string            `param:"id" validate:"required|ulid"`
+	Code       string            `json:"code" validate:"max_len:32"`
+	Name       string            `json:"name"`
+	Address    *string           `json:"address"`
+	Active     *bool             `json:"active"`
+	Locations  []models.Location `json:"locations"`
+	Quarantine *string           `json:"quarantine"`
+}
+
+type DeleteWarehouse struct {
+	ID string `param:"id" validate:"required|ulid"`
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/gookit/validate"
 	"github.com/heiytor/invenda/api/pkg/auth"
 	"github.com/heiytor/invenda/api/pkg/errors"
+	"github.com/heiytor/invenda/api/pkg/money"
 	"github.com/oklog/ulid/v2"
 )
 
@@ -22,11 +23,19 @@ func New() *Validator {
 	validate.AddValidator("password", IsPassword)
 	validate.AddValidator("ulid", IsULID)
 	validate.AddValidator("permissions", IsPermissions)
+	validate.AddValidator("currency", IsCurrency)
+	validate.AddValidator("money", IsMoney)
+	validate.AddValidator("money_min", IsMoneyMin)
+	validate.AddValidator("rounding", IsRounding)
 
 	validate.AddGlobalMessages(map[string]string{
 		"password":    "{field} must be between 8 and 64 characters long, and contain at least one number, one uppercase letter, one lowercase letter, and one special character.",
 		"ulid":        "{field} must be a valid ULID.",
 		"permissions": "{field} must be a valid permission",
+		"currency":    "{field} must be a valid ISO 4217 currency code.",
+		"money":       "{field} must have an amount in minor units and a valid ISO 4217 currency code.",
+		"money_min":   "{field} must have a valid currency and an amount of at least %v minor units.",
+		"rounding":    "{field} must be a valid rounding mode.",
 	})
 
 	return &Validator{}
@@ -49,7 +58,7 @@ func (*Validator) Validate(s interface{}) error {
 		err.Attr(name, details)
 	}
 
-	return err.Layer(errors.LayerPkg).Msg("bad request")
+	return err.Layer(errors.LayerPkg).Msg(errors.MsgBadRequest)
 }
 
 // IsPassword reports wheter a input is a valid password.
@@ -103,3 +112,56 @@ func IsPermissions(inputs []auth.Permission) bool {
 
 	return true
 }
+
+// IsCurrency reports whether a input is an ISO 4217 currency code in circulation, in upper case.
+func IsCurrency(input any) bool {
+	switch c := input.(type) {
+	case string:
+		return money.Currency(c).Valid()
+	case money.Currency:
+		return c.Valid()
+	default:
+		return false
+	}
+}
+
+// IsMoney reports whether a input is an amount of money of a valid currency.
+func IsMoney(input any) bool {
+	switch m := input.(type) {
+	case money.Money:
+		return m.Currency.Valid()
+	case *money.Money:
+		return m != nil && m.Currency.Valid()
+	default:
+		return false
+	}
+}
+
+// IsMoneyMin reports whether a input is an amount of money of a valid currency of at least min minor
+// units.
+func IsMoneyMin(input any, min int64) bool {
+	if !IsMoney(input) {
+		return false
+	}
+
+	switch m := input.(type) {
+	case money.Money:
+		return m.Amount >= min
+	case *money.Money:
+		return m.Amount >= min
+	default:
+		return false
+	}
+}
+
+// IsRounding reports whether a input is one of [money.Roundings].
+func IsRounding(input any) bool {
+	switch r := input.(type) {
+	case string:
+		return money.Rounding(r).Valid()
+	case money.Rounding:
+		return r.Valid()
+	default:
+		return false
+	}
+}
@@ -3,6 +3,8 @@ package validator_test
 import (
 	"testing"
 
+	"github.com/heiytor/invenda/api/pkg/errors"
+	"github.com/heiytor/invenda/api/pkg/money"
 	"github.com/heiytor/invenda/api/pkg/validator"
 	"github.com/stretchr/testify/require"
 )
@@ -76,3 +78,66 @@ func TestIsPassword(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateMoney(t *testing.T) {
+	type request struct {
+		Price    money.Money  `json:"price" validate:"required|money_min:0"`
+		Cost     *money.Money `json:"cost" validate:"money"`
+		Currency string       `json:"currency" validate:"currency"`
+		Rounding string       `json:"rounding" validate:"rounding"`
+	}
+
+	v := validator.New()
+
+	cases := []struct {
+		description string
+		req         *request
+		fields      []string
+	}{
+		{
+			description: "valid amounts",
+			req:         &request{Price: money.New(1000, "BRL"), Cost: &money.Money{Amount: 500, Currency: "USD"}, Currency: "USD", Rounding: "half_even"},
+			fields:      []string{},
+		},
+		{
+			description: "absent optional fields",
+			req:         &request{Price: money.New(0, "BRL")},
+			fields:      []string{},
+		},
+		{
+			description: "missing required amount",
+			req:         &request{},
+			fields:      []string{"price"},
+		},
+		{
+			description: "negative amount",
+			req:         &request{Price: money.New(-1, "BRL")},
+			fields:      []string{"price"},
+		},
+		{
+			description: "amounts and codes of invalid currencies",
+			req:         &request{Price: money.New(1000, "XXX"), Cost: &money.Money{Amount: 500}, Currency: "usd", Rounding: "nearest"},
+			fields:      []string{"cost", "currency", "price", "rounding"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			err := v.Validate(tc.req)
+			if len(tc.fields) == 0 {
+				require.NoError(t, err)
+
+				return
+			}
+
+			require.Error(t, err)
+
+			fields := make([]string, 0)
+			for f := range errors.As(err).Attrs {
+				fields = append(fields, f)
+			}
+
+			require.ElementsMatch(t, tc.fields, fields)
+		})
+	}
+}
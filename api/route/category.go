@@ -0,0 +1,314 @@
+package route
+
+import (
+	"net/http"
+
+	"github.com/heiytor/invenda/api/pkg/auth"
+	"github.com/heiytor/invenda/api/pkg/errors"
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/requests"
+	"github.com/labstack/echo/v4"
+)
+
+func (rs *Routes) categoryList() *route[ProtectedHandler] {
+	return &route[ProtectedHandler]{
+		method:      http.MethodGet,
+		path:        "/categories",
+		group:       GroupPublic,
+		middlewares: []echo.MiddlewareFunc{},
+		handler: func(c echo.Context, s *models.Session) error {
+			ctx := c.Request().Context()
+			req := new(requests.ListCategory)
+
+			if !auth.Report(s.Permissions, auth.ProductRead) {
+				return errors.
+					New().
+					Layer(errors.LayerRoute).
+					Attr("required", auth.ProductRead).
+					Code(http.StatusForbidden).
+					Msg(errors.MsgInsufficientPermission)
+			}
+
+			if err := c.Bind(req); err != nil {
+				return err
+			}
+
+			req.Paginator.Normalize()
+			req.Sorter.NormalizeWith("name")
+
+			if err := c.Validate(req); err != nil {
+				return err
+			}
+
+			if err := req.Query.Resolve(requests.CategoryFields); err != nil {
+				return err
+			}
+
+			categories, count, err := rs.service.ListCategory(ctx, s.NamespaceID, req)
+			if err != nil {
+				return err
+			}
+
+			return list(c, &req.Paginator, categories, count)
+		},
+	}
+}
+
+func (rs *Routes) categoryGet() *route[ProtectedHandler] {
+	return &route[ProtectedHandler]{
+		method:      http.MethodGet,
+		path:        "/categories/:id",
+		group:       GroupPublic,
+		middlewares: []echo.MiddlewareFunc{},
+		handler: func(c echo.Context, s *models.Session) error {
+			ctx := c.Request().Context()
+			req := new(requests.GetCategory)
+
+			if !auth.Report(s.Permissions, auth.ProductRead) {
+				return errors.
+					New().
+					Layer(errors.LayerRoute).
+					Attr("required", auth.ProductRead).
+					Code(http.StatusForbidden).
+					Msg(errors.MsgInsufficientPermission)
+			}
+
+			if err := c.Bind(req); err != nil {
+				return err
+			}
+
+			if err := c.Validate(req); err != nil {
+				return err
+			}
+
+			cat, err := rs.service.GetCategory(ctx, s.NamespaceID, req)
+			if err != nil {
+				return err
+			}
+
+			return c.JSON(http.StatusOK, cat)
+		},
+	}
+}
+
+func (rs *Routes) categoryCreate() *route[ProtectedHandler] {
+	return &route[ProtectedHandler]{
+		method:      http.MethodPost,
+		path:        "/categories",
+		group:       GroupPublic,
+		middlewares: []echo.MiddlewareFunc{},
+		handler: func(c echo.Context, s *models.Session) error {
+			ctx := c.Request().Context()
+			req := new(requests.CreateCategory)
+
+			if !auth.Report(s.Permissions, auth.ProductWrite) {
+				return errors.
+					New().
+					Layer(errors.LayerRoute).
+					Attr("required", auth.ProductWrite).
+					Code(http.StatusForbidden).
+					Msg(errors.MsgInsufficientPermission)
+			}
+
+			if err := c.Bind(req); err != nil {
+				return err
+			}
+
+			if err := c.Validate(req); err != nil {
+				return err
+			}
+
+			insertedID, err := rs.service.CreateCategory(ctx, s.NamespaceID, req)
+			if err != nil {
+				return err
+			}
+
+			c.Response().Header().Set("X-Inserted-Id", insertedID)
+			return c.NoContent(http.StatusCreated)
+		},
+	}
+}
+
+func (rs *Routes) categoryUpdate() *route[ProtectedHandler] {
+	return &route[ProtectedHandler]{
+		method:      http.MethodPatch,
+		path:        "/categories/:id",
+		group:       GroupPublic,
+		middlewares: []echo.MiddlewareFunc{},
+		handler: func(c echo.Context, s *models.Session) error {
+			ctx := c.Request().Context()
+			req := new(requests.UpdateCategory)
+
+			if !auth.Report(s.Permissions, auth.ProductWrite) {
+				return errors.
+					New().
+					Layer(errors.LayerRoute).
+					Attr("required", auth.ProductWrite).
+					Code(http.StatusForbidden).
+					Msg(errors.MsgInsufficientPermission)
+			}
+
+			if err := c.Bind(req); err != nil {
+				return err
+			}
+
+			if err := c.Validate(req); err != nil {
+				return err
+			}
+
+			cat, err := rs.service.UpdateCategory(ctx, s.NamespaceID, req)
+			if err != nil {
+				return err
+			}
+
+			return c.JSON(http.StatusOK, cat)
+		},
+	}
+}
+
+func (rs *Routes) categoryMove() *route[ProtectedHandler] {
+	return &route[ProtectedHandler]{
+		method:      http.MethodPost,
+		path:        "/categories/:id/move",
+		group:       GroupPublic,
+		middlewares: []echo.MiddlewareFunc{},
+		handler: func(c echo.Context, s *models.Session) error {
+			ctx := c.Request().Context()
+			req := new(requests.MoveCategory)
+
+			if !auth.Report(s.Permissions, auth.ProductWrite) {
+				return errors.
+					New().
+					Layer(errors.LayerRoute).
+					Attr("required", auth.ProductWrite).
+					Code(http.StatusForbidden).
+					Msg(errors.MsgInsufficientPermission)
+			}
+
+			if err := c.Bind(req); err != nil {
+				return err
+			}
+
+			if err := c.Validate(req); err != nil {
+				return err
+			}
+
+			cat, err := rs.service.MoveCategory(ctx, s.NamespaceID, req)
+			if err != nil {
+				return err
+			}
+
+			return c.JSON(http.StatusOK, cat)
+		},
+	}
+}
+
+func (rs *Routes) categoryDelete() *route[ProtectedHandler] {
+	return &route[ProtectedHandler]{
+		method:      http.MethodDelete,
+		path:        "/categories/:id",
+		group:       GroupPublic,
+		middlewares: []echo.MiddlewareFunc{},
+		handler: func(c echo.Context, s *models.Session) error {
+			ctx := c.Request().Context()
+			req := new(requests.DeleteCategory)
+
+			if !auth.Report(s.Permissions, auth.ProductDelete) {
+				return errors.
+					New().
+					Layer(errors.LayerRoute).
+					Attr("required", auth.ProductDelete).
+					Code(http.StatusForbidden).
+					Msg(errors.MsgInsufficientPermission)
+			}
+
+			if err := c.Bind(req); err != nil {
+				return err
+			}
+
+			if err := c.Validate(req); err != nil {
+				return err
+			}
+
+			if err := rs.service.DeleteCategory(ctx, s.NamespaceID, req); err != nil {
+				return err
+			}
+
+			return c.NoContent(http.StatusNoContent)
+		},
+	}
+}
+
+func (rs *Routes) categoryAssignProducts() *route[ProtectedHandler] {
+	return &route[ProtectedHandler]{
+		method:      http.MethodPost,
+		path:        "/categories/:id/products",
+		group:       GroupPublic,
+		middlewares: []echo.MiddlewareFunc{},
+		handler: func(c echo.Context, s *models.Session) error {
+			ctx := c.Request().Context()
+			req := new(requests.CategoryProducts)
+
+			if !auth.Report(s.Permissions, auth.ProductWrite) {
+				return errors.
+					New().
+					Layer(errors.LayerRoute).
+					Attr("required", auth.ProductWrite).
+					Code(http.StatusForbidden).
+					Msg(errors.MsgInsufficientPermission)
+			}
+
+			if err := c.Bind(req); err != nil {
+				return err
+			}
+
+			if err := c.Validate(req); err != nil {
+				return err
+			}
+
+			count, err := rs.service.AssignCategoryProducts(ctx, s.NamespaceID, req)
+			if err != nil {
+				return err
+			}
+
+			return c.JSON(http.StatusOK, map[string]int64{"modified": count})
+		},
+	}
+}
+
+func (rs *Routes) categoryUnassignProducts() *route[ProtectedHandler] {
+	return &route[ProtectedHandler]{
+		method:      http.MethodDelete,
+		path:        "/categories/:id/products",
+		group:       GroupPublic,
+		middlewares: []echo.MiddlewareFunc{},
+		handler: func(c echo.Context, s *models.Session) error {
+			ctx := c.Request().Context()
+			req := new(requests.CategoryProducts)
+
+			if !auth.Report(s.Permissions, auth.ProductWrite) {
+				return errors.
+					New().
+					Layer(errors.LayerRoute).
+					Attr("required", auth.ProductWrite).
+					Code(http.StatusForbidden).
+					Msg(errors.MsgInsufficientPermission)
+			}
+
+			if err := c.Bind(req); err != nil {
+				return err
+			}
+
+			if err := c.Validate(req); err != nil {
+				return err
+			}
+
+			count, err := rs.service.UnassignCategoryProducts(ctx, s.NamespaceID, req)
+			if err != nil {
+				return err
+			}
+
+			return c.JSON(http.StatusOK, map[string]int64{"modified": count})
+		},
+	}
+}
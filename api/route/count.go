@@ -0,0 +1,278 @@
+package route
+
+import (
+	"net/http"
+
+	"github.com/heiytor/invenda/api/pkg/auth"
+	"github.com/heiytor/invenda/api/pkg/errors"
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/requests"
+	"github.com/labstack/echo/v4"
+)
+
+func (rs *Routes) stockCountList() *route[ProtectedHandler] {
+	return &route[ProtectedHandler]{
+		method:      http.MethodGet,
+		path:        "/stock-counts",
+		group:       GroupPublic,
+		middlewares: []echo.MiddlewareFunc{},
+		handler: func(c echo.Context, s *models.Session) error {
+			ctx := c.Request().Context()
+			req := new(requests.ListStockCount)
+
+			if !auth.Report(s.Permissions, auth.StockRead) {
+				return errors.
+					New().
+					Layer(errors.LayerRoute).
+					Attr("required", auth.StockRead).
+					Code(http.StatusForbidden).
+					Msg(errors.MsgInsufficientPermission)
+			}
+
+			if err := c.Bind(req); err != nil {
+				return err
+			}
+
+			req.Paginator.Normalize()
+			req.Sorter.NormalizeWith("created_at")
+
+			if err := c.Validate(req); err != nil {
+				return err
+			}
+
+			if err := req.Query.Resolve(requests.StockCountFields); err != nil {
+				return err
+			}
+
+			counts, count, err := rs.service.ListStockCount(ctx, s.NamespaceID, req)
+			if err != nil {
+				return err
+			}
+
+			return list(c, &req.Paginator, counts, count)
+		},
+	}
+}
+
+func (rs *Routes) stockCountGet() *route[ProtectedHandler] {
+	return &route[ProtectedHandler]{
+		method:      http.MethodGet,
+		path:        "/stock-counts/:id",
+		group:       GroupPublic,
+		middlewares: []echo.MiddlewareFunc{},
+		handler: func(c echo.Context, s *models.Session) error {
+			ctx := c.Request().Context()
+			req := new(requests.GetStockCount)
+
+			if !auth.Report(s.Permissions, auth.StockRead) {
+				return errors.
+					New().
+					Layer(errors.LayerRoute).
+					Attr("required", auth.StockRead).
+					Code(http.StatusForbidden).
+					Msg(errors.MsgInsufficientPermission)
+			}
+
+			if err := c.Bind(req); err != nil {
+				return err
+			}
+
+			if err := c.Validate(req); err != nil {
+				return err
+			}
+
+			count, err := rs.service.GetStockCount(ctx, s.NamespaceID, req)
+			if err != nil {
+				return err
+			}
+
+			return c.JSON(http.StatusOK, count)
+		},
+	}
+}
+
+func (rs *Routes) stockCountCreate() *route[ProtectedHandler] {
+	return &route[ProtectedHandler]{
+		method:      http.MethodPost,
+		path:        "/stock-counts",
+		group:       GroupPublic,
+		middlewares: []echo.MiddlewareFunc{},
+		handler: func(c echo.Context, s *models.Session) error {
+			ctx := c.Request().Context()
+			req := new(requests.CreateStockCount)
+
+			if !auth.Report(s.Permissions, auth.StockWrite) {
+				return errors.
+					New().
+					Layer(errors.LayerRoute).
+					Attr("required", auth.StockWrite).
+					Code(http.StatusForbidden).
+					Msg(errors.MsgInsufficientPermission)
+			}
+
+			if err := c.Bind(req); err != nil {
+				return err
+			}
+
+			if err := c.Validate(req); err != nil {
+				return err
+			}
+
+			insertedID, err := rs.service.CreateStockCount(ctx, s.NamespaceID, s.UserID, req)
+			if err != nil {
+				return err
+			}
+
+			c.Response().Header().Set("X-Inserted-Id", insertedID)
+			return c.NoContent(http.StatusCreated)
+		},
+	}
+}
+
+func (rs *Routes) stockCountSubmit() *route[ProtectedHandler] {
+	return &route[ProtectedHandler]{
+		method:      http.MethodPost,
+		path:        "/stock-counts/:id/items",
+		group:       GroupPublic,
+		middlewares: []echo.MiddlewareFunc{},
+		handler: func(c echo.Context, s *models.Session) error {
+			ctx := c.Request().Context()
+			req := new(requests.SubmitStockCount)
+
+			if !auth.Report(s.Permissions, auth.StockWrite) {
+				return errors.
+					New().
+					Layer(errors.LayerRoute).
+					Attr("required", auth.StockWrite).
+					Code(http.StatusForbidden).
+					Msg(errors.MsgInsufficientPermission)
+			}
+
+			if err := c.Bind(req); err != nil {
+				return err
+			}
+
+			if err := c.Validate(req); err != nil {
+				return err
+			}
+
+			count, err := rs.service.SubmitStockCount(ctx, s.NamespaceID, s.UserID, req)
+			if err != nil {
+				return err
+			}
+
+			return c.JSON(http.StatusOK, count)
+		},
+	}
+}
+
+func (rs *Routes) stockCountVariance() *route[ProtectedHandler] {
+	return &route[ProtectedHandler]{
+		method:      http.MethodGet,
+		path:        "/stock-counts/:id/variance",
+		group:       GroupPublic,
+		middlewares: []echo.MiddlewareFunc{},
+		handler: func(c echo.Context, s *models.Session) error {
+			ctx := c.Request().Context()
+			req := new(requests.GetStockCountVariance)
+
+			if !auth.Report(s.Permissions, auth.StockRead) {
+				return errors.
+					New().
+					Layer(errors.LayerRoute).
+					Attr("required", auth.StockRead).
+					Code(http.StatusForbidden).
+					Msg(errors.MsgInsufficientPermission)
+			}
+
+			if err := c.Bind(req); err != nil {
+				return err
+			}
+
+			if err := c.Validate(req); err != nil {
+				return err
+			}
+
+			variances, err := rs.service.GetStockCountVariance(ctx, s.NamespaceID, req)
+			if err != nil {
+				return err
+			}
+
+			return c.JSON(http.StatusOK, variances)
+		},
+	}
+}
+
+func (rs *Routes) stockCountApprove() *route[ProtectedHandler] {
+	return &route[ProtectedHandler]{
+		method:      http.MethodPost,
+		path:        "/stock-counts/:id/approve",
+		group:       GroupPublic,
+		middlewares: []echo.MiddlewareFunc{},
+		handler: func(c echo.Context, s *models.Session) error {
+			ctx := c.Request().Context()
+			req := new(requests.ApproveStockCount)
+
+			if !auth.Report(s.Permissions, auth.StockWrite) {
+				return errors.
+					New().
+					Layer(errors.LayerRoute).
+					Attr("required", auth.StockWrite).
+					Code(http.StatusForbidden).
+					Msg(errors.MsgInsufficientPermission)
+			}
+
+			if err := c.Bind(req); err != nil {
+				return err
+			}
+
+			if err := c.Validate(req); err != nil {
+				return err
+			}
+
+			count, err := rs.service.ApproveStockCount(ctx, s.NamespaceID, s.UserID, req)
+			if err != nil {
+				return err
+			}
+
+			return c.JSON(http.StatusOK, count)
+		},
+	}
+}
+
+func (rs *Routes) stockCountCancel() *route[ProtectedHandler] {
+	return &route[ProtectedHandler]{
+		method:      http.MethodPost,
+		path:        "/stock-counts/:id/cancel",
+		group:       GroupPublic,
+		middlewares: []echo.MiddlewareFunc{},
+		handler: func(c echo.Context, s *models.Session) error {
+			ctx := c.Request().Context()
+			req := new(requests.CancelStockCount)
+
+			if !auth.Report(s.Permissions, auth.StockWrite) {
+				return errors.
+					New().
+					Layer(errors.LayerRoute).
+					Attr("required", auth.StockWrite).
+					Code(http.StatusForbidden).
+					Msg(errors.MsgInsufficientPermission)
+			}
+
+			if err := c.Bind(req); err != nil {
+				return err
+			}
+
+			if err := c.Validate(req); err != nil {
+				return err
+			}
+
+			count, err := rs.service.CancelStockCount(ctx, s.NamespaceID, req)
+			if err != nil {
+				return err
+			}
+
+			return c.JSON(http.StatusOK, count)
+		},
+	}
+}
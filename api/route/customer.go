@@ -0,0 +1,240 @@
+package route
+
+import (
+	"net/http"
+
+	"github.com/heiytor/invenda/api/pkg/auth"
+	"github.com/heiytor/invenda/api/pkg/errors"
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/requests"
+	"github.com/labstack/echo/v4"
+)
+
+func (rs *Routes) customerList() *route[ProtectedHandler] {
+	return &route[ProtectedHandler]{
+		method:      http.MethodGet,
+		path:        "/customers",
+		group:       GroupPublic,
+		middlewares: []echo.MiddlewareFunc{},
+		handler: func(c echo.Context, s *models.Session) error {
+			ctx := c.Request().Context()
+			req := new(requests.ListCustomer)
+
+			if !auth.Report(s.Permissions, auth.CustomerRead) {
+				return errors.
+					New().
+					Layer(errors.LayerRoute).
+					Attr("required", auth.CustomerRead).
+					Code(http.StatusForbidden).
+					Msg(errors.MsgInsufficientPermission)
+			}
+
+			if err := c.Bind(req); err != nil {
+				return err
+			}
+
+			req.Paginator.Normalize()
+			req.Sorter.NormalizeWith("created_at")
+
+			if err := c.Validate(req); err != nil {
+				return err
+			}
+
+			if err := req.Query.Resolve(requests.CustomerFields); err != nil {
+				return err
+			}
+
+			customers, count, err := rs.service.ListCustomer(ctx, s.NamespaceID, req)
+			if err != nil {
+				return err
+			}
+
+			return list(c, &req.Paginator, customers, count)
+		},
+	}
+}
+
+func (rs *Routes) customerGet() *route[ProtectedHandler] {
+	return &route[ProtectedHandler]{
+		method:      http.MethodGet,
+		path:        "/customers/:id",
+		group:       GroupPublic,
+		middlewares: []echo.MiddlewareFunc{},
+		handler: func(c echo.Context, s *models.Session) error {
+			ctx := c.Request().Context()
+			req := new(requests.GetCustomer)
+
+			if !auth.Report(s.Permissions, auth.CustomerRead) {
+				return errors.
+					New().
+					Layer(errors.LayerRoute).
+					Attr("required", auth.CustomerRead).
+					Code(http.StatusForbidden).
+					Msg(errors.MsgInsufficientPermission)
+			}
+
+			if err := c.Bind(req); err != nil {
+				return err
+			}
+
+			if err := c.Validate(req); err != nil {
+				return err
+			}
+
+			cus, err := rs.service.GetCustomer(ctx, s.NamespaceID, req)
+			if err != nil {
+				return err
+			}
+
+			return c.JSON(http.StatusOK, cus)
+		},
+	}
+}
+
+func (rs *Routes) customerCreate() *route[ProtectedHandler] {
+	return &route[ProtectedHandler]{
+		method:      http.MethodPost,
+		path:        "/customers",
+		group:       GroupPublic,
+		middlewares: []echo.MiddlewareFunc{},
+		handler: func(c echo.Context, s *models.Session) error {
+			ctx := c.Request().Context()
+			req := new(requests.CreateCustomer)
+
+			if !auth.Report(s.Permissions, auth.CustomerWrite) {
+				return errors.
+					New().
+					Layer(errors.LayerRoute).
+					Attr("required", auth.CustomerWrite).
+					Code(http.StatusForbidden).
+					Msg(errors.MsgInsufficientPermission)
+			}
+
+			if err := c.Bind(req); err != nil {
+				return err
+			}
+
+			if err := c.Validate(req); err != nil {
+				return err
+			}
+
+			insertedID, err := rs.service.CreateCustomer(ctx, s.NamespaceID, req)
+			if err != nil {
+				return err
+			}
+
+			c.Response().Header().Set("X-Inserted-Id", insertedID)
+			return c.NoContent(http.StatusCreated)
+		},
+	}
+}
+
+func (rs *Routes) customerUpdate() *route[ProtectedHandler] {
+	return &route[ProtectedHandler]{
+		method:      http.MethodPatch,
+		path:        "/customers/:id",
+		group:       GroupPublic,
+		middlewares: []echo.MiddlewareFunc{},
+		handler: func(c echo.Context, s *models.Session) error {
+			ctx := c.Request().Context()
+			req := new(requests.UpdateCustomer)
+
+			if !auth.Report(s.Permissions, auth.CustomerWrite) {
+				return errors.
+					New().
+					Layer(errors.LayerRoute).
+					Attr("required", auth.CustomerWrite).
+					Code(http.StatusForbidden).
+					Msg(errors.MsgInsufficientPermission)
+			}
+
+			if err := c.Bind(req); err != nil {
+				return err
+			}
+
+			if err := c.Validate(req); err != nil {
+				return err
+			}
+
+			cus, err := rs.service.UpdateCustomer(ctx, s.NamespaceID, req)
+			if err != nil {
+				return err
+			}
+
+			return c.JSON(http.StatusOK, cus)
+		},
+	}
+}
+
+func (rs *Routes) customerDelete() *route[ProtectedHandler] {
+	return &route[ProtectedHandler]{
+		method:      http.MethodDelete,
+		path:        "/customers/:id",
+		group:       GroupPublic,
+		middlewares: []echo.MiddlewareFunc{},
+		handler: func(c echo.Context, s *models.Session) error {
+			ctx := c.Request().Context()
+			req := new(requests.DeleteCustomer)
+
+			if !auth.Report(s.Permissions, auth.CustomerDelete) {
+				return errors.
+					New().
+					Layer(errors.LayerRoute).
+					Attr("required", auth.CustomerDelete).
+					Code(http.StatusForbidden).
+					Msg(errors.MsgInsufficientPermission)
+			}
+
+			if err := c.Bind(req); err != nil {
+				return err
+			}
+
+			if err := c.Validate(req); err != nil {
+				return err
+			}
+
+			if err := rs.service.DeleteCustomer(ctx, s.NamespaceID, req); err != nil {
+				return err
+			}
+
+			return c.NoContent(http.StatusNoContent)
+		},
+	}
+}
+
+func (rs *Routes) customerHistoryGet() *route[ProtectedHandler] {
+	return &route[ProtectedHandler]{
+		method:      http.MethodGet,
+		path:        "/customers/:id/history",
+		group:       GroupPublic,
+		middlewares: []echo.MiddlewareFunc{},
+		handler: func(c echo.Context, s *models.Session) error {
+			ctx := c.Request().Context()
+			req := new(requests.GetCustomerHistory)
+
+			if !auth.Report(s.Permissions, auth.CustomerRead) {
+				return errors.
+					New().
+					Layer(errors.LayerRoute).
+					Attr("required", auth.CustomerRead).
+					Code(http.StatusForbidden).
+					Msg(errors.MsgInsufficientPermission)
+			}
+
+			if err := c.Bind(req); err != nil {
+				return err
+			}
+
+			if err := c.Validate(req); err != nil {
+				return err
+			}
+
+			history, err := rs.service.GetCustomerHistory(ctx, s.NamespaceID, req)
+			if err != nil {
+				return err
+			}
+
+			return c.JSON(http.StatusOK, history)
+		},
+	}
+}
@@ -0,0 +1,230 @@
+package route
+
+import (
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/heiytor/invenda/api/pkg/auth"
+	"github.com/heiytor/invenda/api/pkg/errors"
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/requests"
+	"github.com/labstack/echo/v4"
+)
+
+func (rs *Routes) exchangeRateList() *route[ProtectedHandler] {
+	return &route[ProtectedHandler]{
+		method:      http.MethodGet,
+		path:        "/exchange-rates",
+		group:       GroupPublic,
+		middlewares: []echo.MiddlewareFunc{},
+		handler: func(c echo.Context, s *models.Session) error {
+			ctx := c.Request().Context()
+			req := new(requests.ListExchangeRate)
+
+			if !auth.Report(s.Permissions, auth.ExchangeRead) {
+				return errors.
+					New().
+					Layer(errors.LayerRoute).
+					Attr("required", auth.ExchangeRead).
+					Code(http.StatusForbidden).
+					Msg(errors.MsgInsufficientPermission)
+			}
+
+			if err := c.Bind(req); err != nil {
+				return err
+			}
+
+			req.Paginator.Normalize()
+			req.Sorter.NormalizeWith("created_at")
+
+			if err := c.Validate(req); err != nil {
+				return err
+			}
+
+			if err := req.Query.Resolve(requests.ExchangeRateFields); err != nil {
+				return err
+			}
+
+			exchangeRates, count, err := rs.service.ListExchangeRate(ctx, s.NamespaceID, req)
+			if err != nil {
+				return err
+			}
+
+			return list(c, &req.Paginator, exchangeRates, count)
+		},
+	}
+}
+
+func (rs *Routes) exchangeRateGet() *route[ProtectedHandler] {
+	return &route[ProtectedHandler]{
+		method:      http.MethodGet,
+		path:        "/exchange-rates/:id",
+		group:       GroupPublic,
+		middlewares: []echo.MiddlewareFunc{},
+		handler: func(c echo.Context, s *models.Session) error {
+			ctx := c.Request().Context()
+			req := new(requests.GetExchangeRate)
+
+			if !auth.Report(s.Permissions, auth.ExchangeRead) {
+				return errors.
+					New().
+					Layer(errors.LayerRoute).
+					Attr("required", auth.ExchangeRead).
+					Code(http.StatusForbidden).
+					Msg(errors.MsgInsufficientPermission)
+			}
+
+			if err := c.Bind(req); err != nil {
+				return err
+			}
+
+			if err := c.Validate(req); err != nil {
+				return err
+			}
+
+			exchangeRate, err := rs.service.GetExchangeRate(ctx, s.NamespaceID, req)
+			if err != nil {
+				return err
+			}
+
+			return c.JSON(http.StatusOK, exchangeRate)
+		},
+	}
+}
+
+func (rs *Routes) exchangeRateLoad() *route[ProtectedHandler] {
+	return &route[ProtectedHandler]{
+		method:      http.MethodPost,
+		path:        "/exchange-rates",
+		group:       GroupPublic,
+		middlewares: []echo.MiddlewareFunc{},
+		handler: func(c echo.Context, s *models.Session) error {
+			ctx := c.Request().Context()
+			req := new(requests.LoadExchangeRates)
+
+			if !auth.Report(s.Permissions, auth.ExchangeWrite) {
+				return errors.
+					New().
+					Layer(errors.LayerRoute).
+					Attr("required", auth.ExchangeWrite).
+					Code(http.StatusForbidden).
+					Msg(errors.MsgInsufficientPermission)
+			}
+
+			if err := c.Bind(req); err != nil {
+				return err
+			}
+
+			if err := c.Validate(req); err != nil {
+				return err
+			}
+
+			load, err := rs.service.LoadExchangeRates(ctx, s.NamespaceID, req)
+			if err != nil {
+				return err
+			}
+
+			return c.JSON(http.StatusOK, load)
+		},
+	}
+}
+
+func (rs *Routes) exchangeRateImport() *route[ProtectedHandler] {
+	return &route[ProtectedHandler]{
+		method:      http.MethodPost,
+		path:        "/exchange-rates/import",
+		group:       GroupPublic,
+		middlewares: []echo.MiddlewareFunc{},
+		handler: func(c echo.Context, s *models.Session) error {
+			ctx := c.Request().Context()
+			req := new(requests.LoadExchangeRates)
+
+			if !auth.Report(s.Permissions, auth.ExchangeWrite) {
+				return errors.
+					New().
+					Layer(errors.LayerRoute).
+					Attr("required", auth.ExchangeWrite).
+					Code(http.StatusForbidden).
+					Msg(errors.MsgInsufficientPermission)
+			}
+
+			// The CSV file is either uploaded as the "file" field of a multipart form or sent as the body.
+			var file io.Reader = c.Request().Body
+			if strings.HasPrefix(c.Request().Header.Get(echo.HeaderContentType), echo.MIMEMultipartForm) {
+				header, err := c.FormFile("file")
+				if err != nil {
+					return errors.
+						New().
+						Layer(errors.LayerRoute).
+						Attr("file", []string{"a CSV file is required"}).
+						Code(http.StatusBadRequest).
+						Msg(errors.MsgBadRequest)
+				}
+
+				f, err := header.Open()
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+
+				file = f
+			}
+
+			rates, err := models.ParseExchangeRates(file)
+			if err != nil {
+				return errors.
+					New().
+					Layer(errors.LayerRoute).
+					Attr("file", []string{err.Error()}).
+					Code(http.StatusBadRequest).
+					Msg(errors.MsgBadRequest)
+			}
+
+			req.Rates = rates
+
+			load, err := rs.service.LoadExchangeRates(ctx, s.NamespaceID, req)
+			if err != nil {
+				return err
+			}
+
+			return c.JSON(http.StatusOK, load)
+		},
+	}
+}
+
+func (rs *Routes) exchangeRateDelete() *route[ProtectedHandler] {
+	return &route[ProtectedHandler]{
+		method:      http.MethodDelete,
+		path:        "/exchange-rates/:id",
+		group:       GroupPublic,
+		middlewares: []echo.MiddlewareFunc{},
+		handler: func(c echo.Context, s *models.Session) error {
+			ctx := c.Request().Context()
+			req := new(requests.DeleteExchangeRate)
+
+			if !auth.Report(s.Permissions, auth.ExchangeDelete) {
+				return errors.
+					New().
+					Layer(errors.LayerRoute).
+					Attr("required", auth.ExchangeDelete).
+					Code(http.StatusForbidden).
+					Msg(errors.MsgInsufficientPermission)
+			}
+
+			if err := c.Bind(req); err != nil {
+				return err
+			}
+
+			if err := c.Validate(req); err != nil {
+				return err
+			}
+
+			if err := rs.service.DeleteExchangeRate(ctx, s.NamespaceID, req); err != nil {
+				return err
+			}
+
+			return c.NoContent(http.StatusNoContent)
+		},
+	}
+}
@@ -0,0 +1,172 @@
+package route
+
+import (
+	"net/http"
+
+	"github.com/heiytor/invenda/api/pkg/auth"
+	"github.com/heiytor/invenda/api/pkg/errors"
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/requests"
+	"github.com/labstack/echo/v4"
+)
+
+func (rs *Routes) stockLevelList() *route[ProtectedHandler] {
+	return &route[ProtectedHandler]{
+		method:      http.MethodGet,
+		path:        "/stock-levels",
+		group:       GroupPublic,
+		middlewares: []echo.MiddlewareFunc{},
+		handler: func(c echo.Context, s *models.Session) error {
+			ctx := c.Request().Context()
+			req := new(requests.ListStockLevel)
+
+			if !auth.Report(s.Permissions, auth.StockRead) {
+				return errors.
+					New().
+					Layer(errors.LayerRoute).
+					Attr("required", auth.StockRead).
+					Code(http.StatusForbidden).
+					Msg(errors.MsgInsufficientPermission)
+			}
+
+			if err := c.Bind(req); err != nil {
+				return err
+			}
+
+			req.Paginator.Normalize()
+			req.Sorter.NormalizeWith("updated_at")
+
+			if err := c.Validate(req); err != nil {
+				return err
+			}
+
+			if err := req.Query.Resolve(requests.StockLevelFields); err != nil {
+				return err
+			}
+
+			levels, count, err := rs.service.ListStockLevel(ctx, s.NamespaceID, req)
+			if err != nil {
+				return err
+			}
+
+			return list(c, &req.Paginator, levels, count)
+		},
+	}
+}
+
+func (rs *Routes) stockLevelSet() *route[ProtectedHandler] {
+	return &route[ProtectedHandler]{
+		method:      http.MethodPut,
+		path:        "/stock-levels",
+		group:       GroupPublic,
+		middlewares: []echo.MiddlewareFunc{},
+		handler: func(c echo.Context, s *models.Session) error {
+			ctx := c.Request().Context()
+			req := new(requests.SetStockLevel)
+
+			if !auth.Report(s.Permissions, auth.StockWrite) {
+				return errors.
+					New().
+					Layer(errors.LayerRoute).
+					Attr("required", auth.StockWrite).
+					Code(http.StatusForbidden).
+					Msg(errors.MsgInsufficientPermission)
+			}
+
+			if err := c.Bind(req); err != nil {
+				return err
+			}
+
+			if err := c.Validate(req); err != nil {
+				return err
+			}
+
+			level, err := rs.service.SetStockLevel(ctx, s.NamespaceID, req)
+			if err != nil {
+				return err
+			}
+
+			return c.JSON(http.StatusOK, level)
+		},
+	}
+}
+
+func (rs *Routes) stockLevelDelete() *route[ProtectedHandler] {
+	return &route[ProtectedHandler]{
+		method:      http.MethodDelete,
+		path:        "/stock-levels/:id",
+		group:       GroupPublic,
+		middlewares: []echo.MiddlewareFunc{},
+		handler: func(c echo.Context, s *models.Session) error {
+			ctx := c.Request().Context()
+			req := new(requests.DeleteStockLevel)
+
+			if !auth.Report(s.Permissions, auth.StockWrite) {
+				return errors.
+					New().
+					Layer(errors.LayerRoute).
+					Attr("required", auth.StockWrite).
+					Code(http.StatusForbidden).
+					Msg(errors.MsgInsufficientPermission)
+			}
+
+			if err := c.Bind(req); err != nil {
+				return err
+			}
+
+			if err := c.Validate(req); err != nil {
+				return err
+			}
+
+			if err := rs.service.DeleteStockLevel(ctx, s.NamespaceID, req); err != nil {
+				return err
+			}
+
+			return c.NoContent(http.StatusNoContent)
+		},
+	}
+}
+
+func (rs *Routes) reorderSuggestionList() *route[ProtectedHandler] {
+	return &route[ProtectedHandler]{
+		method:      http.MethodGet,
+		path:        "/inventory/reorder-suggestions",
+		group:       GroupPublic,
+		middlewares: []echo.MiddlewareFunc{},
+		handler: func(c echo.Context, s *models.Session) error {
+			ctx := c.Request().Context()
+			req := new(requests.ListReorderSuggestion)
+
+			if !auth.Report(s.Permissions, auth.StockRead) {
+				return errors.
+					New().
+					Layer(errors.LayerRoute).
+					Attr("required", auth.StockRead).
+					Code(http.StatusForbidden).
+					Msg(errors.MsgInsufficientPermission)
+			}
+
+			if err := c.Bind(req); err != nil {
+				return err
+			}
+
+			req.Paginator.Normalize()
+			req.Sorter.NormalizeWith("quantity")
+
+			if err := c.Validate(req); err != nil {
+				return err
+			}
+
+			if err := req.Query.Resolve(requests.ReorderSuggestionFields); err != nil {
+				return err
+			}
+
+			suggestions, count, err := rs.service.ListReorderSuggestion(ctx, s.NamespaceID, req)
+			if err != nil {
+				return err
+			}
+
+			return list(c, &req.Paginator, suggestions, count)
+		},
+	}
+}
@@ -2,7 +2,6 @@ package route
 
 import (
 	"net/http"
-	"strconv"
 
 	"github.com/heiytor/invenda/api/pkg/auth"
 	"github.com/heiytor/invenda/api/pkg/errors"
@@ -33,14 +32,16 @@ func (rs *Routes) namespaceList() *route[ProtectedHandler] {
 				return err
 			}
 
-			ns, count, err := rs.service.ListNamespace(ctx, s.UserID, req)
-			c.Response().Header().Set("X-Total-Count", strconv.FormatInt(count, 10))
+			if err := req.Query.Resolve(requests.NamespaceFields); err != nil {
+				return err
+			}
 
+			ns, count, err := rs.service.ListNamespace(ctx, s.UserID, req)
 			if err != nil {
 				return err
 			}
 
-			return c.JSON(http.StatusOK, ns)
+			return list(c, &req.Paginator, ns, count)
 		},
 	}
 }
@@ -0,0 +1,27 @@
+package route
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/heiytor/invenda/api/pkg/query"
+	"github.com/labstack/echo/v4"
+)
+
+// listResponse is the body of every route that lists documents.
+type listResponse[T any] struct {
+	Data       []T              `json:"data"`
+	Pagination query.Pagination `json:"pagination"`
+}
+
+// list writes items with status 200 along with the pagination metadata of p. The total count
+// is also sent in the "X-Total-Count" header.
+func list[T any](c echo.Context, p *query.Paginator, items []T, total int64) error {
+	c.Response().Header().Set("X-Total-Count", strconv.FormatInt(total, 10))
+
+	if items == nil {
+		items = []T{}
+	}
+
+	return c.JSON(http.StatusOK, &listResponse[T]{Data: items, Pagination: p.Pagination(total)})
+}
@@ -105,6 +105,157 @@ func (rs *Routes) allRoutes() ([]*route[echo.HandlerFunc], []*route[ProtectedHan
 		rs.namespaceCreate(),
 		rs.namespaceUpdate(),
 		rs.namespaceDelete(),
+
+		rs.productList(),
+		rs.productGet(),
+		rs.productCreate(),
+		rs.productUpdate(),
+		rs.productDelete(),
+
+		rs.variantList(),
+		rs.variantGet(),
+		rs.variantCreate(),
+		rs.variantGenerate(),
+		rs.variantUpdate(),
+		rs.variantDelete(),
+
+		rs.categoryList(),
+		rs.categoryGet(),
+		rs.categoryCreate(),
+		rs.categoryUpdate(),
+		rs.categoryMove(),
+		rs.categoryDelete(),
+		rs.categoryAssignProducts(),
+		rs.categoryUnassignProducts(),
+
+		rs.warehouseList(),
+		rs.warehouseGet(),
+		rs.warehouseCreate(),
+		rs.warehouseUpdate(),
+		rs.warehouseDelete(),
+
+		rs.stockMovementList(),
+		rs.stockMovementCreate(),
+		rs.stockBalanceList(),
+		rs.stockBalanceRebuild(),
+
+		rs.transferList(),
+		rs.transferGet(),
+		rs.transferCreate(),
+		rs.transferUpdate(),
+		rs.transferDelete(),
+		rs.transferDispatch(),
+		rs.transferReceive(),
+		rs.transferClose(),
+
+		rs.stockCountList(),
+		rs.stockCountGet(),
+		rs.stockCountCreate(),
+		rs.stockCountSubmit(),
+		rs.stockCountVariance(),
+		rs.stockCountApprove(),
+		rs.stockCountCancel(),
+
+		rs.stockLevelList(),
+		rs.stockLevelSet(),
+		rs.stockLevelDelete(),
+		rs.reorderSuggestionList(),
+
+		rs.stockLotList(),
+		rs.expiringLotList(),
+
+		rs.stockSerialList(),
+		rs.serialHistoryGet(),
+
+		rs.reservationList(),
+		rs.reservationGet(),
+		rs.reservationCreate(),
+		rs.reservationRelease(),
+
+		rs.valuationGet(),
+
+		rs.supplierList(),
+		rs.supplierGet(),
+		rs.supplierCreate(),
+		rs.supplierUpdate(),
+		rs.supplierDelete(),
+
+		rs.purchaseOrderList(),
+		rs.purchaseOrderGet(),
+		rs.purchaseOrderCreate(),
+		rs.purchaseOrderUpdate(),
+		rs.purchaseOrderDelete(),
+		rs.purchaseOrderSend(),
+		rs.purchaseOrderReceive(),
+		rs.purchaseOrderClose(),
+		rs.purchaseOrderCancel(),
+
+		rs.customerList(),
+		rs.customerGet(),
+		rs.customerCreate(),
+		rs.customerUpdate(),
+		rs.customerDelete(),
+		rs.customerHistoryGet(),
+
+		rs.salesOrderList(),
+		rs.salesOrderGet(),
+		rs.salesOrderCreate(),
+		rs.salesOrderUpdate(),
+		rs.salesOrderDelete(),
+		rs.salesOrderConfirm(),
+		rs.salesOrderPick(),
+		rs.salesOrderShip(),
+		rs.salesOrderInvoice(),
+		rs.salesOrderClose(),
+		rs.salesOrderCancel(),
+
+		rs.registerList(),
+		rs.registerGet(),
+		rs.registerCreate(),
+		rs.registerUpdate(),
+		rs.registerDelete(),
+
+		rs.saleList(),
+		rs.saleGet(),
+		rs.saleCreate(),
+		rs.saleItemLookup(),
+
+		rs.shiftList(),
+		rs.shiftGet(),
+		rs.shiftOpen(),
+		rs.shiftCash(),
+		rs.shiftClose(),
+
+		rs.returnList(),
+		rs.returnGet(),
+		rs.returnCreate(),
+
+		rs.priceListList(),
+		rs.priceListGet(),
+		rs.priceListCreate(),
+		rs.priceListUpdate(),
+		rs.priceListDelete(),
+		rs.priceResolve(),
+
+		rs.promotionList(),
+		rs.promotionGet(),
+		rs.promotionCreate(),
+		rs.promotionUpdate(),
+		rs.promotionDelete(),
+		rs.promotionEvaluate(),
+
+		rs.taxRateList(),
+		rs.taxRateGet(),
+		rs.taxRateCreate(),
+		rs.taxRateUpdate(),
+		rs.taxRateDelete(),
+		rs.taxReportGet(),
+
+		rs.exchangeRateList(),
+		rs.exchangeRateGet(),
+		rs.exchangeRateLoad(),
+		rs.exchangeRateImport(),
+		rs.exchangeRateDelete(),
 	}
 
 	return handlers, protectedHandlers
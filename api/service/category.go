@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/heiytor/invenda/api/pkg/errors"
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/query"
+	"github.com/heiytor/invenda/api/pkg/requests"
+)
+
+type Category interface {
+	ListCategory(ctx context.Context, namespaceID string, req *requests.ListCategory) (categories []models.Category, count int64, err error)
+	GetCategory(ctx context.Context, namespaceID string, req *requests.GetCategory) (category *models.Category, err error)
+	CreateCategory(ctx context.Context, namespaceID string, req *requests.CreateCategory) (insertedID string, err error)
+	UpdateCategory(ctx context.Context, namespaceID string, req *requests.UpdateCategory) (category *models.Category, err error)
+	MoveCategory(ctx context.Context, namespaceID string, req *requests.MoveCategory) (category *models.Category, err error)
+	DeleteCategory(ctx context.Context, namespaceID string, req *requests.DeleteCategory) (err error)
+	AssignCategoryProducts(ctx context.Context, namespaceID string, req *requests.CategoryProducts) (count int64, err error)
+	UnassignCategoryProducts(ctx context.Context, namespaceID string, req *requests.CategoryProducts) (count int64, err error)
+}
+
+func (s *service) ListCategory(ctx context.Context, namespaceID string, req *requests.ListCategory) ([]models.Category, int64, error) {
+	categories, count, err := s.store.Category.GetMany(ctx, namespaceID, &req.Query)
+	return categories, count, mapError(err, s.store.Category.Entity())
+}
+
+func (s *service) GetCategory(ctx context.Context, namespaceID string, req *requests.GetCategory) (*models.Category, error) {
+	cat, err := s.store.Category.Get(ctx, namespaceID, req.ID)
+	return cat, mapError(err, s.store.Category.Entity())
+}
+
+func (s *service) CreateCategory(ctx context.Context, namespaceID string, req *requests.CreateCategory) (string, error) {
+	cat := &models.Category{
+		NamespaceID: namespaceID,
+		Name:        req.Name,
+		Ancestors:   []string{},
+	}
+
+	if req.ParentID != "" {
+		parent, err := s.store.Category.Get(ctx, namespaceID, req.ParentID)
+		if err != nil {
+			return "", mapError(err, s.store.Category.Entity())
+		}
+
+		cat.ParentID = parent.ID
+		cat.Ancestors = parent.Path()
+	}
+
+	insertedID, err := s.store.Category.Create(ctx, cat)
+	return insertedID, mapError(err, s.store.Category.Entity())
+}
+
+func (s *service) UpdateCategory(ctx context.Context, namespaceID string, req *requests.UpdateCategory) (*models.Category, error) {
+	if err := s.store.Category.Update(ctx, namespaceID, req.ID, &models.CategoryChanges{Name: req.Name}); err != nil {
+		return nil, mapError(err, s.store.Category.Entity())
+	}
+
+	cat, err := s.store.Category.Get(ctx, namespaceID, req.ID)
+	return cat, mapError(err, s.store.Category.Entity())
+}
+
+func (s *service) MoveCategory(ctx context.Context, namespaceID string, req *requests.MoveCategory) (*models.Category, error) {
+	// The parent is read and checked within the transaction, which Move makes conflict with concurrent
+	// moves of the same categories, so two moves cannot create a cycle together.
+	err := s.store.WithTransaction(ctx, func(ctx context.Context) error {
+		cat, err := s.store.Category.Get(ctx, namespaceID, req.ID)
+		if err != nil {
+			return err
+		}
+
+		var parent *models.Category
+		if req.ParentID != "" {
+			if parent, err = s.store.Category.Get(ctx, namespaceID, req.ParentID); err != nil {
+				return err
+			}
+
+			// A category cannot be moved under itself or any of its descendants.
+			if parent.IsDescendantOf(cat.ID) {
+				return errors.
+					New().
+					Code(http.StatusConflict).
+					Layer(errors.LayerService).
+					Attr("entity", s.store.Category.Entity()).
+					Attr("parent_id", parent.ID).
+					Msg("a category cannot be moved into its own subtree")
+			}
+		}
+
+		return s.store.Category.Move(ctx, namespaceID, cat.ID, parent)
+	})
+	if err != nil {
+		return nil, mapError(err, s.store.Category.Entity())
+	}
+
+	cat, err := s.store.Category.Get(ctx, namespaceID, req.ID)
+	return cat, mapError(err, s.store.Category.Entity())
+}
+
+func (s *service) DeleteCategory(ctx context.Context, namespaceID string, req *requests.DeleteCategory) error {
+	children, _, err := s.store.Category.GetMany(ctx, namespaceID, &query.Query{
+		Paginator: query.Paginator{Page: 1, Size: 1},
+		Sorter:    query.Sorter{By: "created_at", Order: query.OrderAsc},
+		Filter:    query.Filter{Conditions: []query.Condition{{Field: "parent_id", Operator: query.OperatorEq, Value: req.ID}}},
+	})
+	if err != nil {
+		return mapError(err, s.store.Category.Entity())
+	}
+
+	if len(children) > 0 {
+		return errors.
+			New().
+			Code(http.StatusConflict).
+			Layer(errors.LayerService).
+			Attr("entity", s.store.Category.Entity()).
+			Msg("a category with subcategories cannot be deleted")
+	}
+
+	err = s.store.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.store.Category.Delete(ctx, namespaceID, req.ID); err != nil {
+			return err
+		}
+
+		_, err := s.store.Product.UnassignCategory(ctx, namespaceID, req.ID, nil)
+		return err
+	})
+
+	return mapError(err, s.store.Category.Entity())
+}
+
+func (s *service) AssignCategoryProducts(ctx context.Context, namespaceID string, req *requests.CategoryProducts) (int64, error) {
+	if _, err := s.store.Category.Get(ctx, namespaceID, req.ID); err != nil {
+		return 0, mapError(err, s.store.Category.Entity())
+	}
+
+	count, err := s.store.Product.AssignCategory(ctx, namespaceID, req.ID, req.ProductIDs)
+	return count, mapError(err, s.store.Product.Entity())
+}
+
+func (s *service) UnassignCategoryProducts(ctx context.Context, namespaceID string, req *requests.CategoryProducts) (int64, error) {
+	count, err := s.store.Product.UnassignCategory(ctx, namespaceID, req.ID, req.ProductIDs)
+	return count, mapError(err, s.store.Product.Entity())
+}
+
+// expandCategoryFilter rewrites the "category_id" condition of a filter so it also matches the
+// descendants of the filtered categories.
+func (s *service) expandCategoryFilter(ctx context.Context, namespaceID string, f *query.Filter) error {
+	cond, ok := f.Get("category_id")
+	if !ok || (cond.Operator != query.OperatorEq && cond.Operator != query.OperatorIn) {
+		return nil
+	}
+
+	ids := make([]interface{}, 0)
+	switch v := cond.Value.(type) {
+	case []interface{}:
+		ids = append(ids, v...)
+	default:
+		ids = append(ids, v)
+	}
+
+	expanded := append([]interface{}{}, ids...)
+	for _, id := range ids {
+		descendants, err := s.store.Category.Descendants(ctx, namespaceID, id.(string))
+		if err != nil {
+			return err
+		}
+
+		for _, d := range descendants {
+			expanded = append(expanded, d.ID)
+		}
+	}
+
+	cond.Operator = query.OperatorIn
+	cond.Value = expanded
+
+	return nil
+}
@@ -0,0 +1,285 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/heiytor/invenda/api/pkg/clock"
+	"github.com/heiytor/invenda/api/pkg/errors"
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/query"
+	"github.com/heiytor/invenda/api/pkg/requests"
+)
+
+type StockCount interface {
+	ListStockCount(ctx context.Context, namespaceID string, req *requests.ListStockCount) (counts []models.StockCount, total int64, err error)
+	GetStockCount(ctx context.Context, namespaceID string, req *requests.GetStockCount) (count *models.StockCount, err error)
+
+	// CreateStockCount opens a count, snapshotting the current balances of the counted warehouse.
+	CreateStockCount(ctx context.Context, namespaceID, userID string, req *requests.CreateStockCount) (insertedID string, err error)
+
+	// SubmitStockCount sets the quantities counted by the user with the specified ID.
+	SubmitStockCount(ctx context.Context, namespaceID, userID string, req *requests.SubmitStockCount) (count *models.StockCount, err error)
+
+	// GetStockCountVariance returns the variances of the counted items.
+	GetStockCountVariance(ctx context.Context, namespaceID string, req *requests.GetStockCountVariance) (variances []models.Variance, err error)
+
+	// ApproveStockCount posts an adjustment for each variance, skipping and flagging the items whose
+	// balances changed during the count.
+	ApproveStockCount(ctx context.Context, namespaceID, userID string, req *requests.ApproveStockCount) (count *models.StockCount, err error)
+
+	CancelStockCount(ctx context.Context, namespaceID string, req *requests.CancelStockCount) (count *models.StockCount, err error)
+}
+
+func (s *service) ListStockCount(ctx context.Context, namespaceID string, req *requests.ListStockCount) ([]models.StockCount, int64, error) {
+	counts, total, err := s.store.StockCount.GetMany(ctx, namespaceID, &req.Query)
+	return counts, total, mapError(err, s.store.StockCount.Entity())
+}
+
+func (s *service) GetStockCount(ctx context.Context, namespaceID string, req *requests.GetStockCount) (*models.StockCount, error) {
+	count, err := s.store.StockCount.Get(ctx, namespaceID, req.ID)
+	return count, mapError(err, s.store.StockCount.Entity())
+}
+
+func (s *service) CreateStockCount(ctx context.Context, namespaceID, userID string, req *requests.CreateStockCount) (string, error) {
+	wh, err := s.store.Warehouse.Get(ctx, namespaceID, req.WarehouseID)
+	if err != nil {
+		return "", mapError(err, s.store.Warehouse.Entity())
+	}
+
+	if !wh.HasLocation(req.Location) {
+		return "", errors.
+			New().
+			Code(http.StatusNotFound).
+			Attr("entity", "location").
+			Attr("location", req.Location).
+			Layer(errors.LayerService).
+			Msg(errors.MsgNotFound)
+	}
+
+	balances, err := s.balancesOf(ctx, namespaceID, req.WarehouseID, req.Location)
+	if err != nil {
+		return "", mapError(err, s.store.Stock.Entity())
+	}
+
+	count := &models.StockCount{
+		NamespaceID: namespaceID,
+		Status:      models.CountOpen,
+		WarehouseID: req.WarehouseID,
+		Location:    req.Location,
+		Items:       make([]models.CountItem, 0, len(balances)),
+		CreatedBy:   userID,
+	}
+
+	for _, b := range balances {
+		count.Items = append(count.Items, models.CountItem{
+			Location:  b.Location,
+			ProductID: b.ProductID,
+			VariantID: b.VariantID,
+			Expected:  b.OnHand,
+			Version:   b.Version,
+		})
+	}
+
+	// Balances are indexed by key, so the items are sorted to keep the count sheet stable.
+	slices.SortFunc(count.Items, func(a, b models.CountItem) int {
+		return cmp.Or(
+			cmp.Compare(a.Location, b.Location),
+			cmp.Compare(a.ProductID, b.ProductID),
+			cmp.Compare(a.VariantID, b.VariantID),
+		)
+	})
+
+	insertedID, err := s.store.StockCount.Create(ctx, count)
+	return insertedID, mapError(err, s.store.StockCount.Entity())
+}
+
+func (s *service) SubmitStockCount(ctx context.Context, namespaceID, userID string, req *requests.SubmitStockCount) (*models.StockCount, error) {
+	count, err := s.store.StockCount.Get(ctx, namespaceID, req.ID)
+	if err != nil {
+		return nil, mapError(err, s.store.StockCount.Entity())
+	}
+
+	if count.Status != models.CountOpen {
+		return nil, illegalTransition(s.store.StockCount.Entity(), count.Status, "submit")
+	}
+
+	items := make([]*models.CountItem, 0, len(req.Items))
+	for _, i := range req.Items {
+		if i.Location == "" {
+			i.Location = count.Location
+		}
+
+		switch {
+		case i.Counted == nil || *i.Counted < 0:
+			return nil, errors.
+				New().
+				Code(http.StatusBadRequest).
+				Attr("items", []string{fmt.Sprintf("counted quantity of %q must be zero or positive", i.ProductID)}).
+				Layer(errors.LayerService).
+				Msg(errors.MsgBadRequest)
+		case count.Location != "" && i.Location != count.Location:
+			return nil, errors.
+				New().
+				Code(http.StatusBadRequest).
+				Attr("items", []string{fmt.Sprintf("location %q is not part of the count", i.Location)}).
+				Layer(errors.LayerService).
+				Msg(errors.MsgBadRequest)
+		}
+
+		item := &models.CountItem{Location: i.Location, ProductID: i.ProductID, VariantID: i.VariantID, Counted: i.Counted, CountedBy: userID}
+		if _, err := s.checkStockKey(ctx, namespaceID, count.Key(*item)); err != nil {
+			return nil, err
+		}
+
+		items = append(items, item)
+	}
+
+	for _, item := range items {
+		if err := s.store.StockCount.Submit(ctx, namespaceID, req.ID, item); err != nil {
+			return nil, mapError(err, s.store.StockCount.Entity())
+		}
+	}
+
+	count, err = s.store.StockCount.Get(ctx, namespaceID, req.ID)
+	return count, mapError(err, s.store.StockCount.Entity())
+}
+
+func (s *service) GetStockCountVariance(ctx context.Context, namespaceID string, req *requests.GetStockCountVariance) ([]models.Variance, error) {
+	count, err := s.store.StockCount.Get(ctx, namespaceID, req.ID)
+	if err != nil {
+		return nil, mapError(err, s.store.StockCount.Entity())
+	}
+
+	balances, err := s.balancesOf(ctx, namespaceID, count.WarehouseID, count.Location)
+	if err != nil {
+		return nil, mapError(err, s.store.Stock.Entity())
+	}
+
+	return count.Variances(balances), nil
+}
+
+func (s *service) ApproveStockCount(ctx context.Context, namespaceID, userID string, req *requests.ApproveStockCount) (*models.StockCount, error) {
+	err := s.store.WithTransaction(ctx, func(ctx context.Context) error {
+		count, err := s.store.StockCount.Get(ctx, namespaceID, req.ID)
+		if err != nil {
+			return err
+		}
+
+		if count.Status != models.CountOpen {
+			return illegalTransition(s.store.StockCount.Entity(), count.Status, "approve")
+		}
+
+		balances, err := s.balancesOf(ctx, namespaceID, count.WarehouseID, count.Location)
+		if err != nil {
+			return err
+		}
+
+		flagged := make(map[models.StockKey]bool)
+		movements := make([]*models.Movement, 0)
+		for _, v := range count.Variances(balances) {
+			switch {
+			case v.Flagged:
+				flagged[v.StockKey] = true
+			case v.Variance != 0:
+				// Counts do not name serials, so the variances of serialized products are flagged to be
+				// reconciled by adjustments that name them.
+				prd, err := s.store.Product.Get(ctx, namespaceID, v.ProductID)
+				if err != nil {
+					return err
+				}
+
+				if prd.Serialized {
+					flagged[v.StockKey] = true
+					continue
+				}
+
+				mov := &models.Movement{
+					NamespaceID: namespaceID,
+					StockKey:    v.StockKey,
+					Type:        models.MovementAdjustment,
+					Quantity:    v.Variance,
+					Reason:      "stock count",
+					UserID:      userID,
+					Reference:   count.ID,
+				}
+
+				// Surplus of lot-tracked products enters the unassigned lot, while shortages are taken
+				// from the lots the product would issue first.
+				lotted, err := s.lotMovements(ctx, namespaceID, mov, "", nil, true)
+				if err != nil {
+					return err
+				}
+
+				movements = append(movements, lotted...)
+			}
+		}
+
+		for i := range count.Items {
+			count.Items[i].Flagged = flagged[count.Key(count.Items[i])]
+		}
+
+		if err := s.post(ctx, namespaceID, movements...); err != nil {
+			return err
+		}
+
+		now := clock.Now()
+		changes := &models.StockCountChanges{
+			Status:     models.CountApproved,
+			Items:      count.Items,
+			ApprovedBy: userID,
+			ApprovedAt: &now,
+		}
+
+		return s.store.StockCount.Update(ctx, namespaceID, count.ID, []models.CountStatus{models.CountOpen}, changes)
+	})
+	if err != nil {
+		return nil, mapError(err, s.store.StockCount.Entity())
+	}
+
+	count, err := s.store.StockCount.Get(ctx, namespaceID, req.ID)
+	return count, mapError(err, s.store.StockCount.Entity())
+}
+
+func (s *service) CancelStockCount(ctx context.Context, namespaceID string, req *requests.CancelStockCount) (*models.StockCount, error) {
+	count, err := s.store.StockCount.Get(ctx, namespaceID, req.ID)
+	if err != nil {
+		return nil, mapError(err, s.store.StockCount.Entity())
+	}
+
+	if count.Status != models.CountOpen {
+		return nil, illegalTransition(s.store.StockCount.Entity(), count.Status, "cancel")
+	}
+
+	changes := &models.StockCountChanges{Status: models.CountCancelled}
+	if err := s.store.StockCount.Update(ctx, namespaceID, req.ID, []models.CountStatus{models.CountOpen}, changes); err != nil {
+		return nil, mapError(err, s.store.StockCount.Entity())
+	}
+
+	count, err = s.store.StockCount.Get(ctx, namespaceID, req.ID)
+	return count, mapError(err, s.store.StockCount.Entity())
+}
+
+// balancesOf returns every balance of a warehouse, or of one of its locations when location is not
+// empty, indexed by key.
+func (s *service) balancesOf(ctx context.Context, namespaceID, warehouseID, location string) (map[models.StockKey]models.Balance, error) {
+	conditions := []query.Condition{{Field: "warehouse_id", Operator: query.OperatorEq, Value: warehouseID}}
+	if location != "" {
+		conditions = append(conditions, query.Condition{Field: "location", Operator: query.OperatorEq, Value: location})
+	}
+
+	balances, _, err := s.store.Stock.Balances(ctx, namespaceID, &query.Query{Filter: query.Filter{Conditions: conditions}})
+	if err != nil {
+		return nil, err
+	}
+
+	indexed := make(map[models.StockKey]models.Balance, len(balances))
+	for _, b := range balances {
+		indexed[b.StockKey] = b
+	}
+
+	return indexed, nil
+}
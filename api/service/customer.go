@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/heiytor/invenda/api/pkg/errors"
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/requests"
+)
+
+type Customer interface {
+	ListCustomer(ctx context.Context, namespaceID string, req *requests.ListCustomer) (customers []models.Customer, count int64, err error)
+	GetCustomer(ctx context.Context, namespaceID string, req *requests.GetCustomer) (customer *models.Customer, err error)
+	CreateCustomer(ctx context.Context, namespaceID string, req *requests.CreateCustomer) (insertedID string, err error)
+	UpdateCustomer(ctx context.Context, namespaceID string, req *requests.UpdateCustomer) (customer *models.Customer, err error)
+	DeleteCustomer(ctx context.Context, namespaceID string, req *requests.DeleteCustomer) (err error)
+
+	// GetCustomerHistory summarizes the orders placed by a customer over its lifetime.
+	GetCustomerHistory(ctx context.Context, namespaceID string, req *requests.GetCustomerHistory) (history *models.CustomerHistory, err error)
+}
+
+func (s *service) ListCustomer(ctx context.Context, namespaceID string, req *requests.ListCustomer) ([]models.Customer, int64, error) {
+	customers, count, err := s.store.Customer.GetMany(ctx, namespaceID, &req.Query)
+	return customers, count, mapError(err, s.store.Customer.Entity())
+}
+
+func (s *service) GetCustomer(ctx context.Context, namespaceID string, req *requests.GetCustomer) (*models.Customer, error) {
+	cus, err := s.store.Customer.Get(ctx, namespaceID, req.ID)
+	return cus, mapError(err, s.store.Customer.Entity())
+}
+
+func (s *service) CreateCustomer(ctx context.Context, namespaceID string, req *requests.CreateCustomer) (string, error) {
+	if err := models.CheckAddresses(req.Addresses); err != nil {
+		return "", errors.
+			New().
+			Code(http.StatusBadRequest).
+			Attr("addresses", []string{err.Error()}).
+			Layer(errors.LayerService).
+			Msg(errors.MsgBadRequest)
+	}
+
+	req.Email = strings.ToLower(req.Email)
+
+	target := &models.Customer{Email: req.Email, TaxID: req.TaxID}
+	if target.Email != "" || target.TaxID != "" {
+		conflicts, err := s.store.Customer.Conflicts(ctx, namespaceID, target)
+		if err != nil {
+			return "", mapError(err, s.store.Customer.Entity())
+		}
+
+		if len(conflicts) > 0 {
+			return "", errors.
+				New().
+				Code(http.StatusConflict).
+				Attr("entity", s.store.Customer.Entity()).
+				Attr("conflicts", conflicts).
+				Layer(errors.LayerService).
+				Msg(errors.MsgConflict)
+		}
+	}
+
+	cus := &models.Customer{
+		NamespaceID: namespaceID,
+		Name:        req.Name,
+		Email:       req.Email,
+		Phone:       req.Phone,
+		TaxID:       req.TaxID,
+		Addresses:   req.Addresses,
+		Notes:       req.Notes,
+		Tags:        req.Tags,
+		Group:       req.Group,
+	}
+
+	insertedID, err := s.store.Customer.Create(ctx, cus)
+	return insertedID, mapError(err, s.store.Customer.Entity())
+}
+
+func (s *service) UpdateCustomer(ctx context.Context, namespaceID string, req *requests.UpdateCustomer) (*models.Customer, error) {
+	if err := models.CheckAddresses(req.Addresses); err != nil {
+		return nil, errors.
+			New().
+			Code(http.StatusBadRequest).
+			Attr("addresses", []string{err.Error()}).
+			Layer(errors.LayerService).
+			Msg(errors.MsgBadRequest)
+	}
+
+	cus, err := s.store.Customer.Get(ctx, namespaceID, req.ID)
+	if err != nil {
+		return nil, mapError(err, s.store.Customer.Entity())
+	}
+
+	// Only the attributes being modified can conflict with other customers.
+	target := new(models.Customer)
+	if req.Email != nil {
+		*req.Email = strings.ToLower(*req.Email)
+		if *req.Email != cus.Email {
+			target.Email = *req.Email
+		}
+	}
+
+	if req.TaxID != nil && *req.TaxID != cus.TaxID {
+		target.TaxID = *req.TaxID
+	}
+
+	if target.Email != "" || target.TaxID != "" {
+		conflicts, err := s.store.Customer.Conflicts(ctx, namespaceID, target)
+		if err != nil {
+			return nil, mapError(err, s.store.Customer.Entity())
+		}
+
+		if len(conflicts) > 0 {
+			return nil, errors.
+				New().
+				Code(http.StatusConflict).
+				Attr("entity", s.store.Customer.Entity()).
+				Attr("conflicts", conflicts).
+				Layer(errors.LayerService).
+				Msg(errors.MsgConflict)
+		}
+	}
+
+	changes := &models.CustomerChanges{
+		Name:      req.Name,
+		Email:     req.Email,
+		Phone:     req.Phone,
+		TaxID:     req.TaxID,
+		Addresses: req.Addresses,
+		Notes:     req.Notes,
+		Tags:      req.Tags,
+		Group:     req.Group,
+	}
+
+	if err := s.store.Customer.Update(ctx, namespaceID, req.ID, changes); err != nil {
+		return nil, mapError(err, s.store.Customer.Entity())
+	}
+
+	cus, err = s.store.Customer.Get(ctx, namespaceID, req.ID)
+	return cus, mapError(err, s.store.Customer.Entity())
+}
+
+func (s *service) DeleteCustomer(ctx context.Context, namespaceID string, req *requests.DeleteCustomer) error {
+	return mapError(s.store.Customer.Delete(ctx, namespaceID, req.ID), s.store.Customer.Entity())
+}
+
+func (s *service) GetCustomerHistory(ctx context.Context, namespaceID string, req *requests.GetCustomerHistory) (*models.CustomerHistory, error) {
+	if _, err := s.store.Customer.Get(ctx, namespaceID, req.ID); err != nil {
+		return nil, mapError(err, s.store.Customer.Entity())
+	}
+
+	history, err := s.store.Customer.History(ctx, namespaceID, req.ID)
+	if err != nil {
+		return nil, mapError(err, s.store.Customer.Entity())
+	}
+
+	// The spend is summed from the totals' amounts, which are all in the base currency.
+	if history.Spend.Currency, err = s.baseCurrency(ctx, namespaceID); err != nil {
+		return nil, err
+	}
+
+	return history, nil
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/heiytor/invenda/api/pkg/errors"
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/money"
+	"github.com/heiytor/invenda/api/pkg/requests"
+	"github.com/heiytor/invenda/api/store"
+)
+
+type ExchangeRate interface {
+	ListExchangeRate(ctx context.Context, namespaceID string, req *requests.ListExchangeRate) (exchangeRates []models.ExchangeRate, count int64, err error)
+	GetExchangeRate(ctx context.Context, namespaceID string, req *requests.GetExchangeRate) (exchangeRate *models.ExchangeRate, err error)
+
+	// LoadExchangeRates creates the requested rates or replaces the ones of the same currency and start,
+	// whether they were sent through the API or uploaded in a CSV file. The namespace must have a base
+	// currency, in which the rates are quoted.
+	LoadExchangeRates(ctx context.Context, namespaceID string, req *requests.LoadExchangeRates) (load *models.ExchangeRateLoad, err error)
+
+	DeleteExchangeRate(ctx context.Context, namespaceID string, req *requests.DeleteExchangeRate) (err error)
+}
+
+func (s *service) ListExchangeRate(ctx context.Context, namespaceID string, req *requests.ListExchangeRate) ([]models.ExchangeRate, int64, error) {
+	exchangeRates, count, err := s.store.ExchangeRate.GetMany(ctx, namespaceID, &req.Query)
+	return exchangeRates, count, mapError(err, s.store.ExchangeRate.Entity())
+}
+
+func (s *service) GetExchangeRate(ctx context.Context, namespaceID string, req *requests.GetExchangeRate) (*models.ExchangeRate, error) {
+	xr, err := s.store.ExchangeRate.Get(ctx, namespaceID, req.ID)
+	return xr, mapError(err, s.store.ExchangeRate.Entity())
+}
+
+func (s *service) LoadExchangeRates(ctx context.Context, namespaceID string, req *requests.LoadExchangeRates) (*models.ExchangeRateLoad, error) {
+	ns, err := s.store.Namespace.Get(ctx, namespaceID)
+	if err != nil {
+		return nil, mapError(err, s.store.Namespace.Entity())
+	}
+
+	if ns.Settings.Currency == "" {
+		return nil, errors.
+			New().
+			Code(http.StatusBadRequest).
+			Attr("rates", []string{"the namespace has no base currency"}).
+			Layer(errors.LayerService).
+			Msg(errors.MsgBadRequest)
+	}
+
+	if err := models.CheckExchangeRates(req.Rates, ns.Settings.Currency); err != nil {
+		return nil, errors.
+			New().
+			Code(http.StatusBadRequest).
+			Attr("rates", []string{err.Error()}).
+			Layer(errors.LayerService).
+			Msg(errors.MsgBadRequest)
+	}
+
+	load, err := s.store.ExchangeRate.Load(ctx, namespaceID, req.Rates)
+	return load, mapError(err, s.store.ExchangeRate.Entity())
+}
+
+func (s *service) DeleteExchangeRate(ctx context.Context, namespaceID string, req *requests.DeleteExchangeRate) error {
+	return mapError(s.store.ExchangeRate.Delete(ctx, namespaceID, req.ID), s.store.ExchangeRate.Entity())
+}
+
+// conversion returns how a document of the currency, priced at a time, converts to the namespace's base
+// currency. Documents without a currency are in the base currency. It reports a bad request when the
+// namespace has no base currency to convert to or when the currency has no rate effective by then.
+func (s *service) conversion(ctx context.Context, namespaceID string, settings *models.Settings, currency money.Currency, at time.Time) (models.Conversion, error) {
+	base := settings.Currency
+	if currency == "" || currency == base {
+		if base == "" {
+			return models.Conversion{}, nil
+		}
+
+		return models.Conversion{Currency: base, Base: base, Rate: "1"}, nil
+	}
+
+	if base == "" {
+		return models.Conversion{}, errors.
+			New().
+			Code(http.StatusBadRequest).
+			Attr("currency", []string{"the namespace has no base currency"}).
+			Layer(errors.LayerService).
+			Msg(errors.MsgBadRequest)
+	}
+
+	xr, err := s.store.ExchangeRate.GetEffective(ctx, namespaceID, currency, at)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return models.Conversion{}, errors.
+				New().
+				Code(http.StatusBadRequest).
+				Attr("currency", []string{fmt.Sprintf("%s has no exchange rate effective at %s", currency, at.Format(time.RFC3339))}).
+				Layer(errors.LayerService).
+				Msg(errors.MsgBadRequest)
+		}
+
+		return models.Conversion{}, mapError(err, s.store.ExchangeRate.Entity())
+	}
+
+	return models.Conversion{Currency: currency, Base: base, Rate: xr.Rate}, nil
+}
+
+// baseCurrency returns the namespace's base currency, which is empty when the namespace has none.
+func (s *service) baseCurrency(ctx context.Context, namespaceID string) (money.Currency, error) {
+	ns, err := s.store.Namespace.Get(ctx, namespaceID)
+	if err != nil {
+		return "", mapError(err, s.store.Namespace.Entity())
+	}
+
+	return ns.Settings.Currency, nil
+}
+
+// inCurrency makes the amounts given for a document amounts of the document's currency: amounts without a
+// currency take it, while amounts of another currency are reported as a bad request on the field. Nil
+// amounts are skipped and documents without a currency take amounts of any.
+func inCurrency(currency money.Currency, field string, amounts ...*money.Money) error {
+	for _, m := range amounts {
+		switch {
+		case m == nil || m.Currency == currency:
+		case m.Currency == "":
+			m.Currency = currency
+		case currency != "":
+			return errors.
+				New().
+				Code(http.StatusBadRequest).
+				Attr(field, []string{fmt.Sprintf("must be an amount of %s", currency)}).
+				Layer(errors.LayerService).
+				Msg(errors.MsgBadRequest)
+		}
+	}
+
+	return nil
+}
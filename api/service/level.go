@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/heiytor/invenda/api/pkg/errors"
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/requests"
+)
+
+type StockLevel interface {
+	ListStockLevel(ctx context.Context, namespaceID string, req *requests.ListStockLevel) (levels []models.StockLevel, count int64, err error)
+	SetStockLevel(ctx context.Context, namespaceID string, req *requests.SetStockLevel) (level *models.StockLevel, err error)
+	DeleteStockLevel(ctx context.Context, namespaceID string, req *requests.DeleteStockLevel) (err error)
+
+	// ListReorderSuggestion returns the items at or below their reorder point, grouped by the products'
+	// preferred supplier.
+	ListReorderSuggestion(ctx context.Context, namespaceID string, req *requests.ListReorderSuggestion) (suggestions []models.ReorderSuggestion, count int64, err error)
+}
+
+func (s *service) ListStockLevel(ctx context.Context, namespaceID string, req *requests.ListStockLevel) ([]models.StockLevel, int64, error) {
+	levels, count, err := s.store.StockLevel.GetMany(ctx, namespaceID, &req.Query)
+	return levels, count, mapError(err, s.store.StockLevel.Entity())
+}
+
+func (s *service) SetStockLevel(ctx context.Context, namespaceID string, req *requests.SetStockLevel) (*models.StockLevel, error) {
+	level := &models.StockLevel{
+		NamespaceID:  namespaceID,
+		WarehouseID:  req.WarehouseID,
+		ProductID:    req.ProductID,
+		VariantID:    req.VariantID,
+		Min:          req.Min,
+		Max:          req.Max,
+		ReorderPoint: req.ReorderPoint,
+	}
+
+	if err := level.CheckThresholds(); err != nil {
+		return nil, errors.
+			New().
+			Code(http.StatusBadRequest).
+			Attr("thresholds", []string{err.Error()}).
+			Layer(errors.LayerService).
+			Msg(errors.MsgBadRequest)
+	}
+
+	key := models.StockKey{WarehouseID: req.WarehouseID, ProductID: req.ProductID, VariantID: req.VariantID}
+	if _, err := s.checkStockKey(ctx, namespaceID, key); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.store.StockLevel.Set(ctx, level); err != nil {
+		return nil, mapError(err, s.store.StockLevel.Entity())
+	}
+
+	return level, nil
+}
+
+func (s *service) DeleteStockLevel(ctx context.Context, namespaceID string, req *requests.DeleteStockLevel) error {
+	return mapError(s.store.StockLevel.Delete(ctx, namespaceID, req.ID), s.store.StockLevel.Entity())
+}
+
+func (s *service) ListReorderSuggestion(ctx context.Context, namespaceID string, req *requests.ListReorderSuggestion) ([]models.ReorderSuggestion, int64, error) {
+	suggestions, count, err := s.store.StockLevel.Suggestions(ctx, namespaceID, &req.Query)
+	return suggestions, count, mapError(err, s.store.StockLevel.Entity())
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/heiytor/invenda/api/pkg/auth"
+	"github.com/heiytor/invenda/api/pkg/clock"
+	"github.com/heiytor/invenda/api/pkg/errors"
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/query"
+	"github.com/heiytor/invenda/api/pkg/requests"
+)
+
+type Lot interface {
+	ListLot(ctx context.Context, namespaceID string, req *requests.ListLot) (lots []models.StockLot, count int64, err error)
+
+	// ListExpiringLot lists the lots with stock on hand that expire within the requested number of days,
+	// including the ones already expired.
+	ListExpiringLot(ctx context.Context, namespaceID string, req *requests.ListExpiringLot) (lots []models.StockLot, count int64, err error)
+}
+
+func (s *service) ListLot(ctx context.Context, namespaceID string, req *requests.ListLot) ([]models.StockLot, int64, error) {
+	lots, count, err := s.store.Stock.Lots(ctx, namespaceID, &req.Query)
+	return lots, count, mapError(err, s.store.Stock.Entity())
+}
+
+func (s *service) ListExpiringLot(ctx context.Context, namespaceID string, req *requests.ListExpiringLot) ([]models.StockLot, int64, error) {
+	until := clock.Now().AddDate(0, 0, req.Days)
+
+	req.Query.Filter.Conditions = append(
+		req.Query.Filter.Conditions,
+		query.Condition{Field: "expires_at", Operator: query.OperatorLte, Value: until},
+		query.Condition{Field: "on_hand", Operator: query.OperatorGt, Value: 0},
+	)
+
+	lots, count, err := s.store.Stock.Lots(ctx, namespaceID, &req.Query)
+	return lots, count, mapError(err, s.store.Stock.Entity())
+}
+
+// lotMovements assigns the movement to lots when its product is lot-tracked. Inbound movements enter the
+// specified lot, or [models.UnassignedLot] when it is empty, and outbound movements are split across the
+// lots chosen by the product's issue strategy, one movement per lot. Receipts and issues of products with
+// the specific strategy must name their lot; other movements are never blocked by a missing lot. Expired
+// lots are only issued when allowExpired is true. It must be called within a transaction.
+func (s *service) lotMovements(ctx context.Context, namespaceID string, mov *models.Movement, lot string, expiresAt *time.Time, allowExpired bool) ([]*models.Movement, error) {
+	prd, err := s.store.Product.Get(ctx, namespaceID, mov.ProductID)
+	if err != nil {
+		return nil, mapError(err, s.store.Product.Entity())
+	}
+
+	if !prd.LotTracked {
+		if lot != "" || expiresAt != nil {
+			return nil, lotError(models.ErrLotRequired, []string{"product is not lot-tracked"})
+		}
+
+		return []*models.Movement{mov}, nil
+	}
+
+	if mov.Quantity >= 0 {
+		if lot == "" && mov.Type == models.MovementReceipt {
+			return nil, lotError(models.ErrLotRequired, []string{"lot is required for lot-tracked products"})
+		}
+
+		mov.Lot = lot
+		if mov.Lot == "" {
+			mov.Lot = models.UnassignedLot
+		}
+
+		mov.ExpiresAt = expiresAt
+
+		return []*models.Movement{mov}, nil
+	}
+
+	strategy := prd.Strategy()
+	if strategy == models.IssueSpecific && mov.Type != models.MovementIssue {
+		strategy = models.IssueFEFO
+	}
+
+	lots, err := s.store.Stock.LotsOf(ctx, namespaceID, mov.StockKey)
+	if err != nil {
+		return nil, err
+	}
+
+	allocations, err := models.AllocateLots(lots, -mov.Quantity, strategy, lot, clock.Now(), allowExpired)
+	if err != nil {
+		return nil, lotError(err, []string{"lot is required by the product's issue strategy"})
+	}
+
+	movements := make([]*models.Movement, 0, len(allocations))
+	for _, a := range allocations {
+		m := *mov
+		m.Quantity = -a.Quantity
+		m.Lot = a.Lot
+		m.ExpiresAt = a.ExpiresAt
+
+		movements = append(movements, &m)
+	}
+
+	return movements, nil
+}
+
+// lotError maps an error of [models.AllocateLots] to the service's errors. The reasons are reported when
+// the lot is missing or not applicable.
+func lotError(err error, reasons []string) error {
+	out := errors.New().Layer(errors.LayerService)
+
+	switch {
+	case errors.Is(err, models.ErrLotExpired):
+		return out.
+			Code(http.StatusForbidden).
+			Attr("required", auth.StockIssueExpired).
+			Msg(errors.MsgInsufficientPermission)
+	case errors.Is(err, models.ErrInsufficientLots):
+		return out.
+			Code(http.StatusConflict).
+			Attr("entity", "lot").
+			Msg(errors.MsgInsufficientStock)
+	default:
+		return out.
+			Code(http.StatusBadRequest).
+			Attr("lot", reasons).
+			Msg(errors.MsgBadRequest)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/heiytor/invenda/api/pkg/auth"
 	"github.com/heiytor/invenda/api/pkg/errors"
 	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/money"
 	"github.com/heiytor/invenda/api/pkg/requests"
 	"github.com/heiytor/invenda/api/store"
 )
@@ -43,14 +44,19 @@ func (s *service) GetNamespace(ctx context.Context, memberID, namespaceID string
 }
 
 func (s *service) CreateNamespace(ctx context.Context, ownerID string, req *requests.CreateNamespace) (string, error) {
-	if _, err := s.store.User.GetByID(ctx, ownerID); err != nil {
+	owner, err := s.store.User.GetByID(ctx, ownerID)
+	if err != nil {
 		return "", mapError(err, s.store.User.Entity())
 	}
 
-	for _, m := range req.Members {
-		if _, err := s.store.User.GetByID(ctx, m.ID); err != nil {
+	for i, m := range req.Members {
+		usr, err := s.store.User.GetByID(ctx, m.ID)
+		if err != nil {
 			return "", mapError(err, s.store.User.Entity())
 		}
+
+		req.Members[i].Name = usr.Name
+		req.Members[i].Email = usr.Email
 	}
 
 	ns := &models.Namespace{
@@ -58,6 +64,8 @@ func (s *service) CreateNamespace(ctx context.Context, ownerID string, req *requ
 		Members: append([]models.Member{
 			{
 				ID:          ownerID,
+				Name:        owner.Name,
+				Email:       owner.Email,
 				Owner:       true,
 				Permissions: auth.All(),
 			},
@@ -84,6 +92,38 @@ func (s *service) UpdateNamespace(ctx context.Context, memberID, namespaceID str
 		Name: req.Name,
 	}
 
+	if req.Settings != nil {
+		changes.AllowBackorders = req.Settings.AllowBackorders
+		changes.ValuationMethod = models.ValuationMethod(req.Settings.ValuationMethod)
+		changes.TaxInclusive = req.Settings.TaxInclusive
+		changes.TaxRounding = models.TaxRounding(req.Settings.TaxRounding)
+
+		var currency money.Currency
+		if req.Settings.Currency != "" {
+			if currency, err = money.ParseCurrency(req.Settings.Currency); err != nil {
+				return errors.
+					New().
+					Code(http.StatusBadRequest).
+					Attr("settings.currency", []string{err.Error()}).
+					Layer(errors.LayerService).
+					Msg(errors.MsgBadRequest)
+			}
+		}
+
+		// Documents store the rates at which they convert to the base currency, which would not convert
+		// to another one.
+		if currency != "" && ns.Settings.Currency != "" && currency != ns.Settings.Currency {
+			return errors.
+				New().
+				Code(http.StatusConflict).
+				Attr("currency", ns.Settings.Currency).
+				Layer(errors.LayerService).
+				Msg("the base currency cannot be changed once set")
+		}
+
+		changes.Currency = currency
+	}
+
 	if err := s.store.Namespace.Update(ctx, namespaceID, changes); err != nil {
 		return mapError(err, s.store.Namespace.Entity())
 	}
@@ -91,7 +131,8 @@ func (s *service) UpdateNamespace(ctx context.Context, memberID, namespaceID str
 	for _, usr := range req.Members {
 		switch usr.Operation {
 		case "upsert":
-			if _, err := s.store.User.GetByID(ctx, usr.ID); err != nil {
+			profile, err := s.store.User.GetByID(ctx, usr.ID)
+			if err != nil {
 				return mapError(err, s.store.User.Entity())
 			}
 
@@ -106,6 +147,8 @@ func (s *service) UpdateNamespace(ctx context.Context, memberID, namespaceID str
 
 			member := &models.Member{
 				ID:          usr.ID,
+				Name:        profile.Name,
+				Email:       profile.Email,
 				Owner:       false,
 				Permissions: usr.Permissions,
 			}
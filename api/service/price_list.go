@@ -0,0 +1,244 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/heiytor/invenda/api/pkg/clock"
+	"github.com/heiytor/invenda/api/pkg/errors"
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/money"
+	"github.com/heiytor/invenda/api/pkg/requests"
+)
+
+type PriceList interface {
+	ListPriceList(ctx context.Context, namespaceID string, req *requests.ListPriceList) (priceLists []models.PriceList, count int64, err error)
+	GetPriceList(ctx context.Context, namespaceID string, req *requests.GetPriceList) (priceList *models.PriceList, err error)
+	CreatePriceList(ctx context.Context, namespaceID string, req *requests.CreatePriceList) (insertedID string, err error)
+	UpdatePriceList(ctx context.Context, namespaceID string, req *requests.UpdatePriceList) (priceList *models.PriceList, err error)
+	DeletePriceList(ctx context.Context, namespaceID string, req *requests.DeletePriceList) (err error)
+
+	// ResolvePrice quotes the unit price of a product or variant for a customer, as sales orders and
+	// counter sales would price it.
+	ResolvePrice(ctx context.Context, namespaceID string, req *requests.ResolvePrice) (quote *models.PriceQuote, err error)
+}
+
+func (s *service) ListPriceList(ctx context.Context, namespaceID string, req *requests.ListPriceList) ([]models.PriceList, int64, error) {
+	priceLists, count, err := s.store.PriceList.GetMany(ctx, namespaceID, &req.Query)
+	return priceLists, count, mapError(err, s.store.PriceList.Entity())
+}
+
+func (s *service) GetPriceList(ctx context.Context, namespaceID string, req *requests.GetPriceList) (*models.PriceList, error) {
+	prl, err := s.store.PriceList.Get(ctx, namespaceID, req.ID)
+	return prl, mapError(err, s.store.PriceList.Entity())
+}
+
+func (s *service) CreatePriceList(ctx context.Context, namespaceID string, req *requests.CreatePriceList) (string, error) {
+	if err := s.checkPriceList(ctx, namespaceID, req.ValidFrom, req.ValidUntil, req.Prices); err != nil {
+		return "", err
+	}
+
+	conflicts, err := s.store.PriceList.Conflicts(ctx, namespaceID, &models.PriceList{Code: req.Code})
+	if err != nil {
+		return "", mapError(err, s.store.PriceList.Entity())
+	}
+
+	if len(conflicts) > 0 {
+		return "", errors.
+			New().
+			Code(http.StatusConflict).
+			Attr("entity", s.store.PriceList.Entity()).
+			Attr("conflicts", conflicts).
+			Layer(errors.LayerService).
+			Msg(errors.MsgConflict)
+	}
+
+	prl := &models.PriceList{
+		NamespaceID: namespaceID,
+		Code:        req.Code,
+		Name:        req.Name,
+		Active:      req.Active == nil || *req.Active,
+		Groups:      req.Groups,
+		Priority:    req.Priority,
+		ValidFrom:   req.ValidFrom,
+		ValidUntil:  req.ValidUntil,
+		Prices:      req.Prices,
+	}
+
+	insertedID, err := s.store.PriceList.Create(ctx, prl)
+	return insertedID, mapError(err, s.store.PriceList.Entity())
+}
+
+func (s *service) UpdatePriceList(ctx context.Context, namespaceID string, req *requests.UpdatePriceList) (*models.PriceList, error) {
+	prl, err := s.store.PriceList.Get(ctx, namespaceID, req.ID)
+	if err != nil {
+		return nil, mapError(err, s.store.PriceList.Entity())
+	}
+
+	// The validity range is checked as it will be after the update.
+	from, until := prl.ValidFrom, prl.ValidUntil
+	if req.ValidFrom != nil {
+		from = req.ValidFrom
+	}
+
+	if req.ValidUntil != nil {
+		until = req.ValidUntil
+	}
+
+	if err := s.checkPriceList(ctx, namespaceID, from, until, req.Prices); err != nil {
+		return nil, err
+	}
+
+	if req.Code != "" && req.Code != prl.Code {
+		conflicts, err := s.store.PriceList.Conflicts(ctx, namespaceID, &models.PriceList{Code: req.Code})
+		if err != nil {
+			return nil, mapError(err, s.store.PriceList.Entity())
+		}
+
+		if len(conflicts) > 0 {
+			return nil, errors.
+				New().
+				Code(http.StatusConflict).
+				Attr("entity", s.store.PriceList.Entity()).
+				Attr("conflicts", conflicts).
+				Layer(errors.LayerService).
+				Msg(errors.MsgConflict)
+		}
+	}
+
+	changes := &models.PriceListChanges{
+		Code:       req.Code,
+		Name:       req.Name,
+		Active:     req.Active,
+		Groups:     req.Groups,
+		Priority:   req.Priority,
+		ValidFrom:  req.ValidFrom,
+		ValidUntil: req.ValidUntil,
+		Prices:     req.Prices,
+	}
+
+	if err := s.store.PriceList.Update(ctx, namespaceID, req.ID, changes); err != nil {
+		return nil, mapError(err, s.store.PriceList.Entity())
+	}
+
+	prl, err = s.store.PriceList.Get(ctx, namespaceID, req.ID)
+	return prl, mapError(err, s.store.PriceList.Entity())
+}
+
+func (s *service) DeletePriceList(ctx context.Context, namespaceID string, req *requests.DeletePriceList) error {
+	return mapError(s.store.PriceList.Delete(ctx, namespaceID, req.ID), s.store.PriceList.Entity())
+}
+
+func (s *service) ResolvePrice(ctx context.Context, namespaceID string, req *requests.ResolvePrice) (*models.PriceQuote, error) {
+	prd, err := s.store.Product.Get(ctx, namespaceID, req.ProductID)
+	if err != nil {
+		return nil, mapError(err, s.store.Product.Entity())
+	}
+
+	quote := &models.PriceQuote{
+		ProductID:  req.ProductID,
+		VariantID:  req.VariantID,
+		CustomerID: req.CustomerID,
+		Quantity:   req.Quantity,
+		At:         req.At,
+		BasePrice:  prd.Price,
+	}
+
+	if quote.Quantity == 0 {
+		quote.Quantity = 1
+	}
+
+	if quote.At.IsZero() {
+		quote.At = clock.Now()
+	}
+
+	if req.VariantID != "" {
+		vrt, err := s.store.Variant.Get(ctx, namespaceID, req.ProductID, req.VariantID)
+		if err != nil {
+			return nil, mapError(err, s.store.Variant.Entity())
+		}
+
+		quote.BasePrice = vrt.EffectivePrice(prd)
+	}
+
+	group := ""
+	if req.CustomerID != "" {
+		cus, err := s.store.Customer.Get(ctx, namespaceID, req.CustomerID)
+		if err != nil {
+			return nil, mapError(err, s.store.Customer.Entity())
+		}
+
+		group = cus.Group
+	}
+
+	quote.UnitPrice, quote.PriceListID, err = s.unitPrice(ctx, namespaceID, group, quote.ProductID, quote.VariantID, quote.Quantity, quote.BasePrice, quote.At)
+	if err != nil {
+		return nil, err
+	}
+
+	return quote, nil
+}
+
+// unitPrice resolves the unit price of a quantity of a product or variant for the customers of a group at
+// a time, as in [models.ResolvePrice]. It returns the base price when no price list applies, along with the
+// ID of the list the price comes from, if any.
+func (s *service) unitPrice(ctx context.Context, namespaceID, group, productID, variantID string, quantity int64, base money.Money, at time.Time) (money.Money, string, error) {
+	lists, err := s.store.PriceList.GetApplicable(ctx, namespaceID, productID, group, at)
+	if err != nil {
+		return money.Money{}, "", mapError(err, s.store.PriceList.Entity())
+	}
+
+	if price, listID, ok := models.ResolvePrice(lists, group, at, productID, variantID, quantity); ok {
+		return price, listID, nil
+	}
+
+	return base, "", nil
+}
+
+// checkPriceList reports whether the price list's validity range and prices are valid and whether its
+// products exist in the namespace.
+func (s *service) checkPriceList(ctx context.Context, namespaceID string, from, until *time.Time, prices []models.PriceListItem) error {
+	if err := models.CheckValidity(from, until); err != nil {
+		return errors.
+			New().
+			Code(http.StatusBadRequest).
+			Attr("valid_until", []string{err.Error()}).
+			Layer(errors.LayerService).
+			Msg(errors.MsgBadRequest)
+	}
+
+	if err := models.CheckPriceListItems(prices); err != nil {
+		return errors.
+			New().
+			Code(http.StatusBadRequest).
+			Attr("prices", []string{err.Error()}).
+			Layer(errors.LayerService).
+			Msg(errors.MsgBadRequest)
+	}
+
+	base, err := s.baseCurrency(ctx, namespaceID)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range prices {
+		for i := range p.Tiers {
+			if err := inCurrency(base, "prices", &p.Tiers[i].Price); err != nil {
+				return err
+			}
+		}
+
+		if _, err := s.store.Product.Get(ctx, namespaceID, p.ProductID); err != nil {
+			return mapError(err, s.store.Product.Entity())
+		}
+
+		if p.VariantID != "" {
+			if _, err := s.store.Variant.Get(ctx, namespaceID, p.ProductID, p.VariantID); err != nil {
+				return mapError(err, s.store.Variant.Entity())
+			}
+		}
+	}
+
+	return nil
+}
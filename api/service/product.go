@@ -0,0 +1,241 @@
+package service
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/heiytor/invenda/api/pkg/errors"
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/query"
+	"github.com/heiytor/invenda/api/pkg/requests"
+)
+
+type Product interface {
+	ListProduct(ctx context.Context, namespaceID string, req *requests.ListProduct) (products []models.Product, count int64, err error)
+	GetProduct(ctx context.Context, namespaceID string, req *requests.GetProduct) (product *models.Product, err error)
+	CreateProduct(ctx context.Context, namespaceID string, req *requests.CreateProduct) (insertedID string, err error)
+	UpdateProduct(ctx context.Context, namespaceID string, req *requests.UpdateProduct) (product *models.Product, err error)
+	DeleteProduct(ctx context.Context, namespaceID string, req *requests.DeleteProduct) (err error)
+}
+
+func (s *service) ListProduct(ctx context.Context, namespaceID string, req *requests.ListProduct) ([]models.Product, int64, error) {
+	if err := s.expandCategoryFilter(ctx, namespaceID, &req.Query.Filter); err != nil {
+		return nil, 0, mapError(err, s.store.Category.Entity())
+	}
+
+	products, count, err := s.store.Product.GetMany(ctx, namespaceID, &req.Query)
+	return products, count, mapError(err, s.store.Product.Entity())
+}
+
+func (s *service) GetProduct(ctx context.Context, namespaceID string, req *requests.GetProduct) (*models.Product, error) {
+	prd, err := s.store.Product.Get(ctx, namespaceID, req.ID)
+	return prd, mapError(err, s.store.Product.Entity())
+}
+
+func (s *service) CreateProduct(ctx context.Context, namespaceID string, req *requests.CreateProduct) (string, error) {
+	if err := models.CheckOptions(req.Options); err != nil {
+		return "", errors.
+			New().
+			Code(http.StatusBadRequest).
+			Attr("options", []string{err.Error()}).
+			Layer(errors.LayerService).
+			Msg(errors.MsgBadRequest)
+	}
+
+	// Products are priced in the namespace's base currency.
+	base, err := s.baseCurrency(ctx, namespaceID)
+	if err != nil {
+		return "", err
+	}
+
+	if err := inCurrency(base, "price", &req.Price); err != nil {
+		return "", err
+	}
+
+	if err := inCurrency(base, "cost", &req.Cost); err != nil {
+		return "", err
+	}
+
+	if req.CategoryID != "" {
+		if _, err := s.store.Category.Get(ctx, namespaceID, req.CategoryID); err != nil {
+			return "", mapError(err, s.store.Category.Entity())
+		}
+	}
+
+	if req.PreferredSupplierID != "" {
+		if _, err := s.store.Supplier.Get(ctx, namespaceID, req.PreferredSupplierID); err != nil {
+			return "", mapError(err, s.store.Supplier.Entity())
+		}
+	}
+
+	target := &models.Product{SKU: req.SKU, Barcode: req.Barcode}
+	conflicts, err := s.store.Product.Conflicts(ctx, namespaceID, target)
+	if err != nil {
+		return "", mapError(err, s.store.Product.Entity())
+	}
+
+	if len(conflicts) > 0 {
+		return "", errors.
+			New().
+			Code(http.StatusConflict).
+			Attr("entity", s.store.Product.Entity()).
+			Attr("conflicts", conflicts).
+			Layer(errors.LayerService).
+			Msg(errors.MsgConflict)
+	}
+
+	prd := &models.Product{
+		NamespaceID: namespaceID,
+		SKU:         req.SKU,
+		Name:        req.Name,
+		Description: req.Description,
+		Unit:        req.Unit,
+		Price:       req.Price,
+		Cost:        req.Cost,
+		Barcode:     req.Barcode,
+		Tags:        req.Tags,
+		Active:      req.Active == nil || *req.Active,
+		CategoryID:  req.CategoryID,
+		TaxCategory: req.TaxCategory,
+		Options:     req.Options,
+
+		PreferredSupplierID: req.PreferredSupplierID,
+		LotTracked:          req.LotTracked,
+		IssueStrategy:       models.IssueStrategy(req.IssueStrategy),
+		Serialized:          req.Serialized,
+	}
+
+	if prd.Options == nil {
+		prd.Options = []models.ProductOption{}
+	}
+
+	insertedID, err := s.store.Product.Create(ctx, prd)
+	return insertedID, mapError(err, s.store.Product.Entity())
+}
+
+func (s *service) UpdateProduct(ctx context.Context, namespaceID string, req *requests.UpdateProduct) (*models.Product, error) {
+	if err := models.CheckOptions(req.Options); err != nil {
+		return nil, errors.
+			New().
+			Code(http.StatusBadRequest).
+			Attr("options", []string{err.Error()}).
+			Layer(errors.LayerService).
+			Msg(errors.MsgBadRequest)
+	}
+
+	prd, err := s.store.Product.Get(ctx, namespaceID, req.ID)
+	if err != nil {
+		return nil, mapError(err, s.store.Product.Entity())
+	}
+
+	base, err := s.baseCurrency(ctx, namespaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := inCurrency(base, "price", req.Price); err != nil {
+		return nil, err
+	}
+
+	if err := inCurrency(base, "cost", req.Cost); err != nil {
+		return nil, err
+	}
+
+	if req.CategoryID != "" {
+		if _, err := s.store.Category.Get(ctx, namespaceID, req.CategoryID); err != nil {
+			return nil, mapError(err, s.store.Category.Entity())
+		}
+	}
+
+	if req.PreferredSupplierID != "" {
+		if _, err := s.store.Supplier.Get(ctx, namespaceID, req.PreferredSupplierID); err != nil {
+			return nil, mapError(err, s.store.Supplier.Entity())
+		}
+	}
+
+	// Only the attributes being modified can conflict with other products.
+	target := new(models.Product)
+	if req.SKU != "" && req.SKU != prd.SKU {
+		target.SKU = req.SKU
+	}
+
+	if req.Barcode != nil && *req.Barcode != prd.Barcode {
+		target.Barcode = *req.Barcode
+	}
+
+	if target.SKU != "" || target.Barcode != "" {
+		conflicts, err := s.store.Product.Conflicts(ctx, namespaceID, target)
+		if err != nil {
+			return nil, mapError(err, s.store.Product.Entity())
+		}
+
+		if len(conflicts) > 0 {
+			return nil, errors.
+				New().
+				Code(http.StatusConflict).
+				Attr("entity", s.store.Product.Entity()).
+				Attr("conflicts", conflicts).
+				Layer(errors.LayerService).
+				Msg(errors.MsgConflict)
+		}
+	}
+
+	changes := &models.ProductChanges{
+		SKU:         req.SKU,
+		Name:        req.Name,
+		Description: req.Description,
+		Unit:        req.Unit,
+		Price:       req.Price,
+		Cost:        req.Cost,
+		Barcode:     req.Barcode,
+		Tags:        req.Tags,
+		Active:      req.Active,
+		Options:     req.Options,
+		CategoryID:  req.CategoryID,
+		TaxCategory: req.TaxCategory,
+
+		PreferredSupplierID: req.PreferredSupplierID,
+		LotTracked:          req.LotTracked,
+		IssueStrategy:       models.IssueStrategy(req.IssueStrategy),
+		Serialized:          req.Serialized,
+	}
+
+	if err := s.store.Product.Update(ctx, namespaceID, req.ID, changes); err != nil {
+		return nil, mapError(err, s.store.Product.Entity())
+	}
+
+	prd, err = s.store.Product.Get(ctx, namespaceID, req.ID)
+	return prd, mapError(err, s.store.Product.Entity())
+}
+
+func (s *service) DeleteProduct(ctx context.Context, namespaceID string, req *requests.DeleteProduct) error {
+	err := s.store.WithTransaction(ctx, func(ctx context.Context) error {
+		// The ledger keeps referencing the product, and its balances, lots and serials are built from it, so
+		// only products that never moved stock can be deleted.
+		movements, _, err := s.store.Stock.Movements(ctx, namespaceID, &query.Query{
+			Paginator: query.Paginator{Page: 1, Size: 1},
+			Filter:    query.Filter{Conditions: []query.Condition{{Field: "product_id", Operator: query.OperatorEq, Value: req.ID}}},
+		})
+		if err != nil {
+			return err
+		}
+
+		if len(movements) > 0 {
+			return errors.
+				New().
+				Code(http.StatusConflict).
+				Layer(errors.LayerService).
+				Attr("entity", s.store.Product.Entity()).
+				Msg("a product with stock movements cannot be deleted")
+		}
+
+		if err := s.store.Product.Delete(ctx, namespaceID, req.ID); err != nil {
+			return err
+		}
+
+		_, err = s.store.Variant.DeleteMany(ctx, namespaceID, req.ID)
+		return err
+	})
+
+	return mapError(err, s.store.Product.Entity())
+}
@@ -0,0 +1,391 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/heiytor/invenda/api/pkg/clock"
+	"github.com/heiytor/invenda/api/pkg/errors"
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/requests"
+	"github.com/heiytor/invenda/api/store"
+)
+
+type Promotion interface {
+	ListPromotion(ctx context.Context, namespaceID string, req *requests.ListPromotion) (promotions []models.Promotion, count int64, err error)
+	GetPromotion(ctx context.Context, namespaceID string, req *requests.GetPromotion) (promotion *models.Promotion, err error)
+	CreatePromotion(ctx context.Context, namespaceID string, req *requests.CreatePromotion) (insertedID string, err error)
+	UpdatePromotion(ctx context.Context, namespaceID string, req *requests.UpdatePromotion) (promotion *models.Promotion, err error)
+	DeletePromotion(ctx context.Context, namespaceID string, req *requests.DeletePromotion) (err error)
+
+	// EvaluatePromotions prices a cart as sales orders and counter sales do, returning the promotions
+	// applied to each of its lines.
+	EvaluatePromotions(ctx context.Context, namespaceID string, req *requests.EvaluatePromotions) (cart *models.Cart, err error)
+}
+
+func (s *service) ListPromotion(ctx context.Context, namespaceID string, req *requests.ListPromotion) ([]models.Promotion, int64, error) {
+	promotions, count, err := s.store.Promotion.GetMany(ctx, namespaceID, &req.Query)
+	return promotions, count, mapError(err, s.store.Promotion.Entity())
+}
+
+func (s *service) GetPromotion(ctx context.Context, namespaceID string, req *requests.GetPromotion) (*models.Promotion, error) {
+	prm, err := s.store.Promotion.Get(ctx, namespaceID, req.ID)
+	return prm, mapError(err, s.store.Promotion.Entity())
+}
+
+func (s *service) CreatePromotion(ctx context.Context, namespaceID string, req *requests.CreatePromotion) (string, error) {
+	base, err := s.baseCurrency(ctx, namespaceID)
+	if err != nil {
+		return "", err
+	}
+
+	if err := inCurrency(base, "amount", &req.Amount); err != nil {
+		return "", err
+	}
+
+	if err := inCurrency(base, "rules", &req.Rules.MinTotal); err != nil {
+		return "", err
+	}
+
+	prm := &models.Promotion{
+		NamespaceID: namespaceID,
+		Name:        req.Name,
+		Active:      req.Active == nil || *req.Active,
+		Type:        req.Type,
+		Value:       req.Value,
+		Amount:      req.Amount,
+		Buy:         req.Buy,
+		Get:         req.Get,
+		BundleSize:  req.BundleSize,
+		Rules:       req.Rules,
+		Priority:    req.Priority,
+		Stackable:   req.Stackable,
+		Coupon:      normalizeCoupon(req.Coupon),
+		UsageLimit:  req.UsageLimit,
+		ValidFrom:   req.ValidFrom,
+		ValidUntil:  req.ValidUntil,
+	}
+
+	if err := s.checkPromotion(ctx, namespaceID, prm); err != nil {
+		return "", err
+	}
+
+	if prm.Coupon != "" {
+		conflicts, err := s.store.Promotion.Conflicts(ctx, namespaceID, &models.Promotion{Coupon: prm.Coupon})
+		if err != nil {
+			return "", mapError(err, s.store.Promotion.Entity())
+		}
+
+		if len(conflicts) > 0 {
+			return "", errors.
+				New().
+				Code(http.StatusConflict).
+				Attr("entity", s.store.Promotion.Entity()).
+				Attr("conflicts", conflicts).
+				Layer(errors.LayerService).
+				Msg(errors.MsgConflict)
+		}
+	}
+
+	insertedID, err := s.store.Promotion.Create(ctx, prm)
+	return insertedID, mapError(err, s.store.Promotion.Entity())
+}
+
+func (s *service) UpdatePromotion(ctx context.Context, namespaceID string, req *requests.UpdatePromotion) (*models.Promotion, error) {
+	prm, err := s.store.Promotion.Get(ctx, namespaceID, req.ID)
+	if err != nil {
+		return nil, mapError(err, s.store.Promotion.Entity())
+	}
+
+	if req.Coupon != nil {
+		*req.Coupon = normalizeCoupon(*req.Coupon)
+
+		if *req.Coupon != "" && *req.Coupon != prm.Coupon {
+			conflicts, err := s.store.Promotion.Conflicts(ctx, namespaceID, &models.Promotion{Coupon: *req.Coupon})
+			if err != nil {
+				return nil, mapError(err, s.store.Promotion.Entity())
+			}
+
+			if len(conflicts) > 0 {
+				return nil, errors.
+					New().
+					Code(http.StatusConflict).
+					Attr("entity", s.store.Promotion.Entity()).
+					Attr("conflicts", conflicts).
+					Layer(errors.LayerService).
+					Msg(errors.MsgConflict)
+			}
+		}
+	}
+
+	base, err := s.baseCurrency(ctx, namespaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := inCurrency(base, "amount", req.Amount); err != nil {
+		return nil, err
+	}
+
+	if req.Rules != nil {
+		if err := inCurrency(base, "rules", &req.Rules.MinTotal); err != nil {
+			return nil, err
+		}
+	}
+
+	// The promotion is checked as it will be after the update.
+	updated := *prm
+	if req.Value != nil {
+		updated.Value = *req.Value
+	}
+
+	if req.Amount != nil {
+		updated.Amount = *req.Amount
+	}
+
+	if req.Buy != nil {
+		updated.Buy = *req.Buy
+	}
+
+	if req.Get != nil {
+		updated.Get = *req.Get
+	}
+
+	if req.BundleSize != nil {
+		updated.BundleSize = *req.BundleSize
+	}
+
+	if req.Rules != nil {
+		updated.Rules = *req.Rules
+	}
+
+	if req.UsageLimit != nil {
+		updated.UsageLimit = *req.UsageLimit
+	}
+
+	if req.ValidFrom != nil {
+		updated.ValidFrom = req.ValidFrom
+	}
+
+	if req.ValidUntil != nil {
+		updated.ValidUntil = req.ValidUntil
+	}
+
+	if err := s.checkPromotion(ctx, namespaceID, &updated); err != nil {
+		return nil, err
+	}
+
+	changes := &models.PromotionChanges{
+		Name:       req.Name,
+		Active:     req.Active,
+		Value:      req.Value,
+		Amount:     req.Amount,
+		Buy:        req.Buy,
+		Get:        req.Get,
+		BundleSize: req.BundleSize,
+		Rules:      req.Rules,
+		Priority:   req.Priority,
+		Stackable:  req.Stackable,
+		Coupon:     req.Coupon,
+		UsageLimit: req.UsageLimit,
+		ValidFrom:  req.ValidFrom,
+		ValidUntil: req.ValidUntil,
+	}
+
+	if err := s.store.Promotion.Update(ctx, namespaceID, req.ID, changes); err != nil {
+		return nil, mapError(err, s.store.Promotion.Entity())
+	}
+
+	prm, err = s.store.Promotion.Get(ctx, namespaceID, req.ID)
+	return prm, mapError(err, s.store.Promotion.Entity())
+}
+
+func (s *service) DeletePromotion(ctx context.Context, namespaceID string, req *requests.DeletePromotion) error {
+	return mapError(s.store.Promotion.Delete(ctx, namespaceID, req.ID), s.store.Promotion.Entity())
+}
+
+func (s *service) EvaluatePromotions(ctx context.Context, namespaceID string, req *requests.EvaluatePromotions) (*models.Cart, error) {
+	base, err := s.baseCurrency(ctx, namespaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Carts evaluated on their own are priced in the base currency.
+	cart := &models.Cart{Coupons: req.Coupons, At: clock.Now(), Currency: base, Lines: req.Lines}
+
+	if req.CustomerID != "" {
+		cus, err := s.store.Customer.Get(ctx, namespaceID, req.CustomerID)
+		if err != nil {
+			return nil, mapError(err, s.store.Customer.Entity())
+		}
+
+		cart.CustomerGroup = cus.Group
+	}
+
+	for i := range cart.Lines {
+		l := &cart.Lines[i]
+
+		// Lines without a product are reported by CheckCartLines.
+		if l.ProductID == "" {
+			continue
+		}
+
+		prd, err := s.store.Product.Get(ctx, namespaceID, l.ProductID)
+		if err != nil {
+			return nil, mapError(err, s.store.Product.Entity())
+		}
+
+		if err := inCurrency(base, "lines", l.UnitPrice, &l.Discount); err != nil {
+			return nil, err
+		}
+
+		l.CategoryID = prd.CategoryID
+		price := prd.Price
+
+		if l.VariantID != "" {
+			vrt, err := s.store.Variant.Get(ctx, namespaceID, l.ProductID, l.VariantID)
+			if err != nil {
+				return nil, mapError(err, s.store.Variant.Entity())
+			}
+
+			price = vrt.EffectivePrice(prd)
+		}
+
+		if l.UnitPrice == nil {
+			price, _, err = s.unitPrice(ctx, namespaceID, cart.CustomerGroup, l.ProductID, l.VariantID, l.Quantity, price, cart.At)
+			if err != nil {
+				return nil, err
+			}
+
+			l.UnitPrice = &price
+		}
+	}
+
+	if err := models.CheckCartLines(cart.Lines); err != nil {
+		return nil, errors.
+			New().
+			Code(http.StatusBadRequest).
+			Attr("lines", []string{err.Error()}).
+			Layer(errors.LayerService).
+			Msg(errors.MsgBadRequest)
+	}
+
+	if err := s.promote(ctx, namespaceID, cart, models.Conversion{}); err != nil {
+		return nil, err
+	}
+
+	return cart, nil
+}
+
+// promote evaluates the namespace's active promotions on the cart, as in [models.Cart.Apply], after
+// normalizing its coupons and checking that each of them can be used. The promotions' amounts are in the
+// base currency and are converted to the cart's currency with conv.
+func (s *service) promote(ctx context.Context, namespaceID string, cart *models.Cart, conv models.Conversion) error {
+	coupons := make([]string, 0, len(cart.Coupons))
+	for _, c := range cart.Coupons {
+		if c = normalizeCoupon(c); c != "" && !slices.Contains(coupons, c) {
+			coupons = append(coupons, c)
+		}
+	}
+
+	cart.Coupons = coupons
+
+	promotions, err := s.store.Promotion.GetActive(ctx, namespaceID, cart.At)
+	if err != nil {
+		return mapError(err, s.store.Promotion.Entity())
+	}
+
+	for i := range promotions {
+		promotions[i].Convert(conv.FromBase)
+	}
+
+	if err := cart.CheckCoupons(promotions); err != nil {
+		return errors.
+			New().
+			Code(http.StatusBadRequest).
+			Attr("coupons", []string{err.Error()}).
+			Layer(errors.LayerService).
+			Msg(errors.MsgBadRequest)
+	}
+
+	cart.Apply(promotions)
+
+	return nil
+}
+
+// redeemPromotions records a use of each of the promotions. It reports a conflict when one of them reached
+// its usage limit since the document was priced. It must be called within a transaction.
+func (s *service) redeemPromotions(ctx context.Context, namespaceID string, ids []string) error {
+	for _, id := range ids {
+		if err := s.store.Promotion.Redeem(ctx, namespaceID, id); err != nil {
+			if errors.Is(err, store.ErrNotFound) {
+				return errors.
+					New().
+					Code(http.StatusConflict).
+					Attr("entity", s.store.Promotion.Entity()).
+					Attr("id", id).
+					Layer(errors.LayerService).
+					Msg("the promotion reached its usage limit")
+			}
+
+			return err
+		}
+	}
+
+	return nil
+}
+
+// releasePromotions undoes a use of each of the promotions, ignoring the ones that were deleted since. It
+// must be called within a transaction.
+func (s *service) releasePromotions(ctx context.Context, namespaceID string, ids []string) error {
+	for _, id := range ids {
+		if err := s.store.Promotion.Release(ctx, namespaceID, id); err != nil && !errors.Is(err, store.ErrNotFound) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkPromotion reports whether the promotion is valid and whether the products and categories of its
+// rules exist in the namespace.
+func (s *service) checkPromotion(ctx context.Context, namespaceID string, prm *models.Promotion) error {
+	if err := models.CheckPromotion(prm); err != nil {
+		return errors.
+			New().
+			Code(http.StatusBadRequest).
+			Attr("promotion", []string{err.Error()}).
+			Layer(errors.LayerService).
+			Msg(errors.MsgBadRequest)
+	}
+
+	if err := models.CheckValidity(prm.ValidFrom, prm.ValidUntil); err != nil {
+		return errors.
+			New().
+			Code(http.StatusBadRequest).
+			Attr("valid_until", []string{err.Error()}).
+			Layer(errors.LayerService).
+			Msg(errors.MsgBadRequest)
+	}
+
+	for _, id := range prm.Rules.ProductIDs {
+		if _, err := s.store.Product.Get(ctx, namespaceID, id); err != nil {
+			return mapError(err, s.store.Product.Entity())
+		}
+	}
+
+	for _, id := range prm.Rules.CategoryIDs {
+		if _, err := s.store.Category.Get(ctx, namespaceID, id); err != nil {
+			return mapError(err, s.store.Category.Entity())
+		}
+	}
+
+	return nil
+}
+
+// normalizeCoupon returns the coupon code as it is stored, so coupons match regardless of case.
+func normalizeCoupon(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
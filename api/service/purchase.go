@@ -0,0 +1,412 @@
+package service
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/heiytor/invenda/api/pkg/clock"
+	"github.com/heiytor/invenda/api/pkg/errors"
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/money"
+	"github.com/heiytor/invenda/api/pkg/requests"
+)
+
+type PurchaseOrder interface {
+	ListPurchaseOrder(ctx context.Context, namespaceID string, req *requests.ListPurchaseOrder) (orders []models.PurchaseOrder, count int64, err error)
+	GetPurchaseOrder(ctx context.Context, namespaceID string, req *requests.GetPurchaseOrder) (order *models.PurchaseOrder, err error)
+	CreatePurchaseOrder(ctx context.Context, namespaceID, userID string, req *requests.CreatePurchaseOrder) (insertedID string, err error)
+	UpdatePurchaseOrder(ctx context.Context, namespaceID string, req *requests.UpdatePurchaseOrder) (order *models.PurchaseOrder, err error)
+	DeletePurchaseOrder(ctx context.Context, namespaceID string, req *requests.DeletePurchaseOrder) (err error)
+
+	// SendPurchaseOrder places the order with the supplier, adding its quantities to the products' incoming
+	// quantities.
+	SendPurchaseOrder(ctx context.Context, namespaceID string, req *requests.SendPurchaseOrder) (order *models.PurchaseOrder, err error)
+
+	// ReceivePurchaseOrder posts the received quantities into the stock at the lines' unit costs.
+	ReceivePurchaseOrder(ctx context.Context, namespaceID, userID string, req *requests.ReceivePurchaseOrder) (order *models.PurchaseOrder, err error)
+
+	// ClosePurchaseOrder ends a received order, no longer expecting any quantity that was not received.
+	ClosePurchaseOrder(ctx context.Context, namespaceID string, req *requests.ClosePurchaseOrder) (order *models.PurchaseOrder, err error)
+
+	// CancelPurchaseOrder cancels an order before any of its goods is received.
+	CancelPurchaseOrder(ctx context.Context, namespaceID string, req *requests.CancelPurchaseOrder) (order *models.PurchaseOrder, err error)
+}
+
+func (s *service) ListPurchaseOrder(ctx context.Context, namespaceID string, req *requests.ListPurchaseOrder) ([]models.PurchaseOrder, int64, error) {
+	orders, count, err := s.store.PurchaseOrder.GetMany(ctx, namespaceID, &req.Query)
+	return orders, count, mapError(err, s.store.PurchaseOrder.Entity())
+}
+
+func (s *service) GetPurchaseOrder(ctx context.Context, namespaceID string, req *requests.GetPurchaseOrder) (*models.PurchaseOrder, error) {
+	po, err := s.store.PurchaseOrder.Get(ctx, namespaceID, req.ID)
+	return po, mapError(err, s.store.PurchaseOrder.Entity())
+}
+
+func (s *service) CreatePurchaseOrder(ctx context.Context, namespaceID, userID string, req *requests.CreatePurchaseOrder) (string, error) {
+	po := &models.PurchaseOrder{
+		NamespaceID: namespaceID,
+		Status:      models.PurchaseOrderDraft,
+		SupplierID:  req.SupplierID,
+		WarehouseID: req.WarehouseID,
+		Location:    req.Location,
+		Lines:       req.Lines,
+		Notes:       req.Notes,
+		ExpectedAt:  req.ExpectedAt,
+		Currency:    money.Currency(req.Currency),
+		CreatedBy:   userID,
+	}
+
+	if err := s.checkPurchaseOrder(ctx, namespaceID, po); err != nil {
+		return "", err
+	}
+
+	insertedID, err := s.store.PurchaseOrder.Create(ctx, po)
+	return insertedID, mapError(err, s.store.PurchaseOrder.Entity())
+}
+
+func (s *service) UpdatePurchaseOrder(ctx context.Context, namespaceID string, req *requests.UpdatePurchaseOrder) (*models.PurchaseOrder, error) {
+	po, err := s.store.PurchaseOrder.Get(ctx, namespaceID, req.ID)
+	if err != nil {
+		return nil, mapError(err, s.store.PurchaseOrder.Entity())
+	}
+
+	if po.Status != models.PurchaseOrderDraft {
+		return nil, illegalTransition(s.store.PurchaseOrder.Entity(), po.Status, "update")
+	}
+
+	if req.Location != nil {
+		po.Location = *req.Location
+	}
+
+	if req.Lines != nil {
+		po.Lines = req.Lines
+	}
+
+	if err := s.checkPurchaseOrder(ctx, namespaceID, po); err != nil {
+		return nil, err
+	}
+
+	changes := &models.PurchaseOrderChanges{
+		Location:   req.Location,
+		Notes:      req.Notes,
+		ExpectedAt: req.ExpectedAt,
+	}
+
+	// The lines are only changed when requested, with the defaults applied by checkPurchaseOrder, and
+	// repriced at the current exchange rate.
+	if req.Lines != nil {
+		changes.Lines = po.Lines
+		changes.Currency = po.Currency
+		changes.ExchangeRate = po.ExchangeRate
+	}
+
+	if err := s.store.PurchaseOrder.Update(ctx, namespaceID, req.ID, []models.PurchaseOrderStatus{models.PurchaseOrderDraft}, changes); err != nil {
+		return nil, mapError(err, s.store.PurchaseOrder.Entity())
+	}
+
+	po, err = s.store.PurchaseOrder.Get(ctx, namespaceID, req.ID)
+	return po, mapError(err, s.store.PurchaseOrder.Entity())
+}
+
+func (s *service) DeletePurchaseOrder(ctx context.Context, namespaceID string, req *requests.DeletePurchaseOrder) error {
+	err := s.store.WithTransaction(ctx, func(ctx context.Context) error {
+		po, err := s.store.PurchaseOrder.Get(ctx, namespaceID, req.ID)
+		if err != nil {
+			return err
+		}
+
+		if po.Status != models.PurchaseOrderDraft {
+			return illegalTransition(s.store.PurchaseOrder.Entity(), po.Status, "delete")
+		}
+
+		return s.store.PurchaseOrder.Delete(ctx, namespaceID, req.ID)
+	})
+
+	return mapError(err, s.store.PurchaseOrder.Entity())
+}
+
+func (s *service) SendPurchaseOrder(ctx context.Context, namespaceID string, req *requests.SendPurchaseOrder) (*models.PurchaseOrder, error) {
+	err := s.store.WithTransaction(ctx, func(ctx context.Context) error {
+		po, err := s.store.PurchaseOrder.Get(ctx, namespaceID, req.ID)
+		if err != nil {
+			return err
+		}
+
+		if po.Status != models.PurchaseOrderDraft {
+			return illegalTransition(s.store.PurchaseOrder.Entity(), po.Status, "send")
+		}
+
+		sup, err := s.store.Supplier.Get(ctx, namespaceID, po.SupplierID)
+		if err != nil {
+			return mapError(err, s.store.Supplier.Entity())
+		}
+
+		for _, l := range po.Lines {
+			if err := s.store.Product.AddIncoming(ctx, namespaceID, l.ProductID, l.Quantity); err != nil {
+				return mapError(err, s.store.Product.Entity())
+			}
+		}
+
+		now := clock.Now()
+		changes := &models.PurchaseOrderChanges{Status: models.PurchaseOrderSent, SentAt: &now}
+		if po.ExpectedAt == nil {
+			expectedAt := now.AddDate(0, 0, sup.LeadTimeDays)
+			changes.ExpectedAt = &expectedAt
+		}
+
+		return s.store.PurchaseOrder.Update(ctx, namespaceID, po.ID, []models.PurchaseOrderStatus{models.PurchaseOrderDraft}, changes)
+	})
+	if err != nil {
+		return nil, mapError(err, s.store.PurchaseOrder.Entity())
+	}
+
+	po, err := s.store.PurchaseOrder.Get(ctx, namespaceID, req.ID)
+	return po, mapError(err, s.store.PurchaseOrder.Entity())
+}
+
+func (s *service) ReceivePurchaseOrder(ctx context.Context, namespaceID, userID string, req *requests.ReceivePurchaseOrder) (*models.PurchaseOrder, error) {
+	receivable := []models.PurchaseOrderStatus{models.PurchaseOrderSent, models.PurchaseOrderPartiallyReceived}
+
+	err := s.store.WithTransaction(ctx, func(ctx context.Context) error {
+		po, err := s.store.PurchaseOrder.Get(ctx, namespaceID, req.ID)
+		if err != nil {
+			return err
+		}
+
+		if po.Status != models.PurchaseOrderSent && po.Status != models.PurchaseOrderPartiallyReceived {
+			return illegalTransition(s.store.PurchaseOrder.Entity(), po.Status, "receive")
+		}
+
+		receipts, err := po.Receive(req.Lines)
+		if err != nil {
+			return errors.
+				New().
+				Code(http.StatusBadRequest).
+				Attr("lines", []string{err.Error()}).
+				Layer(errors.LayerService).
+				Msg(errors.MsgBadRequest)
+		}
+
+		ns, err := s.store.Namespace.Get(ctx, namespaceID)
+		if err != nil {
+			return err
+		}
+
+		// Received goods are valued in the base currency, at the rate the order was priced with.
+		conv := po.Conversion(ns.Settings.Currency)
+
+		movements := make([]*models.Movement, 0, len(receipts))
+		for _, r := range receipts {
+			cost := conv.ToBase(*po.Line(r.ProductID, r.VariantID).UnitCost)
+			mov := &models.Movement{
+				NamespaceID: namespaceID,
+				StockKey:    po.Key(r.ProductID, r.VariantID),
+				Type:        models.MovementReceipt,
+				Quantity:    r.Quantity,
+				Reason:      "purchase order received",
+				UserID:      userID,
+				Reference:   po.ID,
+				UnitCost:    &cost,
+			}
+
+			lotted, err := s.lotMovements(ctx, namespaceID, mov, r.Lot, r.ExpiresAt, false)
+			if err != nil {
+				return err
+			}
+
+			if err := s.serialMovements(ctx, namespaceID, lotted, r.Serials); err != nil {
+				return err
+			}
+
+			movements = append(movements, lotted...)
+
+			if err := s.store.Product.AddIncoming(ctx, namespaceID, r.ProductID, -r.Quantity); err != nil {
+				return mapError(err, s.store.Product.Entity())
+			}
+		}
+
+		if err := s.post(ctx, namespaceID, movements...); err != nil {
+			return err
+		}
+
+		changes := &models.PurchaseOrderChanges{Status: po.Status, Lines: po.Lines}
+		if po.Status == models.PurchaseOrderReceived {
+			now := clock.Now()
+			changes.ReceivedAt = &now
+		}
+
+		return s.store.PurchaseOrder.Update(ctx, namespaceID, po.ID, receivable, changes)
+	})
+	if err != nil {
+		return nil, mapError(err, s.store.PurchaseOrder.Entity())
+	}
+
+	po, err := s.store.PurchaseOrder.Get(ctx, namespaceID, req.ID)
+	return po, mapError(err, s.store.PurchaseOrder.Entity())
+}
+
+func (s *service) ClosePurchaseOrder(ctx context.Context, namespaceID string, req *requests.ClosePurchaseOrder) (*models.PurchaseOrder, error) {
+	closable := []models.PurchaseOrderStatus{models.PurchaseOrderReceived, models.PurchaseOrderPartiallyReceived}
+
+	err := s.store.WithTransaction(ctx, func(ctx context.Context) error {
+		po, err := s.store.PurchaseOrder.Get(ctx, namespaceID, req.ID)
+		if err != nil {
+			return err
+		}
+
+		if po.Status != models.PurchaseOrderReceived && po.Status != models.PurchaseOrderPartiallyReceived {
+			return illegalTransition(s.store.PurchaseOrder.Entity(), po.Status, "close")
+		}
+
+		if err := s.releaseIncoming(ctx, namespaceID, po); err != nil {
+			return err
+		}
+
+		now := clock.Now()
+		changes := &models.PurchaseOrderChanges{Status: models.PurchaseOrderClosed, ClosedAt: &now}
+
+		return s.store.PurchaseOrder.Update(ctx, namespaceID, po.ID, closable, changes)
+	})
+	if err != nil {
+		return nil, mapError(err, s.store.PurchaseOrder.Entity())
+	}
+
+	po, err := s.store.PurchaseOrder.Get(ctx, namespaceID, req.ID)
+	return po, mapError(err, s.store.PurchaseOrder.Entity())
+}
+
+func (s *service) CancelPurchaseOrder(ctx context.Context, namespaceID string, req *requests.CancelPurchaseOrder) (*models.PurchaseOrder, error) {
+	cancellable := []models.PurchaseOrderStatus{models.PurchaseOrderDraft, models.PurchaseOrderSent}
+
+	err := s.store.WithTransaction(ctx, func(ctx context.Context) error {
+		po, err := s.store.PurchaseOrder.Get(ctx, namespaceID, req.ID)
+		if err != nil {
+			return err
+		}
+
+		if po.Status != models.PurchaseOrderDraft && po.Status != models.PurchaseOrderSent {
+			return illegalTransition(s.store.PurchaseOrder.Entity(), po.Status, "cancel")
+		}
+
+		// Draft orders were never added to the incoming quantities.
+		if po.Status == models.PurchaseOrderSent {
+			if err := s.releaseIncoming(ctx, namespaceID, po); err != nil {
+				return err
+			}
+		}
+
+		now := clock.Now()
+		changes := &models.PurchaseOrderChanges{Status: models.PurchaseOrderCancelled, CancelledAt: &now}
+
+		return s.store.PurchaseOrder.Update(ctx, namespaceID, po.ID, cancellable, changes)
+	})
+	if err != nil {
+		return nil, mapError(err, s.store.PurchaseOrder.Entity())
+	}
+
+	po, err := s.store.PurchaseOrder.Get(ctx, namespaceID, req.ID)
+	return po, mapError(err, s.store.PurchaseOrder.Entity())
+}
+
+// releaseIncoming removes the quantities of the purchase order that are still to be received from the
+// products' incoming quantities. It must be called within a transaction.
+func (s *service) releaseIncoming(ctx context.Context, namespaceID string, po *models.PurchaseOrder) error {
+	for _, l := range po.Lines {
+		if l.Remaining() <= 0 {
+			continue
+		}
+
+		if err := s.store.Product.AddIncoming(ctx, namespaceID, l.ProductID, -l.Remaining()); err != nil {
+			return mapError(err, s.store.Product.Entity())
+		}
+	}
+
+	return nil
+}
+
+// checkPurchaseOrder reports whether the purchase order buys existent items from an active supplier into
+// an existent warehouse location. Lines without a supplier SKU or unit cost take the ones of the supplier's
+// mapping of the product, and the unit cost falls back to the product's cost.
+func (s *service) checkPurchaseOrder(ctx context.Context, namespaceID string, po *models.PurchaseOrder) error {
+	if err := models.CheckPurchaseOrderLines(po.Lines); err != nil {
+		return errors.
+			New().
+			Code(http.StatusBadRequest).
+			Attr("lines", []string{err.Error()}).
+			Layer(errors.LayerService).
+			Msg(errors.MsgBadRequest)
+	}
+
+	sup, err := s.store.Supplier.Get(ctx, namespaceID, po.SupplierID)
+	if err != nil {
+		return mapError(err, s.store.Supplier.Entity())
+	}
+
+	if !sup.Active {
+		return errors.
+			New().
+			Code(http.StatusBadRequest).
+			Attr("supplier_id", []string{"supplier is not active"}).
+			Layer(errors.LayerService).
+			Msg(errors.MsgBadRequest)
+	}
+
+	ns, err := s.store.Namespace.Get(ctx, namespaceID)
+	if err != nil {
+		return mapError(err, s.store.Namespace.Entity())
+	}
+
+	now := clock.Now()
+
+	if po.Currency == "" {
+		po.Currency = sup.Currency
+	}
+
+	conv, err := s.conversion(ctx, namespaceID, &ns.Settings, po.Currency, now)
+	if err != nil {
+		return err
+	}
+
+	po.Currency, po.ExchangeRate = conv.Currency, conv.Rate
+
+	// The supplier's costs are quoted in its currency and the products' costs in the base currency.
+	supplier := conv
+	if sup.Currency != conv.Currency {
+		if supplier, err = s.conversion(ctx, namespaceID, &ns.Settings, sup.Currency, now); err != nil {
+			return err
+		}
+	}
+
+	for i, l := range po.Lines {
+		// Quantities are only received after the order is sent.
+		po.Lines[i].Received = 0
+
+		prd, err := s.checkStockKey(ctx, namespaceID, po.Key(l.ProductID, l.VariantID))
+		if err != nil {
+			return err
+		}
+
+		if err := inCurrency(po.Currency, "lines", po.Lines[i].UnitCost); err != nil {
+			return err
+		}
+
+		mapping, ok := sup.Product(l.ProductID, l.VariantID)
+		if l.SupplierSKU == "" {
+			po.Lines[i].SupplierSKU = mapping.SKU
+		}
+
+		if po.Lines[i].UnitCost == nil {
+			cost := conv.FromBase(prd.Cost)
+			switch {
+			case ok && mapping.Cost.Amount > 0 && supplier == conv:
+				cost = mapping.Cost
+			case ok && mapping.Cost.Amount > 0:
+				cost = conv.FromBase(supplier.ToBase(mapping.Cost))
+			}
+
+			po.Lines[i].UnitCost = &cost
+		}
+	}
+
+	return nil
+}
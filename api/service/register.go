@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/heiytor/invenda/api/pkg/errors"
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/requests"
+	"github.com/heiytor/invenda/api/store"
+)
+
+type Register interface {
+	ListRegister(ctx context.Context, namespaceID string, req *requests.ListRegister) (registers []models.Register, count int64, err error)
+	GetRegister(ctx context.Context, namespaceID string, req *requests.GetRegister) (register *models.Register, err error)
+	CreateRegister(ctx context.Context, namespaceID string, req *requests.CreateRegister) (insertedID string, err error)
+	UpdateRegister(ctx context.Context, namespaceID string, req *requests.UpdateRegister) (register *models.Register, err error)
+	DeleteRegister(ctx context.Context, namespaceID string, req *requests.DeleteRegister) (err error)
+}
+
+func (s *service) ListRegister(ctx context.Context, namespaceID string, req *requests.ListRegister) ([]models.Register, int64, error) {
+	registers, count, err := s.store.Register.GetMany(ctx, namespaceID, &req.Query)
+	return registers, count, mapError(err, s.store.Register.Entity())
+}
+
+func (s *service) GetRegister(ctx context.Context, namespaceID string, req *requests.GetRegister) (*models.Register, error) {
+	reg, err := s.store.Register.Get(ctx, namespaceID, req.ID)
+	return reg, mapError(err, s.store.Register.Entity())
+}
+
+func (s *service) CreateRegister(ctx context.Context, namespaceID string, req *requests.CreateRegister) (string, error) {
+	if err := s.checkRegisterLocation(ctx, namespaceID, req.WarehouseID, req.Location); err != nil {
+		return "", err
+	}
+
+	conflicts, err := s.store.Register.Conflicts(ctx, namespaceID, &models.Register{Code: req.Code})
+	if err != nil {
+		return "", mapError(err, s.store.Register.Entity())
+	}
+
+	if len(conflicts) > 0 {
+		return "", errors.
+			New().
+			Code(http.StatusConflict).
+			Attr("entity", s.store.Register.Entity()).
+			Attr("conflicts", conflicts).
+			Layer(errors.LayerService).
+			Msg(errors.MsgConflict)
+	}
+
+	reg := &models.Register{
+		NamespaceID: namespaceID,
+		Code:        req.Code,
+		Name:        req.Name,
+		WarehouseID: req.WarehouseID,
+		Location:    req.Location,
+		Active:      req.Active == nil || *req.Active,
+	}
+
+	insertedID, err := s.store.Register.Create(ctx, reg)
+	return insertedID, mapError(err, s.store.Register.Entity())
+}
+
+func (s *service) UpdateRegister(ctx context.Context, namespaceID string, req *requests.UpdateRegister) (*models.Register, error) {
+	reg, err := s.store.Register.Get(ctx, namespaceID, req.ID)
+	if err != nil {
+		return nil, mapError(err, s.store.Register.Entity())
+	}
+
+	if req.WarehouseID != "" || req.Location != nil {
+		warehouseID, location := reg.WarehouseID, reg.Location
+		if req.WarehouseID != "" {
+			warehouseID = req.WarehouseID
+		}
+
+		if req.Location != nil {
+			location = *req.Location
+		}
+
+		if err := s.checkRegisterLocation(ctx, namespaceID, warehouseID, location); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.Code != "" && req.Code != reg.Code {
+		conflicts, err := s.store.Register.Conflicts(ctx, namespaceID, &models.Register{Code: req.Code})
+		if err != nil {
+			return nil, mapError(err, s.store.Register.Entity())
+		}
+
+		if len(conflicts) > 0 {
+			return nil, errors.
+				New().
+				Code(http.StatusConflict).
+				Attr("entity", s.store.Register.Entity()).
+				Attr("conflicts", conflicts).
+				Layer(errors.LayerService).
+				Msg(errors.MsgConflict)
+		}
+	}
+
+	changes := &models.RegisterChanges{
+		Code:        req.Code,
+		Name:        req.Name,
+		WarehouseID: req.WarehouseID,
+		Location:    req.Location,
+		Active:      req.Active,
+	}
+
+	if err := s.store.Register.Update(ctx, namespaceID, req.ID, changes); err != nil {
+		return nil, mapError(err, s.store.Register.Entity())
+	}
+
+	reg, err = s.store.Register.Get(ctx, namespaceID, req.ID)
+	return reg, mapError(err, s.store.Register.Entity())
+}
+
+func (s *service) DeleteRegister(ctx context.Context, namespaceID string, req *requests.DeleteRegister) error {
+	// The open shift of a register must be closed first, so its drawer is reconciled.
+	if _, err := s.store.Shift.GetOpen(ctx, namespaceID, req.ID); err == nil {
+		return errors.
+			New().
+			Code(http.StatusConflict).
+			Layer(errors.LayerService).
+			Attr("entity", s.store.Register.Entity()).
+			Msg("a register with an open shift cannot be deleted")
+	} else if !errors.Is(err, store.ErrNotFound) {
+		return mapError(err, s.store.Shift.Entity())
+	}
+
+	return mapError(s.store.Register.Delete(ctx, namespaceID, req.ID), s.store.Register.Entity())
+}
+
+// checkRegisterLocation reports whether the warehouse location from which a register sells exists.
+func (s *service) checkRegisterLocation(ctx context.Context, namespaceID, warehouseID, location string) error {
+	wh, err := s.store.Warehouse.Get(ctx, namespaceID, warehouseID)
+	if err != nil {
+		return mapError(err, s.store.Warehouse.Entity())
+	}
+
+	if !wh.HasLocation(location) {
+		return errors.
+			New().
+			Code(http.StatusNotFound).
+			Attr("entity", "location").
+			Attr("location", location).
+			Layer(errors.LayerService).
+			Msg(errors.MsgNotFound)
+	}
+
+	return nil
+}
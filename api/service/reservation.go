@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/heiytor/invenda/api/pkg/clock"
+	"github.com/heiytor/invenda/api/pkg/errors"
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/requests"
+	"github.com/heiytor/invenda/api/store"
+	"github.com/rs/zerolog/log"
+)
+
+type Reservation interface {
+	ListReservation(ctx context.Context, namespaceID string, req *requests.ListReservation) (reservations []models.Reservation, count int64, err error)
+	GetReservation(ctx context.Context, namespaceID string, req *requests.GetReservation) (reservation *models.Reservation, err error)
+
+	// CreateReservation reserves stock of a key on behalf of the user with the specified ID. It fails
+	// when the key's available quantity does not cover the reservation.
+	CreateReservation(ctx context.Context, namespaceID, userID string, req *requests.CreateReservation) (insertedID string, err error)
+
+	// ReleaseReservation gives up an active reservation, making its stock available again.
+	ReleaseReservation(ctx context.Context, namespaceID string, req *requests.ReleaseReservation) (reservation *models.Reservation, err error)
+
+	// ExpireReservations releases the active reservations of every namespace that have expired, in
+	// batches until none is left. It returns the number of expired reservations.
+	ExpireReservations(ctx context.Context) (count int, err error)
+}
+
+func (s *service) ListReservation(ctx context.Context, namespaceID string, req *requests.ListReservation) ([]models.Reservation, int64, error) {
+	reservations, count, err := s.store.Reservation.GetMany(ctx, namespaceID, &req.Query)
+	return reservations, count, mapError(err, s.store.Reservation.Entity())
+}
+
+func (s *service) GetReservation(ctx context.Context, namespaceID string, req *requests.GetReservation) (*models.Reservation, error) {
+	rsv, err := s.store.Reservation.Get(ctx, namespaceID, req.ID)
+	return rsv, mapError(err, s.store.Reservation.Entity())
+}
+
+func (s *service) CreateReservation(ctx context.Context, namespaceID, userID string, req *requests.CreateReservation) (string, error) {
+	if !req.ExpiresAt.After(clock.Now()) {
+		return "", errors.
+			New().
+			Code(http.StatusBadRequest).
+			Attr("expires_at", []string{"expires_at must be in the future"}).
+			Layer(errors.LayerService).
+			Msg(errors.MsgBadRequest)
+	}
+
+	rsv := &models.Reservation{
+		NamespaceID: namespaceID,
+		StockKey: models.StockKey{
+			WarehouseID: req.WarehouseID,
+			Location:    req.Location,
+			ProductID:   req.ProductID,
+			VariantID:   req.VariantID,
+		},
+		Quantity:  req.Quantity,
+		ExpiresAt: req.ExpiresAt,
+		Reference: req.Reference,
+		CreatedBy: userID,
+	}
+
+	if _, err := s.checkStockKey(ctx, namespaceID, rsv.StockKey); err != nil {
+		return "", err
+	}
+
+	var insertedID string
+	err := s.store.WithTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		insertedID, err = s.reserve(ctx, rsv)
+
+		return err
+	})
+
+	return insertedID, mapError(err, s.store.Reservation.Entity())
+}
+
+func (s *service) ReleaseReservation(ctx context.Context, namespaceID string, req *requests.ReleaseReservation) (*models.Reservation, error) {
+	err := s.store.WithTransaction(ctx, func(ctx context.Context) error {
+		return s.endReservation(ctx, namespaceID, req.ID, models.ReservationReleased)
+	})
+	if err != nil {
+		return nil, mapError(err, s.store.Reservation.Entity())
+	}
+
+	rsv, err := s.store.Reservation.Get(ctx, namespaceID, req.ID)
+	return rsv, mapError(err, s.store.Reservation.Entity())
+}
+
+// expireBatch is the number of expired reservations read at a time.
+const expireBatch = 100
+
+func (s *service) ExpireReservations(ctx context.Context) (int, error) {
+	now := clock.Now()
+
+	count := 0
+	for {
+		expired, err := s.store.Reservation.Expired(ctx, now, expireBatch)
+		if err != nil {
+			return count, mapError(err, s.store.Reservation.Entity())
+		}
+
+		ended := 0
+		for _, rsv := range expired {
+			err := s.store.WithTransaction(ctx, func(ctx context.Context) error {
+				return s.endReservation(ctx, rsv.NamespaceID, rsv.ID, models.ReservationExpired)
+			})
+
+			switch {
+			case err == nil:
+				count++
+				ended++
+			case errors.As(err) != nil:
+				// The reservation ended concurrently, e.g. its order was shipped.
+				ended++
+			default:
+				log.Error().Err(err).Str("reservation", rsv.ID).Msg("unable to expire the reservation")
+			}
+		}
+
+		// The reservations that could not be ended are still active and would be read again, so they are
+		// left to the next sweep once a batch ends none.
+		if len(expired) < expireBatch || ended == 0 {
+			return count, nil
+		}
+	}
+}
+
+// reserve increments the reserved quantity of the reservation's key and creates the reservation. It
+// must be called within a transaction.
+func (s *service) reserve(ctx context.Context, rsv *models.Reservation) (string, error) {
+	if err := s.store.Stock.Reserve(ctx, rsv.NamespaceID, rsv.StockKey, rsv.Quantity); err != nil {
+		return "", err
+	}
+
+	return s.store.Reservation.Create(ctx, rsv)
+}
+
+// endReservation moves an active reservation to status, giving its quantity back to the key's available
+// stock. It must be called within a transaction.
+func (s *service) endReservation(ctx context.Context, namespaceID, id string, status models.ReservationStatus) error {
+	rsv, err := s.store.Reservation.Get(ctx, namespaceID, id)
+	if err != nil {
+		return err
+	}
+
+	if rsv.Status != models.ReservationActive {
+		return illegalTransition(s.store.Reservation.Entity(), rsv.Status, string(status))
+	}
+
+	if err := s.store.Reservation.SetStatus(ctx, namespaceID, id, status); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return illegalTransition(s.store.Reservation.Entity(), rsv.Status, string(status))
+		}
+
+		return err
+	}
+
+	return s.store.Stock.Reserve(ctx, namespaceID, rsv.StockKey, -rsv.Quantity)
+}
@@ -0,0 +1,260 @@
+package service
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/heiytor/invenda/api/pkg/errors"
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/money"
+	"github.com/heiytor/invenda/api/pkg/query"
+	"github.com/heiytor/invenda/api/pkg/requests"
+	"github.com/heiytor/invenda/api/store"
+)
+
+type Return interface {
+	ListReturn(ctx context.Context, namespaceID string, req *requests.ListReturn) (returns []models.Return, count int64, err error)
+	GetReturn(ctx context.Context, namespaceID string, req *requests.GetReturn) (ret *models.Return, err error)
+
+	// CreateReturn returns lines of a counter sale on behalf of the user within the open shift of a
+	// register. The returned goods are put back into the stock they were sold from, or into the
+	// warehouse's quarantine when damaged, and the customer is refunded with the requested method.
+	CreateReturn(ctx context.Context, namespaceID, userID string, req *requests.CreateReturn) (ret *models.Return, err error)
+}
+
+func (s *service) ListReturn(ctx context.Context, namespaceID string, req *requests.ListReturn) ([]models.Return, int64, error) {
+	returns, count, err := s.store.Return.GetMany(ctx, namespaceID, &req.Query)
+	return returns, count, mapError(err, s.store.Return.Entity())
+}
+
+func (s *service) GetReturn(ctx context.Context, namespaceID string, req *requests.GetReturn) (*models.Return, error) {
+	ret, err := s.store.Return.Get(ctx, namespaceID, req.ID)
+	return ret, mapError(err, s.store.Return.Entity())
+}
+
+func (s *service) CreateReturn(ctx context.Context, namespaceID, userID string, req *requests.CreateReturn) (*models.Return, error) {
+	if err := models.CheckReturnLines(req.Lines); err != nil {
+		return nil, errors.
+			New().
+			Code(http.StatusBadRequest).
+			Attr("lines", []string{err.Error()}).
+			Layer(errors.LayerService).
+			Msg(errors.MsgBadRequest)
+	}
+
+	sal, err := s.store.Sale.Get(ctx, namespaceID, req.SaleID)
+	if err != nil {
+		return nil, mapError(err, s.store.Sale.Entity())
+	}
+
+	if req.RegisterID == "" {
+		req.RegisterID = sal.RegisterID
+	}
+
+	if _, err := s.store.Register.Get(ctx, namespaceID, req.RegisterID); err != nil {
+		return nil, mapError(err, s.store.Register.Entity())
+	}
+
+	shf, err := s.openShift(ctx, namespaceID, req.RegisterID)
+	if err != nil {
+		return nil, err
+	}
+
+	wh, err := s.store.Warehouse.Get(ctx, namespaceID, sal.WarehouseID)
+	if err != nil {
+		return nil, mapError(err, s.store.Warehouse.Entity())
+	}
+
+	ret := &models.Return{
+		NamespaceID: namespaceID,
+		SaleID:      sal.ID,
+		RegisterID:  req.RegisterID,
+		ShiftID:     shf.ID,
+		WarehouseID: sal.WarehouseID,
+		CustomerID:  sal.CustomerID,
+		Lines:       req.Lines,
+		Notes:       req.Notes,
+		Refund:      models.Refund{Method: req.RefundMethod},
+		CreatedBy:   userID,
+	}
+
+	for i, l := range ret.Lines {
+		ret.Lines[i].Damaged = l.Damaged || l.Reason == models.ReturnDamaged
+		ret.Lines[i].Location = sal.Location
+
+		if ret.Lines[i].Damaged {
+			if wh.Quarantine == "" {
+				return nil, errors.
+					New().
+					Code(http.StatusBadRequest).
+					Attr("lines", []string{"the warehouse has no quarantine for damaged goods"}).
+					Layer(errors.LayerService).
+					Msg(errors.MsgBadRequest)
+			}
+
+			ret.Lines[i].Location = wh.Quarantine
+		}
+	}
+
+	err = s.store.WithTransaction(ctx, func(ctx context.Context) error {
+		// The sale is read again within the transaction, so concurrent returns of the same sale conflict
+		// instead of returning or refunding it twice.
+		sal, err := s.store.Sale.Get(ctx, namespaceID, req.SaleID)
+		if err != nil {
+			return err
+		}
+
+		amount, err := sal.Return(ret.Lines)
+		if err != nil {
+			return errors.
+				New().
+				Code(http.StatusBadRequest).
+				Attr("lines", []string{err.Error()}).
+				Layer(errors.LayerService).
+				Msg(errors.MsgBadRequest)
+		}
+
+		ret.Refund.Amount = amount
+
+		refunded := make(map[models.TenderMethod]money.Money)
+		switch ret.Refund.Method {
+		case models.RefundOriginalTender:
+			tenders, err := sal.RefundTenders(amount)
+			if err != nil {
+				return errors.
+					New().
+					Code(http.StatusBadRequest).
+					Attr("refund_method", []string{err.Error()}).
+					Layer(errors.LayerService).
+					Msg(errors.MsgBadRequest)
+			}
+
+			ret.Refund.Tenders = tenders
+			// Each method is refunded by a single tender.
+			for _, t := range tenders {
+				refunded[t.Method] = t.Amount
+			}
+		case models.RefundStoreCredit:
+			if sal.CustomerID == "" {
+				return errors.
+					New().
+					Code(http.StatusBadRequest).
+					Attr("refund_method", []string{"store credit requires a sale with a customer"}).
+					Layer(errors.LayerService).
+					Msg(errors.MsgBadRequest)
+			}
+
+			if err := s.store.Customer.AddCredit(ctx, namespaceID, sal.CustomerID, amount); err != nil {
+				return mapError(err, s.store.Customer.Entity())
+			}
+		}
+
+		if err := s.store.Sale.Return(ctx, namespaceID, sal.ID, ret.Lines, refunded); err != nil {
+			return err
+		}
+
+		if _, err := s.store.Return.Create(ctx, ret); err != nil {
+			return err
+		}
+
+		// The units of each item returned before this return, across the sale's lines, from which the value
+		// the sale issued them at is split.
+		returned := make(map[string]int64, len(ret.Lines))
+		for _, sl := range sal.Lines {
+			returned[sl.ProductID+"/"+sl.VariantID] += sl.Returned
+		}
+
+		for _, l := range ret.Lines {
+			returned[l.ProductID+"/"+l.VariantID] -= l.Quantity
+		}
+
+		base, err := s.baseCurrency(ctx, namespaceID)
+		if err != nil {
+			return err
+		}
+
+		movements := make([]*models.Movement, 0, len(ret.Lines))
+		for _, l := range ret.Lines {
+			issued, value, err := s.saleIssues(ctx, namespaceID, base, sal.ID, l.ProductID, l.VariantID)
+			if err != nil {
+				return err
+			}
+
+			key := l.ProductID + "/" + l.VariantID
+			value = models.ValueOfUnits(value, issued, returned[key], l.Quantity)
+			returned[key] += l.Quantity
+
+			mov := &models.Movement{
+				NamespaceID: namespaceID,
+				StockKey:    models.StockKey{WarehouseID: ret.WarehouseID, Location: l.Location, ProductID: l.ProductID, VariantID: l.VariantID},
+				Type:        models.MovementReturn,
+				Quantity:    l.Quantity,
+				Reason:      "customer return: " + string(l.Reason),
+				UserID:      userID,
+				Reference:   ret.ID,
+			}
+
+			lotted, err := s.lotMovements(ctx, namespaceID, mov, l.Lot, nil, false)
+			if err != nil {
+				return err
+			}
+
+			if err := s.serialMovements(ctx, namespaceID, lotted, l.Serials); err != nil {
+				return err
+			}
+
+			// Items the sale never issued come back at their default cost.
+			if issued > 0 {
+				models.AllocateValue(lotted, value)
+			}
+
+			movements = append(movements, lotted...)
+		}
+
+		if err := s.post(ctx, namespaceID, movements...); err != nil {
+			return err
+		}
+
+		// The cash refunded leaves the shift's drawer.
+		err = s.store.Shift.Record(ctx, namespaceID, shf.ID, &models.ShiftDelta{CashRefunds: refunded[models.TenderCash]})
+		if errors.Is(err, store.ErrNotFound) {
+			return illegalTransition(s.store.Shift.Entity(), models.ShiftClosed, "refund")
+		}
+
+		return err
+	})
+	if err != nil {
+		return nil, mapError(err, s.store.Return.Entity())
+	}
+
+	return ret, nil
+}
+
+// saleIssues returns the quantity of a product or variant a sale issued and the value, in the base
+// currency, it left the stock at, so its returns come back into the stock at the cost they left it. It
+// returns zeros when the sale issued no such item.
+func (s *service) saleIssues(ctx context.Context, namespaceID string, base money.Currency, saleID, productID, variantID string) (int64, money.Money, error) {
+	// The query has no paginator, so every movement is read however many lots the issues were split
+	// across.
+	movements, _, err := s.store.Stock.Movements(ctx, namespaceID, &query.Query{
+		Filter: query.Filter{Conditions: []query.Condition{
+			{Field: "reference", Operator: query.OperatorEq, Value: saleID},
+			{Field: "product_id", Operator: query.OperatorEq, Value: productID},
+			{Field: "variant_id", Operator: query.OperatorEq, Value: variantID},
+			{Field: "type", Operator: query.OperatorEq, Value: models.MovementIssue},
+		}},
+	})
+	if err != nil {
+		return 0, money.Money{}, err
+	}
+
+	var quantity, value int64
+	for _, m := range movements {
+		quantity -= m.Quantity
+		if m.Value != nil {
+			value -= m.Value.Amount
+		}
+	}
+
+	return quantity, money.New(value, base), nil
+}
@@ -0,0 +1,342 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+
+	"github.com/heiytor/invenda/api/pkg/clock"
+	"github.com/heiytor/invenda/api/pkg/errors"
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/requests"
+	"github.com/heiytor/invenda/api/store"
+)
+
+type Sale interface {
+	ListSale(ctx context.Context, namespaceID string, req *requests.ListSale) (sales []models.Sale, count int64, err error)
+	GetSale(ctx context.Context, namespaceID string, req *requests.GetSale) (sale *models.Sale, err error)
+
+	// CreateSale completes a counter sale made by the user within the open shift of the register, issuing
+	// its lines from the register's stock and adding its takings to the shift. A
+	// request whose idempotency key was already used returns the sale made by the first request and
+	// reports it as replayed, so a sale is never charged twice. It returns a conflict when the request
+	// repeating the key has another payload.
+	CreateSale(ctx context.Context, namespaceID, userID string, req *requests.CreateSale) (sale *models.Sale, replayed bool, err error)
+
+	// LookupSaleItem identifies the product or variant with the requested barcode.
+	LookupSaleItem(ctx context.Context, namespaceID string, req *requests.LookupSaleItem) (item *models.SaleItem, err error)
+}
+
+func (s *service) ListSale(ctx context.Context, namespaceID string, req *requests.ListSale) ([]models.Sale, int64, error) {
+	sales, count, err := s.store.Sale.GetMany(ctx, namespaceID, &req.Query)
+	return sales, count, mapError(err, s.store.Sale.Entity())
+}
+
+func (s *service) GetSale(ctx context.Context, namespaceID string, req *requests.GetSale) (*models.Sale, error) {
+	sal, err := s.store.Sale.Get(ctx, namespaceID, req.ID)
+	return sal, mapError(err, s.store.Sale.Entity())
+}
+
+func (s *service) CreateSale(ctx context.Context, namespaceID, userID string, req *requests.CreateSale) (*models.Sale, bool, error) {
+	hash := requestHash(req)
+
+	if sal, err := s.store.Sale.GetByIdempotencyKey(ctx, namespaceID, req.IdempotencyKey); err == nil {
+		return replaySale(sal, hash)
+	} else if !errors.Is(err, store.ErrNotFound) {
+		return nil, false, mapError(err, s.store.Sale.Entity())
+	}
+
+	reg, err := s.store.Register.Get(ctx, namespaceID, req.RegisterID)
+	if err != nil {
+		return nil, false, mapError(err, s.store.Register.Entity())
+	}
+
+	if !reg.Active {
+		return nil, false, errors.
+			New().
+			Code(http.StatusBadRequest).
+			Attr("register_id", []string{"register is not active"}).
+			Layer(errors.LayerService).
+			Msg(errors.MsgBadRequest)
+	}
+
+	shf, err := s.openShift(ctx, namespaceID, reg.ID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// Anonymous customers only get the prices of the lists that apply to every customer.
+	group := ""
+	if req.CustomerID != "" {
+		cus, err := s.store.Customer.Get(ctx, namespaceID, req.CustomerID)
+		if err != nil {
+			return nil, false, mapError(err, s.store.Customer.Entity())
+		}
+
+		group = cus.Group
+	}
+
+	sal := &models.Sale{
+		NamespaceID:    namespaceID,
+		IdempotencyKey: req.IdempotencyKey,
+		RequestHash:    hash,
+		RegisterID:     reg.ID,
+		ShiftID:        shf.ID,
+		WarehouseID:    reg.WarehouseID,
+		Location:       reg.Location,
+		CustomerID:     req.CustomerID,
+		CashierID:      userID,
+		Lines:          req.Lines,
+		Payments:       req.Payments,
+		Coupons:        req.Coupons,
+	}
+
+	if err := s.checkSale(ctx, namespaceID, group, sal); err != nil {
+		return nil, false, err
+	}
+
+	// The sale is created before its stock is issued so that a concurrent request with the same key fails
+	// on the key's unique index, aborting the transaction before any stock is issued twice. As the sale's
+	// ID is new, only the sale_idempotency_key index can reject its insert; duplicates raised by any other
+	// write, e.g. of a serial, are conflicts of the sale itself.
+	replayed := false
+	err = s.store.WithTransaction(ctx, func(ctx context.Context) error {
+		if _, err := s.store.Sale.Create(ctx, sal); err != nil {
+			replayed = errors.Is(err, store.ErrDuplicated)
+
+			return err
+		}
+
+		if err := s.redeemPromotions(ctx, namespaceID, sal.PromotionIDs()); err != nil {
+			return err
+		}
+
+		movements := make([]*models.Movement, 0, len(sal.Lines))
+		for _, l := range sal.Lines {
+			mov := &models.Movement{
+				NamespaceID: namespaceID,
+				StockKey:    sal.Key(l),
+				Type:        models.MovementIssue,
+				Quantity:    -l.Quantity,
+				Reason:      "counter sale",
+				UserID:      userID,
+				Reference:   sal.ID,
+			}
+
+			lotted, err := s.lotMovements(ctx, namespaceID, mov, l.Lot, nil, false)
+			if err != nil {
+				return err
+			}
+
+			if err := s.serialMovements(ctx, namespaceID, lotted, l.Serials); err != nil {
+				return err
+			}
+
+			movements = append(movements, lotted...)
+		}
+
+		if err := s.post(ctx, namespaceID, movements...); err != nil {
+			return err
+		}
+
+		// The shift may have been closed since it was read, in which case the sale cannot be made.
+		err := s.store.Shift.Record(ctx, namespaceID, shf.ID, &models.ShiftDelta{Sales: 1, Takings: sal.Takings()})
+		if errors.Is(err, store.ErrNotFound) {
+			return illegalTransition(s.store.Shift.Entity(), models.ShiftClosed, "sell")
+		}
+
+		return err
+	})
+
+	switch {
+	case replayed:
+		sal, err := s.store.Sale.GetByIdempotencyKey(ctx, namespaceID, req.IdempotencyKey)
+		if err != nil {
+			return nil, false, mapError(err, s.store.Sale.Entity())
+		}
+
+		return replaySale(sal, hash)
+	case err != nil:
+		return nil, false, mapError(err, s.store.Sale.Entity())
+	}
+
+	return sal, false, nil
+}
+
+// requestHash returns the hash of the request's payload, which tells a retry of a request apart from
+// another request reusing its idempotency key.
+func requestHash(req *requests.CreateSale) string {
+	data, _ := json.Marshal(req)
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:])
+}
+
+// replaySale returns the sale made by the first request with an idempotency key as replayed. It returns a
+// conflict when the request repeating the key has another payload.
+func replaySale(sal *models.Sale, hash string) (*models.Sale, bool, error) {
+	if sal.RequestHash != hash {
+		return nil, false, errors.
+			New().
+			Code(http.StatusConflict).
+			Attr("idempotency_key", []string{"key was already used by a request with another payload"}).
+			Layer(errors.LayerService).
+			Msg(errors.MsgConflict)
+	}
+
+	return sal, true, nil
+}
+
+func (s *service) LookupSaleItem(ctx context.Context, namespaceID string, req *requests.LookupSaleItem) (*models.SaleItem, error) {
+	return s.lookupBarcode(ctx, namespaceID, req.Barcode)
+}
+
+// lookupBarcode returns the sale item of the product or variant with the barcode. As barcodes are unique
+// across the namespace's products and variants, at most one of them matches.
+func (s *service) lookupBarcode(ctx context.Context, namespaceID, barcode string) (*models.SaleItem, error) {
+	vrt, err := s.store.Variant.GetByBarcode(ctx, namespaceID, barcode)
+	switch {
+	case err == nil:
+		prd, err := s.store.Product.Get(ctx, namespaceID, vrt.ProductID)
+		if err != nil {
+			return nil, mapError(err, s.store.Product.Entity())
+		}
+
+		return &models.SaleItem{ProductID: prd.ID, VariantID: vrt.ID, SKU: vrt.SKU, Name: prd.Name, Price: vrt.EffectivePrice(prd)}, nil
+	case !errors.Is(err, store.ErrNotFound):
+		return nil, mapError(err, s.store.Variant.Entity())
+	}
+
+	prd, err := s.store.Product.GetByBarcode(ctx, namespaceID, barcode)
+	if err != nil {
+		return nil, mapError(err, s.store.Product.Entity())
+	}
+
+	return &models.SaleItem{ProductID: prd.ID, SKU: prd.SKU, Name: prd.Name, Price: prd.Price}, nil
+}
+
+// checkSale reports whether the sale sells existent items from its register's warehouse location and is
+// fully paid. Lines identified by a barcode are resolved to their product or variant, whose SKU and name
+// they take. Lines without a unit price take the price resolved for the customer's group from the price
+// lists, falling back to the price of their product or variant. The promotions and tax rates that apply to
+// the sale are evaluated, as for sales orders, and the sale's amounts are computed and its payments settled.
+func (s *service) checkSale(ctx context.Context, namespaceID, group string, sal *models.Sale) error {
+	now := clock.Now()
+
+	settings, rates, err := s.taxes(ctx, namespaceID, now)
+	if err != nil {
+		return err
+	}
+
+	// Counter sales are made in the base currency, which is the currency of the registers' drawers.
+	sal.TaxInclusive, sal.TaxRounding, sal.Currency = settings.TaxInclusive, settings.Rounding(), settings.Currency
+	cart := &models.Cart{CustomerGroup: group, Coupons: sal.Coupons, At: now, Currency: sal.Currency, Lines: make([]models.CartLine, len(sal.Lines))}
+
+	for i := range sal.Lines {
+		l := &sal.Lines[i]
+		l.PriceListID, l.Taxes = "", nil
+
+		if err := inCurrency(sal.Currency, "lines", l.UnitPrice, &l.Discount); err != nil {
+			return err
+		}
+
+		if l.ProductID == "" && l.Barcode != "" {
+			item, err := s.lookupBarcode(ctx, namespaceID, l.Barcode)
+			if err != nil {
+				return err
+			}
+
+			l.ProductID, l.VariantID = item.ProductID, item.VariantID
+		}
+
+		// Lines without a product are reported by CheckSaleLines.
+		if l.ProductID == "" {
+			continue
+		}
+
+		prd, err := s.checkStockKey(ctx, namespaceID, sal.Key(*l))
+		if err != nil {
+			return err
+		}
+
+		l.SKU, l.Name = prd.SKU, prd.Name
+		price := prd.Price
+
+		if l.VariantID != "" {
+			vrt, err := s.store.Variant.Get(ctx, namespaceID, l.ProductID, l.VariantID)
+			if err != nil {
+				return mapError(err, s.store.Variant.Entity())
+			}
+
+			l.SKU, price = vrt.SKU, vrt.EffectivePrice(prd)
+		}
+
+		if l.UnitPrice == nil {
+			price, l.PriceListID, err = s.unitPrice(ctx, namespaceID, group, l.ProductID, l.VariantID, l.Quantity, price, now)
+			if err != nil {
+				return err
+			}
+
+			l.UnitPrice = &price
+		}
+
+		l.Taxes = models.TaxesFor(rates, prd.TaxCategory, now)
+
+		cart.Lines[i] = models.CartLine{
+			ProductID:  l.ProductID,
+			VariantID:  l.VariantID,
+			CategoryID: prd.CategoryID,
+			Quantity:   l.Quantity,
+			UnitPrice:  l.UnitPrice,
+			Discount:   l.Discount,
+		}
+	}
+
+	if err := models.CheckSaleLines(sal.Lines); err != nil {
+		return errors.
+			New().
+			Code(http.StatusBadRequest).
+			Attr("lines", []string{err.Error()}).
+			Layer(errors.LayerService).
+			Msg(errors.MsgBadRequest)
+	}
+
+	for i := range sal.Payments {
+		if err := inCurrency(sal.Currency, "payments", &sal.Payments[i].Amount); err != nil {
+			return err
+		}
+	}
+
+	if err := models.CheckTenders(sal.Payments); err != nil {
+		return errors.
+			New().
+			Code(http.StatusBadRequest).
+			Attr("payments", []string{err.Error()}).
+			Layer(errors.LayerService).
+			Msg(errors.MsgBadRequest)
+	}
+
+	if err := s.promote(ctx, namespaceID, cart, models.Conversion{}); err != nil {
+		return err
+	}
+
+	sal.Coupons = cart.Coupons
+	for i := range sal.Lines {
+		sal.Lines[i].Promotions = cart.Lines[i].Promotions
+	}
+
+	sal.Compute()
+
+	if err := sal.Settle(); err != nil {
+		return errors.
+			New().
+			Code(http.StatusBadRequest).
+			Attr("payments", []string{err.Error()}).
+			Layer(errors.LayerService).
+			Msg(errors.MsgBadRequest)
+	}
+
+	return nil
+}
@@ -0,0 +1,466 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/heiytor/invenda/api/pkg/clock"
+	"github.com/heiytor/invenda/api/pkg/errors"
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/money"
+	"github.com/heiytor/invenda/api/pkg/requests"
+)
+
+// salesReservationTTL is how long a confirmed sales order holds its stock. An order whose reservations
+// expired can still be shipped while the stock is available.
+const salesReservationTTL = 30 * 24 * time.Hour
+
+type SalesOrder interface {
+	ListSalesOrder(ctx context.Context, namespaceID string, req *requests.ListSalesOrder) (orders []models.SalesOrder, count int64, err error)
+	GetSalesOrder(ctx context.Context, namespaceID string, req *requests.GetSalesOrder) (order *models.SalesOrder, err error)
+	CreateSalesOrder(ctx context.Context, namespaceID, userID string, req *requests.CreateSalesOrder) (insertedID string, err error)
+	UpdateSalesOrder(ctx context.Context, namespaceID string, req *requests.UpdateSalesOrder) (order *models.SalesOrder, err error)
+	DeleteSalesOrder(ctx context.Context, namespaceID string, req *requests.DeleteSalesOrder) (err error)
+
+	// ConfirmSalesOrder places the order, reserving the stock of its lines on behalf of the user and using
+	// the promotions applied to them.
+	ConfirmSalesOrder(ctx context.Context, namespaceID, userID string, req *requests.ConfirmSalesOrder) (order *models.SalesOrder, err error)
+
+	// PickSalesOrder records that the order's goods were picked from the warehouse.
+	PickSalesOrder(ctx context.Context, namespaceID string, req *requests.PickSalesOrder) (order *models.SalesOrder, err error)
+
+	// ShipSalesOrder consumes the order's reservations and issues its lines from the stock.
+	ShipSalesOrder(ctx context.Context, namespaceID, userID string, req *requests.ShipSalesOrder) (order *models.SalesOrder, err error)
+
+	// InvoiceSalesOrder records that the shipped order was invoiced to the customer.
+	InvoiceSalesOrder(ctx context.Context, namespaceID string, req *requests.InvoiceSalesOrder) (order *models.SalesOrder, err error)
+
+	// CloseSalesOrder ends an invoiced order.
+	CloseSalesOrder(ctx context.Context, namespaceID string, req *requests.CloseSalesOrder) (order *models.SalesOrder, err error)
+
+	// CancelSalesOrder cancels an order that was not shipped, releasing its reservations and the uses of its
+	// promotions.
+	CancelSalesOrder(ctx context.Context, namespaceID string, req *requests.CancelSalesOrder) (order *models.SalesOrder, err error)
+}
+
+func (s *service) ListSalesOrder(ctx context.Context, namespaceID string, req *requests.ListSalesOrder) ([]models.SalesOrder, int64, error) {
+	orders, count, err := s.store.SalesOrder.GetMany(ctx, namespaceID, &req.Query)
+	return orders, count, mapError(err, s.store.SalesOrder.Entity())
+}
+
+func (s *service) GetSalesOrder(ctx context.Context, namespaceID string, req *requests.GetSalesOrder) (*models.SalesOrder, error) {
+	so, err := s.store.SalesOrder.Get(ctx, namespaceID, req.ID)
+	return so, mapError(err, s.store.SalesOrder.Entity())
+}
+
+func (s *service) CreateSalesOrder(ctx context.Context, namespaceID, userID string, req *requests.CreateSalesOrder) (string, error) {
+	cus, err := s.store.Customer.Get(ctx, namespaceID, req.CustomerID)
+	if err != nil {
+		return "", mapError(err, s.store.Customer.Entity())
+	}
+
+	so := &models.SalesOrder{
+		NamespaceID:     namespaceID,
+		Status:          models.SalesOrderDraft,
+		CustomerID:      req.CustomerID,
+		WarehouseID:     req.WarehouseID,
+		Location:        req.Location,
+		ShippingAddress: req.ShippingAddress,
+		Lines:           req.Lines,
+		Notes:           req.Notes,
+		Coupons:         req.Coupons,
+		Currency:        money.Currency(req.Currency),
+		CreatedBy:       userID,
+	}
+
+	if so.ShippingAddress == nil {
+		so.ShippingAddress = cus.DefaultAddress()
+	}
+
+	if err := s.checkSalesOrder(ctx, namespaceID, cus.Group, so); err != nil {
+		return "", err
+	}
+
+	insertedID, err := s.store.SalesOrder.Create(ctx, so)
+	return insertedID, mapError(err, s.store.SalesOrder.Entity())
+}
+
+func (s *service) UpdateSalesOrder(ctx context.Context, namespaceID string, req *requests.UpdateSalesOrder) (*models.SalesOrder, error) {
+	so, err := s.store.SalesOrder.Get(ctx, namespaceID, req.ID)
+	if err != nil {
+		return nil, mapError(err, s.store.SalesOrder.Entity())
+	}
+
+	if so.Status != models.SalesOrderDraft {
+		return nil, illegalTransition(s.store.SalesOrder.Entity(), so.Status, "update")
+	}
+
+	cus, err := s.store.Customer.Get(ctx, namespaceID, so.CustomerID)
+	if err != nil {
+		return nil, mapError(err, s.store.Customer.Entity())
+	}
+
+	if req.Location != nil {
+		so.Location = *req.Location
+	}
+
+	if req.Lines != nil {
+		so.Lines = req.Lines
+	}
+
+	if req.Coupons != nil {
+		so.Coupons = req.Coupons
+	}
+
+	if err := s.checkSalesOrder(ctx, namespaceID, cus.Group, so); err != nil {
+		return nil, err
+	}
+
+	changes := &models.SalesOrderChanges{
+		Location:        req.Location,
+		ShippingAddress: req.ShippingAddress,
+		Notes:           req.Notes,
+	}
+
+	// The lines are only changed when their items or coupons are, with the defaults, promotions and amounts
+	// applied by checkSalesOrder.
+	if req.Lines != nil || req.Coupons != nil {
+		changes.Lines = so.Lines
+		changes.Coupons = &so.Coupons
+		changes.Subtotal = &so.Subtotal
+		changes.Discount = &so.Discount
+		changes.Tax = &so.Tax
+		changes.Total = &so.Total
+		changes.TaxInclusive = &so.TaxInclusive
+		changes.TaxRounding = so.TaxRounding
+		changes.Taxes = &so.Taxes
+		changes.Currency = so.Currency
+		changes.ExchangeRate = so.ExchangeRate
+		changes.BaseTotal = &so.BaseTotal
+	}
+
+	if err := s.store.SalesOrder.Update(ctx, namespaceID, req.ID, []models.SalesOrderStatus{models.SalesOrderDraft}, changes); err != nil {
+		return nil, mapError(err, s.store.SalesOrder.Entity())
+	}
+
+	so, err = s.store.SalesOrder.Get(ctx, namespaceID, req.ID)
+	return so, mapError(err, s.store.SalesOrder.Entity())
+}
+
+func (s *service) DeleteSalesOrder(ctx context.Context, namespaceID string, req *requests.DeleteSalesOrder) error {
+	err := s.store.WithTransaction(ctx, func(ctx context.Context) error {
+		so, err := s.store.SalesOrder.Get(ctx, namespaceID, req.ID)
+		if err != nil {
+			return err
+		}
+
+		if so.Status != models.SalesOrderDraft {
+			return illegalTransition(s.store.SalesOrder.Entity(), so.Status, "delete")
+		}
+
+		return s.store.SalesOrder.Delete(ctx, namespaceID, req.ID)
+	})
+
+	return mapError(err, s.store.SalesOrder.Entity())
+}
+
+func (s *service) ConfirmSalesOrder(ctx context.Context, namespaceID, userID string, req *requests.ConfirmSalesOrder) (*models.SalesOrder, error) {
+	return s.transitionSalesOrder(ctx, namespaceID, req.ID, models.SalesOrderConfirm, func(ctx context.Context, so *models.SalesOrder, changes *models.SalesOrderChanges) error {
+		now := clock.Now()
+
+		for i, l := range so.Lines {
+			rsv := &models.Reservation{
+				NamespaceID: namespaceID,
+				StockKey:    so.Key(l),
+				Quantity:    l.Quantity,
+				ExpiresAt:   now.Add(salesReservationTTL),
+				Reference:   so.ID,
+				CreatedBy:   userID,
+			}
+
+			id, err := s.reserve(ctx, rsv)
+			if err != nil {
+				return err
+			}
+
+			so.Lines[i].ReservationID = id
+		}
+
+		if err := s.redeemPromotions(ctx, namespaceID, so.PromotionIDs()); err != nil {
+			return err
+		}
+
+		changes.Lines = so.Lines
+		changes.PlacedAt = &now
+
+		return nil
+	})
+}
+
+func (s *service) PickSalesOrder(ctx context.Context, namespaceID string, req *requests.PickSalesOrder) (*models.SalesOrder, error) {
+	return s.transitionSalesOrder(ctx, namespaceID, req.ID, models.SalesOrderPick, func(_ context.Context, _ *models.SalesOrder, changes *models.SalesOrderChanges) error {
+		now := clock.Now()
+		changes.PickedAt = &now
+
+		return nil
+	})
+}
+
+func (s *service) ShipSalesOrder(ctx context.Context, namespaceID, userID string, req *requests.ShipSalesOrder) (*models.SalesOrder, error) {
+	return s.transitionSalesOrder(ctx, namespaceID, req.ID, models.SalesOrderShip, func(ctx context.Context, so *models.SalesOrder, changes *models.SalesOrderChanges) error {
+		for _, sh := range req.Shipments {
+			if !slices.ContainsFunc(so.Lines, func(l models.SalesOrderLine) bool { return l.ProductID == sh.ProductID && l.VariantID == sh.VariantID }) {
+				return errors.
+					New().
+					Code(http.StatusBadRequest).
+					Attr("shipments", []string{fmt.Sprintf("line %q does not belong to the sales order", sh.ProductID)}).
+					Layer(errors.LayerService).
+					Msg(errors.MsgBadRequest)
+			}
+		}
+
+		movements := make([]*models.Movement, 0, len(so.Lines))
+		for _, l := range so.Lines {
+			// The reserved stock is given back right before being issued. Expired reservations no longer
+			// hold anything, so the line is issued from the available stock.
+			if err := s.consumeReservation(ctx, namespaceID, l.ReservationID, models.ReservationConsumed); err != nil {
+				return err
+			}
+
+			var shipment models.SalesOrderShipment
+			if idx := slices.IndexFunc(req.Shipments, func(sh models.SalesOrderShipment) bool {
+				return sh.ProductID == l.ProductID && sh.VariantID == l.VariantID
+			}); idx >= 0 {
+				shipment = req.Shipments[idx]
+			}
+
+			mov := &models.Movement{
+				NamespaceID: namespaceID,
+				StockKey:    so.Key(l),
+				Type:        models.MovementIssue,
+				Quantity:    -l.Quantity,
+				Reason:      "sales order shipped",
+				UserID:      userID,
+				Reference:   so.ID,
+			}
+
+			lotted, err := s.lotMovements(ctx, namespaceID, mov, shipment.Lot, nil, false)
+			if err != nil {
+				return err
+			}
+
+			if err := s.serialMovements(ctx, namespaceID, lotted, shipment.Serials); err != nil {
+				return err
+			}
+
+			movements = append(movements, lotted...)
+		}
+
+		if err := s.post(ctx, namespaceID, movements...); err != nil {
+			return err
+		}
+
+		now := clock.Now()
+		changes.ShippedAt = &now
+
+		return nil
+	})
+}
+
+func (s *service) InvoiceSalesOrder(ctx context.Context, namespaceID string, req *requests.InvoiceSalesOrder) (*models.SalesOrder, error) {
+	return s.transitionSalesOrder(ctx, namespaceID, req.ID, models.SalesOrderInvoice, func(_ context.Context, _ *models.SalesOrder, changes *models.SalesOrderChanges) error {
+		now := clock.Now()
+		changes.InvoicedAt = &now
+
+		return nil
+	})
+}
+
+func (s *service) CloseSalesOrder(ctx context.Context, namespaceID string, req *requests.CloseSalesOrder) (*models.SalesOrder, error) {
+	return s.transitionSalesOrder(ctx, namespaceID, req.ID, models.SalesOrderClose, func(_ context.Context, _ *models.SalesOrder, changes *models.SalesOrderChanges) error {
+		now := clock.Now()
+		changes.ClosedAt = &now
+
+		return nil
+	})
+}
+
+func (s *service) CancelSalesOrder(ctx context.Context, namespaceID string, req *requests.CancelSalesOrder) (*models.SalesOrder, error) {
+	return s.transitionSalesOrder(ctx, namespaceID, req.ID, models.SalesOrderCancel, func(ctx context.Context, so *models.SalesOrder, changes *models.SalesOrderChanges) error {
+		for _, l := range so.Lines {
+			if err := s.consumeReservation(ctx, namespaceID, l.ReservationID, models.ReservationReleased); err != nil {
+				return err
+			}
+		}
+
+		// Only placed orders used their promotions.
+		if so.PlacedAt != nil {
+			if err := s.releasePromotions(ctx, namespaceID, so.PromotionIDs()); err != nil {
+				return err
+			}
+		}
+
+		now := clock.Now()
+		changes.CancelledAt = &now
+
+		return nil
+	})
+}
+
+// transitionSalesOrder performs an action on a sales order within a transaction. The order must be in one
+// of the statuses the action can be performed from, otherwise an illegal transition is reported. apply
+// performs the action's side effects and fills the changes, which move the order to the action's status.
+func (s *service) transitionSalesOrder(
+	ctx context.Context,
+	namespaceID, id string,
+	action models.SalesOrderAction,
+	apply func(ctx context.Context, so *models.SalesOrder, changes *models.SalesOrderChanges) error,
+) (*models.SalesOrder, error) {
+	from, to := action.Transition()
+
+	err := s.store.WithTransaction(ctx, func(ctx context.Context) error {
+		so, err := s.store.SalesOrder.Get(ctx, namespaceID, id)
+		if err != nil {
+			return err
+		}
+
+		if !so.Status.Can(action) {
+			return illegalTransition(s.store.SalesOrder.Entity(), so.Status, string(action))
+		}
+
+		changes := &models.SalesOrderChanges{Status: to}
+		if err := apply(ctx, so, changes); err != nil {
+			return err
+		}
+
+		return s.store.SalesOrder.Update(ctx, namespaceID, id, from, changes)
+	})
+	if err != nil {
+		return nil, mapError(err, s.store.SalesOrder.Entity())
+	}
+
+	so, err := s.store.SalesOrder.Get(ctx, namespaceID, id)
+	return so, mapError(err, s.store.SalesOrder.Entity())
+}
+
+// consumeReservation ends the reservation with the specified ID when it is still active, moving it to
+// status. Reservations that already ended, e.g. by expiring, are left untouched. It must be called within
+// a transaction.
+func (s *service) consumeReservation(ctx context.Context, namespaceID, id string, status models.ReservationStatus) error {
+	if id == "" {
+		return nil
+	}
+
+	rsv, err := s.store.Reservation.Get(ctx, namespaceID, id)
+	if err != nil {
+		return err
+	}
+
+	if rsv.Status != models.ReservationActive {
+		return nil
+	}
+
+	return s.endReservation(ctx, namespaceID, id, status)
+}
+
+// checkSalesOrder reports whether the sales order sells existent items from an existent warehouse
+// location. Lines without a unit price take the price resolved for the customer's group from the price
+// lists, falling back to the price of their product or variant. The promotions that apply to the order
+// are evaluated, the lines take the tax rates of their products' tax categories and the amounts of the
+// lines and the order are computed with the namespace's tax mode.
+func (s *service) checkSalesOrder(ctx context.Context, namespaceID, group string, so *models.SalesOrder) error {
+	now := clock.Now()
+
+	settings, rates, err := s.taxes(ctx, namespaceID, now)
+	if err != nil {
+		return err
+	}
+
+	so.TaxInclusive, so.TaxRounding = settings.TaxInclusive, settings.Rounding()
+
+	// The catalog and the promotions are priced in the base currency, converted to the order's one.
+	conv, err := s.conversion(ctx, namespaceID, settings, so.Currency, now)
+	if err != nil {
+		return err
+	}
+
+	so.Currency, so.ExchangeRate = conv.Currency, conv.Rate
+
+	cart := &models.Cart{CustomerGroup: group, Coupons: so.Coupons, At: now, Currency: so.Currency, Lines: make([]models.CartLine, len(so.Lines))}
+
+	for i := range so.Lines {
+		// Reservations are only assigned when the order is confirmed and price lists only when they
+		// price the line.
+		so.Lines[i].ReservationID = ""
+		so.Lines[i].PriceListID = ""
+
+		if err := inCurrency(so.Currency, "lines", so.Lines[i].UnitPrice, &so.Lines[i].Discount); err != nil {
+			return err
+		}
+
+		l := so.Lines[i]
+
+		prd, err := s.checkStockKey(ctx, namespaceID, so.Key(l))
+		if err != nil {
+			return err
+		}
+
+		if l.UnitPrice == nil {
+			base := prd.Price
+
+			if l.VariantID != "" {
+				vrt, err := s.store.Variant.Get(ctx, namespaceID, l.ProductID, l.VariantID)
+				if err != nil {
+					return mapError(err, s.store.Variant.Entity())
+				}
+
+				base = vrt.EffectivePrice(prd)
+			}
+
+			base, so.Lines[i].PriceListID, err = s.unitPrice(ctx, namespaceID, group, l.ProductID, l.VariantID, l.Quantity, base, now)
+			if err != nil {
+				return err
+			}
+
+			price := conv.FromBase(base)
+			so.Lines[i].UnitPrice = &price
+		}
+
+		so.Lines[i].Taxes = models.TaxesFor(rates, prd.TaxCategory, now)
+
+		cart.Lines[i] = models.CartLine{
+			ProductID:  l.ProductID,
+			VariantID:  l.VariantID,
+			CategoryID: prd.CategoryID,
+			Quantity:   l.Quantity,
+			UnitPrice:  so.Lines[i].UnitPrice,
+			Discount:   l.Discount,
+		}
+	}
+
+	if err := models.CheckSalesOrderLines(so.Lines); err != nil {
+		return errors.
+			New().
+			Code(http.StatusBadRequest).
+			Attr("lines", []string{err.Error()}).
+			Layer(errors.LayerService).
+			Msg(errors.MsgBadRequest)
+	}
+
+	if err := s.promote(ctx, namespaceID, cart, conv); err != nil {
+		return err
+	}
+
+	so.Coupons = cart.Coupons
+	for i := range so.Lines {
+		so.Lines[i].Promotions = cart.Lines[i].Promotions
+	}
+
+	so.Compute()
+	so.BaseTotal = conv.ToBase(so.Total)
+
+	return nil
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/heiytor/invenda/api/pkg/errors"
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/query"
+	"github.com/heiytor/invenda/api/pkg/requests"
+)
+
+type Serial interface {
+	ListSerial(ctx context.Context, namespaceID string, req *requests.ListSerial) (units []models.StockSerial, count int64, err error)
+
+	// GetSerialHistory returns a serialized unit along with every movement that named it.
+	GetSerialHistory(ctx context.Context, namespaceID string, req *requests.GetSerialHistory) (history *models.SerialHistory, err error)
+}
+
+func (s *service) ListSerial(ctx context.Context, namespaceID string, req *requests.ListSerial) ([]models.StockSerial, int64, error) {
+	units, count, err := s.store.Stock.Serials(ctx, namespaceID, &req.Query)
+	return units, count, mapError(err, s.store.Stock.Entity())
+}
+
+func (s *service) GetSerialHistory(ctx context.Context, namespaceID string, req *requests.GetSerialHistory) (*models.SerialHistory, error) {
+	unit, err := s.store.Stock.Serial(ctx, namespaceID, req.ProductID, req.Serial)
+	if err != nil {
+		return nil, mapError(err, "serial")
+	}
+
+	q := &query.Query{
+		Filter: query.Filter{
+			Conditions: []query.Condition{
+				{Field: "product_id", Operator: query.OperatorEq, Value: req.ProductID},
+				{Field: "serials", Operator: query.OperatorEq, Value: req.Serial},
+			},
+		},
+		Sorter: query.Sorter{By: "created_at", Order: query.OrderAsc},
+	}
+
+	movements, _, err := s.store.Stock.Movements(ctx, namespaceID, q)
+	if err != nil {
+		return nil, mapError(err, s.store.Stock.Entity())
+	}
+
+	return &models.SerialHistory{Serial: unit, Movements: movements}, nil
+}
+
+// serialMovements assigns the serials to the movements of a serialized product, one per unit moved.
+// Movements of serialized products must name exactly one serial per unit, while movements of other
+// products cannot name any.
+func (s *service) serialMovements(ctx context.Context, namespaceID string, movements []*models.Movement, serials []string) error {
+	if len(movements) == 0 {
+		return nil
+	}
+
+	prd, err := s.store.Product.Get(ctx, namespaceID, movements[0].ProductID)
+	if err != nil {
+		return mapError(err, s.store.Product.Entity())
+	}
+
+	if !prd.Serialized {
+		if len(serials) > 0 {
+			return errors.
+				New().
+				Code(http.StatusBadRequest).
+				Attr("serials", []string{"product is not serialized"}).
+				Layer(errors.LayerService).
+				Msg(errors.MsgBadRequest)
+		}
+
+		return nil
+	}
+
+	var quantity int64
+	for _, m := range movements {
+		quantity += max(m.Quantity, -m.Quantity)
+	}
+
+	if err := models.CheckSerials(serials, quantity); err != nil {
+		return errors.
+			New().
+			Code(http.StatusBadRequest).
+			Attr("serials", []string{err.Error()}).
+			Layer(errors.LayerService).
+			Msg(errors.MsgBadRequest)
+	}
+
+	models.AssignSerials(movements, serials)
+
+	return nil
+}
@@ -14,6 +14,30 @@ type Service interface {
 	User
 	Namespace
 	Session
+	Product
+	Variant
+	Category
+	Warehouse
+	Stock
+	Transfer
+	StockCount
+	StockLevel
+	Lot
+	Serial
+	Reservation
+	Valuation
+	Supplier
+	PurchaseOrder
+	Customer
+	SalesOrder
+	Register
+	Sale
+	Shift
+	Return
+	PriceList
+	Promotion
+	TaxRate
+	ExchangeRate
 }
 
 func New(store *store.Store, cache cache.Cache) Service {
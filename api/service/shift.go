@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/heiytor/invenda/api/pkg/clock"
+	"github.com/heiytor/invenda/api/pkg/errors"
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/money"
+	"github.com/heiytor/invenda/api/pkg/requests"
+	"github.com/heiytor/invenda/api/store"
+)
+
+type Shift interface {
+	ListShift(ctx context.Context, namespaceID string, req *requests.ListShift) (shifts []models.Shift, count int64, err error)
+	GetShift(ctx context.Context, namespaceID string, req *requests.GetShift) (shift *models.Shift, err error)
+
+	// OpenShift opens a shift at a register on behalf of the user. A register that already has an open
+	// shift cannot open another one.
+	OpenShift(ctx context.Context, namespaceID, userID string, req *requests.OpenShift) (insertedID string, err error)
+
+	// RecordShiftCash records a pay-in or a pay-out in the drawer of an open shift on behalf of the user.
+	RecordShiftCash(ctx context.Context, namespaceID, userID string, req *requests.RecordShiftCash) (shift *models.Shift, err error)
+
+	// CloseShift closes an open shift on behalf of the user, reconciling the counted cash with the cash the
+	// drawer was expected to hold.
+	CloseShift(ctx context.Context, namespaceID, userID string, req *requests.CloseShift) (shift *models.Shift, err error)
+}
+
+func (s *service) ListShift(ctx context.Context, namespaceID string, req *requests.ListShift) ([]models.Shift, int64, error) {
+	shifts, count, err := s.store.Shift.GetMany(ctx, namespaceID, &req.Query)
+	return shifts, count, mapError(err, s.store.Shift.Entity())
+}
+
+func (s *service) GetShift(ctx context.Context, namespaceID string, req *requests.GetShift) (*models.Shift, error) {
+	shf, err := s.store.Shift.Get(ctx, namespaceID, req.ID)
+	return shf, mapError(err, s.store.Shift.Entity())
+}
+
+func (s *service) OpenShift(ctx context.Context, namespaceID, userID string, req *requests.OpenShift) (string, error) {
+	reg, err := s.store.Register.Get(ctx, namespaceID, req.RegisterID)
+	if err != nil {
+		return "", mapError(err, s.store.Register.Entity())
+	}
+
+	if !reg.Active {
+		return "", errors.
+			New().
+			Code(http.StatusBadRequest).
+			Attr("register_id", []string{"register is not active"}).
+			Layer(errors.LayerService).
+			Msg(errors.MsgBadRequest)
+	}
+
+	// Drawers hold cash of the base currency.
+	base, err := s.baseCurrency(ctx, namespaceID)
+	if err != nil {
+		return "", err
+	}
+
+	if err := inCurrency(base, "float", &req.Float); err != nil {
+		return "", err
+	}
+
+	insertedID, err := s.store.Shift.Create(ctx, &models.Shift{
+		NamespaceID: namespaceID,
+		RegisterID:  reg.ID,
+		Float:       req.Float,
+		CashRefunds: money.Zero(base),
+		PayIns:      money.Zero(base),
+		PayOuts:     money.Zero(base),
+		OpenedBy:    userID,
+	})
+	if errors.Is(err, store.ErrDuplicated) {
+		return "", errors.
+			New().
+			Code(http.StatusConflict).
+			Attr("entity", s.store.Shift.Entity()).
+			Attr("register_id", reg.ID).
+			Layer(errors.LayerService).
+			Msg("the register already has an open shift")
+	}
+
+	return insertedID, mapError(err, s.store.Shift.Entity())
+}
+
+func (s *service) RecordShiftCash(ctx context.Context, namespaceID, userID string, req *requests.RecordShiftCash) (*models.Shift, error) {
+	shf, err := s.store.Shift.Get(ctx, namespaceID, req.ID)
+	if err != nil {
+		return nil, mapError(err, s.store.Shift.Entity())
+	}
+
+	if shf.Status != models.ShiftOpen {
+		return nil, illegalTransition(s.store.Shift.Entity(), shf.Status, string(req.Type))
+	}
+
+	if err := inCurrency(shf.Float.Currency, "amount", &req.Amount); err != nil {
+		return nil, err
+	}
+
+	if req.Type == models.CashPayOut && req.Amount.Amount > shf.ExpectedCash().Amount {
+		return nil, errors.
+			New().
+			Code(http.StatusBadRequest).
+			Attr("amount", []string{"pay-out exceeds the cash expected in the drawer"}).
+			Layer(errors.LayerService).
+			Msg(errors.MsgBadRequest)
+	}
+
+	delta := &models.ShiftDelta{
+		CashMovement: &models.CashMovement{
+			Type:      req.Type,
+			Amount:    req.Amount,
+			Reason:    req.Reason,
+			UserID:    userID,
+			CreatedAt: clock.Now(),
+		},
+	}
+
+	if err := s.store.Shift.Record(ctx, namespaceID, req.ID, delta); err != nil {
+		return nil, mapError(err, s.store.Shift.Entity())
+	}
+
+	shf, err = s.store.Shift.Get(ctx, namespaceID, req.ID)
+	return shf, mapError(err, s.store.Shift.Entity())
+}
+
+func (s *service) CloseShift(ctx context.Context, namespaceID, userID string, req *requests.CloseShift) (*models.Shift, error) {
+	// The shift is read and closed within a transaction, so an amount recorded in the meantime conflicts
+	// with the closing instead of being left out of the report.
+	err := s.store.WithTransaction(ctx, func(ctx context.Context) error {
+		shf, err := s.store.Shift.Get(ctx, namespaceID, req.ID)
+		if err != nil {
+			return err
+		}
+
+		if shf.Status != models.ShiftOpen {
+			return illegalTransition(s.store.Shift.Entity(), shf.Status, "close")
+		}
+
+		if err := inCurrency(shf.Float.Currency, "counted", &req.Counted); err != nil {
+			return err
+		}
+
+		return s.store.Shift.Close(ctx, namespaceID, req.ID, userID, shf.Reconcile(req.Counted, req.Notes))
+	})
+	if err != nil {
+		return nil, mapError(err, s.store.Shift.Entity())
+	}
+
+	shf, err := s.store.Shift.Get(ctx, namespaceID, req.ID)
+	return shf, mapError(err, s.store.Shift.Entity())
+}
+
+// openShift returns the open shift of a register, within which the register's sales are made.
+func (s *service) openShift(ctx context.Context, namespaceID, registerID string) (*models.Shift, error) {
+	shf, err := s.store.Shift.GetOpen(ctx, namespaceID, registerID)
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, errors.
+			New().
+			Code(http.StatusConflict).
+			Attr("entity", s.store.Shift.Entity()).
+			Attr("register_id", registerID).
+			Layer(errors.LayerService).
+			Msg("the register has no open shift")
+	}
+
+	return shf, mapError(err, s.store.Shift.Entity())
+}
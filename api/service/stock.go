@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/heiytor/invenda/api/pkg/errors"
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/requests"
+)
+
+type Stock interface {
+	ListMovement(ctx context.Context, namespaceID string, req *requests.ListMovement) (movements []models.Movement, count int64, err error)
+	ListBalance(ctx context.Context, namespaceID string, req *requests.ListBalance) (balances []models.Balance, count int64, err error)
+
+	// CreateMovement posts a receipt, an issue or an adjustment to the ledger on behalf of the user
+	// with the specified ID. Movements of lot-tracked products are split by lot, so it returns every
+	// posted movement. Movements of serialized products must name one serial per unit.
+	CreateMovement(ctx context.Context, namespaceID, userID string, req *requests.CreateMovement) (movements []*models.Movement, err error)
+
+	// RebuildBalances recomputes the namespace's balances from its ledger.
+	RebuildBalances(ctx context.Context, namespaceID string) (err error)
+}
+
+func (s *service) ListMovement(ctx context.Context, namespaceID string, req *requests.ListMovement) ([]models.Movement, int64, error) {
+	movements, count, err := s.store.Stock.Movements(ctx, namespaceID, &req.Query)
+	return movements, count, mapError(err, s.store.Stock.Entity())
+}
+
+func (s *service) ListBalance(ctx context.Context, namespaceID string, req *requests.ListBalance) ([]models.Balance, int64, error) {
+	balances, count, err := s.store.Stock.Balances(ctx, namespaceID, &req.Query)
+	for i := range balances {
+		balances[i].Available = balances[i].OnHand - balances[i].Reserved
+	}
+
+	return balances, count, mapError(err, s.store.Stock.Entity())
+}
+
+func (s *service) CreateMovement(ctx context.Context, namespaceID, userID string, req *requests.CreateMovement) ([]*models.Movement, error) {
+	key := models.StockKey{
+		WarehouseID: req.WarehouseID,
+		Location:    req.Location,
+		ProductID:   req.ProductID,
+		VariantID:   req.VariantID,
+	}
+
+	if _, err := s.checkStockKey(ctx, namespaceID, key); err != nil {
+		return nil, err
+	}
+
+	base, err := s.baseCurrency(ctx, namespaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := inCurrency(base, "unit_cost", req.UnitCost); err != nil {
+		return nil, err
+	}
+
+	typ := models.MovementType(req.Type)
+
+	quantity := req.Quantity
+	switch {
+	case (typ.Inbound() || typ.Outbound()) && quantity < 0:
+		return nil, errors.
+			New().
+			Code(http.StatusBadRequest).
+			Attr("quantity", []string{"quantity must be positive for " + req.Type}).
+			Layer(errors.LayerService).
+			Msg(errors.MsgBadRequest)
+	case typ.Outbound():
+		quantity = -quantity
+	}
+
+	mov := &models.Movement{
+		NamespaceID: namespaceID,
+		StockKey:    key,
+		Type:        typ,
+		Quantity:    quantity,
+		Reason:      req.Reason,
+		UserID:      userID,
+		UnitCost:    req.UnitCost,
+	}
+
+	// Only issues are blocked by expired lots; adjustments must be able to write expired stock off.
+	allowExpired := req.AllowExpired || typ == models.MovementAdjustment
+
+	var movements []*models.Movement
+	err = s.store.WithTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		if movements, err = s.lotMovements(ctx, namespaceID, mov, req.Lot, req.ExpiresAt, allowExpired); err != nil {
+			return err
+		}
+
+		if err := s.serialMovements(ctx, namespaceID, movements, req.Serials); err != nil {
+			return err
+		}
+
+		return s.post(ctx, namespaceID, movements...)
+	})
+	if err != nil {
+		return nil, mapError(err, s.store.Stock.Entity())
+	}
+
+	return movements, nil
+}
+
+func (s *service) RebuildBalances(ctx context.Context, namespaceID string) error {
+	err := s.store.WithTransaction(ctx, func(ctx context.Context) error {
+		return s.store.Stock.Rebuild(ctx, namespaceID)
+	})
+
+	return mapError(err, s.store.Stock.Entity())
+}
+
+// post values the movements with the namespace's valuation method and appends them to the ledger,
+// honoring the namespace's backorder setting. It must be called within a transaction.
+func (s *service) post(ctx context.Context, namespaceID string, movements ...*models.Movement) error {
+	ns, err := s.store.Namespace.Get(ctx, namespaceID)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range movements {
+		if err := s.value(ctx, ns.Settings.Valuation(), ns.Settings.Currency, m); err != nil {
+			return err
+		}
+	}
+
+	return s.store.Stock.Post(ctx, ns.Settings.AllowBackorders, movements...)
+}
+
+// checkStockKey reports whether the key refers to an existent warehouse location and product. Products
+// with options are stocked by variant, so the key must refer to one of its variants. It returns the
+// key's product.
+func (s *service) checkStockKey(ctx context.Context, namespaceID string, key models.StockKey) (*models.Product, error) {
+	wh, err := s.store.Warehouse.Get(ctx, namespaceID, key.WarehouseID)
+	if err != nil {
+		return nil, mapError(err, s.store.Warehouse.Entity())
+	}
+
+	if !wh.HasLocation(key.Location) {
+		return nil, errors.
+			New().
+			Code(http.StatusNotFound).
+			Attr("entity", "location").
+			Attr("location", key.Location).
+			Layer(errors.LayerService).
+			Msg(errors.MsgNotFound)
+	}
+
+	prd, err := s.store.Product.Get(ctx, namespaceID, key.ProductID)
+	if err != nil {
+		return nil, mapError(err, s.store.Product.Entity())
+	}
+
+	if key.VariantID == "" {
+		if len(prd.Options) > 0 {
+			return nil, errors.
+				New().
+				Code(http.StatusBadRequest).
+				Attr("variant_id", []string{"variant_id is required for products with options"}).
+				Layer(errors.LayerService).
+				Msg(errors.MsgBadRequest)
+		}
+
+		return prd, nil
+	}
+
+	if _, err := s.store.Variant.Get(ctx, namespaceID, key.ProductID, key.VariantID); err != nil {
+		return nil, mapError(err, s.store.Variant.Entity())
+	}
+
+	return prd, nil
+}
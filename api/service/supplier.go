@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/heiytor/invenda/api/pkg/errors"
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/money"
+	"github.com/heiytor/invenda/api/pkg/query"
+	"github.com/heiytor/invenda/api/pkg/requests"
+)
+
+type Supplier interface {
+	ListSupplier(ctx context.Context, namespaceID string, req *requests.ListSupplier) (suppliers []models.Supplier, count int64, err error)
+	GetSupplier(ctx context.Context, namespaceID string, req *requests.GetSupplier) (supplier *models.Supplier, err error)
+	CreateSupplier(ctx context.Context, namespaceID string, req *requests.CreateSupplier) (insertedID string, err error)
+	UpdateSupplier(ctx context.Context, namespaceID string, req *requests.UpdateSupplier) (supplier *models.Supplier, err error)
+	DeleteSupplier(ctx context.Context, namespaceID string, req *requests.DeleteSupplier) (err error)
+}
+
+func (s *service) ListSupplier(ctx context.Context, namespaceID string, req *requests.ListSupplier) ([]models.Supplier, int64, error) {
+	suppliers, count, err := s.store.Supplier.GetMany(ctx, namespaceID, &req.Query)
+	return suppliers, count, mapError(err, s.store.Supplier.Entity())
+}
+
+func (s *service) GetSupplier(ctx context.Context, namespaceID string, req *requests.GetSupplier) (*models.Supplier, error) {
+	sup, err := s.store.Supplier.Get(ctx, namespaceID, req.ID)
+	return sup, mapError(err, s.store.Supplier.Entity())
+}
+
+func (s *service) CreateSupplier(ctx context.Context, namespaceID string, req *requests.CreateSupplier) (string, error) {
+	if err := s.checkSupplierProducts(ctx, namespaceID, money.Currency(req.Currency), req.Products); err != nil {
+		return "", err
+	}
+
+	conflicts, err := s.store.Supplier.Conflicts(ctx, namespaceID, &models.Supplier{Code: req.Code})
+	if err != nil {
+		return "", mapError(err, s.store.Supplier.Entity())
+	}
+
+	if len(conflicts) > 0 {
+		return "", errors.
+			New().
+			Code(http.StatusConflict).
+			Attr("entity", s.store.Supplier.Entity()).
+			Attr("conflicts", conflicts).
+			Layer(errors.LayerService).
+			Msg(errors.MsgConflict)
+	}
+
+	sup := &models.Supplier{
+		NamespaceID:  namespaceID,
+		Code:         req.Code,
+		Name:         req.Name,
+		Contact:      req.Contact,
+		Address:      req.Address,
+		PaymentTerms: req.PaymentTerms,
+		Currency:     money.Currency(req.Currency),
+		LeadTimeDays: req.LeadTimeDays,
+		Active:       req.Active == nil || *req.Active,
+		Products:     req.Products,
+	}
+
+	insertedID, err := s.store.Supplier.Create(ctx, sup)
+	return insertedID, mapError(err, s.store.Supplier.Entity())
+}
+
+func (s *service) UpdateSupplier(ctx context.Context, namespaceID string, req *requests.UpdateSupplier) (*models.Supplier, error) {
+	sup, err := s.store.Supplier.Get(ctx, namespaceID, req.ID)
+	if err != nil {
+		return nil, mapError(err, s.store.Supplier.Entity())
+	}
+
+	currency := sup.Currency
+	if req.Currency != "" {
+		currency = money.Currency(req.Currency)
+	}
+
+	if err := s.checkSupplierProducts(ctx, namespaceID, currency, req.Products); err != nil {
+		return nil, err
+	}
+
+	if req.Code != "" && req.Code != sup.Code {
+		conflicts, err := s.store.Supplier.Conflicts(ctx, namespaceID, &models.Supplier{Code: req.Code})
+		if err != nil {
+			return nil, mapError(err, s.store.Supplier.Entity())
+		}
+
+		if len(conflicts) > 0 {
+			return nil, errors.
+				New().
+				Code(http.StatusConflict).
+				Attr("entity", s.store.Supplier.Entity()).
+				Attr("conflicts", conflicts).
+				Layer(errors.LayerService).
+				Msg(errors.MsgConflict)
+		}
+	}
+
+	changes := &models.SupplierChanges{
+		Code:         req.Code,
+		Name:         req.Name,
+		Contact:      req.Contact,
+		Address:      req.Address,
+		PaymentTerms: req.PaymentTerms,
+		Currency:     money.Currency(req.Currency),
+		LeadTimeDays: req.LeadTimeDays,
+		Active:       req.Active,
+		Products:     req.Products,
+	}
+
+	if err := s.store.Supplier.Update(ctx, namespaceID, req.ID, changes); err != nil {
+		return nil, mapError(err, s.store.Supplier.Entity())
+	}
+
+	sup, err = s.store.Supplier.Get(ctx, namespaceID, req.ID)
+	return sup, mapError(err, s.store.Supplier.Entity())
+}
+
+func (s *service) DeleteSupplier(ctx context.Context, namespaceID string, req *requests.DeleteSupplier) error {
+	// Open purchase orders still expect goods from the supplier, so they must be closed or cancelled first.
+	orders, _, err := s.store.PurchaseOrder.GetMany(ctx, namespaceID, &query.Query{
+		Paginator: query.Paginator{Page: 1, Size: 1},
+		Filter: query.Filter{Conditions: []query.Condition{
+			{Field: "supplier_id", Operator: query.OperatorEq, Value: req.ID},
+			{Field: "status", Operator: query.OperatorIn, Value: []models.PurchaseOrderStatus{
+				models.PurchaseOrderDraft,
+				models.PurchaseOrderSent,
+				models.PurchaseOrderPartiallyReceived,
+			}},
+		}},
+	})
+	if err != nil {
+		return mapError(err, s.store.PurchaseOrder.Entity())
+	}
+
+	if len(orders) > 0 {
+		return errors.
+			New().
+			Code(http.StatusConflict).
+			Layer(errors.LayerService).
+			Attr("entity", s.store.Supplier.Entity()).
+			Msg("a supplier with open purchase orders cannot be deleted")
+	}
+
+	return mapError(s.store.Supplier.Delete(ctx, namespaceID, req.ID), s.store.Supplier.Entity())
+}
+
+// checkSupplierProducts reports whether the supplier's products are valid and exist in the namespace.
+func (s *service) checkSupplierProducts(ctx context.Context, namespaceID string, currency money.Currency, products []models.SupplierProduct) error {
+	if err := models.CheckSupplierProducts(products); err != nil {
+		return errors.
+			New().
+			Code(http.StatusBadRequest).
+			Attr("products", []string{err.Error()}).
+			Layer(errors.LayerService).
+			Msg(errors.MsgBadRequest)
+	}
+
+	// Suppliers without a currency quote their costs in the base currency.
+	if currency == "" {
+		base, err := s.baseCurrency(ctx, namespaceID)
+		if err != nil {
+			return err
+		}
+
+		currency = base
+	}
+
+	for i, p := range products {
+		if err := inCurrency(currency, "products", &products[i].Cost); err != nil {
+			return err
+		}
+
+		if _, err := s.store.Product.Get(ctx, namespaceID, p.ProductID); err != nil {
+			return mapError(err, s.store.Product.Entity())
+		}
+
+		if p.VariantID != "" {
+			if _, err := s.store.Variant.Get(ctx, namespaceID, p.ProductID, p.VariantID); err != nil {
+				return mapError(err, s.store.Variant.Entity())
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/heiytor/invenda/api/pkg/clock"
+	"github.com/heiytor/invenda/api/pkg/errors"
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/money"
+	"github.com/heiytor/invenda/api/pkg/requests"
+)
+
+type TaxRate interface {
+	ListTaxRate(ctx context.Context, namespaceID string, req *requests.ListTaxRate) (taxRates []models.TaxRate, count int64, err error)
+	GetTaxRate(ctx context.Context, namespaceID string, req *requests.GetTaxRate) (taxRate *models.TaxRate, err error)
+	CreateTaxRate(ctx context.Context, namespaceID string, req *requests.CreateTaxRate) (insertedID string, err error)
+	UpdateTaxRate(ctx context.Context, namespaceID string, req *requests.UpdateTaxRate) (taxRate *models.TaxRate, err error)
+	DeleteTaxRate(ctx context.Context, namespaceID string, req *requests.DeleteTaxRate) (err error)
+
+	// GetTaxReport returns the taxes levied by the namespace's counter sales and invoiced sales orders
+	// within the requested period, converted to the namespace's base currency.
+	GetTaxReport(ctx context.Context, namespaceID string, req *requests.GetTaxReport) (report *models.TaxReport, err error)
+}
+
+func (s *service) ListTaxRate(ctx context.Context, namespaceID string, req *requests.ListTaxRate) ([]models.TaxRate, int64, error) {
+	taxRates, count, err := s.store.TaxRate.GetMany(ctx, namespaceID, &req.Query)
+	return taxRates, count, mapError(err, s.store.TaxRate.Entity())
+}
+
+func (s *service) GetTaxRate(ctx context.Context, namespaceID string, req *requests.GetTaxRate) (*models.TaxRate, error) {
+	txr, err := s.store.TaxRate.Get(ctx, namespaceID, req.ID)
+	return txr, mapError(err, s.store.TaxRate.Entity())
+}
+
+func (s *service) CreateTaxRate(ctx context.Context, namespaceID string, req *requests.CreateTaxRate) (string, error) {
+	if err := models.CheckValidity(req.ValidFrom, req.ValidUntil); err != nil {
+		return "", errors.
+			New().
+			Code(http.StatusBadRequest).
+			Attr("valid_until", []string{err.Error()}).
+			Layer(errors.LayerService).
+			Msg(errors.MsgBadRequest)
+	}
+
+	txr := &models.TaxRate{
+		NamespaceID: namespaceID,
+		Name:        req.Name,
+		Category:    req.Category,
+		Rate:        req.Rate,
+		Compound:    req.Compound,
+		Order:       req.Order,
+		ValidFrom:   req.ValidFrom,
+		ValidUntil:  req.ValidUntil,
+	}
+
+	insertedID, err := s.store.TaxRate.Create(ctx, txr)
+	return insertedID, mapError(err, s.store.TaxRate.Entity())
+}
+
+func (s *service) UpdateTaxRate(ctx context.Context, namespaceID string, req *requests.UpdateTaxRate) (*models.TaxRate, error) {
+	txr, err := s.store.TaxRate.Get(ctx, namespaceID, req.ID)
+	if err != nil {
+		return nil, mapError(err, s.store.TaxRate.Entity())
+	}
+
+	// The validity range is checked as it will be after the update.
+	from, until := txr.ValidFrom, txr.ValidUntil
+	if req.ValidFrom != nil {
+		from = req.ValidFrom
+	}
+
+	if req.ValidUntil != nil {
+		until = req.ValidUntil
+	}
+
+	if err := models.CheckValidity(from, until); err != nil {
+		return nil, errors.
+			New().
+			Code(http.StatusBadRequest).
+			Attr("valid_until", []string{err.Error()}).
+			Layer(errors.LayerService).
+			Msg(errors.MsgBadRequest)
+	}
+
+	changes := &models.TaxRateChanges{
+		Name:       req.Name,
+		Rate:       req.Rate,
+		Compound:   req.Compound,
+		Order:      req.Order,
+		ValidFrom:  req.ValidFrom,
+		ValidUntil: req.ValidUntil,
+	}
+
+	if err := s.store.TaxRate.Update(ctx, namespaceID, req.ID, changes); err != nil {
+		return nil, mapError(err, s.store.TaxRate.Entity())
+	}
+
+	txr, err = s.store.TaxRate.Get(ctx, namespaceID, req.ID)
+	return txr, mapError(err, s.store.TaxRate.Entity())
+}
+
+func (s *service) DeleteTaxRate(ctx context.Context, namespaceID string, req *requests.DeleteTaxRate) error {
+	return mapError(s.store.TaxRate.Delete(ctx, namespaceID, req.ID), s.store.TaxRate.Entity())
+}
+
+func (s *service) GetTaxReport(ctx context.Context, namespaceID string, req *requests.GetTaxReport) (*models.TaxReport, error) {
+	until := req.Until
+	if until.IsZero() {
+		until = clock.Now()
+	}
+
+	from := req.From
+	if from.IsZero() {
+		from = time.Date(until.Year(), until.Month(), 1, 0, 0, 0, 0, until.Location())
+	}
+
+	if !until.After(from) {
+		return nil, errors.
+			New().
+			Code(http.StatusBadRequest).
+			Attr("until", []string{"until must be after from"}).
+			Layer(errors.LayerService).
+			Msg(errors.MsgBadRequest)
+	}
+
+	sales, err := s.store.Sale.Taxes(ctx, namespaceID, from, until)
+	if err != nil {
+		return nil, mapError(err, s.store.Sale.Entity())
+	}
+
+	orders, err := s.store.SalesOrder.Taxes(ctx, namespaceID, from, until)
+	if err != nil {
+		return nil, mapError(err, s.store.SalesOrder.Entity())
+	}
+
+	ns, err := s.store.Namespace.Get(ctx, namespaceID)
+	if err != nil {
+		return nil, mapError(err, s.store.Namespace.Entity())
+	}
+
+	report := &models.TaxReport{
+		From:     from,
+		Until:    until,
+		Currency: ns.Settings.Currency,
+		Taxes:    models.ConvertTaxes(append(sales, orders...), ns.Settings.Currency),
+	}
+
+	report.Tax = money.New(report.Taxes.Amount(), ns.Settings.Currency)
+
+	return report, nil
+}
+
+// taxes returns the namespace's tax mode and the rates effective at the given time, with which the lines
+// of its documents are taxed as in [models.TaxesFor].
+func (s *service) taxes(ctx context.Context, namespaceID string, at time.Time) (*models.Settings, []models.TaxRate, error) {
+	ns, err := s.store.Namespace.Get(ctx, namespaceID)
+	if err != nil {
+		return nil, nil, mapError(err, s.store.Namespace.Entity())
+	}
+
+	rates, err := s.store.TaxRate.GetEffective(ctx, namespaceID, at)
+	if err != nil {
+		return nil, nil, mapError(err, s.store.TaxRate.Entity())
+	}
+
+	return &ns.Settings, rates, nil
+}
@@ -0,0 +1,373 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/heiytor/invenda/api/pkg/clock"
+	"github.com/heiytor/invenda/api/pkg/errors"
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/money"
+	"github.com/heiytor/invenda/api/pkg/requests"
+)
+
+type Transfer interface {
+	ListTransfer(ctx context.Context, namespaceID string, req *requests.ListTransfer) (transfers []models.Transfer, count int64, err error)
+	GetTransfer(ctx context.Context, namespaceID string, req *requests.GetTransfer) (transfer *models.Transfer, err error)
+	CreateTransfer(ctx context.Context, namespaceID, userID string, req *requests.CreateTransfer) (insertedID string, err error)
+	UpdateTransfer(ctx context.Context, namespaceID string, req *requests.UpdateTransfer) (transfer *models.Transfer, err error)
+	DeleteTransfer(ctx context.Context, namespaceID string, req *requests.DeleteTransfer) (err error)
+
+	// DispatchTransfer takes the items out of the source and puts them in transit to the destination.
+	DispatchTransfer(ctx context.Context, namespaceID, userID string, req *requests.DispatchTransfer) (transfer *models.Transfer, err error)
+
+	// ReceiveTransfer moves the received quantities from in transit to the destination's stock.
+	ReceiveTransfer(ctx context.Context, namespaceID, userID string, req *requests.ReceiveTransfer) (transfer *models.Transfer, err error)
+
+	// CloseTransfer ends the transfer, discarding any quantity that is still in transit.
+	CloseTransfer(ctx context.Context, namespaceID string, req *requests.CloseTransfer) (transfer *models.Transfer, err error)
+}
+
+func (s *service) ListTransfer(ctx context.Context, namespaceID string, req *requests.ListTransfer) ([]models.Transfer, int64, error) {
+	transfers, count, err := s.store.Transfer.GetMany(ctx, namespaceID, &req.Query)
+	return transfers, count, mapError(err, s.store.Transfer.Entity())
+}
+
+func (s *service) GetTransfer(ctx context.Context, namespaceID string, req *requests.GetTransfer) (*models.Transfer, error) {
+	tr, err := s.store.Transfer.Get(ctx, namespaceID, req.ID)
+	return tr, mapError(err, s.store.Transfer.Entity())
+}
+
+func (s *service) CreateTransfer(ctx context.Context, namespaceID, userID string, req *requests.CreateTransfer) (string, error) {
+	tr := &models.Transfer{
+		NamespaceID:         namespaceID,
+		Status:              models.TransferDraft,
+		SourceID:            req.SourceID,
+		SourceLocation:      req.SourceLocation,
+		DestinationID:       req.DestinationID,
+		DestinationLocation: req.DestinationLocation,
+		Items:               req.Items,
+		Notes:               req.Notes,
+		CreatedBy:           userID,
+	}
+
+	if err := s.checkTransfer(ctx, namespaceID, tr); err != nil {
+		return "", err
+	}
+
+	insertedID, err := s.store.Transfer.Create(ctx, tr)
+	return insertedID, mapError(err, s.store.Transfer.Entity())
+}
+
+func (s *service) UpdateTransfer(ctx context.Context, namespaceID string, req *requests.UpdateTransfer) (*models.Transfer, error) {
+	tr, err := s.store.Transfer.Get(ctx, namespaceID, req.ID)
+	if err != nil {
+		return nil, mapError(err, s.store.Transfer.Entity())
+	}
+
+	if tr.Status != models.TransferDraft {
+		return nil, illegalTransition(s.store.Transfer.Entity(), tr.Status, "update")
+	}
+
+	if req.SourceLocation != nil {
+		tr.SourceLocation = *req.SourceLocation
+	}
+
+	if req.DestinationLocation != nil {
+		tr.DestinationLocation = *req.DestinationLocation
+	}
+
+	if req.Items != nil {
+		tr.Items = req.Items
+	}
+
+	if err := s.checkTransfer(ctx, namespaceID, tr); err != nil {
+		return nil, err
+	}
+
+	changes := &models.TransferChanges{
+		SourceLocation:      req.SourceLocation,
+		DestinationLocation: req.DestinationLocation,
+		Items:               req.Items,
+		Notes:               req.Notes,
+	}
+
+	if err := s.store.Transfer.Update(ctx, namespaceID, req.ID, []models.TransferStatus{models.TransferDraft}, changes); err != nil {
+		return nil, mapError(err, s.store.Transfer.Entity())
+	}
+
+	tr, err = s.store.Transfer.Get(ctx, namespaceID, req.ID)
+	return tr, mapError(err, s.store.Transfer.Entity())
+}
+
+func (s *service) DeleteTransfer(ctx context.Context, namespaceID string, req *requests.DeleteTransfer) error {
+	err := s.store.WithTransaction(ctx, func(ctx context.Context) error {
+		tr, err := s.store.Transfer.Get(ctx, namespaceID, req.ID)
+		if err != nil {
+			return err
+		}
+
+		if tr.Status != models.TransferDraft {
+			return illegalTransition(s.store.Transfer.Entity(), tr.Status, "delete")
+		}
+
+		return s.store.Transfer.Delete(ctx, namespaceID, req.ID)
+	})
+
+	return mapError(err, s.store.Transfer.Entity())
+}
+
+func (s *service) DispatchTransfer(ctx context.Context, namespaceID, userID string, req *requests.DispatchTransfer) (*models.Transfer, error) {
+	err := s.store.WithTransaction(ctx, func(ctx context.Context) error {
+		tr, err := s.store.Transfer.Get(ctx, namespaceID, req.ID)
+		if err != nil {
+			return err
+		}
+
+		if tr.Status != models.TransferDraft {
+			return illegalTransition(s.store.Transfer.Entity(), tr.Status, "dispatch")
+		}
+
+		movements := make([]*models.Movement, 0, len(tr.Items))
+		for i, item := range tr.Items {
+			mov := &models.Movement{
+				NamespaceID: namespaceID,
+				StockKey:    tr.SourceKey(item),
+				Type:        models.MovementTransferOut,
+				Quantity:    -item.Quantity,
+				Reason:      "transfer dispatched",
+				UserID:      userID,
+				Reference:   tr.ID,
+			}
+
+			// Expired lots can be transferred, e.g. to be disposed of elsewhere. The lots are kept in the
+			// item so they can enter the destination when received.
+			lotted, err := s.lotMovements(ctx, namespaceID, mov, "", nil, true)
+			if err != nil {
+				return err
+			}
+
+			if err := s.serialMovements(ctx, namespaceID, lotted, item.Serials); err != nil {
+				return err
+			}
+
+			for _, m := range lotted {
+				if m.Lot != "" {
+					tr.Items[i].Lots = append(tr.Items[i].Lots, models.LotAllocation{Lot: m.Lot, ExpiresAt: m.ExpiresAt, Quantity: -m.Quantity})
+				}
+			}
+
+			movements = append(movements, lotted...)
+
+			if err := s.store.Stock.Transit(ctx, namespaceID, tr.DestinationKey(item), item.Quantity); err != nil {
+				return err
+			}
+		}
+
+		if err := s.post(ctx, namespaceID, movements...); err != nil {
+			return err
+		}
+
+		// Posted movements are valued in the base currency.
+		base, err := s.baseCurrency(ctx, namespaceID)
+		if err != nil {
+			return err
+		}
+
+		for i, item := range tr.Items {
+			var value int64
+			for _, m := range movements {
+				if m.ProductID == item.ProductID && m.VariantID == item.VariantID {
+					value -= m.Value.Amount
+				}
+			}
+
+			tr.Items[i].Value = money.New(value, base)
+			tr.Items[i].UnitCost = money.New(value/item.Quantity, base)
+		}
+
+		now := clock.Now()
+		changes := &models.TransferChanges{Status: models.TransferDispatched, Items: tr.Items, DispatchedAt: &now}
+
+		return s.store.Transfer.Update(ctx, namespaceID, tr.ID, []models.TransferStatus{models.TransferDraft}, changes)
+	})
+	if err != nil {
+		return nil, mapError(err, s.store.Transfer.Entity())
+	}
+
+	tr, err := s.store.Transfer.Get(ctx, namespaceID, req.ID)
+	return tr, mapError(err, s.store.Transfer.Entity())
+}
+
+func (s *service) ReceiveTransfer(ctx context.Context, namespaceID, userID string, req *requests.ReceiveTransfer) (*models.Transfer, error) {
+	receivable := []models.TransferStatus{models.TransferDispatched, models.TransferPartiallyReceived}
+
+	err := s.store.WithTransaction(ctx, func(ctx context.Context) error {
+		tr, err := s.store.Transfer.Get(ctx, namespaceID, req.ID)
+		if err != nil {
+			return err
+		}
+
+		if tr.Status != models.TransferDispatched && tr.Status != models.TransferPartiallyReceived {
+			return illegalTransition(s.store.Transfer.Entity(), tr.Status, "receive")
+		}
+
+		received, err := tr.Receive(req.Items)
+		if err != nil {
+			return errors.
+				New().
+				Code(http.StatusBadRequest).
+				Attr("items", []string{err.Error()}).
+				Layer(errors.LayerService).
+				Msg(errors.MsgBadRequest)
+		}
+
+		movements := make([]*models.Movement, 0, len(received))
+		for _, item := range received {
+			idx := slices.IndexFunc(tr.Items, func(i models.TransferItem) bool {
+				return i.ProductID == item.ProductID && i.VariantID == item.VariantID
+			})
+
+			mov := models.Movement{
+				NamespaceID: namespaceID,
+				StockKey:    tr.DestinationKey(item),
+				Type:        models.MovementTransferIn,
+				Quantity:    item.Quantity,
+				Reason:      "transfer received",
+				UserID:      userID,
+				Reference:   tr.ID,
+			}
+
+			// Receive has already added the quantity to the item, so the lots and the value are split from
+			// where the previous receipts stopped.
+			received := tr.Items[idx].Received - item.Quantity
+			lots := tr.Items[idx].LotsOf(received, item.Quantity)
+
+			lotted := make([]*models.Movement, 0, len(lots))
+			if lots == nil {
+				lotted = append(lotted, &mov)
+			}
+
+			for _, l := range lots {
+				m := mov
+				m.Quantity = l.Quantity
+				m.Lot = l.Lot
+				m.ExpiresAt = l.ExpiresAt
+
+				lotted = append(lotted, &m)
+			}
+
+			models.AssignSerials(lotted, item.Serials)
+			models.AllocateValue(lotted, tr.Items[idx].ReceiptValue(received, item.Quantity))
+			movements = append(movements, lotted...)
+
+			if err := s.store.Stock.Transit(ctx, namespaceID, tr.DestinationKey(item), -item.Quantity); err != nil {
+				return err
+			}
+		}
+
+		if err := s.post(ctx, namespaceID, movements...); err != nil {
+			return err
+		}
+
+		changes := &models.TransferChanges{Status: tr.Status, Items: tr.Items}
+		if tr.Status == models.TransferReceived {
+			now := clock.Now()
+			changes.ReceivedAt = &now
+		}
+
+		return s.store.Transfer.Update(ctx, namespaceID, tr.ID, receivable, changes)
+	})
+	if err != nil {
+		return nil, mapError(err, s.store.Transfer.Entity())
+	}
+
+	tr, err := s.store.Transfer.Get(ctx, namespaceID, req.ID)
+	return tr, mapError(err, s.store.Transfer.Entity())
+}
+
+func (s *service) CloseTransfer(ctx context.Context, namespaceID string, req *requests.CloseTransfer) (*models.Transfer, error) {
+	closable := []models.TransferStatus{models.TransferReceived, models.TransferPartiallyReceived}
+
+	err := s.store.WithTransaction(ctx, func(ctx context.Context) error {
+		tr, err := s.store.Transfer.Get(ctx, namespaceID, req.ID)
+		if err != nil {
+			return err
+		}
+
+		if tr.Status != models.TransferReceived && tr.Status != models.TransferPartiallyReceived {
+			return illegalTransition(s.store.Transfer.Entity(), tr.Status, "close")
+		}
+
+		// The goods that never arrived leave the in-transit balance without entering the destination.
+		for _, item := range tr.Items {
+			if item.Remaining() > 0 {
+				if err := s.store.Stock.Transit(ctx, namespaceID, tr.DestinationKey(item), -item.Remaining()); err != nil {
+					return err
+				}
+			}
+		}
+
+		now := clock.Now()
+		changes := &models.TransferChanges{Status: models.TransferClosed, ClosedAt: &now}
+
+		return s.store.Transfer.Update(ctx, namespaceID, tr.ID, closable, changes)
+	})
+	if err != nil {
+		return nil, mapError(err, s.store.Transfer.Entity())
+	}
+
+	tr, err := s.store.Transfer.Get(ctx, namespaceID, req.ID)
+	return tr, mapError(err, s.store.Transfer.Entity())
+}
+
+// checkTransfer reports whether the transfer moves existent items between two distinct warehouses.
+func (s *service) checkTransfer(ctx context.Context, namespaceID string, tr *models.Transfer) error {
+	if err := models.CheckTransferItems(tr.Items); err != nil {
+		return errors.
+			New().
+			Code(http.StatusBadRequest).
+			Attr("items", []string{err.Error()}).
+			Layer(errors.LayerService).
+			Msg(errors.MsgBadRequest)
+	}
+
+	if tr.SourceID == tr.DestinationID && tr.SourceLocation == tr.DestinationLocation {
+		return errors.
+			New().
+			Code(http.StatusBadRequest).
+			Attr("destination_id", []string{"destination must differ from the source"}).
+			Layer(errors.LayerService).
+			Msg(errors.MsgBadRequest)
+	}
+
+	for i, item := range tr.Items {
+		// Lots and costs are only assigned when the transfer is dispatched and serials when it is received.
+		tr.Items[i].Lots = nil
+		tr.Items[i].Value = money.Money{}
+		tr.Items[i].UnitCost = money.Money{}
+		tr.Items[i].ReceivedSerials = nil
+
+		prd, err := s.checkStockKey(ctx, namespaceID, tr.SourceKey(item))
+		if err != nil {
+			return err
+		}
+
+		if _, err := s.checkStockKey(ctx, namespaceID, tr.DestinationKey(item)); err != nil {
+			return err
+		}
+
+		if prd.Serialized != (len(item.Serials) > 0) {
+			return errors.
+				New().
+				Code(http.StatusBadRequest).
+				Attr("items", []string{fmt.Sprintf("item %q must have serials if and only if its product is serialized", item.ProductID)}).
+				Layer(errors.LayerService).
+				Msg(errors.MsgBadRequest)
+		}
+	}
+
+	return nil
+}
@@ -71,6 +71,11 @@ func (s *service) UpdateUser(ctx context.Context, id string, req *requests.Updat
 		return nil, mapError(err, s.store.User.Entity())
 	}
 
+	// Members keep a copy of the user's profile so namespaces can be searched by them.
+	if err := s.store.Namespace.UpdateMemberProfile(ctx, id, changes.Name, changes.Email); err != nil {
+		return nil, mapError(err, s.store.Namespace.Entity())
+	}
+
 	usr, err := s.store.User.GetByID(ctx, id, store.RemoveUserPassword)
 	return usr, mapError(err, s.store.User.Entity())
 }
@@ -1,6 +1,8 @@
 package service
 
 import (
+	"net/http"
+
 	"github.com/heiytor/invenda/api/pkg/errors"
 	"github.com/heiytor/invenda/api/store"
 	"github.com/rs/zerolog/log"
@@ -13,6 +15,11 @@ func mapError(in error, entity string) error {
 		return nil
 	}
 
+	// Errors built by the service itself, e.g. within a transaction, are already mapped.
+	if e := errors.As(in); e != nil {
+		return e
+	}
+
 	out := errors.New().Layer(errors.LayerService)
 
 	if entity != "" {
@@ -22,6 +29,10 @@ func mapError(in error, entity string) error {
 	switch {
 	case errors.Is(in, store.ErrNotFound):
 		return out.Code(404).Msg(errors.MsgNotFound)
+	case errors.Is(in, store.ErrDuplicated):
+		return out.Code(409).Msg(errors.MsgConflict)
+	case errors.Is(in, store.ErrInsufficientStock):
+		return out.Code(409).Msg(errors.MsgInsufficientStock)
 	default:
 		// default branch handle non-expected mongo errors.
 		// TODO: send to sentry
@@ -30,3 +41,16 @@ func mapError(in error, entity string) error {
 		return out.Layer(errors.LayerStore).Code(500).Msg("internal server error")
 	}
 }
+
+// illegalTransition returns the error of an action that cannot be performed on an entity in the
+// specified status.
+func illegalTransition(entity string, status interface{}, action string) error {
+	return errors.
+		New().
+		Code(http.StatusConflict).
+		Layer(errors.LayerService).
+		Attr("entity", entity).
+		Attr("status", status).
+		Attr("action", action).
+		Msg(errors.MsgIllegalTransition)
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+
+	"github.com/heiytor/invenda/api/pkg/clock"
+	"github.com/heiytor/invenda/api/pkg/errors"
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/money"
+	"github.com/heiytor/invenda/api/pkg/requests"
+	"github.com/heiytor/invenda/api/store"
+)
+
+type Valuation interface {
+	// GetValuation returns the worth of the namespace's stock as of the requested date, which defaults
+	// to now.
+	GetValuation(ctx context.Context, namespaceID string, req *requests.GetValuation) (valuation *models.Valuation, err error)
+}
+
+func (s *service) GetValuation(ctx context.Context, namespaceID string, req *requests.GetValuation) (*models.Valuation, error) {
+	ns, err := s.store.Namespace.Get(ctx, namespaceID)
+	if err != nil {
+		return nil, mapError(err, s.store.Namespace.Entity())
+	}
+
+	asOf := req.AsOf
+	if asOf.IsZero() {
+		asOf = clock.Now()
+	}
+
+	lines, err := s.store.Stock.Valuation(ctx, namespaceID, asOf, req.WarehouseID)
+	if err != nil {
+		return nil, mapError(err, s.store.Stock.Entity())
+	}
+
+	var value int64
+	for _, l := range lines {
+		value += l.Value.Amount
+	}
+
+	valuation := &models.Valuation{AsOf: asOf, Method: ns.Settings.Valuation(), Value: money.New(value, ns.Settings.Currency), Lines: lines}
+
+	return valuation, nil
+}
+
+// value sets the unit cost and value of a movement, in the base currency, and applies it to the cost pool
+// and layers of its product. Inbound movements that carry their value keep it whole, even when zero, those
+// without a unit cost are valued at the product's cost for receipts and at the current average cost
+// otherwise, while outbound movements are valued by method. It must be called within a transaction.
+func (s *service) value(ctx context.Context, method models.ValuationMethod, base money.Currency, m *models.Movement) error {
+	prd, err := s.store.Product.Get(ctx, m.NamespaceID, m.ProductID)
+	if err != nil {
+		return err
+	}
+
+	pool, err := s.store.Cost.Pool(ctx, m.NamespaceID, m.ProductID, m.VariantID)
+	switch {
+	case errors.Is(err, store.ErrNotFound):
+		pool = &models.CostPool{}
+	case err != nil:
+		return err
+	}
+
+	cost := money.New(prd.Cost.Amount, base)
+
+	if m.Quantity >= 0 {
+		var unitCost, value int64
+		switch {
+		case m.Value != nil && m.Quantity > 0:
+			value = m.Value.Amount
+			unitCost = value / m.Quantity
+		case m.UnitCost == nil:
+			unitCost = cost.Amount
+			if m.Type != models.MovementReceipt {
+				unitCost = pool.Cost(1, cost).Amount
+			}
+
+			value = m.Quantity * unitCost
+		default:
+			unitCost = m.UnitCost.Amount
+			value = m.Quantity * unitCost
+		}
+
+		m.UnitCost = &money.Money{Amount: unitCost, Currency: base}
+		m.Value = &money.Money{Amount: value, Currency: base}
+
+		// The units the unit cost leaves short of the value are layered one minor unit dearer, so the
+		// layers add up to the value.
+		extra := value - m.Quantity*unitCost
+
+		layer := &models.CostLayer{
+			NamespaceID: m.NamespaceID,
+			ProductID:   m.ProductID,
+			VariantID:   m.VariantID,
+			Quantity:    m.Quantity - extra,
+			UnitCost:    *m.UnitCost,
+		}
+
+		if err := s.store.Cost.AddLayer(ctx, layer); err != nil {
+			return err
+		}
+
+		if extra > 0 {
+			layer := &models.CostLayer{
+				NamespaceID: m.NamespaceID,
+				ProductID:   m.ProductID,
+				VariantID:   m.VariantID,
+				Quantity:    extra,
+				UnitCost:    money.New(unitCost+1, base),
+			}
+
+			if err := s.store.Cost.AddLayer(ctx, layer); err != nil {
+				return err
+			}
+		}
+
+		return s.store.Cost.ApplyPool(ctx, m.NamespaceID, m.ProductID, m.VariantID, m.Quantity, *m.Value)
+	}
+
+	layers, err := s.store.Cost.Layers(ctx, m.NamespaceID, m.ProductID, m.VariantID)
+	if err != nil {
+		return err
+	}
+
+	// The layers are consumed with either method, so they stay in step with the stock if the namespace
+	// switches to FIFO.
+	quantity := -m.Quantity
+	consumed, value := models.ConsumeLayers(layers, quantity, pool.Cost(1, cost))
+	for _, c := range consumed {
+		if err := s.store.Cost.ConsumeLayer(ctx, m.NamespaceID, c.LayerID, c.Quantity); err != nil {
+			return err
+		}
+	}
+
+	if method == models.ValuationAverage {
+		value = pool.Cost(quantity, cost)
+	}
+
+	m.UnitCost = &money.Money{Amount: value.Amount / quantity, Currency: base}
+	m.Value = &money.Money{Amount: -value.Amount, Currency: base}
+	if m.Type == models.MovementIssue {
+		m.COGS = &value
+	}
+
+	return s.store.Cost.ApplyPool(ctx, m.NamespaceID, m.ProductID, m.VariantID, m.Quantity, *m.Value)
+}
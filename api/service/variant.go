@@ -0,0 +1,208 @@
+package service
+
+import (
+	"context"
+	"maps"
+	"net/http"
+	"slices"
+
+	"github.com/heiytor/invenda/api/pkg/errors"
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/query"
+	"github.com/heiytor/invenda/api/pkg/requests"
+)
+
+type Variant interface {
+	ListVariant(ctx context.Context, namespaceID string, req *requests.ListVariant) (variants []models.Variant, count int64, err error)
+	GetVariant(ctx context.Context, namespaceID string, req *requests.GetVariant) (variant *models.Variant, err error)
+	CreateVariant(ctx context.Context, namespaceID string, req *requests.CreateVariant) (insertedID string, err error)
+	GenerateVariants(ctx context.Context, namespaceID string, req *requests.GenerateVariants) (variants []models.Variant, err error)
+	UpdateVariant(ctx context.Context, namespaceID string, req *requests.UpdateVariant) (variant *models.Variant, err error)
+	DeleteVariant(ctx context.Context, namespaceID string, req *requests.DeleteVariant) (err error)
+}
+
+func (s *service) ListVariant(ctx context.Context, namespaceID string, req *requests.ListVariant) ([]models.Variant, int64, error) {
+	if _, err := s.store.Product.Get(ctx, namespaceID, req.ProductID); err != nil {
+		return nil, 0, mapError(err, s.store.Product.Entity())
+	}
+
+	variants, count, err := s.store.Variant.GetMany(ctx, namespaceID, req.ProductID, &req.Query)
+	return variants, count, mapError(err, s.store.Variant.Entity())
+}
+
+func (s *service) GetVariant(ctx context.Context, namespaceID string, req *requests.GetVariant) (*models.Variant, error) {
+	vrt, err := s.store.Variant.Get(ctx, namespaceID, req.ProductID, req.ID)
+	return vrt, mapError(err, s.store.Variant.Entity())
+}
+
+func (s *service) CreateVariant(ctx context.Context, namespaceID string, req *requests.CreateVariant) (string, error) {
+	prd, err := s.store.Product.Get(ctx, namespaceID, req.ProductID)
+	if err != nil {
+		return "", mapError(err, s.store.Product.Entity())
+	}
+
+	if err := prd.CheckAttributes(req.Attributes); err != nil {
+		return "", errors.
+			New().
+			Code(http.StatusBadRequest).
+			Attr("attributes", []string{err.Error()}).
+			Layer(errors.LayerService).
+			Msg(errors.MsgBadRequest)
+	}
+
+	base, err := s.baseCurrency(ctx, namespaceID)
+	if err != nil {
+		return "", err
+	}
+
+	if err := inCurrency(base, "price", req.Price); err != nil {
+		return "", err
+	}
+
+	vrt := &models.Variant{
+		NamespaceID: namespaceID,
+		ProductID:   prd.ID,
+		SKU:         req.SKU,
+		Barcode:     req.Barcode,
+		Attributes:  req.Attributes,
+		Price:       req.Price,
+	}
+
+	if vrt.SKU == "" {
+		vrt.SKU = prd.VariantSKU(req.Attributes)
+	}
+
+	if err := s.variantConflicts(ctx, namespaceID, &models.Variant{SKU: vrt.SKU, Barcode: vrt.Barcode}); err != nil {
+		return "", err
+	}
+
+	insertedIDs, err := s.store.Variant.Create(ctx, vrt)
+	if err != nil {
+		return "", mapError(err, s.store.Variant.Entity())
+	}
+
+	return insertedIDs[0], nil
+}
+
+func (s *service) GenerateVariants(ctx context.Context, namespaceID string, req *requests.GenerateVariants) ([]models.Variant, error) {
+	prd, err := s.store.Product.Get(ctx, namespaceID, req.ProductID)
+	if err != nil {
+		return nil, mapError(err, s.store.Product.Entity())
+	}
+
+	existing, _, err := s.store.Variant.GetMany(ctx, namespaceID, prd.ID, &query.Query{Sorter: query.Sorter{By: "created_at", Order: query.OrderAsc}})
+	if err != nil {
+		return nil, mapError(err, s.store.Variant.Entity())
+	}
+
+	variants := make([]*models.Variant, 0)
+	for _, attrs := range prd.Combinations() {
+		if slices.ContainsFunc(existing, func(v models.Variant) bool { return maps.Equal(v.Attributes, attrs) }) {
+			continue
+		}
+
+		vrt := &models.Variant{
+			NamespaceID: namespaceID,
+			ProductID:   prd.ID,
+			SKU:         prd.VariantSKU(attrs),
+			Attributes:  attrs,
+		}
+
+		if err := s.variantConflicts(ctx, namespaceID, &models.Variant{SKU: vrt.SKU}); err != nil {
+			return nil, err
+		}
+
+		variants = append(variants, vrt)
+	}
+
+	if _, err := s.store.Variant.Create(ctx, variants...); err != nil {
+		return nil, mapError(err, s.store.Variant.Entity())
+	}
+
+	generated := make([]models.Variant, 0, len(variants))
+	for _, v := range variants {
+		generated = append(generated, *v)
+	}
+
+	return generated, nil
+}
+
+func (s *service) UpdateVariant(ctx context.Context, namespaceID string, req *requests.UpdateVariant) (*models.Variant, error) {
+	vrt, err := s.store.Variant.Get(ctx, namespaceID, req.ProductID, req.ID)
+	if err != nil {
+		return nil, mapError(err, s.store.Variant.Entity())
+	}
+
+	base, err := s.baseCurrency(ctx, namespaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := inCurrency(base, "price", req.Price); err != nil {
+		return nil, err
+	}
+
+	// Only the attributes being modified can conflict with other variants.
+	target := new(models.Variant)
+	if req.SKU != "" && req.SKU != vrt.SKU {
+		target.SKU = req.SKU
+	}
+
+	if req.Barcode != nil && *req.Barcode != vrt.Barcode {
+		target.Barcode = *req.Barcode
+	}
+
+	if err := s.variantConflicts(ctx, namespaceID, target); err != nil {
+		return nil, err
+	}
+
+	changes := &models.VariantChanges{
+		SKU:     req.SKU,
+		Barcode: req.Barcode,
+		Price:   req.Price,
+	}
+
+	if err := s.store.Variant.Update(ctx, namespaceID, req.ProductID, req.ID, changes); err != nil {
+		return nil, mapError(err, s.store.Variant.Entity())
+	}
+
+	vrt, err = s.store.Variant.Get(ctx, namespaceID, req.ProductID, req.ID)
+	return vrt, mapError(err, s.store.Variant.Entity())
+}
+
+func (s *service) DeleteVariant(ctx context.Context, namespaceID string, req *requests.DeleteVariant) error {
+	return mapError(s.store.Variant.Delete(ctx, namespaceID, req.ProductID, req.ID), s.store.Variant.Entity())
+}
+
+// variantConflicts returns a conflict error when the target's SKU or barcode is already used by
+// another variant or product of the namespace. Zero attributes are ignored.
+func (s *service) variantConflicts(ctx context.Context, namespaceID string, target *models.Variant) error {
+	if target.SKU == "" && target.Barcode == "" {
+		return nil
+	}
+
+	conflicts, err := s.store.Variant.Conflicts(ctx, namespaceID, target)
+	if err != nil {
+		return mapError(err, s.store.Variant.Entity())
+	}
+
+	products, err := s.store.Product.Conflicts(ctx, namespaceID, &models.Product{SKU: target.SKU, Barcode: target.Barcode})
+	if err != nil {
+		return mapError(err, s.store.Product.Entity())
+	}
+
+	conflicts = append(conflicts, products...)
+
+	if len(conflicts) > 0 {
+		return errors.
+			New().
+			Code(http.StatusConflict).
+			Attr("entity", s.store.Variant.Entity()).
+			Attr("conflicts", conflicts).
+			Attr("sku", target.SKU).
+			Layer(errors.LayerService).
+			Msg(errors.MsgConflict)
+	}
+
+	return nil
+}
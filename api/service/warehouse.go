@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/heiytor/invenda/api/pkg/errors"
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/query"
+	"github.com/heiytor/invenda/api/pkg/requests"
+)
+
+type Warehouse interface {
+	ListWarehouse(ctx context.Context, namespaceID string, req *requests.ListWarehouse) (warehouses []models.Warehouse, count int64, err error)
+	GetWarehouse(ctx context.Context, namespaceID string, req *requests.GetWarehouse) (warehouse *models.Warehouse, err error)
+	CreateWarehouse(ctx context.Context, namespaceID string, req *requests.CreateWarehouse) (insertedID string, err error)
+	UpdateWarehouse(ctx context.Context, namespaceID string, req *requests.UpdateWarehouse) (warehouse *models.Warehouse, err error)
+	DeleteWarehouse(ctx context.Context, namespaceID string, req *requests.DeleteWarehouse) (err error)
+}
+
+func (s *service) ListWarehouse(ctx context.Context, namespaceID string, req *requests.ListWarehouse) ([]models.Warehouse, int64, error) {
+	warehouses, count, err := s.store.Warehouse.GetMany(ctx, namespaceID, &req.Query)
+	return warehouses, count, mapError(err, s.store.Warehouse.Entity())
+}
+
+func (s *service) GetWarehouse(ctx context.Context, namespaceID string, req *requests.GetWarehouse) (*models.Warehouse, error) {
+	wh, err := s.store.Warehouse.Get(ctx, namespaceID, req.ID)
+	return wh, mapError(err, s.store.Warehouse.Entity())
+}
+
+func (s *service) CreateWarehouse(ctx context.Context, namespaceID string, req *requests.CreateWarehouse) (string, error) {
+	if err := models.CheckLocations(req.Locations); err != nil {
+		return "", errors.
+			New().
+			Code(http.StatusBadRequest).
+			Attr("locations", []string{err.Error()}).
+			Layer(errors.LayerService).
+			Msg(errors.MsgBadRequest)
+	}
+
+	conflicts, err := s.store.Warehouse.Conflicts(ctx, namespaceID, &models.Warehouse{Code: req.Code})
+	if err != nil {
+		return "", mapError(err, s.store.Warehouse.Entity())
+	}
+
+	if len(conflicts) > 0 {
+		return "", errors.
+			New().
+			Code(http.StatusConflict).
+			Attr("entity", s.store.Warehouse.Entity()).
+			Attr("conflicts", conflicts).
+			Layer(errors.LayerService).
+			Msg(errors.MsgConflict)
+	}
+
+	wh := &models.Warehouse{
+		NamespaceID: namespaceID,
+		Code:        req.Code,
+		Name:        req.Name,
+		Address:     req.Address,
+		Active:      req.Active == nil || *req.Active,
+		Locations:   req.Locations,
+		Quarantine:  req.Quarantine,
+	}
+
+	if !wh.HasLocation(wh.Quarantine) {
+		return "", errors.
+			New().
+			Code(http.StatusBadRequest).
+			Attr("quarantine", []string{"quarantine must be one of the warehouse's locations"}).
+			Layer(errors.LayerService).
+			Msg(errors.MsgBadRequest)
+	}
+
+	insertedID, err := s.store.Warehouse.Create(ctx, wh)
+	return insertedID, mapError(err, s.store.Warehouse.Entity())
+}
+
+func (s *service) UpdateWarehouse(ctx context.Context, namespaceID string, req *requests.UpdateWarehouse) (*models.Warehouse, error) {
+	if err := models.CheckLocations(req.Locations); err != nil {
+		return nil, errors.
+			New().
+			Code(http.StatusBadRequest).
+			Attr("locations", []string{err.Error()}).
+			Layer(errors.LayerService).
+			Msg(errors.MsgBadRequest)
+	}
+
+	wh, err := s.store.Warehouse.Get(ctx, namespaceID, req.ID)
+	if err != nil {
+		return nil, mapError(err, s.store.Warehouse.Entity())
+	}
+
+	if req.Code != "" && req.Code != wh.Code {
+		conflicts, err := s.store.Warehouse.Conflicts(ctx, namespaceID, &models.Warehouse{Code: req.Code})
+		if err != nil {
+			return nil, mapError(err, s.store.Warehouse.Entity())
+		}
+
+		if len(conflicts) > 0 {
+			return nil, errors.
+				New().
+				Code(http.StatusConflict).
+				Attr("entity", s.store.Warehouse.Entity()).
+				Attr("conflicts", conflicts).
+				Layer(errors.LayerService).
+				Msg(errors.MsgConflict)
+		}
+	}
+
+	// The quarantine must remain one of the locations, whichever of them changes.
+	if req.Locations != nil {
+		wh.Locations = req.Locations
+	}
+
+	if req.Quarantine != nil {
+		wh.Quarantine = *req.Quarantine
+	}
+
+	if !wh.HasLocation(wh.Quarantine) {
+		return nil, errors.
+			New().
+			Code(http.StatusBadRequest).
+			Attr("quarantine", []string{"quarantine must be one of the warehouse's locations"}).
+			Layer(errors.LayerService).
+			Msg(errors.MsgBadRequest)
+	}
+
+	changes := &models.WarehouseChanges{
+		Code:       req.Code,
+		Name:       req.Name,
+		Address:    req.Address,
+		Active:     req.Active,
+		Locations:  req.Locations,
+		Quarantine: req.Quarantine,
+	}
+
+	if err := s.store.Warehouse.Update(ctx, namespaceID, req.ID, changes); err != nil {
+		return nil, mapError(err, s.store.Warehouse.Entity())
+	}
+
+	wh, err = s.store.Warehouse.Get(ctx, namespaceID, req.ID)
+	return wh, mapError(err, s.store.Warehouse.Entity())
+}
+
+func (s *service) DeleteWarehouse(ctx context.Context, namespaceID string, req *requests.DeleteWarehouse) error {
+	// The ledger keeps referencing the warehouse, so only warehouses without stock can be deleted.
+	balances, _, err := s.store.Stock.Balances(ctx, namespaceID, &query.Query{
+		Paginator: query.Paginator{Page: 1, Size: 1},
+		Filter: query.Filter{Conditions: []query.Condition{
+			{Field: "warehouse_id", Operator: query.OperatorEq, Value: req.ID},
+			{Field: "on_hand", Operator: query.OperatorNe, Value: 0},
+		}},
+	})
+	if err != nil {
+		return mapError(err, s.store.Stock.Entity())
+	}
+
+	if len(balances) > 0 {
+		return errors.
+			New().
+			Code(http.StatusConflict).
+			Layer(errors.LayerService).
+			Attr("entity", s.store.Warehouse.Entity()).
+			Msg("a warehouse with stock cannot be deleted")
+	}
+
+	return mapError(s.store.Warehouse.Delete(ctx, namespaceID, req.ID), s.store.Warehouse.Entity())
+}
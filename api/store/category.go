@@ -0,0 +1,225 @@
+package store
+
+import (
+	"context"
+	"slices"
+
+	"github.com/heiytor/invenda/api/pkg/clock"
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/query"
+	"github.com/heiytor/invenda/api/store/internal"
+	"github.com/oklog/ulid/v2"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Category handles the namespace's category tree. The tree is stored as a materialized path, where each
+// category holds the IDs of all of its ancestors. Every operation is scoped to a namespace ID.
+type Category interface {
+	Entity
+
+	// Get retrieves a category with the specified ID. It returns the category or an error if any.
+	Get(ctx context.Context, namespaceID, id string) (category *models.Category, err error)
+
+	// GetMany retrieves a list of categories of a namespace. It returns the list of categories, the total count
+	// of the existent documents and an error if any.
+	GetMany(ctx context.Context, namespaceID string, query *query.Query) (categories []models.Category, count int64, err error)
+
+	// Descendants retrieves every category in the subtree of the category with the specified ID, excluding
+	// itself. It returns the list of categories or an error if any.
+	Descendants(ctx context.Context, namespaceID, id string) (categories []models.Category, err error)
+
+	// Create creates a new category with the provided data. It returns the inserted ID or an error
+	// if any.
+	Create(ctx context.Context, category *models.Category) (insertedID string, err error)
+
+	// Update updates a category with the specified changes and ID. It returns [ErrNotFound] if no category is found.
+	Update(ctx context.Context, namespaceID, id string, changes *models.CategoryChanges) (err error)
+
+	// Move moves the category with the specified ID, along with its subtree, under parent. A nil parent
+	// moves the category to the root. Cycles are not checked, and the operation should be executed within
+	// a transaction as it updates all the descendants. The parent is written too, so concurrent moves that
+	// read it conflict. It returns [ErrNotFound] if the category or the parent is not found.
+	Move(ctx context.Context, namespaceID, id string, parent *models.Category) (err error)
+
+	// Delete deletes a category with the specified ID. It returns [ErrNotFound] if no category is found.
+	Delete(ctx context.Context, namespaceID, id string) (err error)
+}
+
+type category struct {
+	c *mongo.Collection // c is the "category" collection
+}
+
+var _ Category = (*category)(nil)
+
+func (*category) Entity() string {
+	return "category"
+}
+
+func (c *category) Get(ctx context.Context, namespaceID, id string) (*models.Category, error) {
+	cat := new(models.Category)
+	if err := c.c.FindOne(ctx, bson.M{"_id": id, "namespace_id": namespaceID}).Decode(cat); err != nil {
+		return nil, mapError(err)
+	}
+
+	return cat, nil
+}
+
+func (c *category) GetMany(ctx context.Context, namespaceID string, query *query.Query) ([]models.Category, int64, error) {
+	match := bson.M{
+		"$and": []bson.M{
+			{"namespace_id": namespaceID},
+			internal.FromFilter(&query.Filter),
+		},
+	}
+
+	count, err := c.c.CountDocuments(ctx, match)
+	if err != nil {
+		log.Error().Err(err).Msg("unable to count the total documents")
+	}
+
+	pipeline := make([]bson.M, 0)
+	pipeline = append(pipeline, bson.M{"$match": match})
+	pipeline = append(pipeline, internal.FromSorter(&query.Sorter)...)
+	pipeline = append(pipeline, internal.FromPaginator(&query.Paginator)...)
+
+	cursor, err := c.c.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, 0, mapError(err)
+	}
+	defer cursor.Close(ctx)
+
+	categories := make([]models.Category, 0)
+	if err := cursor.All(ctx, &categories); err != nil {
+		return nil, 0, mapError(err)
+	}
+
+	return categories, count, nil
+}
+
+func (c *category) Descendants(ctx context.Context, namespaceID, id string) ([]models.Category, error) {
+	cursor, err := c.c.Find(ctx, bson.M{"namespace_id": namespaceID, "ancestors": id})
+	if err != nil {
+		return nil, mapError(err)
+	}
+	defer cursor.Close(ctx)
+
+	categories := make([]models.Category, 0)
+	if err := cursor.All(ctx, &categories); err != nil {
+		return nil, mapError(err)
+	}
+
+	return categories, nil
+}
+
+func (c *category) Create(ctx context.Context, cat *models.Category) (string, error) {
+	cat.ID = "cat_" + ulid.Make().String()
+
+	now := clock.Now()
+	cat.CreatedAt = now
+	cat.UpdatedAt = now
+
+	if cat.Ancestors == nil {
+		cat.Ancestors = []string{}
+	}
+
+	if _, err := c.c.InsertOne(ctx, cat); err != nil {
+		return "", mapError(err)
+	}
+
+	return cat.ID, nil
+}
+
+func (c *category) Update(ctx context.Context, namespaceID, id string, changes *models.CategoryChanges) error {
+	if changes == nil {
+		return nil
+	}
+
+	changes.UpdatedAt = clock.Now()
+
+	res, err := c.c.UpdateOne(ctx, bson.M{"_id": id, "namespace_id": namespaceID}, bson.M{"$set": changes})
+	if err != nil {
+		return mapError(err)
+	}
+
+	if res.MatchedCount < 1 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (c *category) Move(ctx context.Context, namespaceID, id string, parent *models.Category) error {
+	parentID, ancestors := "", []string{}
+	if parent != nil {
+		parentID, ancestors = parent.ID, parent.Path()
+	}
+
+	now := clock.Now()
+
+	if parent != nil {
+		res, err := c.c.UpdateOne(ctx, bson.M{"_id": parent.ID, "namespace_id": namespaceID}, bson.M{"$set": bson.M{"updated_at": now}})
+		if err != nil {
+			return mapError(err)
+		}
+
+		if res.MatchedCount < 1 {
+			return ErrNotFound
+		}
+	}
+
+	res, err := c.c.UpdateOne(
+		ctx,
+		bson.M{"_id": id, "namespace_id": namespaceID},
+		bson.M{"$set": bson.M{"parent_id": parentID, "ancestors": ancestors, "updated_at": now}},
+	)
+	if err != nil {
+		return mapError(err)
+	}
+
+	if res.MatchedCount < 1 {
+		return ErrNotFound
+	}
+
+	// Replaces the path prefix up to the moved category of each descendant with the new path.
+	prefix := append(slices.Clone(ancestors), id)
+	update := []bson.M{
+		{
+			"$set": bson.M{
+				"ancestors": bson.M{
+					"$concatArrays": []interface{}{
+						prefix,
+						bson.M{
+							"$slice": []interface{}{
+								"$ancestors",
+								bson.M{"$add": []interface{}{bson.M{"$indexOfArray": []interface{}{"$ancestors", id}}, 1}},
+								bson.M{"$max": []interface{}{bson.M{"$size": "$ancestors"}, 1}},
+							},
+						},
+					},
+				},
+				"updated_at": now,
+			},
+		},
+	}
+
+	if _, err := c.c.UpdateMany(ctx, bson.M{"namespace_id": namespaceID, "ancestors": id}, update); err != nil {
+		return mapError(err)
+	}
+
+	return nil
+}
+
+func (c *category) Delete(ctx context.Context, namespaceID, id string) error {
+	res, err := c.c.DeleteOne(ctx, bson.M{"_id": id, "namespace_id": namespaceID})
+	if err != nil {
+		return mapError(err)
+	}
+
+	if res.DeletedCount < 1 {
+		return ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,237 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/store"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestCategoryGet(t *testing.T) {
+	type Actual struct {
+		category *models.Category
+		err      error
+	}
+
+	cases := []struct {
+		description string
+		namespaceID string
+		id          string
+		fixtures    []fixture
+		expected    Actual
+	}{
+		{
+			description: "fails when category is not found",
+			namespaceID: "ns_01HV7FKH5SRB0TGWM7MQ15PYKN",
+			id:          "cat_00000000000000000000000000",
+			fixtures:    []fixture{},
+			expected:    Actual{category: nil, err: store.ErrNotFound},
+		},
+		{
+			description: "fails when category belongs to another namespace",
+			namespaceID: "ns_01HWS7Q0H1JCEMKZADAFMETRZJ",
+			id:          "cat_01HX4B2M3N4P5Q6R7S8T9V0W1X",
+			fixtures:    []fixture{fixtureCategory},
+			expected:    Actual{category: nil, err: store.ErrNotFound},
+		},
+		{
+			description: "succeeds to find a category",
+			namespaceID: "ns_01HV7FKH5SRB0TGWM7MQ15PYKN",
+			id:          "cat_01HX4B2M3N4P5Q6R7S8T9V0W1X",
+			fixtures:    []fixture{fixtureCategory},
+			expected: Actual{
+				category: &models.Category{
+					ID:          "cat_01HX4B2M3N4P5Q6R7S8T9V0W1X",
+					NamespaceID: "ns_01HV7FKH5SRB0TGWM7MQ15PYKN",
+					CreatedAt:   time.Date(2023, 1, 2, 12, 0, 0, 0, time.UTC),
+					UpdatedAt:   time.Date(2023, 1, 2, 12, 0, 0, 0, time.UTC),
+					Name:        "Soft drinks",
+					ParentID:    "cat_01HX4B1A2B3C4D5E6F7G8H9J0K",
+					Ancestors:   []string{"cat_01HX4B1A2B3C4D5E6F7G8H9J0K"},
+				},
+				err: nil,
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			srv.apply(tc.fixtures...)
+			defer srv.reset()
+
+			ctx := context.Background()
+
+			category, err := s.Category.Get(ctx, tc.namespaceID, tc.id)
+			require.Equal(t, tc.expected, Actual{category, err})
+		})
+	}
+}
+
+func TestCategoryDescendants(t *testing.T) {
+	type Actual struct {
+		ids []string
+		err error
+	}
+
+	cases := []struct {
+		description string
+		id          string
+		fixtures    []fixture
+		expected    Actual
+	}{
+		{
+			description: "succeeds when the category is a leaf",
+			id:          "cat_01HX4B3Y4Z5A6B7C8D9E0F1G2H",
+			fixtures:    []fixture{fixtureCategory},
+			expected:    Actual{ids: []string{}, err: nil},
+		},
+		{
+			description: "succeeds to find the whole subtree",
+			id:          "cat_01HX4B1A2B3C4D5E6F7G8H9J0K",
+			fixtures:    []fixture{fixtureCategory},
+			expected: Actual{
+				ids: []string{"cat_01HX4B2M3N4P5Q6R7S8T9V0W1X", "cat_01HX4B3Y4Z5A6B7C8D9E0F1G2H"},
+				err: nil,
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			srv.apply(tc.fixtures...)
+			defer srv.reset()
+
+			ctx := context.Background()
+
+			categories, err := s.Category.Descendants(ctx, "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", tc.id)
+
+			ids := make([]string, 0, len(categories))
+			for _, c := range categories {
+				ids = append(ids, c.ID)
+			}
+
+			require.Equal(t, tc.expected.err, err)
+			require.ElementsMatch(t, tc.expected.ids, ids)
+		})
+	}
+}
+
+func TestCategoryMove(t *testing.T) {
+	type Expected struct {
+		err       error
+		ancestors map[string][]string
+	}
+
+	cases := []struct {
+		description string
+		id          string
+		parent      *models.Category
+		fixtures    []fixture
+		expected    Expected
+	}{
+		{
+			description: "fails when category is not found",
+			id:          "cat_00000000000000000000000000",
+			parent:      nil,
+			fixtures:    []fixture{},
+			expected:    Expected{err: store.ErrNotFound},
+		},
+		{
+			description: "succeeds to move a subtree under another category",
+			id:          "cat_01HX4B2M3N4P5Q6R7S8T9V0W1X",
+			parent: &models.Category{
+				ID:        "cat_01HX4B4J5K6M7N8P9Q0R1S2T3V",
+				Ancestors: []string{},
+			},
+			fixtures: []fixture{fixtureCategory},
+			expected: Expected{
+				err: nil,
+				ancestors: map[string][]string{
+					"cat_01HX4B2M3N4P5Q6R7S8T9V0W1X": {"cat_01HX4B4J5K6M7N8P9Q0R1S2T3V"},
+					"cat_01HX4B3Y4Z5A6B7C8D9E0F1G2H": {"cat_01HX4B4J5K6M7N8P9Q0R1S2T3V", "cat_01HX4B2M3N4P5Q6R7S8T9V0W1X"},
+					"cat_01HX4B1A2B3C4D5E6F7G8H9J0K": {},
+				},
+			},
+		},
+		{
+			description: "succeeds to move a subtree to the root",
+			id:          "cat_01HX4B2M3N4P5Q6R7S8T9V0W1X",
+			parent:      nil,
+			fixtures:    []fixture{fixtureCategory},
+			expected: Expected{
+				err: nil,
+				ancestors: map[string][]string{
+					"cat_01HX4B2M3N4P5Q6R7S8T9V0W1X": {},
+					"cat_01HX4B3Y4Z5A6B7C8D9E0F1G2H": {"cat_01HX4B2M3N4P5Q6R7S8T9V0W1X"},
+				},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			srv.apply(tc.fixtures...)
+			defer srv.reset()
+
+			ctx := context.Background()
+
+			err := s.WithTransaction(ctx, func(ctx context.Context) error {
+				return s.Category.Move(ctx, "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", tc.id, tc.parent)
+			})
+			require.Equal(t, tc.expected.err, err)
+
+			for id, ancestors := range tc.expected.ancestors {
+				cat := new(models.Category)
+				require.NoError(t, db.Collection("category").FindOne(ctx, bson.M{"_id": id}).Decode(cat))
+				require.Equal(t, ancestors, cat.Ancestors)
+			}
+		})
+	}
+}
+
+func TestCategoryDelete(t *testing.T) {
+	type Actual struct {
+		err error
+	}
+
+	cases := []struct {
+		description string
+		id          string
+		fixtures    []fixture
+		expected    Actual
+	}{
+		{
+			description: "fails when category is not found",
+			id:          "cat_00000000000000000000000000",
+			fixtures:    []fixture{},
+			expected:    Actual{err: store.ErrNotFound},
+		},
+		{
+			description: "succeeds to delete a category",
+			id:          "cat_01HX4B4J5K6M7N8P9Q0R1S2T3V",
+			fixtures:    []fixture{fixtureCategory},
+			expected:    Actual{err: nil},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			srv.apply(tc.fixtures...)
+			defer srv.reset()
+
+			ctx := context.Background()
+
+			if err := s.Category.Delete(ctx, "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", tc.id); err != nil {
+				require.Equal(t, tc.expected, Actual{err})
+				return
+			}
+
+			cat := new(models.Category)
+			require.Error(t, db.Collection("category").FindOne(ctx, bson.M{"_id": tc.id}).Decode(cat))
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package store
+
+import (
+	"context"
+
+	"github.com/heiytor/invenda/api/pkg/clock"
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/money"
+	"github.com/oklog/ulid/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Cost handles the cost pools and layers from which the namespace's movements are valued. Every
+// operation is scoped to a namespace ID and, as pools and layers are read before being written, must be
+// executed within a transaction.
+type Cost interface {
+	Entity
+
+	// Pool retrieves the cost pool of a product or variant. It returns [ErrNotFound] if the product has
+	// never been moved.
+	Pool(ctx context.Context, namespaceID, productID, variantID string) (pool *models.CostPool, err error)
+
+	// ApplyPool increments the quantity and value of the cost pool of a product or variant, creating the
+	// pool when it does not exist.
+	ApplyPool(ctx context.Context, namespaceID, productID, variantID string, quantity int64, value money.Money) (err error)
+
+	// Layers retrieves the cost layers of a product or variant that are still in stock, the oldest first.
+	Layers(ctx context.Context, namespaceID, productID, variantID string) (layers []models.CostLayer, err error)
+
+	// AddLayer creates a new cost layer with the provided data.
+	AddLayer(ctx context.Context, layer *models.CostLayer) (err error)
+
+	// ConsumeLayer decrements the quantity of a cost layer. It returns [ErrInsufficientStock] if the layer
+	// does not have the quantity.
+	ConsumeLayer(ctx context.Context, namespaceID, id string, quantity int64) (err error)
+}
+
+type cost struct {
+	pools  *mongo.Collection // pools is the "cost_pool" collection
+	layers *mongo.Collection // layers is the "cost_layer" collection
+}
+
+var _ Cost = (*cost)(nil)
+
+func (*cost) Entity() string {
+	return "cost"
+}
+
+func (c *cost) Pool(ctx context.Context, namespaceID, productID, variantID string) (*models.CostPool, error) {
+	pool := new(models.CostPool)
+
+	filter := bson.M{"namespace_id": namespaceID, "product_id": productID, "variant_id": variantID}
+	if err := c.pools.FindOne(ctx, filter).Decode(pool); err != nil {
+		return nil, mapError(err)
+	}
+
+	return pool, nil
+}
+
+func (c *cost) ApplyPool(ctx context.Context, namespaceID, productID, variantID string, quantity int64, value money.Money) error {
+	inc, set := bson.M{"quantity": quantity}, bson.M{"updated_at": clock.Now()}
+	incMoney(inc, set, "value", value)
+
+	update := bson.M{
+		"$inc":         inc,
+		"$set":         set,
+		"$setOnInsert": bson.M{"_id": "cpl_" + ulid.Make().String()},
+	}
+
+	filter := bson.M{"namespace_id": namespaceID, "product_id": productID, "variant_id": variantID}
+	_, err := c.pools.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+
+	return mapError(err)
+}
+
+func (c *cost) Layers(ctx context.Context, namespaceID, productID, variantID string) ([]models.CostLayer, error) {
+	filter := bson.M{"namespace_id": namespaceID, "product_id": productID, "variant_id": variantID, "quantity": bson.M{"$gt": 0}}
+
+	cursor, err := c.layers.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, mapError(err)
+	}
+	defer cursor.Close(ctx)
+
+	layers := make([]models.CostLayer, 0)
+	if err := cursor.All(ctx, &layers); err != nil {
+		return nil, mapError(err)
+	}
+
+	return layers, nil
+}
+
+func (c *cost) AddLayer(ctx context.Context, layer *models.CostLayer) error {
+	layer.ID = "cly_" + ulid.Make().String()
+	layer.CreatedAt = clock.Now()
+
+	_, err := c.layers.InsertOne(ctx, layer)
+
+	return mapError(err)
+}
+
+func (c *cost) ConsumeLayer(ctx context.Context, namespaceID, id string, quantity int64) error {
+	res, err := c.layers.UpdateOne(
+		ctx,
+		bson.M{"_id": id, "namespace_id": namespaceID, "quantity": bson.M{"$gte": quantity}},
+		bson.M{"$inc": bson.M{"quantity": -quantity}},
+	)
+	if err != nil {
+		return mapError(err)
+	}
+
+	if res.MatchedCount < 1 {
+		return ErrInsufficientStock
+	}
+
+	return nil
+}
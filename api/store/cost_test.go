@@ -0,0 +1,95 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/money"
+	"github.com/heiytor/invenda/api/store"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCostPool(t *testing.T) {
+	srv.apply(fixtureCost)
+	defer srv.reset()
+
+	ctx := context.Background()
+
+	require.NoError(t, s.Cost.ApplyPool(ctx, "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", "prd_01HX3A1B2C3D4E5F6G7H8J9K0M", "", -2, money.New(-300, "BRL")))
+
+	pool, err := s.Cost.Pool(ctx, "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", "prd_01HX3A1B2C3D4E5F6G7H8J9K0M", "")
+	require.NoError(t, err)
+	require.Equal(t, int64(4), pool.Quantity)
+	require.Equal(t, money.New(600, "BRL"), pool.Value)
+
+	_, err = s.Cost.Pool(ctx, "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", "prd_01HX3B4C5D6E7F8G9H0J1K2M3N", "")
+	require.Equal(t, store.ErrNotFound, err)
+}
+
+func TestCostLayers(t *testing.T) {
+	srv.apply(fixtureCost)
+	defer srv.reset()
+
+	ctx := context.Background()
+
+	layers, err := s.Cost.Layers(ctx, "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", "prd_01HX3A1B2C3D4E5F6G7H8J9K0M", "")
+	require.NoError(t, err)
+
+	ids := make([]string, 0, len(layers))
+	for _, l := range layers {
+		ids = append(ids, l.ID)
+	}
+
+	// Consumed layers are skipped and the others are listed oldest first.
+	require.Equal(t, []string{"cly_01HX5K2M3N4P5Q6R7S8T9V0W1X", "cly_01HX5K1A2B3C4D5E6F7G8H9J0K"}, ids)
+}
+
+func TestCostConsumeLayer(t *testing.T) {
+	cases := []struct {
+		description string
+		quantity    int64
+		expected    error
+	}{
+		{
+			description: "succeeds to consume the layer's quantity",
+			quantity:    4,
+			expected:    nil,
+		},
+		{
+			description: "fails when the layer does not have the quantity",
+			quantity:    5,
+			expected:    store.ErrInsufficientStock,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			srv.apply(fixtureCost)
+			defer srv.reset()
+
+			err := s.Cost.ConsumeLayer(context.Background(), "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", "cly_01HX5K2M3N4P5Q6R7S8T9V0W1X", tc.quantity)
+			require.Equal(t, tc.expected, err)
+		})
+	}
+}
+
+func TestCostAddLayer(t *testing.T) {
+	srv.apply(fixtureCost)
+	defer srv.reset()
+
+	ctx := context.Background()
+
+	layer := &models.CostLayer{
+		NamespaceID: "ns_01HV7FKH5SRB0TGWM7MQ15PYKN",
+		ProductID:   "prd_01HX3A1B2C3D4E5F6G7H8J9K0M",
+		Quantity:    3,
+		UnitCost:    money.New(250, "BRL"),
+	}
+	require.NoError(t, s.Cost.AddLayer(ctx, layer))
+
+	layers, err := s.Cost.Layers(ctx, "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", "prd_01HX3A1B2C3D4E5F6G7H8J9K0M", "")
+	require.NoError(t, err)
+	require.Len(t, layers, 3)
+	require.Equal(t, layer.ID, layers[2].ID)
+}
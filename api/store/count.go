@@ -0,0 +1,166 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/heiytor/invenda/api/pkg/clock"
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/query"
+	"github.com/oklog/ulid/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// StockCount handles the namespace's stock counts. Every operation is scoped to a namespace ID.
+type StockCount interface {
+	Entity
+
+	// Get retrieves a stock count with the specified ID. It returns the stock count or an error if any.
+	Get(ctx context.Context, namespaceID, id string) (count *models.StockCount, err error)
+
+	// GetMany retrieves a list of stock counts of a namespace. It returns the list of stock counts, the total
+	// count of the existent documents and an error if any.
+	GetMany(ctx context.Context, namespaceID string, query *query.Query) (counts []models.StockCount, total int64, err error)
+
+	// Create creates a new stock count with the provided data. It returns the inserted ID or an error
+	// if any.
+	Create(ctx context.Context, count *models.StockCount) (insertedID string, err error)
+
+	// Submit sets the counted quantity of an item of an open stock count, appending the item when it was
+	// not part of the snapshot. Only the submitted item is written, so several users can submit items
+	// concurrently. It returns [ErrNotFound] if no open stock count is found.
+	Submit(ctx context.Context, namespaceID, id string, item *models.CountItem) (err error)
+
+	// Update updates a stock count with the specified changes and ID. When statuses are provided, the count
+	// is only updated if it is in one of them. It returns [ErrNotFound] if no matching stock count is found.
+	Update(ctx context.Context, namespaceID, id string, statuses []models.CountStatus, changes *models.StockCountChanges) (err error)
+}
+
+type stockCount struct {
+	c *mongo.Collection // c is the "stock_count" collection
+}
+
+var _ StockCount = (*stockCount)(nil)
+
+func (*stockCount) Entity() string {
+	return "stock_count"
+}
+
+func (sc *stockCount) Get(ctx context.Context, namespaceID, id string) (*models.StockCount, error) {
+	count := new(models.StockCount)
+	if err := sc.c.FindOne(ctx, bson.M{"_id": id, "namespace_id": namespaceID}).Decode(count); err != nil {
+		return nil, mapError(err)
+	}
+
+	return count, nil
+}
+
+func (sc *stockCount) GetMany(ctx context.Context, namespaceID string, query *query.Query) ([]models.StockCount, int64, error) {
+	counts := make([]models.StockCount, 0)
+	total, err := find(ctx, sc.c, namespaceID, query, &counts)
+
+	return counts, total, err
+}
+
+func (sc *stockCount) Create(ctx context.Context, count *models.StockCount) (string, error) {
+	count.ID = "cnt_" + ulid.Make().String()
+
+	now := clock.Now()
+	count.CreatedAt = now
+	count.UpdatedAt = now
+
+	if count.Items == nil {
+		count.Items = []models.CountItem{}
+	}
+
+	if _, err := sc.c.InsertOne(ctx, count); err != nil {
+		return "", mapError(err)
+	}
+
+	return count.ID, nil
+}
+
+func (sc *stockCount) Submit(ctx context.Context, namespaceID, id string, item *models.CountItem) error {
+	now := clock.Now()
+	item.CountedAt = &now
+
+	// An item can be appended between the two attempts by a concurrent submission, so it is tried
+	// once more before giving up.
+	for attempt := 0; attempt < 2; attempt++ {
+		ok, err := sc.set(ctx, namespaceID, id, item, now)
+		if err != nil || ok {
+			return err
+		}
+
+		if ok, err = sc.push(ctx, namespaceID, id, item, now); err != nil || ok {
+			return err
+		}
+	}
+
+	return ErrNotFound
+}
+
+// set updates the counted quantity of an existent item. It reports whether the item was found.
+func (sc *stockCount) set(ctx context.Context, namespaceID, id string, item *models.CountItem, now time.Time) (bool, error) {
+	match := bson.M{"location": item.Location, "product_id": item.ProductID, "variant_id": item.VariantID}
+	filter := bson.M{"_id": id, "namespace_id": namespaceID, "status": models.CountOpen, "items": bson.M{"$elemMatch": match}}
+	update := bson.M{
+		"$set": bson.M{
+			"updated_at":            now,
+			"items.$[i].counted":    item.Counted,
+			"items.$[i].counted_by": item.CountedBy,
+			"items.$[i].counted_at": item.CountedAt,
+		},
+	}
+
+	opts := options.Update().SetArrayFilters(options.ArrayFilters{
+		Filters: []interface{}{bson.M{"i.location": item.Location, "i.product_id": item.ProductID, "i.variant_id": item.VariantID}},
+	})
+
+	res, err := sc.c.UpdateOne(ctx, filter, update, opts)
+	if err != nil {
+		return false, mapError(err)
+	}
+
+	return res.MatchedCount > 0, nil
+}
+
+// push appends an item that is not part of the count. It reports whether the item was appended.
+func (sc *stockCount) push(ctx context.Context, namespaceID, id string, item *models.CountItem, now time.Time) (bool, error) {
+	match := bson.M{"location": item.Location, "product_id": item.ProductID, "variant_id": item.VariantID}
+	filter := bson.M{"_id": id, "namespace_id": namespaceID, "status": models.CountOpen, "items": bson.M{"$not": bson.M{"$elemMatch": match}}}
+	update := bson.M{"$set": bson.M{"updated_at": now}, "$push": bson.M{"items": item}}
+
+	res, err := sc.c.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, mapError(err)
+	}
+
+	return res.MatchedCount > 0, nil
+}
+
+func (sc *stockCount) Update(ctx context.Context, namespaceID, id string, statuses []models.CountStatus, changes *models.StockCountChanges) error {
+	if changes == nil {
+		return nil
+	}
+
+	changes.UpdatedAt = clock.Now()
+
+	filter := bson.M{"_id": id, "namespace_id": namespaceID}
+	if len(statuses) > 0 {
+		filter["status"] = bson.M{"$in": statuses}
+	}
+
+	res, err := sc.c.UpdateOne(ctx, filter, bson.M{"$set": changes})
+	if err != nil {
+		return mapError(err)
+	}
+
+	if res.MatchedCount < 1 {
+		return ErrNotFound
+	}
+
+	return nil
+}
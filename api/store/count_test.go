@@ -0,0 +1,83 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/store"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStockCountSubmit(t *testing.T) {
+	type Expected struct {
+		err   error
+		items int
+	}
+
+	counted := int64(5)
+
+	cases := []struct {
+		description string
+		id          string
+		item        *models.CountItem
+		fixtures    []fixture
+		expected    Expected
+	}{
+		{
+			description: "fails when the stock count is not found",
+			id:          "cnt_00000000000000000000000000",
+			item:        &models.CountItem{ProductID: "prd_01HX3A1B2C3D4E5F6G7H8J9K0M", Counted: &counted},
+			fixtures:    []fixture{},
+			expected:    Expected{err: store.ErrNotFound},
+		},
+		{
+			description: "fails when the stock count is not open",
+			id:          "cnt_01HX7G2M3N4P5Q6R7S8T9V0W1X",
+			item:        &models.CountItem{ProductID: "prd_01HX3A1B2C3D4E5F6G7H8J9K0M", Counted: &counted},
+			fixtures:    []fixture{fixtureCount},
+			expected:    Expected{err: store.ErrNotFound},
+		},
+		{
+			description: "succeeds to count an item of the snapshot",
+			id:          "cnt_01HX7G1A2B3C4D5E6F7G8H9J0K",
+			item:        &models.CountItem{ProductID: "prd_01HX3A1B2C3D4E5F6G7H8J9K0M", Counted: &counted, CountedBy: "01HNGJ2BTGQAHAZ1XNYZQPG719"},
+			fixtures:    []fixture{fixtureCount},
+			expected:    Expected{err: nil, items: 1},
+		},
+		{
+			description: "succeeds to count an item out of the snapshot",
+			id:          "cnt_01HX7G1A2B3C4D5E6F7G8H9J0K",
+			item:        &models.CountItem{ProductID: "prd_01HX3A2N3P4Q5R6S7T8V9W0X1Y", Counted: &counted, CountedBy: "01HNGJ2BTGQAHAZ1XNYZQPG719"},
+			fixtures:    []fixture{fixtureCount},
+			expected:    Expected{err: nil, items: 2},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			srv.apply(tc.fixtures...)
+			defer srv.reset()
+
+			ctx := context.Background()
+
+			err := s.StockCount.Submit(ctx, "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", tc.id, tc.item)
+			require.Equal(t, tc.expected.err, err)
+
+			if err != nil {
+				return
+			}
+
+			count, err := s.StockCount.Get(ctx, "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", tc.id)
+			require.NoError(t, err)
+			require.Len(t, count.Items, tc.expected.items)
+
+			for _, i := range count.Items {
+				if i.ProductID == tc.item.ProductID {
+					require.Equal(t, counted, *i.Counted)
+					require.Equal(t, tc.item.CountedBy, i.CountedBy)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,268 @@
+package store
+
+import (
+	"context"
+
+	"github.com/heiytor/invenda/api/pkg/clock"
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/money"
+	"github.com/heiytor/invenda/api/pkg/query"
+	"github.com/heiytor/invenda/api/store/internal"
+	"github.com/oklog/ulid/v2"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Customer handles the namespace's customers. Every operation is scoped to a namespace ID.
+type Customer interface {
+	Entity
+
+	// Get retrieves a customer with the specified ID. It returns the customer or an error if any.
+	Get(ctx context.Context, namespaceID, id string) (customer *models.Customer, err error)
+
+	// GetMany retrieves a list of customers of a namespace. It returns the list of customers, the total count
+	// of the existent documents and an error if any.
+	GetMany(ctx context.Context, namespaceID string, query *query.Query) (customers []models.Customer, count int64, err error)
+
+	// Conflicts reports whether the non-zero fields of the provided target already exist in the namespace.
+	// It returns a list of conflicted fields or an error if any.
+	Conflicts(ctx context.Context, namespaceID string, target *models.Customer) (conflicts []string, err error)
+
+	// Create creates a new customer with the provided data. It returns the inserted ID or an error
+	// if any.
+	Create(ctx context.Context, customer *models.Customer) (insertedID string, err error)
+
+	// Update updates a customer with the specified changes and ID. An empty email or tax ID removes it from
+	// the customer. It returns [ErrNotFound] if no customer is found.
+	Update(ctx context.Context, namespaceID, id string, changes *models.CustomerChanges) (err error)
+
+	// Delete deletes a customer with the specified ID. It returns [ErrNotFound] if no customer is found.
+	Delete(ctx context.Context, namespaceID, id string) (err error)
+
+	// AddCredit increments the store credit of a customer by delta. It returns [ErrNotFound] if no customer
+	// is found.
+	AddCredit(ctx context.Context, namespaceID, id string, delta money.Money) (err error)
+
+	// History aggregates the sales orders placed by a customer along with the customer's counter sales. It
+	// returns an empty history for customers without purchases or an error if any.
+	History(ctx context.Context, namespaceID, id string) (history *models.CustomerHistory, err error)
+}
+
+type customer struct {
+	c      *mongo.Collection // c is the "customer" collection
+	orders *mongo.Collection // orders is the "sales_order" collection
+	sales  *mongo.Collection // sales is the "sale" collection
+}
+
+var _ Customer = (*customer)(nil)
+
+func (*customer) Entity() string {
+	return "customer"
+}
+
+func (cs *customer) Get(ctx context.Context, namespaceID, id string) (*models.Customer, error) {
+	cus := new(models.Customer)
+	if err := cs.c.FindOne(ctx, bson.M{"_id": id, "namespace_id": namespaceID}).Decode(cus); err != nil {
+		return nil, mapError(err)
+	}
+
+	return cus, nil
+}
+
+func (cs *customer) GetMany(ctx context.Context, namespaceID string, query *query.Query) ([]models.Customer, int64, error) {
+	conditions := []bson.M{
+		{"namespace_id": namespaceID},
+		internal.FromFilter(&query.Filter),
+	}
+
+	if query.Search != "" {
+		conditions = append(conditions, internal.FromPrefixSearch(query.Search, "name", "email", "tax_id"))
+	}
+
+	match := bson.M{"$and": conditions}
+
+	count, err := cs.c.CountDocuments(ctx, match)
+	if err != nil {
+		log.Error().Err(err).Msg("unable to count the total documents")
+	}
+
+	pipeline := make([]bson.M, 0)
+	pipeline = append(pipeline, bson.M{"$match": match})
+	pipeline = append(pipeline, internal.FromSorter(&query.Sorter)...)
+	pipeline = append(pipeline, internal.FromPaginator(&query.Paginator)...)
+
+	cursor, err := cs.c.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, 0, mapError(err)
+	}
+	defer cursor.Close(ctx)
+
+	customers := make([]models.Customer, 0)
+	if err := cursor.All(ctx, &customers); err != nil {
+		return nil, 0, mapError(err)
+	}
+
+	return customers, count, nil
+}
+
+func (cs *customer) Conflicts(ctx context.Context, namespaceID string, target *models.Customer) ([]string, error) {
+	pipeline := append([]bson.M{{"$match": bson.M{"namespace_id": namespaceID}}}, or(target)...)
+
+	cursor, err := cs.c.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	defer cursor.Close(ctx)
+
+	conflicts := make([]string, 0)
+	for cursor.Next(ctx) {
+		cus := new(models.Customer)
+
+		if err = cursor.Decode(cus); err != nil {
+			return nil, mapError(err)
+		}
+
+		conflicts = append(conflicts, partialEqual(target, cus)...)
+	}
+
+	return conflicts, nil
+}
+
+func (cs *customer) Create(ctx context.Context, cus *models.Customer) (string, error) {
+	cus.ID = "cus_" + ulid.Make().String()
+
+	now := clock.Now()
+	cus.CreatedAt = now
+	cus.UpdatedAt = now
+
+	if cus.Addresses == nil {
+		cus.Addresses = []models.Address{}
+	}
+
+	if cus.Tags == nil {
+		cus.Tags = []string{}
+	}
+
+	if _, err := cs.c.InsertOne(ctx, cus); err != nil {
+		return "", mapError(err)
+	}
+
+	return cus.ID, nil
+}
+
+func (cs *customer) Update(ctx context.Context, namespaceID, id string, changes *models.CustomerChanges) error {
+	if changes == nil {
+		return nil
+	}
+
+	changes.UpdatedAt = clock.Now()
+	update := bson.M{"$set": changes}
+
+	// An empty email or tax ID is removed from the document so it is not indexed as a duplicate.
+	unset := bson.M{}
+	if changes.Email != nil && *changes.Email == "" {
+		changes.Email = nil
+		unset["email"] = ""
+	}
+
+	if changes.TaxID != nil && *changes.TaxID == "" {
+		changes.TaxID = nil
+		unset["tax_id"] = ""
+	}
+
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+
+	res, err := cs.c.UpdateOne(ctx, bson.M{"_id": id, "namespace_id": namespaceID}, update)
+	if err != nil {
+		return mapError(err)
+	}
+
+	if res.MatchedCount < 1 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (cs *customer) Delete(ctx context.Context, namespaceID, id string) error {
+	res, err := cs.c.DeleteOne(ctx, bson.M{"_id": id, "namespace_id": namespaceID})
+	if err != nil {
+		return mapError(err)
+	}
+
+	if res.DeletedCount < 1 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (cs *customer) AddCredit(ctx context.Context, namespaceID, id string, delta money.Money) error {
+	inc, set := bson.M{}, bson.M{"updated_at": clock.Now()}
+	incMoney(inc, set, "store_credit", delta)
+
+	update := bson.M{"$inc": inc, "$set": set}
+
+	res, err := cs.c.UpdateOne(ctx, bson.M{"_id": id, "namespace_id": namespaceID}, update)
+	if err != nil {
+		return mapError(err)
+	}
+
+	if res.MatchedCount < 1 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (cs *customer) History(ctx context.Context, namespaceID, id string) (*models.CustomerHistory, error) {
+	pipeline := []bson.M{
+		{
+			"$match": bson.M{
+				"namespace_id": namespaceID,
+				"customer_id":  id,
+				"placed_at":    bson.M{"$type": "date"},
+				"status":       bson.M{"$ne": "cancelled"},
+			},
+		},
+		// Counter sales are placed when they are created and are never cancelled.
+		{
+			"$unionWith": bson.M{
+				"coll": cs.sales.Name(),
+				"pipeline": []bson.M{
+					{"$match": bson.M{"namespace_id": namespaceID, "customer_id": id}},
+					{"$set": bson.M{"placed_at": "$created_at"}},
+				},
+			},
+		},
+		// Orders in other currencies are summed by their totals in the base currency, in which counter
+		// sales are made.
+		{
+			"$group": bson.M{
+				"_id":              "$customer_id",
+				"orders":           bson.M{"$sum": 1},
+				"spend":            bson.M{"$sum": bson.M{"$ifNull": bson.A{"$base_total.amount", "$total.amount"}}},
+				"last_purchase_at": bson.M{"$max": "$placed_at"},
+			},
+		},
+		{"$set": bson.M{"customer_id": "$_id"}},
+	}
+
+	cursor, err := cs.orders.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	defer cursor.Close(ctx)
+
+	history := &models.CustomerHistory{CustomerID: id}
+	if cursor.Next(ctx) {
+		if err := cursor.Decode(history); err != nil {
+			return nil, mapError(err)
+		}
+	}
+
+	return history, nil
+}
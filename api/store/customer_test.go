@@ -0,0 +1,153 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/money"
+	"github.com/heiytor/invenda/api/store"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestCustomerConflicts(t *testing.T) {
+	type Actual struct {
+		conflicts []string
+		err       error
+	}
+
+	cases := []struct {
+		description string
+		namespaceID string
+		target      *models.Customer
+		fixtures    []fixture
+		expected    Actual
+	}{
+		{
+			description: "succeeds when none conflicts are found",
+			namespaceID: "ns_01HV7FKH5SRB0TGWM7MQ15PYKN",
+			target:      &models.Customer{Email: "jane.doe@test.com", TaxID: "US000000000"},
+			fixtures:    []fixture{fixtureCustomer},
+			expected:    Actual{conflicts: []string{}, err: nil},
+		},
+		{
+			description: "succeeds when the email belongs to another namespace",
+			namespaceID: "ns_01HWS7Q0H1JCEMKZADAFMETRZJ",
+			target:      &models.Customer{Email: "john.doe@test.com"},
+			fixtures:    []fixture{fixtureCustomer},
+			expected:    Actual{conflicts: []string{}, err: nil},
+		},
+		{
+			description: "succeeds when conflicts are found",
+			namespaceID: "ns_01HV7FKH5SRB0TGWM7MQ15PYKN",
+			target:      &models.Customer{Email: "john.doe@test.com", TaxID: "US123456789"},
+			fixtures:    []fixture{fixtureCustomer},
+			expected:    Actual{conflicts: []string{"email", "tax_id"}, err: nil},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			srv.apply(tc.fixtures...)
+			defer srv.reset()
+
+			ctx := context.Background()
+
+			conflicts, err := s.Customer.Conflicts(ctx, tc.namespaceID, tc.target)
+			require.Equal(t, tc.expected, Actual{conflicts, err})
+		})
+	}
+}
+
+func TestCustomerUpdate(t *testing.T) {
+	srv.apply(fixtureCustomer)
+	defer srv.reset()
+
+	ctx := context.Background()
+
+	email := ""
+	changes := &models.CustomerChanges{Name: "John Roe", Email: &email}
+	require.Equal(t, store.ErrNotFound, s.Customer.Update(ctx, "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", "cus_00000000000000000000000000", changes))
+
+	changes = &models.CustomerChanges{Name: "John Roe", Email: &email}
+	require.NoError(t, s.Customer.Update(ctx, "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", "cus_01HXB1C2D3E4F5G6H7J8K9M0NP", changes))
+
+	doc := bson.M{}
+	require.NoError(t, db.Collection("customer").FindOne(ctx, bson.M{"_id": "cus_01HXB1C2D3E4F5G6H7J8K9M0NP"}).Decode(&doc))
+	require.Equal(t, "John Roe", doc["name"])
+	require.NotContains(t, doc, "email")
+	require.Equal(t, "US123456789", doc["tax_id"])
+}
+
+func TestCustomerHistory(t *testing.T) {
+	last := time.Date(2023, 1, 5, 12, 0, 0, 0, time.UTC)
+	sold := time.Date(2023, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		description string
+		id          string
+		fixtures    []fixture
+		expected    *models.CustomerHistory
+	}{
+		{
+			description: "succeeds with an empty history when the customer has no orders",
+			id:          "cus_01HXB1C2D3E4F5G6H7J8K9M0NP",
+			fixtures:    []fixture{fixtureCustomer},
+			expected:    &models.CustomerHistory{CustomerID: "cus_01HXB1C2D3E4F5G6H7J8K9M0NP"},
+		},
+		{
+			description: "succeeds ignoring drafts and cancelled orders",
+			id:          "cus_01HXB1C2D3E4F5G6H7J8K9M0NP",
+			fixtures:    []fixture{fixtureCustomer, fixtureSalesOrder},
+			expected: &models.CustomerHistory{
+				CustomerID:     "cus_01HXB1C2D3E4F5G6H7J8K9M0NP",
+				Orders:         2,
+				Spend:          money.Money{Amount: 16500},
+				LastPurchaseAt: &last,
+			},
+		},
+		{
+			description: "succeeds including counter sales",
+			id:          "cus_01HXB1C2D3E4F5G6H7J8K9M0NP",
+			fixtures:    []fixture{fixtureCustomer, fixtureSalesOrder, fixtureSale},
+			expected: &models.CustomerHistory{
+				CustomerID:     "cus_01HXB1C2D3E4F5G6H7J8K9M0NP",
+				Orders:         3,
+				Spend:          money.Money{Amount: 18500},
+				LastPurchaseAt: &sold,
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			srv.apply(tc.fixtures...)
+			defer srv.reset()
+
+			ctx := context.Background()
+
+			history, err := s.Customer.History(ctx, "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", tc.id)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, history)
+		})
+	}
+}
+
+func TestCustomerAddCredit(t *testing.T) {
+	srv.apply(fixtureCustomer)
+	defer srv.reset()
+
+	ctx := context.Background()
+
+	err := s.Customer.AddCredit(ctx, "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", "cus_00000000000000000000000000", money.New(500, "BRL"))
+	require.Equal(t, store.ErrNotFound, err)
+
+	require.NoError(t, s.Customer.AddCredit(ctx, "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", "cus_01HXB1C2D3E4F5G6H7J8K9M0NP", money.New(500, "BRL")))
+	require.NoError(t, s.Customer.AddCredit(ctx, "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", "cus_01HXB1C2D3E4F5G6H7J8K9M0NP", money.New(250, "BRL")))
+
+	cus, err := s.Customer.Get(ctx, "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", "cus_01HXB1C2D3E4F5G6H7J8K9M0NP")
+	require.NoError(t, err)
+	require.Equal(t, money.New(750, "BRL"), cus.StoreCredit)
+}
@@ -10,12 +10,18 @@ import (
 var (
 	ErrUnexpected = errors.New("unexpected Error")
 	ErrNotFound   = errors.New("document not found")
+	ErrDuplicated = errors.New("document already exists")
+
+	// ErrInsufficientStock is returned when a movement would bring a balance below zero.
+	ErrInsufficientStock = errors.New("insufficient stock")
 )
 
 func mapError(err error) error {
 	switch {
 	case err == mongo.ErrNoDocuments, err == io.EOF:
 		return ErrNotFound
+	case mongo.IsDuplicateKeyError(err):
+		return errors.Join(ErrDuplicated, err)
 	default:
 		if err == nil {
 			return nil
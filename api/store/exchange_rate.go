@@ -0,0 +1,147 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/heiytor/invenda/api/pkg/clock"
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/money"
+	"github.com/heiytor/invenda/api/pkg/query"
+	"github.com/heiytor/invenda/api/store/internal"
+	"github.com/oklog/ulid/v2"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ExchangeRate handles the namespace's exchange rates. Every operation is scoped to a namespace ID.
+type ExchangeRate interface {
+	Entity
+
+	// Get retrieves an exchange rate with the specified ID. It returns the exchange rate or an error if any.
+	Get(ctx context.Context, namespaceID, id string) (exchangeRate *models.ExchangeRate, err error)
+
+	// GetMany retrieves a list of exchange rates of a namespace. It returns the list of exchange rates, the
+	// total count of the existent documents and an error if any.
+	GetMany(ctx context.Context, namespaceID string, query *query.Query) (exchangeRates []models.ExchangeRate, count int64, err error)
+
+	// GetEffective retrieves the rate of a currency effective at a time, which is the one with the latest
+	// start up to it. It returns [ErrNotFound] if the currency has no rate by then.
+	GetEffective(ctx context.Context, namespaceID string, currency money.Currency, at time.Time) (exchangeRate *models.ExchangeRate, err error)
+
+	// Load creates the rates or, for the currencies that already have a rate from the same time, replaces
+	// it. It returns how many rates were created and updated or an error if any.
+	Load(ctx context.Context, namespaceID string, exchangeRates []models.ExchangeRate) (load *models.ExchangeRateLoad, err error)
+
+	// Delete deletes an exchange rate with the specified ID. It returns [ErrNotFound] if no exchange rate is
+	// found.
+	Delete(ctx context.Context, namespaceID, id string) (err error)
+}
+
+type exchangeRate struct {
+	c *mongo.Collection // c is the "exchange_rate" collection
+}
+
+var _ ExchangeRate = (*exchangeRate)(nil)
+
+func (*exchangeRate) Entity() string {
+	return "exchange_rate"
+}
+
+func (er *exchangeRate) Get(ctx context.Context, namespaceID, id string) (*models.ExchangeRate, error) {
+	xr := new(models.ExchangeRate)
+	if err := er.c.FindOne(ctx, bson.M{"_id": id, "namespace_id": namespaceID}).Decode(xr); err != nil {
+		return nil, mapError(err)
+	}
+
+	return xr, nil
+}
+
+func (er *exchangeRate) GetMany(ctx context.Context, namespaceID string, query *query.Query) ([]models.ExchangeRate, int64, error) {
+	conditions := []bson.M{
+		{"namespace_id": namespaceID},
+		internal.FromFilter(&query.Filter),
+	}
+
+	if query.Search != "" {
+		conditions = append(conditions, internal.FromPrefixSearch(query.Search, "currency"))
+	}
+
+	match := bson.M{"$and": conditions}
+
+	count, err := er.c.CountDocuments(ctx, match)
+	if err != nil {
+		log.Error().Err(err).Msg("unable to count the total documents")
+	}
+
+	pipeline := make([]bson.M, 0)
+	pipeline = append(pipeline, bson.M{"$match": match})
+	pipeline = append(pipeline, internal.FromSorter(&query.Sorter)...)
+	pipeline = append(pipeline, internal.FromPaginator(&query.Paginator)...)
+
+	cursor, err := er.c.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, 0, mapError(err)
+	}
+	defer cursor.Close(ctx)
+
+	exchangeRates := make([]models.ExchangeRate, 0)
+	if err := cursor.All(ctx, &exchangeRates); err != nil {
+		return nil, 0, mapError(err)
+	}
+
+	return exchangeRates, count, nil
+}
+
+func (er *exchangeRate) GetEffective(ctx context.Context, namespaceID string, currency money.Currency, at time.Time) (*models.ExchangeRate, error) {
+	filter := bson.M{"namespace_id": namespaceID, "currency": currency, "valid_from": bson.M{"$lte": at}}
+
+	xr := new(models.ExchangeRate)
+	if err := er.c.FindOne(ctx, filter, options.FindOne().SetSort(bson.D{{Key: "valid_from", Value: -1}})).Decode(xr); err != nil {
+		return nil, mapError(err)
+	}
+
+	return xr, nil
+}
+
+func (er *exchangeRate) Load(ctx context.Context, namespaceID string, exchangeRates []models.ExchangeRate) (*models.ExchangeRateLoad, error) {
+	if len(exchangeRates) == 0 {
+		return &models.ExchangeRateLoad{}, nil
+	}
+
+	now := clock.Now()
+
+	writes := make([]mongo.WriteModel, 0, len(exchangeRates))
+	for _, xr := range exchangeRates {
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"namespace_id": namespaceID, "currency": xr.Currency, "valid_from": xr.ValidFrom}).
+			SetUpdate(bson.M{
+				"$set":         bson.M{"rate": xr.Rate, "updated_at": now},
+				"$setOnInsert": bson.M{"_id": "xr_" + ulid.Make().String(), "created_at": now},
+			}).
+			SetUpsert(true),
+		)
+	}
+
+	res, err := er.c.BulkWrite(ctx, writes)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	return &models.ExchangeRateLoad{Created: res.UpsertedCount, Updated: res.MatchedCount}, nil
+}
+
+func (er *exchangeRate) Delete(ctx context.Context, namespaceID, id string) error {
+	res, err := er.c.DeleteOne(ctx, bson.M{"_id": id, "namespace_id": namespaceID})
+	if err != nil {
+		return mapError(err)
+	}
+
+	if res.DeletedCount < 1 {
+		return ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,117 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/money"
+	"github.com/heiytor/invenda/api/store"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExchangeRateGetEffective(t *testing.T) {
+	type Actual struct {
+		rate money.Rate
+		err  error
+	}
+
+	cases := []struct {
+		description string
+		namespaceID string
+		currency    money.Currency
+		at          time.Time
+		fixtures    []fixture
+		expected    Actual
+	}{
+		{
+			description: "fails when the currency has no rates",
+			namespaceID: "ns_01HV7FKH5SRB0TGWM7MQ15PYKN",
+			currency:    "GBP",
+			at:          time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+			fixtures:    []fixture{fixtureExchange},
+			expected:    Actual{rate: "", err: store.ErrNotFound},
+		},
+		{
+			description: "fails when the currency has no rate effective yet",
+			namespaceID: "ns_01HV7FKH5SRB0TGWM7MQ15PYKN",
+			currency:    "USD",
+			at:          time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC),
+			fixtures:    []fixture{fixtureExchange},
+			expected:    Actual{rate: "", err: store.ErrNotFound},
+		},
+		{
+			description: "succeeds to find the rate effective before a change of rate",
+			namespaceID: "ns_01HV7FKH5SRB0TGWM7MQ15PYKN",
+			currency:    "USD",
+			at:          time.Date(2024, 1, 31, 23, 0, 0, 0, time.UTC),
+			fixtures:    []fixture{fixtureExchange},
+			expected:    Actual{rate: "4.9", err: nil},
+		},
+		{
+			description: "succeeds to find the rate effective after a change of rate",
+			namespaceID: "ns_01HV7FKH5SRB0TGWM7MQ15PYKN",
+			currency:    "USD",
+			at:          time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+			fixtures:    []fixture{fixtureExchange},
+			expected:    Actual{rate: "5.1234", err: nil},
+		},
+		{
+			description: "succeeds to find the rate of another namespace",
+			namespaceID: "ns_01HWS7Q0H1JCEMKZADAFMETRZJ",
+			currency:    "USD",
+			at:          time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+			fixtures:    []fixture{fixtureExchange},
+			expected:    Actual{rate: "0.92", err: nil},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			srv.apply(tc.fixtures...)
+			defer srv.reset()
+
+			xr, err := s.ExchangeRate.GetEffective(context.Background(), tc.namespaceID, tc.currency, tc.at)
+
+			rate := money.Rate("")
+			if xr != nil {
+				rate = xr.Rate
+			}
+
+			require.Equal(t, tc.expected, Actual{rate, err})
+		})
+	}
+}
+
+func TestExchangeRateLoad(t *testing.T) {
+	ctx := context.Background()
+
+	srv.apply(fixtureExchange)
+	defer srv.reset()
+
+	// Recreates the unique indexes dropped by previous resets.
+	_, err := store.New(ctx, db.Client(), db.Name())
+	require.NoError(t, err)
+
+	load, err := s.ExchangeRate.Load(ctx, "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", []models.ExchangeRate{
+		{Currency: "USD", Rate: "5.2", ValidFrom: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{Currency: "USD", Rate: "5.3", ValidFrom: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+	})
+	require.NoError(t, err)
+	require.Equal(t, &models.ExchangeRateLoad{Created: 1, Updated: 1}, load)
+
+	xr, err := s.ExchangeRate.Get(ctx, "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", "xr_01HXG2B2C3D4E5F6G7H8J9K0MN")
+	require.NoError(t, err)
+	require.Equal(t, money.Rate("5.2"), xr.Rate)
+
+	xr, err = s.ExchangeRate.GetEffective(ctx, "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", "USD", time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Equal(t, money.Rate("5.3"), xr.Rate)
+	require.NotEmpty(t, xr.ID)
+
+	// The rates of other namespaces are not replaced.
+	xr, err = s.ExchangeRate.Get(ctx, "ns_01HWS7Q0H1JCEMKZADAFMETRZJ", "xr_01HXG2D4E5F6G7H8J9K0MNPQRS")
+	require.NoError(t, err)
+	require.Equal(t, money.Rate("0.92"), xr.Rate)
+}
@@ -0,0 +1,294 @@
+package store
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	mongodb "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// indexes maps each collection to the indexes it requires.
+var indexes = map[string][]mongodb.IndexModel{
+	"namespace": {
+		{
+			Keys: bson.D{
+				{Key: "name", Value: "text"},
+				{Key: "members.name", Value: "text"},
+				{Key: "members.email", Value: "text"},
+			},
+			Options: options.Index().
+				SetName("namespace_search").
+				SetWeights(bson.M{"name": 10, "members.name": 2, "members.email": 1}),
+		},
+	},
+	"product": {
+		{
+			Keys:    bson.D{{Key: "namespace_id", Value: 1}, {Key: "sku", Value: 1}},
+			Options: options.Index().SetName("product_sku").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "namespace_id", Value: 1}, {Key: "category_id", Value: 1}},
+			Options: options.Index().SetName("product_category"),
+		},
+		{
+			Keys: bson.D{{Key: "namespace_id", Value: 1}, {Key: "barcode", Value: 1}},
+			Options: options.Index().
+				SetName("product_barcode").
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"barcode": bson.M{"$type": "string"}}),
+		},
+	},
+	"variant": {
+		{
+			Keys:    bson.D{{Key: "namespace_id", Value: 1}, {Key: "sku", Value: 1}},
+			Options: options.Index().SetName("variant_sku").SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "namespace_id", Value: 1}, {Key: "barcode", Value: 1}},
+			Options: options.Index().
+				SetName("variant_barcode").
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"barcode": bson.M{"$type": "string"}}),
+		},
+		{
+			Keys:    bson.D{{Key: "namespace_id", Value: 1}, {Key: "product_id", Value: 1}},
+			Options: options.Index().SetName("variant_product"),
+		},
+	},
+	"category": {
+		{
+			Keys:    bson.D{{Key: "namespace_id", Value: 1}, {Key: "parent_id", Value: 1}, {Key: "name", Value: 1}},
+			Options: options.Index().SetName("category_name").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "namespace_id", Value: 1}, {Key: "ancestors", Value: 1}},
+			Options: options.Index().SetName("category_ancestors"),
+		},
+	},
+	"warehouse": {
+		{
+			Keys:    bson.D{{Key: "namespace_id", Value: 1}, {Key: "code", Value: 1}},
+			Options: options.Index().SetName("warehouse_code").SetUnique(true),
+		},
+	},
+	"supplier": {
+		{
+			Keys:    bson.D{{Key: "namespace_id", Value: 1}, {Key: "code", Value: 1}},
+			Options: options.Index().SetName("supplier_code").SetUnique(true),
+		},
+	},
+	"purchase_order": {
+		{
+			Keys:    bson.D{{Key: "namespace_id", Value: 1}, {Key: "status", Value: 1}, {Key: "created_at", Value: -1}},
+			Options: options.Index().SetName("purchase_order_status"),
+		},
+		{
+			Keys:    bson.D{{Key: "namespace_id", Value: 1}, {Key: "supplier_id", Value: 1}, {Key: "status", Value: 1}},
+			Options: options.Index().SetName("purchase_order_supplier"),
+		},
+	},
+	"customer": {
+		{
+			Keys: bson.D{{Key: "namespace_id", Value: 1}, {Key: "email", Value: 1}},
+			Options: options.Index().
+				SetName("customer_email").
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"email": bson.M{"$type": "string"}}),
+		},
+		{
+			Keys: bson.D{{Key: "namespace_id", Value: 1}, {Key: "tax_id", Value: 1}},
+			Options: options.Index().
+				SetName("customer_tax_id").
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"tax_id": bson.M{"$type": "string"}}),
+		},
+	},
+	"sales_order": {
+		{
+			Keys:    bson.D{{Key: "namespace_id", Value: 1}, {Key: "status", Value: 1}, {Key: "created_at", Value: -1}},
+			Options: options.Index().SetName("sales_order_status"),
+		},
+		{
+			Keys:    bson.D{{Key: "namespace_id", Value: 1}, {Key: "customer_id", Value: 1}, {Key: "placed_at", Value: -1}},
+			Options: options.Index().SetName("sales_order_customer"),
+		},
+	},
+	"register": {
+		{
+			Keys:    bson.D{{Key: "namespace_id", Value: 1}, {Key: "code", Value: 1}},
+			Options: options.Index().SetName("register_code").SetUnique(true),
+		},
+	},
+	"sale": {
+		{
+			Keys:    bson.D{{Key: "namespace_id", Value: 1}, {Key: "idempotency_key", Value: 1}},
+			Options: options.Index().SetName("sale_idempotency_key").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "namespace_id", Value: 1}, {Key: "register_id", Value: 1}, {Key: "created_at", Value: -1}},
+			Options: options.Index().SetName("sale_register"),
+		},
+		{
+			Keys:    bson.D{{Key: "namespace_id", Value: 1}, {Key: "customer_id", Value: 1}, {Key: "created_at", Value: -1}},
+			Options: options.Index().SetName("sale_customer"),
+		},
+	},
+	"shift": {
+		{
+			// A register has at most one open shift, which the partial index enforces on concurrent openings.
+			Keys: bson.D{{Key: "namespace_id", Value: 1}, {Key: "register_id", Value: 1}},
+			Options: options.Index().
+				SetName("shift_open_register").
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"status": "open"}),
+		},
+		{
+			Keys:    bson.D{{Key: "namespace_id", Value: 1}, {Key: "register_id", Value: 1}, {Key: "created_at", Value: -1}},
+			Options: options.Index().SetName("shift_register"),
+		},
+	},
+	"return": {
+		{
+			Keys:    bson.D{{Key: "namespace_id", Value: 1}, {Key: "sale_id", Value: 1}},
+			Options: options.Index().SetName("return_sale"),
+		},
+	},
+	"price_list": {
+		{
+			Keys:    bson.D{{Key: "namespace_id", Value: 1}, {Key: "code", Value: 1}},
+			Options: options.Index().SetName("price_list_code").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "namespace_id", Value: 1}, {Key: "prices.product_id", Value: 1}},
+			Options: options.Index().SetName("price_list_product"),
+		},
+	},
+	"promotion": {
+		{
+			Keys: bson.D{{Key: "namespace_id", Value: 1}, {Key: "coupon", Value: 1}},
+			Options: options.Index().
+				SetName("promotion_coupon").
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"coupon": bson.M{"$gt": ""}}),
+		},
+	},
+	"tax_rate": {
+		{
+			Keys:    bson.D{{Key: "namespace_id", Value: 1}, {Key: "category", Value: 1}, {Key: "order", Value: 1}},
+			Options: options.Index().SetName("tax_rate_category"),
+		},
+	},
+	"exchange_rate": {
+		{
+			Keys:    bson.D{{Key: "namespace_id", Value: 1}, {Key: "currency", Value: 1}, {Key: "valid_from", Value: -1}},
+			Options: options.Index().SetName("exchange_rate_currency").SetUnique(true),
+		},
+	},
+	"movement": {
+		{
+			Keys:    bson.D{{Key: "namespace_id", Value: 1}, {Key: "product_id", Value: 1}, {Key: "created_at", Value: 1}},
+			Options: options.Index().SetName("movement_product"),
+		},
+		{
+			Keys:    bson.D{{Key: "namespace_id", Value: 1}, {Key: "product_id", Value: 1}, {Key: "serials", Value: 1}},
+			Options: options.Index().SetName("movement_serial"),
+		},
+		{
+			Keys:    bson.D{{Key: "namespace_id", Value: 1}, {Key: "warehouse_id", Value: 1}, {Key: "created_at", Value: 1}},
+			Options: options.Index().SetName("movement_warehouse"),
+		},
+	},
+	"balance": {
+		{
+			Keys: bson.D{
+				{Key: "namespace_id", Value: 1},
+				{Key: "warehouse_id", Value: 1},
+				{Key: "location", Value: 1},
+				{Key: "product_id", Value: 1},
+				{Key: "variant_id", Value: 1},
+			},
+			Options: options.Index().SetName("balance_key").SetUnique(true),
+		},
+	},
+	"stock_lot": {
+		{
+			Keys: bson.D{
+				{Key: "namespace_id", Value: 1},
+				{Key: "warehouse_id", Value: 1},
+				{Key: "location", Value: 1},
+				{Key: "product_id", Value: 1},
+				{Key: "variant_id", Value: 1},
+				{Key: "lot", Value: 1},
+			},
+			Options: options.Index().SetName("stock_lot_key").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "namespace_id", Value: 1}, {Key: "expires_at", Value: 1}},
+			Options: options.Index().SetName("stock_lot_expiry"),
+		},
+	},
+	"stock_serial": {
+		{
+			Keys:    bson.D{{Key: "namespace_id", Value: 1}, {Key: "product_id", Value: 1}, {Key: "serial", Value: 1}},
+			Options: options.Index().SetName("stock_serial_serial").SetUnique(true),
+		},
+	},
+	"cost_pool": {
+		{
+			Keys:    bson.D{{Key: "namespace_id", Value: 1}, {Key: "product_id", Value: 1}, {Key: "variant_id", Value: 1}},
+			Options: options.Index().SetName("cost_pool_product").SetUnique(true),
+		},
+	},
+	"cost_layer": {
+		{
+			Keys:    bson.D{{Key: "namespace_id", Value: 1}, {Key: "product_id", Value: 1}, {Key: "variant_id", Value: 1}, {Key: "created_at", Value: 1}},
+			Options: options.Index().SetName("cost_layer_product"),
+		},
+	},
+	"reservation": {
+		{
+			Keys:    bson.D{{Key: "status", Value: 1}, {Key: "expires_at", Value: 1}},
+			Options: options.Index().SetName("reservation_expiry"),
+		},
+		{
+			Keys:    bson.D{{Key: "namespace_id", Value: 1}, {Key: "reference", Value: 1}},
+			Options: options.Index().SetName("reservation_reference"),
+		},
+	},
+	"transfer": {
+		{
+			Keys:    bson.D{{Key: "namespace_id", Value: 1}, {Key: "status", Value: 1}, {Key: "created_at", Value: -1}},
+			Options: options.Index().SetName("transfer_status"),
+		},
+	},
+	"stock_count": {
+		{
+			Keys:    bson.D{{Key: "namespace_id", Value: 1}, {Key: "warehouse_id", Value: 1}, {Key: "status", Value: 1}},
+			Options: options.Index().SetName("stock_count_warehouse"),
+		},
+	},
+	"stock_level": {
+		{
+			Keys: bson.D{
+				{Key: "namespace_id", Value: 1},
+				{Key: "warehouse_id", Value: 1},
+				{Key: "product_id", Value: 1},
+				{Key: "variant_id", Value: 1},
+			},
+			Options: options.Index().SetName("stock_level_key").SetUnique(true),
+		},
+	},
+}
+
+// ensureIndexes creates all indexes in the database. Creating an index that already exists
+// with the same specification is a no-op.
+func ensureIndexes(ctx context.Context, db *mongodb.Database) error {
+	for collection, models := range indexes {
+		if _, err := db.Collection(collection).Indexes().CreateMany(ctx, models); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -1,14 +1,17 @@
 package internal
 
 import (
+	"fmt"
+	"regexp"
+
 	"github.com/heiytor/invenda/api/pkg/query"
 	"go.mongodb.org/mongo-driver/bson"
 )
 
 // FromPaginator converts the Paginator instance to a BSON pagination expression for MongoDB queries.
-// If the per-page count is less than 1, it returns nil.
+// If the per-page count is less than 1, it returns an empty expression.
 func FromPaginator(p *query.Paginator) []bson.M {
-	if p == nil {
+	if p == nil || p.Size < 1 {
 		return []bson.M{}
 	}
 
@@ -19,8 +22,14 @@ func FromPaginator(p *query.Paginator) []bson.M {
 }
 
 // FromSorter converts the Sort instance to a BSON sorting expression for MongoDB queries.
-// If an invalid value of `Sorter.By` is provided, it defaults to ascending order (OrderAsc).
+// If an invalid value of `Sorter.Order` is provided, it defaults to descending order (OrderDesc). The
+// [query.Sorter.By] must be checked against the entity's allow-list before being converted. It returns
+// an empty expression when no field is provided.
 func FromSorter(s *query.Sorter) []bson.M {
+	if s == nil || s.By == "" {
+		return []bson.M{}
+	}
+
 	options := map[string]int{
 		query.OrderAsc:  1,
 		query.OrderDesc: -1,
@@ -39,3 +48,76 @@ func FromSorter(s *query.Sorter) []bson.M {
 		},
 	}
 }
+
+// FromFilter converts the Filter instance to a BSON expression that can be used as a `$match`
+// stage or as the filter of a find/count operation. Conditions over the same field are merged.
+// The [query.Filter] must be parsed before being converted.
+func FromFilter(f *query.Filter) bson.M {
+	operators := map[query.Operator]string{
+		query.OperatorEq:  "$eq",
+		query.OperatorNe:  "$ne",
+		query.OperatorGt:  "$gt",
+		query.OperatorGte: "$gte",
+		query.OperatorLt:  "$lt",
+		query.OperatorLte: "$lte",
+		query.OperatorIn:  "$in",
+	}
+
+	match := bson.M{}
+	if f == nil {
+		return match
+	}
+
+	for _, c := range f.Conditions {
+		expr, ok := match[c.Field].(bson.M)
+		if !ok {
+			expr = bson.M{}
+			match[c.Field] = expr
+		}
+
+		switch c.Operator {
+		case query.OperatorContains:
+			expr["$regex"] = regexp.QuoteMeta(fmt.Sprint(c.Value))
+			expr["$options"] = "i"
+		default:
+			expr[operators[c.Operator]] = c.Value
+		}
+	}
+
+	return match
+}
+
+// FromTextSearch converts a search term to a BSON `$text` expression. The collection must have a
+// text index and the expression must be used in the first `$match` stage of the pipeline.
+func FromTextSearch(term string) bson.M {
+	return bson.M{"$text": bson.M{"$search": term}}
+}
+
+// FromPrefixSearch converts a search term to a BSON expression that matches documents where any of
+// the fields starts with the term, ignoring case.
+func FromPrefixSearch(term string, fields ...string) bson.M {
+	conditions := make([]bson.M, 0, len(fields))
+	for _, f := range fields {
+		conditions = append(conditions, bson.M{f: bson.M{"$regex": "^" + regexp.QuoteMeta(term), "$options": "i"}})
+	}
+
+	return bson.M{"$or": conditions}
+}
+
+// FromRelevance converts the Sort instance to a BSON sorting expression that orders the documents
+// by their text search score first, using the sorter as a tiebreaker.
+func FromRelevance(s *query.Sorter) []bson.M {
+	order := -1
+	if s.Order == query.OrderAsc {
+		order = 1
+	}
+
+	return []bson.M{
+		{
+			"$sort": bson.D{
+				{Key: "score", Value: bson.M{"$meta": "textScore"}},
+				{Key: s.By, Value: order},
+			},
+		},
+	}
+}
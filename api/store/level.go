@@ -0,0 +1,188 @@
+package store
+
+import (
+	"context"
+
+	"github.com/heiytor/invenda/api/pkg/clock"
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/query"
+	"github.com/heiytor/invenda/api/store/internal"
+	"github.com/oklog/ulid/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// StockLevel handles the replenishment thresholds of the namespace's items. Every operation is scoped to
+// a namespace ID.
+type StockLevel interface {
+	Entity
+
+	// GetMany retrieves a list of stock levels of a namespace. It returns the list of stock levels, the total
+	// count of the existent documents and an error if any.
+	GetMany(ctx context.Context, namespaceID string, query *query.Query) (levels []models.StockLevel, count int64, err error)
+
+	// Set creates or replaces the thresholds of the level's warehouse, product and variant. It returns the
+	// level's ID or an error if any.
+	Set(ctx context.Context, level *models.StockLevel) (id string, err error)
+
+	// Delete deletes a stock level with the specified ID. It returns [ErrNotFound] if no stock level is found.
+	Delete(ctx context.Context, namespaceID, id string) (err error)
+
+	// Suggestions computes the items whose stock position, on hand plus in transit, is at or below their
+	// reorder point, grouped by the products' preferred supplier. The query filters and sorts the lines,
+	// while the pagination applies to the groups. It returns the suggestions, the total count of groups
+	// and an error if any.
+	Suggestions(ctx context.Context, namespaceID string, query *query.Query) (suggestions []models.ReorderSuggestion, count int64, err error)
+}
+
+type stockLevel struct {
+	c *mongo.Collection // c is the "stock_level" collection
+}
+
+var _ StockLevel = (*stockLevel)(nil)
+
+func (*stockLevel) Entity() string {
+	return "stock_level"
+}
+
+func (sl *stockLevel) GetMany(ctx context.Context, namespaceID string, query *query.Query) ([]models.StockLevel, int64, error) {
+	levels := make([]models.StockLevel, 0)
+	count, err := find(ctx, sl.c, namespaceID, query, &levels)
+
+	return levels, count, err
+}
+
+func (sl *stockLevel) Set(ctx context.Context, level *models.StockLevel) (string, error) {
+	now := clock.Now()
+
+	filter := bson.M{
+		"namespace_id": level.NamespaceID,
+		"warehouse_id": level.WarehouseID,
+		"product_id":   level.ProductID,
+		"variant_id":   level.VariantID,
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"updated_at":    now,
+			"min":           level.Min,
+			"max":           level.Max,
+			"reorder_point": level.ReorderPoint,
+		},
+		"$setOnInsert": bson.M{"_id": "lvl_" + ulid.Make().String(), "created_at": now},
+	}
+
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	if err := sl.c.FindOneAndUpdate(ctx, filter, update, opts).Decode(level); err != nil {
+		return "", mapError(err)
+	}
+
+	return level.ID, nil
+}
+
+func (sl *stockLevel) Delete(ctx context.Context, namespaceID, id string) error {
+	res, err := sl.c.DeleteOne(ctx, bson.M{"_id": id, "namespace_id": namespaceID})
+	if err != nil {
+		return mapError(err)
+	}
+
+	if res.DeletedCount < 1 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (sl *stockLevel) Suggestions(ctx context.Context, namespaceID string, query *query.Query) ([]models.ReorderSuggestion, int64, error) {
+	pipeline := []bson.M{
+		{"$match": bson.M{"namespace_id": namespaceID}},
+		{
+			"$lookup": bson.M{
+				"from": "balance",
+				"let":  bson.M{"w": "$warehouse_id", "p": "$product_id", "v": "$variant_id"},
+				"pipeline": []bson.M{
+					{
+						"$match": bson.M{
+							"namespace_id": namespaceID,
+							"$expr": bson.M{
+								"$and": []bson.M{
+									{"$eq": []string{"$warehouse_id", "$$w"}},
+									{"$eq": []string{"$product_id", "$$p"}},
+									{"$eq": []string{"$variant_id", "$$v"}},
+								},
+							},
+						},
+					},
+					{"$group": bson.M{"_id": nil, "on_hand": bson.M{"$sum": "$on_hand"}, "in_transit": bson.M{"$sum": "$in_transit"}}},
+				},
+				"as": "stock",
+			},
+		},
+		{"$lookup": bson.M{"from": "product", "localField": "product_id", "foreignField": "_id", "as": "product"}},
+		{"$lookup": bson.M{"from": "variant", "localField": "variant_id", "foreignField": "_id", "as": "variant"}},
+		{
+			"$set": bson.M{
+				"on_hand":     bson.M{"$ifNull": []interface{}{bson.M{"$first": "$stock.on_hand"}, 0}},
+				"in_transit":  bson.M{"$ifNull": []interface{}{bson.M{"$first": "$stock.in_transit"}, 0}},
+				"sku":         bson.M{"$ifNull": []interface{}{bson.M{"$first": "$variant.sku"}, bson.M{"$first": "$product.sku"}}},
+				"name":        bson.M{"$first": "$product.name"},
+				"supplier_id": bson.M{"$ifNull": []interface{}{bson.M{"$first": "$product.preferred_supplier_id"}, ""}},
+			},
+		},
+		{"$set": bson.M{"position": bson.M{"$add": []string{"$on_hand", "$in_transit"}}}},
+		{"$match": bson.M{"$expr": bson.M{"$lte": []string{"$position", "$reorder_point"}}}},
+		{"$set": bson.M{"quantity": bson.M{"$subtract": []string{"$max", "$position"}}}},
+		{"$project": bson.M{"stock": 0, "product": 0, "variant": 0}},
+		{"$match": internal.FromFilter(&query.Filter)},
+	}
+
+	// A $facet sub-pipeline cannot be empty, so unpaginated queries match every group.
+	page := internal.FromPaginator(&query.Paginator)
+	if len(page) == 0 {
+		page = []bson.M{{"$match": bson.M{}}}
+	}
+
+	pipeline = append(pipeline, internal.FromSorter(&query.Sorter)...)
+	pipeline = append(pipeline,
+		bson.M{"$group": bson.M{"_id": "$supplier_id", "quantity": bson.M{"$sum": "$quantity"}, "lines": bson.M{"$push": "$$ROOT"}}},
+		bson.M{"$sort": bson.M{"_id": 1}},
+		bson.M{
+			"$facet": bson.M{
+				"count": []bson.M{{"$count": "count"}},
+				"data":  page,
+			},
+		},
+	)
+
+	cursor, err := sl.c.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, 0, mapError(err)
+	}
+	defer cursor.Close(ctx)
+
+	result := struct {
+		Count []struct {
+			Count int64 `bson:"count"`
+		} `bson:"count"`
+		Data []models.ReorderSuggestion `bson:"data"`
+	}{}
+
+	if cursor.Next(ctx) {
+		if err := cursor.Decode(&result); err != nil {
+			return nil, 0, mapError(err)
+		}
+	}
+
+	suggestions := result.Data
+	if suggestions == nil {
+		suggestions = []models.ReorderSuggestion{}
+	}
+
+	var count int64
+	if len(result.Count) > 0 {
+		count = result.Count[0].Count
+	}
+
+	return suggestions, count, nil
+}
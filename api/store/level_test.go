@@ -0,0 +1,95 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/query"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStockLevelSuggestions(t *testing.T) {
+	type Actual struct {
+		suggestions []models.ReorderSuggestion
+		count       int64
+		err         error
+	}
+
+	// Cola has 6 on hand against a reorder point of 8, while the water has no stock but a reorder
+	// point of zero; both must be reordered.
+	cola := models.ReorderLine{
+		WarehouseID:  "wh_01HX5C1A2B3C4D5E6F7G8H9J0K",
+		ProductID:    "prd_01HX3A1B2C3D4E5F6G7H8J9K0M",
+		SKU:          "COLA-350",
+		Name:         "Cola 350ml",
+		OnHand:       6,
+		Min:          2,
+		Max:          20,
+		ReorderPoint: 8,
+		Position:     6,
+		Quantity:     14,
+	}
+
+	water := models.ReorderLine{
+		WarehouseID:  "wh_01HX5C1A2B3C4D5E6F7G8H9J0K",
+		ProductID:    "prd_01HX3A2N3P4Q5R6S7T8V9W0X1Y",
+		SKU:          "WATER-500",
+		Name:         "Mineral water 500ml",
+		Max:          10,
+		ReorderPoint: 0,
+		Quantity:     10,
+	}
+
+	cases := []struct {
+		description string
+		query       *query.Query
+		fixtures    []fixture
+		expected    Actual
+	}{
+		{
+			description: "succeeds when there are no stock levels",
+			query:       &query.Query{Paginator: query.Paginator{Page: 1, Size: 10}},
+			fixtures:    []fixture{},
+			expected:    Actual{suggestions: []models.ReorderSuggestion{}, count: 0, err: nil},
+		},
+		{
+			description: "succeeds to group the lines by supplier",
+			query: &query.Query{
+				Paginator: query.Paginator{Page: 1, Size: 10},
+				Sorter:    query.Sorter{By: "quantity", Order: query.OrderDesc},
+			},
+			fixtures: []fixture{fixtureProduct, fixtureStock, fixtureLevel},
+			expected: Actual{
+				suggestions: []models.ReorderSuggestion{{SupplierID: "", Quantity: 24, Lines: []models.ReorderLine{cola, water}}},
+				count:       1,
+				err:         nil,
+			},
+		},
+		{
+			description: "succeeds to filter the lines",
+			query: &query.Query{
+				Paginator: query.Paginator{Page: 1, Size: 10},
+				Filter:    query.Filter{Conditions: []query.Condition{{Field: "sku", Operator: query.OperatorEq, Value: "WATER-500"}}},
+			},
+			fixtures: []fixture{fixtureProduct, fixtureStock, fixtureLevel},
+			expected: Actual{
+				suggestions: []models.ReorderSuggestion{{SupplierID: "", Quantity: 10, Lines: []models.ReorderLine{water}}},
+				count:       1,
+				err:         nil,
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			srv.apply(tc.fixtures...)
+			defer srv.reset()
+
+			ctx := context.Background()
+
+			suggestions, count, err := s.StockLevel.Suggestions(ctx, "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", tc.query)
+			require.Equal(t, tc.expected, Actual{suggestions, count, err})
+		})
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/rs/zerolog/log"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 // GetNamespaceOption is a function to be evaluated when retrieving a namespace document to modify its content.
@@ -55,6 +56,14 @@ type Namespace interface {
 	// RemoveMember removes a member with the specified memberID from the namespace with the specified id.
 	// It returns [ErrNotFound] if no namespace or member is found.
 	RemoveMember(ctx context.Context, id, memberID string) (err error)
+
+	// UpdateMemberProfile updates the name and email of the member with the specified memberID in every
+	// namespace it belongs to. Empty values are ignored.
+	UpdateMemberProfile(ctx context.Context, memberID, name, email string) (err error)
+
+	// SyncMemberProfiles copies the name and email of the users into the members that lack them, such as
+	// the members added before the profiles were copied into namespaces. It is safe to call more than once.
+	SyncMemberProfiles(ctx context.Context) (err error)
 }
 
 type namespace struct {
@@ -98,7 +107,35 @@ func (n *namespace) GetFirst(ctx context.Context, memberID string, opts ...GetNa
 }
 
 func (n *namespace) GetMany(ctx context.Context, userID string, query *query.Query, opts ...GetNamespaceOption) ([]models.Namespace, int64, error) {
-	match := bson.M{"members": bson.M{"$elemMatch": bson.M{"_id": userID}}}
+	match := bson.M{
+		"$and": []bson.M{
+			{"members": bson.M{"$elemMatch": bson.M{"_id": userID}}},
+			internal.FromFilter(&query.Filter),
+		},
+	}
+
+	sort := internal.FromSorter(&query.Sorter)
+
+	// When searching, the text index is used first so the results can be ordered by relevance. As it
+	// only matches whole words, a case-insensitive prefix match is used when no document is found.
+	if query.Search != "" {
+		text := bson.M{"$and": match["$and"]}
+		for k, v := range internal.FromTextSearch(query.Search) {
+			text[k] = v
+		}
+
+		count, err := n.c.CountDocuments(ctx, text)
+		if err != nil {
+			return nil, 0, mapError(err)
+		}
+
+		if count > 0 {
+			match = text
+			sort = internal.FromRelevance(&query.Sorter)
+		} else {
+			match["$and"] = append(match["$and"].([]bson.M), internal.FromPrefixSearch(query.Search, "name", "members.name", "members.email"))
+		}
+	}
 
 	count, err := n.c.CountDocuments(ctx, match)
 	if err != nil {
@@ -107,8 +144,8 @@ func (n *namespace) GetMany(ctx context.Context, userID string, query *query.Que
 
 	pipeline := make([]bson.M, 0)
 	pipeline = append(pipeline, bson.M{"$match": match})
+	pipeline = append(pipeline, sort...)
 	pipeline = append(pipeline, internal.FromPaginator(&query.Paginator)...)
-	pipeline = append(pipeline, internal.FromSorter(&query.Sorter)...)
 
 	cursor, err := n.c.Aggregate(ctx, pipeline)
 	if err != nil {
@@ -222,3 +259,57 @@ func (n *namespace) RemoveMember(ctx context.Context, id, memberID string) error
 
 	return nil
 }
+
+func (n *namespace) UpdateMemberProfile(ctx context.Context, memberID, name, email string) error {
+	set := bson.M{}
+	if name != "" {
+		set["members.$[m].name"] = name
+	}
+
+	if email != "" {
+		set["members.$[m].email"] = email
+	}
+
+	if len(set) == 0 {
+		return nil
+	}
+
+	opts := options.Update().SetArrayFilters(options.ArrayFilters{Filters: []interface{}{bson.M{"m._id": memberID}}})
+	if _, err := n.c.UpdateMany(ctx, bson.M{"members._id": memberID}, bson.M{"$set": set}, opts); err != nil {
+		return mapError(err)
+	}
+
+	return nil
+}
+
+func (n *namespace) SyncMemberProfiles(ctx context.Context) error {
+	missing := bson.M{"$or": []bson.M{{"name": bson.M{"$exists": false}}, {"email": bson.M{"$exists": false}}}}
+
+	ids, err := n.c.Distinct(ctx, "members._id", bson.M{"members": bson.M{"$elemMatch": missing}})
+	if err != nil {
+		return mapError(err)
+	}
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	opts := options.Find().SetProjection(bson.M{"name": 1, "email": 1})
+	cursor, err := n.c.Database().Collection("user").Find(ctx, bson.M{"_id": bson.M{"$in": ids}}, opts)
+	if err != nil {
+		return mapError(err)
+	}
+
+	users := make([]models.User, 0)
+	if err := cursor.All(ctx, &users); err != nil {
+		return mapError(err)
+	}
+
+	for _, u := range users {
+		if err := n.UpdateMemberProfile(ctx, u.ID, u.Name, u.Email); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -313,6 +313,84 @@ func TestNamespaceGetMany(t *testing.T) {
 	}
 }
 
+func TestNamespaceGetManySearch(t *testing.T) {
+	type Actual struct {
+		ids   []string
+		count int64
+		err   error
+	}
+
+	cases := []struct {
+		description string
+		userID      string
+		query       *query.Query
+		expected    Actual
+	}{
+		{
+			description: "succeeds to search by the namespace name",
+			userID:      "usr_01HNGJ2BTGQAHAZ1XNYZQPG719",
+			query:       &query.Query{Sorter: query.Sorter{By: "created_at", Order: query.OrderDesc}, Search: "supplies"},
+			expected: Actual{
+				ids:   []string{"ns_01HX2Q8M6Y4T3K9V7R5N1B2C3D"},
+				count: 1,
+				err:   nil,
+			},
+		},
+		{
+			description: "succeeds to search by the members' names ordering by relevance",
+			userID:      "usr_01HNGJ2BTGQAHAZ1XNYZQPG719",
+			query:       &query.Query{Sorter: query.Sorter{By: "created_at", Order: query.OrderDesc}, Search: "acme"},
+			expected: Actual{
+				ids:   []string{"ns_01HX2Q8M6Y4T3K9V7R5N1B2C3D", "ns_01HX2Q9F0A7W2E4R6T8Y1U3I5O"},
+				count: 2,
+				err:   nil,
+			},
+		},
+		{
+			description: "succeeds to search by prefix when no word matches",
+			userID:      "usr_01HNGJ2BTGQAHAZ1XNYZQPG719",
+			query:       &query.Query{Sorter: query.Sorter{By: "created_at", Order: query.OrderDesc}, Search: "KAN"},
+			expected: Actual{
+				ids:   []string{"ns_01HX2Q9F0A7W2E4R6T8Y1U3I5O"},
+				count: 1,
+				err:   nil,
+			},
+		},
+		{
+			description: "succeeds to search only namespaces where the user is a member",
+			userID:      "usr_01HNGJ2BTGQAHAZ1XNYZQPG719",
+			query:       &query.Query{Sorter: query.Sorter{By: "created_at", Order: query.OrderDesc}, Search: "outlet"},
+			expected: Actual{
+				ids:   []string{},
+				count: 0,
+				err:   nil,
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			srv.apply(fixtureNamespaceSearch)
+			defer srv.reset()
+
+			ctx := context.Background()
+
+			// Recreates the text index dropped by previous resets.
+			_, err := store.New(ctx, db.Client(), db.Name())
+			require.NoError(t, err)
+
+			namespaces, count, err := s.Namespace.GetMany(ctx, tc.userID, tc.query)
+
+			ids := make([]string, 0)
+			for _, ns := range namespaces {
+				ids = append(ids, ns.ID)
+			}
+
+			require.Equal(t, tc.expected, Actual{ids, count, err})
+		})
+	}
+}
+
 func TestNamespaceCreate(t *testing.T) {
 	type Actual struct {
 		err error
@@ -565,3 +643,37 @@ func TestNamespaceRemoveMember(t *testing.T) {
 		})
 	}
 }
+
+func TestNamespaceSyncMemberProfiles(t *testing.T) {
+	srv.apply(fixtureNamespace)
+	defer srv.reset()
+
+	ctx := context.Background()
+
+	// The namespaces' members were added before their profiles were copied.
+	_, err := db.Collection("user").InsertOne(ctx, bson.M{
+		"_id":   "usr_01HNGJ2BTGQAHAZ1XNYZQPG719",
+		"name":  "Maria Souza",
+		"email": "maria.souza@test.com",
+	})
+	require.NoError(t, err)
+
+	// Syncs the profiles and recreates the text index dropped by previous resets.
+	_, err = store.New(ctx, db.Client(), db.Name())
+	require.NoError(t, err)
+
+	for _, search := range []string{"maria", "maria.souza@test.com"} {
+		q := &query.Query{Sorter: query.Sorter{By: "created_at", Order: query.OrderDesc}, Search: search}
+
+		namespaces, count, err := s.Namespace.GetMany(ctx, "usr_01HNGJ2BTGQAHAZ1XNYZQPG719", q)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), count, search)
+
+		for _, ns := range namespaces {
+			require.Equal(t, "Maria Souza", ns.Members[0].Name)
+			require.Equal(t, "maria.souza@test.com", ns.Members[0].Email)
+		}
+	}
+
+	require.NoError(t, s.Namespace.SyncMemberProfiles(ctx))
+}
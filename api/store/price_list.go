@@ -0,0 +1,206 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/heiytor/invenda/api/pkg/clock"
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/query"
+	"github.com/heiytor/invenda/api/store/internal"
+	"github.com/oklog/ulid/v2"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// PriceList handles the namespace's price lists. Every operation is scoped to a namespace ID.
+type PriceList interface {
+	Entity
+
+	// Get retrieves a price list with the specified ID. It returns the price list or an error if any.
+	Get(ctx context.Context, namespaceID, id string) (priceList *models.PriceList, err error)
+
+	// GetMany retrieves a list of price lists of a namespace. It returns the list of price lists, the total
+	// count of the existent documents and an error if any.
+	GetMany(ctx context.Context, namespaceID string, query *query.Query) (priceLists []models.PriceList, count int64, err error)
+
+	// GetApplicable retrieves the active price lists of a namespace that price a product for the customers
+	// of a group at a time. Lists without groups apply to every group. It returns the lists or an error if
+	// any.
+	GetApplicable(ctx context.Context, namespaceID, productID, group string, at time.Time) (priceLists []models.PriceList, err error)
+
+	// Conflicts reports whether the non-zero fields of the provided target already exist in the namespace.
+	// It returns a list of conflicted fields or an error if any.
+	Conflicts(ctx context.Context, namespaceID string, target *models.PriceList) (conflicts []string, err error)
+
+	// Create creates a new price list with the provided data. It returns the inserted ID or an error
+	// if any.
+	Create(ctx context.Context, priceList *models.PriceList) (insertedID string, err error)
+
+	// Update updates a price list with the specified changes and ID. It returns [ErrNotFound] if no price list
+	// is found.
+	Update(ctx context.Context, namespaceID, id string, changes *models.PriceListChanges) (err error)
+
+	// Delete deletes a price list with the specified ID. It returns [ErrNotFound] if no price list is found.
+	Delete(ctx context.Context, namespaceID, id string) (err error)
+}
+
+type priceList struct {
+	c *mongo.Collection // c is the "price_list" collection
+}
+
+var _ PriceList = (*priceList)(nil)
+
+func (*priceList) Entity() string {
+	return "price_list"
+}
+
+func (pl *priceList) Get(ctx context.Context, namespaceID, id string) (*models.PriceList, error) {
+	prl := new(models.PriceList)
+	if err := pl.c.FindOne(ctx, bson.M{"_id": id, "namespace_id": namespaceID}).Decode(prl); err != nil {
+		return nil, mapError(err)
+	}
+
+	return prl, nil
+}
+
+func (pl *priceList) GetMany(ctx context.Context, namespaceID string, query *query.Query) ([]models.PriceList, int64, error) {
+	conditions := []bson.M{
+		{"namespace_id": namespaceID},
+		internal.FromFilter(&query.Filter),
+	}
+
+	if query.Search != "" {
+		conditions = append(conditions, internal.FromPrefixSearch(query.Search, "code", "name"))
+	}
+
+	match := bson.M{"$and": conditions}
+
+	count, err := pl.c.CountDocuments(ctx, match)
+	if err != nil {
+		log.Error().Err(err).Msg("unable to count the total documents")
+	}
+
+	pipeline := make([]bson.M, 0)
+	pipeline = append(pipeline, bson.M{"$match": match})
+	pipeline = append(pipeline, internal.FromSorter(&query.Sorter)...)
+	pipeline = append(pipeline, internal.FromPaginator(&query.Paginator)...)
+
+	cursor, err := pl.c.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, 0, mapError(err)
+	}
+	defer cursor.Close(ctx)
+
+	priceLists := make([]models.PriceList, 0)
+	if err := cursor.All(ctx, &priceLists); err != nil {
+		return nil, 0, mapError(err)
+	}
+
+	return priceLists, count, nil
+}
+
+func (pl *priceList) GetApplicable(ctx context.Context, namespaceID, productID, group string, at time.Time) ([]models.PriceList, error) {
+	filter := bson.M{
+		"namespace_id":      namespaceID,
+		"active":            true,
+		"prices.product_id": productID,
+		"$and": []bson.M{
+			{"$or": []bson.M{{"groups": bson.M{"$size": 0}}, {"groups": group}}},
+			{"$or": []bson.M{{"valid_from": nil}, {"valid_from": bson.M{"$lte": at}}}},
+			{"$or": []bson.M{{"valid_until": nil}, {"valid_until": bson.M{"$gt": at}}}},
+		},
+	}
+
+	cursor, err := pl.c.Find(ctx, filter, options.Find().SetSort(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, mapError(err)
+	}
+	defer cursor.Close(ctx)
+
+	priceLists := make([]models.PriceList, 0)
+	if err := cursor.All(ctx, &priceLists); err != nil {
+		return nil, mapError(err)
+	}
+
+	return priceLists, nil
+}
+
+func (pl *priceList) Conflicts(ctx context.Context, namespaceID string, target *models.PriceList) ([]string, error) {
+	pipeline := append([]bson.M{{"$match": bson.M{"namespace_id": namespaceID}}}, or(target)...)
+
+	cursor, err := pl.c.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	defer cursor.Close(ctx)
+
+	conflicts := make([]string, 0)
+	for cursor.Next(ctx) {
+		prl := new(models.PriceList)
+
+		if err = cursor.Decode(prl); err != nil {
+			return nil, mapError(err)
+		}
+
+		conflicts = append(conflicts, partialEqual(target, prl)...)
+	}
+
+	return conflicts, nil
+}
+
+func (pl *priceList) Create(ctx context.Context, prl *models.PriceList) (string, error) {
+	prl.ID = "prl_" + ulid.Make().String()
+
+	now := clock.Now()
+	prl.CreatedAt = now
+	prl.UpdatedAt = now
+
+	if prl.Groups == nil {
+		prl.Groups = []string{}
+	}
+
+	if prl.Prices == nil {
+		prl.Prices = []models.PriceListItem{}
+	}
+
+	if _, err := pl.c.InsertOne(ctx, prl); err != nil {
+		return "", mapError(err)
+	}
+
+	return prl.ID, nil
+}
+
+func (pl *priceList) Update(ctx context.Context, namespaceID, id string, changes *models.PriceListChanges) error {
+	if changes == nil {
+		return nil
+	}
+
+	changes.UpdatedAt = clock.Now()
+
+	res, err := pl.c.UpdateOne(ctx, bson.M{"_id": id, "namespace_id": namespaceID}, bson.M{"$set": changes})
+	if err != nil {
+		return mapError(err)
+	}
+
+	if res.MatchedCount < 1 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (pl *priceList) Delete(ctx context.Context, namespaceID, id string) error {
+	res, err := pl.c.DeleteOne(ctx, bson.M{"_id": id, "namespace_id": namespaceID})
+	if err != nil {
+		return mapError(err)
+	}
+
+	if res.DeletedCount < 1 {
+		return ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,133 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriceListGetApplicable(t *testing.T) {
+	type Actual struct {
+		ids []string
+		err error
+	}
+
+	cases := []struct {
+		description string
+		namespaceID string
+		productID   string
+		group       string
+		at          time.Time
+		fixtures    []fixture
+		expected    Actual
+	}{
+		{
+			description: "succeeds with no lists for an unlisted product",
+			namespaceID: "ns_01HV7FKH5SRB0TGWM7MQ15PYKN",
+			productID:   "prd_00000000000000000000000000",
+			group:       "",
+			at:          time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+			fixtures:    []fixture{fixturePriceList},
+			expected:    Actual{ids: []string{}, err: nil},
+		},
+		{
+			description: "succeeds to find the lists without groups for anonymous customers",
+			namespaceID: "ns_01HV7FKH5SRB0TGWM7MQ15PYKN",
+			productID:   "prd_01HX3A1B2C3D4E5F6G7H8J9K0M",
+			group:       "",
+			at:          time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+			fixtures:    []fixture{fixturePriceList},
+			expected:    Actual{ids: []string{"prl_01HXD1A2B3C4D5E6F7G8H9J0KM"}, err: nil},
+		},
+		{
+			description: "succeeds to find the lists of a group while they are valid",
+			namespaceID: "ns_01HV7FKH5SRB0TGWM7MQ15PYKN",
+			productID:   "prd_01HX3A1B2C3D4E5F6G7H8J9K0M",
+			group:       "wholesale",
+			at:          time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+			fixtures:    []fixture{fixturePriceList},
+			expected:    Actual{ids: []string{"prl_01HXD1A2B3C4D5E6F7G8H9J0KM", "prl_01HXD1B2C3D4E5F6G7H8J9K0MN"}, err: nil},
+		},
+		{
+			description: "succeeds to ignore the lists of a group once they expire",
+			namespaceID: "ns_01HV7FKH5SRB0TGWM7MQ15PYKN",
+			productID:   "prd_01HX3A1B2C3D4E5F6G7H8J9K0M",
+			group:       "wholesale",
+			at:          time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC),
+			fixtures:    []fixture{fixturePriceList},
+			expected:    Actual{ids: []string{"prl_01HXD1A2B3C4D5E6F7G8H9J0KM"}, err: nil},
+		},
+		{
+			description: "succeeds to ignore inactive lists",
+			namespaceID: "ns_01HV7FKH5SRB0TGWM7MQ15PYKN",
+			productID:   "prd_01HX3A1B2C3D4E5F6G7H8J9K0M",
+			group:       "vip",
+			at:          time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+			fixtures:    []fixture{fixturePriceList},
+			expected:    Actual{ids: []string{"prl_01HXD1A2B3C4D5E6F7G8H9J0KM"}, err: nil},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			srv.apply(tc.fixtures...)
+			defer srv.reset()
+
+			ctx := context.Background()
+
+			lists, err := s.PriceList.GetApplicable(ctx, tc.namespaceID, tc.productID, tc.group, tc.at)
+
+			ids := make([]string, 0, len(lists))
+			for _, pl := range lists {
+				ids = append(ids, pl.ID)
+			}
+
+			require.Equal(t, tc.expected, Actual{ids, err})
+		})
+	}
+}
+
+func TestPriceListConflicts(t *testing.T) {
+	type Actual struct {
+		conflicts []string
+		err       error
+	}
+
+	cases := []struct {
+		description string
+		namespaceID string
+		target      *models.PriceList
+		fixtures    []fixture
+		expected    Actual
+	}{
+		{
+			description: "succeeds when none conflicts are found",
+			namespaceID: "ns_01HV7FKH5SRB0TGWM7MQ15PYKN",
+			target:      &models.PriceList{Code: "EMPLOYEE"},
+			fixtures:    []fixture{fixturePriceList},
+			expected:    Actual{conflicts: []string{}, err: nil},
+		},
+		{
+			description: "succeeds when a conflict is found",
+			namespaceID: "ns_01HV7FKH5SRB0TGWM7MQ15PYKN",
+			target:      &models.PriceList{Code: "WHOLESALE"},
+			fixtures:    []fixture{fixturePriceList},
+			expected:    Actual{conflicts: []string{"code"}, err: nil},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			srv.apply(tc.fixtures...)
+			defer srv.reset()
+
+			ctx := context.Background()
+
+			conflicts, err := s.PriceList.Conflicts(ctx, tc.namespaceID, tc.target)
+			require.Equal(t, tc.expected, Actual{conflicts, err})
+		})
+	}
+}
@@ -0,0 +1,264 @@
+package store
+
+import (
+	"context"
+
+	"github.com/heiytor/invenda/api/pkg/clock"
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/query"
+	"github.com/heiytor/invenda/api/store/internal"
+	"github.com/oklog/ulid/v2"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// GetProductOption is a function to be evaluated when retrieving a product document to modify its content.
+type GetProductOption func(p *models.Product) error
+
+// Product handles the namespace's catalog. Every operation is scoped to a namespace ID and a product
+// from another namespace is reported as not found.
+type Product interface {
+	Entity
+
+	// Get retrieves a product with the specified ID. It returns the product or an error if any.
+	Get(ctx context.Context, namespaceID, id string, opts ...GetProductOption) (product *models.Product, err error)
+
+	// GetByBarcode retrieves the product with the specified barcode. It returns the product or an error if any.
+	GetByBarcode(ctx context.Context, namespaceID, barcode string) (product *models.Product, err error)
+
+	// GetMany retrieves a list of products of a namespace. The set of options will be applied to each retrieved
+	// product. It returns the list of products, the total count of the existent documents and an error if any.
+	GetMany(ctx context.Context, namespaceID string, query *query.Query, opts ...GetProductOption) (products []models.Product, count int64, err error)
+
+	// Conflicts reports whether the non-zero fields of the provided target already exist in the namespace.
+	// It returns a list of conflicted fields or an error if any.
+	Conflicts(ctx context.Context, namespaceID string, target *models.Product) (conflicts []string, err error)
+
+	// Create creates a new product with the provided data. It returns the inserted ID or an error
+	// if any.
+	Create(ctx context.Context, product *models.Product) (insertedID string, err error)
+
+	// Update updates a product with the specified changes and ID. It returns [ErrNotFound] if no product is found.
+	Update(ctx context.Context, namespaceID, id string, changes *models.ProductChanges) (err error)
+
+	// Delete deletes a product with the specified ID. It returns [ErrNotFound] if no product is found.
+	Delete(ctx context.Context, namespaceID, id string) (err error)
+
+	// AssignCategory assigns the products with the specified IDs to a category. It returns the number of
+	// matched products or an error if any.
+	AssignCategory(ctx context.Context, namespaceID, categoryID string, ids []string) (count int64, err error)
+
+	// AddIncoming increments the quantity of a product expected from purchase orders by delta. It returns
+	// [ErrNotFound] if no product is found.
+	AddIncoming(ctx context.Context, namespaceID, id string, delta int64) (err error)
+
+	// UnassignCategory removes the products with the specified IDs from a category. When ids is nil, every
+	// product of the category is removed. It returns the number of matched products or an error if any.
+	UnassignCategory(ctx context.Context, namespaceID, categoryID string, ids []string) (count int64, err error)
+}
+
+type product struct {
+	c *mongo.Collection // c is the "product" collection
+}
+
+var _ Product = (*product)(nil)
+
+func (*product) Entity() string {
+	return "product"
+}
+
+func (p *product) Get(ctx context.Context, namespaceID, id string, opts ...GetProductOption) (*models.Product, error) {
+	prd := new(models.Product)
+	if err := p.c.FindOne(ctx, bson.M{"_id": id, "namespace_id": namespaceID}).Decode(prd); err != nil {
+		return nil, mapError(err)
+	}
+
+	for _, opt := range opts {
+		if err := opt(prd); err != nil {
+			return nil, err
+		}
+	}
+
+	return prd, nil
+}
+
+func (p *product) GetByBarcode(ctx context.Context, namespaceID, barcode string) (*models.Product, error) {
+	prd := new(models.Product)
+	if err := p.c.FindOne(ctx, bson.M{"namespace_id": namespaceID, "barcode": barcode}).Decode(prd); err != nil {
+		return nil, mapError(err)
+	}
+
+	return prd, nil
+}
+
+func (p *product) GetMany(ctx context.Context, namespaceID string, query *query.Query, opts ...GetProductOption) ([]models.Product, int64, error) {
+	conditions := []bson.M{
+		{"namespace_id": namespaceID},
+		internal.FromFilter(&query.Filter),
+	}
+
+	if query.Search != "" {
+		conditions = append(conditions, internal.FromPrefixSearch(query.Search, "sku", "name", "barcode"))
+	}
+
+	match := bson.M{"$and": conditions}
+
+	count, err := p.c.CountDocuments(ctx, match)
+	if err != nil {
+		log.Error().Err(err).Msg("unable to count the total documents")
+	}
+
+	pipeline := make([]bson.M, 0)
+	pipeline = append(pipeline, bson.M{"$match": match})
+	pipeline = append(pipeline, internal.FromSorter(&query.Sorter)...)
+	pipeline = append(pipeline, internal.FromPaginator(&query.Paginator)...)
+
+	cursor, err := p.c.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, 0, mapError(err)
+	}
+	defer cursor.Close(ctx)
+
+	products := make([]models.Product, 0)
+	for cursor.Next(ctx) {
+		prd := new(models.Product)
+		if err := cursor.Decode(prd); err != nil {
+			return nil, 0, mapError(err)
+		}
+
+		for _, opt := range opts {
+			if err := opt(prd); err != nil {
+				return nil, 0, err
+			}
+		}
+
+		products = append(products, *prd)
+	}
+
+	return products, count, nil
+}
+
+func (p *product) Conflicts(ctx context.Context, namespaceID string, target *models.Product) ([]string, error) {
+	pipeline := append([]bson.M{{"$match": bson.M{"namespace_id": namespaceID}}}, or(target)...)
+
+	cursor, err := p.c.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	defer cursor.Close(ctx)
+
+	conflicts := make([]string, 0)
+	for cursor.Next(ctx) {
+		prd := new(models.Product)
+
+		if err = cursor.Decode(prd); err != nil {
+			return nil, mapError(err)
+		}
+
+		conflicts = append(conflicts, partialEqual(target, prd)...)
+	}
+
+	return conflicts, nil
+}
+
+func (p *product) Create(ctx context.Context, prd *models.Product) (string, error) {
+	prd.ID = "prd_" + ulid.Make().String()
+
+	now := clock.Now()
+	prd.CreatedAt = now
+	prd.UpdatedAt = now
+
+	if prd.Tags == nil {
+		prd.Tags = []string{}
+	}
+
+	if _, err := p.c.InsertOne(ctx, prd); err != nil {
+		return "", mapError(err)
+	}
+
+	return prd.ID, nil
+}
+
+func (p *product) Update(ctx context.Context, namespaceID, id string, changes *models.ProductChanges) error {
+	if changes == nil {
+		return nil
+	}
+
+	changes.UpdatedAt = clock.Now()
+	update := bson.M{"$set": changes}
+
+	// An empty barcode is removed from the document so it is not indexed as a duplicate.
+	if changes.Barcode != nil && *changes.Barcode == "" {
+		changes.Barcode = nil
+		update["$unset"] = bson.M{"barcode": ""}
+	}
+
+	res, err := p.c.UpdateOne(ctx, bson.M{"_id": id, "namespace_id": namespaceID}, update)
+	if err != nil {
+		return mapError(err)
+	}
+
+	if res.MatchedCount < 1 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (p *product) Delete(ctx context.Context, namespaceID, id string) error {
+	res, err := p.c.DeleteOne(ctx, bson.M{"_id": id, "namespace_id": namespaceID})
+	if err != nil {
+		return mapError(err)
+	}
+
+	if res.DeletedCount < 1 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (p *product) AssignCategory(ctx context.Context, namespaceID, categoryID string, ids []string) (int64, error) {
+	res, err := p.c.UpdateMany(
+		ctx,
+		bson.M{"namespace_id": namespaceID, "_id": bson.M{"$in": ids}},
+		bson.M{"$set": bson.M{"category_id": categoryID, "updated_at": clock.Now()}},
+	)
+	if err != nil {
+		return 0, mapError(err)
+	}
+
+	return res.MatchedCount, nil
+}
+
+func (p *product) UnassignCategory(ctx context.Context, namespaceID, categoryID string, ids []string) (int64, error) {
+	filter := bson.M{"namespace_id": namespaceID, "category_id": categoryID}
+	if ids != nil {
+		filter["_id"] = bson.M{"$in": ids}
+	}
+
+	res, err := p.c.UpdateMany(ctx, filter, bson.M{"$unset": bson.M{"category_id": ""}, "$set": bson.M{"updated_at": clock.Now()}})
+	if err != nil {
+		return 0, mapError(err)
+	}
+
+	return res.MatchedCount, nil
+}
+
+func (p *product) AddIncoming(ctx context.Context, namespaceID, id string, delta int64) error {
+	res, err := p.c.UpdateOne(
+		ctx,
+		bson.M{"_id": id, "namespace_id": namespaceID},
+		bson.M{"$inc": bson.M{"incoming": delta}, "$set": bson.M{"updated_at": clock.Now()}},
+	)
+	if err != nil {
+		return mapError(err)
+	}
+
+	if res.MatchedCount < 1 {
+		return ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,417 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/money"
+	"github.com/heiytor/invenda/api/pkg/query"
+	"github.com/heiytor/invenda/api/store"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestProductGet(t *testing.T) {
+	type Actual struct {
+		product *models.Product
+		err     error
+	}
+
+	cases := []struct {
+		description string
+		namespaceID string
+		id          string
+		fixtures    []fixture
+		expected    Actual
+	}{
+		{
+			description: "fails when product is not found",
+			namespaceID: "ns_01HV7FKH5SRB0TGWM7MQ15PYKN",
+			id:          "prd_00000000000000000000000000",
+			fixtures:    []fixture{},
+			expected:    Actual{product: nil, err: store.ErrNotFound},
+		},
+		{
+			description: "fails when product belongs to another namespace",
+			namespaceID: "ns_01HWS7Q0H1JCEMKZADAFMETRZJ",
+			id:          "prd_01HX3A1B2C3D4E5F6G7H8J9K0M",
+			fixtures:    []fixture{fixtureProduct},
+			expected:    Actual{product: nil, err: store.ErrNotFound},
+		},
+		{
+			description: "succeeds to find a product",
+			namespaceID: "ns_01HV7FKH5SRB0TGWM7MQ15PYKN",
+			id:          "prd_01HX3A1B2C3D4E5F6G7H8J9K0M",
+			fixtures:    []fixture{fixtureProduct},
+			expected: Actual{
+				product: &models.Product{
+					ID:          "prd_01HX3A1B2C3D4E5F6G7H8J9K0M",
+					NamespaceID: "ns_01HV7FKH5SRB0TGWM7MQ15PYKN",
+					CreatedAt:   time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC),
+					UpdatedAt:   time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC),
+					SKU:         "COLA-350",
+					Name:        "Cola 350ml",
+					Description: "Can of cola",
+					Unit:        "un",
+					Price:       money.New(550, "BRL"),
+					Cost:        money.New(320, "BRL"),
+					Barcode:     "7891000100103",
+					Tags:        []string{"beverage"},
+					Active:      true,
+				},
+				err: nil,
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			srv.apply(tc.fixtures...)
+			defer srv.reset()
+
+			ctx := context.Background()
+
+			product, err := s.Product.Get(ctx, tc.namespaceID, tc.id)
+			require.Equal(t, tc.expected, Actual{product, err})
+		})
+	}
+}
+
+func TestProductGetMany(t *testing.T) {
+	type Actual struct {
+		ids   []string
+		count int64
+		err   error
+	}
+
+	cases := []struct {
+		description string
+		namespaceID string
+		query       *query.Query
+		fixtures    []fixture
+		expected    Actual
+	}{
+		{
+			description: "succeeds when no product is found",
+			namespaceID: "ns_01HV7FKH5SRB0TGWM7MQ15PYKN",
+			query:       &query.Query{Sorter: query.Sorter{By: "created_at", Order: query.OrderAsc}},
+			fixtures:    []fixture{},
+			expected:    Actual{ids: []string{}, count: 0, err: nil},
+		},
+		{
+			description: "succeeds to list only the namespace's products",
+			namespaceID: "ns_01HV7FKH5SRB0TGWM7MQ15PYKN",
+			query:       &query.Query{Sorter: query.Sorter{By: "created_at", Order: query.OrderAsc}},
+			fixtures:    []fixture{fixtureProduct},
+			expected: Actual{
+				ids:   []string{"prd_01HX3A1B2C3D4E5F6G7H8J9K0M", "prd_01HX3A2N3P4Q5R6S7T8V9W0X1Y"},
+				count: 2,
+				err:   nil,
+			},
+		},
+		{
+			description: "succeeds to list the products with filter",
+			namespaceID: "ns_01HV7FKH5SRB0TGWM7MQ15PYKN",
+			query: &query.Query{
+				Sorter: query.Sorter{By: "created_at", Order: query.OrderAsc},
+				Filter: query.Filter{Conditions: []query.Condition{{Field: "active", Operator: query.OperatorEq, Value: false}}},
+			},
+			fixtures: []fixture{fixtureProduct},
+			expected: Actual{
+				ids:   []string{"prd_01HX3A2N3P4Q5R6S7T8V9W0X1Y"},
+				count: 1,
+				err:   nil,
+			},
+		},
+		{
+			description: "succeeds to list the products with search",
+			namespaceID: "ns_01HV7FKH5SRB0TGWM7MQ15PYKN",
+			query:       &query.Query{Sorter: query.Sorter{By: "created_at", Order: query.OrderAsc}, Search: "cola"},
+			fixtures:    []fixture{fixtureProduct},
+			expected: Actual{
+				ids:   []string{"prd_01HX3A1B2C3D4E5F6G7H8J9K0M"},
+				count: 1,
+				err:   nil,
+			},
+		},
+		{
+			description: "succeeds to list the products with pagination",
+			namespaceID: "ns_01HV7FKH5SRB0TGWM7MQ15PYKN",
+			query:       &query.Query{Paginator: query.Paginator{Page: 2, Size: 1}, Sorter: query.Sorter{By: "created_at", Order: query.OrderAsc}},
+			fixtures:    []fixture{fixtureProduct},
+			expected: Actual{
+				ids:   []string{"prd_01HX3A2N3P4Q5R6S7T8V9W0X1Y"},
+				count: 2,
+				err:   nil,
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			srv.apply(tc.fixtures...)
+			defer srv.reset()
+
+			ctx := context.Background()
+
+			products, count, err := s.Product.GetMany(ctx, tc.namespaceID, tc.query)
+
+			ids := make([]string, 0)
+			for _, p := range products {
+				ids = append(ids, p.ID)
+			}
+
+			require.Equal(t, tc.expected, Actual{ids, count, err})
+		})
+	}
+}
+
+func TestProductConflicts(t *testing.T) {
+	type Actual struct {
+		conflicts []string
+		err       error
+	}
+
+	cases := []struct {
+		description string
+		namespaceID string
+		target      *models.Product
+		fixtures    []fixture
+		expected    Actual
+	}{
+		{
+			description: "succeeds when none conflicts are found",
+			namespaceID: "ns_01HV7FKH5SRB0TGWM7MQ15PYKN",
+			target:      &models.Product{SKU: "JUICE-1L"},
+			fixtures:    []fixture{fixtureProduct},
+			expected:    Actual{conflicts: []string{}, err: nil},
+		},
+		{
+			description: "succeeds when the conflict is in another namespace",
+			namespaceID: "ns_01HWS7Q0H1JCEMKZADAFMETRZJ",
+			target:      &models.Product{Barcode: "7891000100103"},
+			fixtures:    []fixture{fixtureProduct},
+			expected:    Actual{conflicts: []string{}, err: nil},
+		},
+		{
+			description: "succeeds when conflicts are found",
+			namespaceID: "ns_01HV7FKH5SRB0TGWM7MQ15PYKN",
+			target:      &models.Product{SKU: "COLA-350", Barcode: "7891000100103"},
+			fixtures:    []fixture{fixtureProduct},
+			expected:    Actual{conflicts: []string{"sku", "barcode"}, err: nil},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			srv.apply(tc.fixtures...)
+			defer srv.reset()
+
+			ctx := context.Background()
+
+			conflicts, err := s.Product.Conflicts(ctx, tc.namespaceID, tc.target)
+			require.Equal(t, tc.expected, Actual{conflicts, err})
+		})
+	}
+}
+
+func TestProductCreate(t *testing.T) {
+	ctx := context.Background()
+	defer srv.reset()
+
+	// Recreates the unique indexes dropped by previous resets.
+	_, err := store.New(ctx, db.Client(), db.Name())
+	require.NoError(t, err)
+
+	product := &models.Product{
+		NamespaceID: "ns_01HV7FKH5SRB0TGWM7MQ15PYKN",
+		SKU:         "JUICE-1L",
+		Name:        "Orange juice 1L",
+		Unit:        "un",
+		Price:       money.New(990, "BRL"),
+		Active:      true,
+	}
+
+	id, err := s.Product.Create(ctx, product)
+	require.NoError(t, err)
+	require.NotEmpty(t, id)
+
+	created := new(models.Product)
+	require.NoError(t, db.Collection("product").FindOne(ctx, bson.M{"_id": id}).Decode(created))
+	require.Equal(t, product.SKU, created.SKU)
+	require.Equal(t, product.NamespaceID, created.NamespaceID)
+	require.Equal(t, []string{}, created.Tags)
+
+	_, err = s.Product.Create(ctx, &models.Product{NamespaceID: "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", SKU: "JUICE-1L"})
+	require.ErrorIs(t, err, store.ErrDuplicated)
+}
+
+func TestProductUpdate(t *testing.T) {
+	type Actual struct {
+		err error
+	}
+
+	price := money.New(700, "BRL")
+	barcode := ""
+
+	cases := []struct {
+		description string
+		namespaceID string
+		id          string
+		changes     *models.ProductChanges
+		fixtures    []fixture
+		expected    Actual
+	}{
+		{
+			description: "fails when product is not found",
+			namespaceID: "ns_01HV7FKH5SRB0TGWM7MQ15PYKN",
+			id:          "prd_00000000000000000000000000",
+			changes:     &models.ProductChanges{},
+			fixtures:    []fixture{},
+			expected:    Actual{err: store.ErrNotFound},
+		},
+		{
+			description: "fails when product belongs to another namespace",
+			namespaceID: "ns_01HWS7Q0H1JCEMKZADAFMETRZJ",
+			id:          "prd_01HX3A1B2C3D4E5F6G7H8J9K0M",
+			changes:     &models.ProductChanges{Name: "New Name"},
+			fixtures:    []fixture{fixtureProduct},
+			expected:    Actual{err: store.ErrNotFound},
+		},
+		{
+			description: "succeeds to update a product",
+			namespaceID: "ns_01HV7FKH5SRB0TGWM7MQ15PYKN",
+			id:          "prd_01HX3A1B2C3D4E5F6G7H8J9K0M",
+			changes:     &models.ProductChanges{Name: "New Name", Price: &price, Barcode: &barcode},
+			fixtures:    []fixture{fixtureProduct},
+			expected:    Actual{err: nil},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			srv.apply(tc.fixtures...)
+			defer srv.reset()
+
+			ctx := context.Background()
+
+			if err := s.Product.Update(ctx, tc.namespaceID, tc.id, tc.changes); err != nil {
+				require.Equal(t, tc.expected, Actual{err})
+				return
+			}
+
+			product := new(models.Product)
+			require.NoError(t, db.Collection("product").FindOne(ctx, bson.M{"_id": tc.id}).Decode(product))
+			require.Equal(t, "New Name", product.Name)
+			require.Equal(t, price, product.Price)
+			require.Equal(t, "", product.Barcode)
+			require.WithinDuration(t, tc.changes.UpdatedAt, product.UpdatedAt, time.Millisecond)
+		})
+	}
+}
+
+func TestProductDelete(t *testing.T) {
+	type Actual struct {
+		err error
+	}
+
+	cases := []struct {
+		description string
+		namespaceID string
+		id          string
+		fixtures    []fixture
+		expected    Actual
+	}{
+		{
+			description: "fails when product is not found",
+			namespaceID: "ns_01HV7FKH5SRB0TGWM7MQ15PYKN",
+			id:          "prd_00000000000000000000000000",
+			fixtures:    []fixture{},
+			expected:    Actual{err: store.ErrNotFound},
+		},
+		{
+			description: "succeeds to delete a product",
+			namespaceID: "ns_01HV7FKH5SRB0TGWM7MQ15PYKN",
+			id:          "prd_01HX3A1B2C3D4E5F6G7H8J9K0M",
+			fixtures:    []fixture{fixtureProduct},
+			expected:    Actual{err: nil},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			srv.apply(tc.fixtures...)
+			defer srv.reset()
+
+			ctx := context.Background()
+
+			require.Equal(t, tc.expected, Actual{s.Product.Delete(ctx, tc.namespaceID, tc.id)})
+		})
+	}
+}
+
+func TestProductAddIncoming(t *testing.T) {
+	type Expected struct {
+		err      error
+		incoming int64
+	}
+
+	cases := []struct {
+		description string
+		id          string
+		deltas      []int64
+		fixtures    []fixture
+		expected    Expected
+	}{
+		{
+			description: "fails when product is not found",
+			id:          "prd_00000000000000000000000000",
+			deltas:      []int64{5},
+			fixtures:    []fixture{},
+			expected:    Expected{err: store.ErrNotFound},
+		},
+		{
+			description: "succeeds to add and remove incoming quantities",
+			id:          "prd_01HX3A1B2C3D4E5F6G7H8J9K0M",
+			deltas:      []int64{10, 4, -6},
+			fixtures:    []fixture{fixtureProduct},
+			expected:    Expected{err: nil, incoming: 8},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			srv.apply(tc.fixtures...)
+			defer srv.reset()
+
+			ctx := context.Background()
+
+			for _, d := range tc.deltas {
+				if err := s.Product.AddIncoming(ctx, "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", tc.id, d); err != nil {
+					require.Equal(t, tc.expected.err, err)
+					return
+				}
+			}
+
+			product := new(models.Product)
+			require.NoError(t, db.Collection("product").FindOne(ctx, bson.M{"_id": tc.id}).Decode(product))
+			require.Equal(t, tc.expected.incoming, product.Incoming)
+		})
+	}
+}
+
+func TestProductGetByBarcode(t *testing.T) {
+	srv.apply(fixtureProduct)
+	defer srv.reset()
+
+	ctx := context.Background()
+
+	prd, err := s.Product.GetByBarcode(ctx, "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", "7891000100103")
+	require.NoError(t, err)
+	require.Equal(t, "prd_01HX3A1B2C3D4E5F6G7H8J9K0M", prd.ID)
+
+	_, err = s.Product.GetByBarcode(ctx, "ns_01HWS7Q0H1JCEMKZADAFMETRZJ", "7891000100103")
+	require.Equal(t, store.ErrNotFound, err)
+}
@@ -0,0 +1,240 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/heiytor/invenda/api/pkg/clock"
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/query"
+	"github.com/heiytor/invenda/api/store/internal"
+	"github.com/oklog/ulid/v2"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Promotion handles the namespace's promotions. Every operation is scoped to a namespace ID.
+type Promotion interface {
+	Entity
+
+	// Get retrieves a promotion with the specified ID. It returns the promotion or an error if any.
+	Get(ctx context.Context, namespaceID, id string) (promotion *models.Promotion, err error)
+
+	// GetMany retrieves a list of promotions of a namespace. It returns the list of promotions, the total
+	// count of the existent documents and an error if any.
+	GetMany(ctx context.Context, namespaceID string, query *query.Query) (promotions []models.Promotion, count int64, err error)
+
+	// GetActive retrieves the active promotions of a namespace that are valid at a time. It returns the
+	// promotions or an error if any.
+	GetActive(ctx context.Context, namespaceID string, at time.Time) (promotions []models.Promotion, err error)
+
+	// Conflicts reports whether the non-zero fields of the provided target already exist in the namespace.
+	// It returns a list of conflicted fields or an error if any.
+	Conflicts(ctx context.Context, namespaceID string, target *models.Promotion) (conflicts []string, err error)
+
+	// Create creates a new promotion with the provided data. It returns the inserted ID or an error
+	// if any.
+	Create(ctx context.Context, promotion *models.Promotion) (insertedID string, err error)
+
+	// Update updates a promotion with the specified changes and ID. It returns [ErrNotFound] if no promotion
+	// is found.
+	Update(ctx context.Context, namespaceID, id string, changes *models.PromotionChanges) (err error)
+
+	// Delete deletes a promotion with the specified ID. It returns [ErrNotFound] if no promotion is found.
+	Delete(ctx context.Context, namespaceID, id string) (err error)
+
+	// Redeem records a use of a promotion with the specified ID. It returns [ErrNotFound] if no promotion
+	// is found or if the promotion reached its usage limit.
+	Redeem(ctx context.Context, namespaceID, id string) (err error)
+
+	// Release undoes a use of a promotion with the specified ID, such as when the document that used it is
+	// cancelled. It returns [ErrNotFound] if no promotion is found.
+	Release(ctx context.Context, namespaceID, id string) (err error)
+}
+
+type promotion struct {
+	c *mongo.Collection // c is the "promotion" collection
+}
+
+var _ Promotion = (*promotion)(nil)
+
+func (*promotion) Entity() string {
+	return "promotion"
+}
+
+func (pm *promotion) Get(ctx context.Context, namespaceID, id string) (*models.Promotion, error) {
+	prm := new(models.Promotion)
+	if err := pm.c.FindOne(ctx, bson.M{"_id": id, "namespace_id": namespaceID}).Decode(prm); err != nil {
+		return nil, mapError(err)
+	}
+
+	return prm, nil
+}
+
+func (pm *promotion) GetMany(ctx context.Context, namespaceID string, query *query.Query) ([]models.Promotion, int64, error) {
+	conditions := []bson.M{
+		{"namespace_id": namespaceID},
+		internal.FromFilter(&query.Filter),
+	}
+
+	if query.Search != "" {
+		conditions = append(conditions, internal.FromPrefixSearch(query.Search, "name", "coupon"))
+	}
+
+	match := bson.M{"$and": conditions}
+
+	count, err := pm.c.CountDocuments(ctx, match)
+	if err != nil {
+		log.Error().Err(err).Msg("unable to count the total documents")
+	}
+
+	pipeline := make([]bson.M, 0)
+	pipeline = append(pipeline, bson.M{"$match": match})
+	pipeline = append(pipeline, internal.FromSorter(&query.Sorter)...)
+	pipeline = append(pipeline, internal.FromPaginator(&query.Paginator)...)
+
+	cursor, err := pm.c.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, 0, mapError(err)
+	}
+	defer cursor.Close(ctx)
+
+	promotions := make([]models.Promotion, 0)
+	if err := cursor.All(ctx, &promotions); err != nil {
+		return nil, 0, mapError(err)
+	}
+
+	return promotions, count, nil
+}
+
+func (pm *promotion) GetActive(ctx context.Context, namespaceID string, at time.Time) ([]models.Promotion, error) {
+	filter := bson.M{
+		"namespace_id": namespaceID,
+		"active":       true,
+		"$and": []bson.M{
+			{"$or": []bson.M{{"valid_from": nil}, {"valid_from": bson.M{"$lte": at}}}},
+			{"$or": []bson.M{{"valid_until": nil}, {"valid_until": bson.M{"$gt": at}}}},
+		},
+	}
+
+	cursor, err := pm.c.Find(ctx, filter, options.Find().SetSort(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, mapError(err)
+	}
+	defer cursor.Close(ctx)
+
+	promotions := make([]models.Promotion, 0)
+	if err := cursor.All(ctx, &promotions); err != nil {
+		return nil, mapError(err)
+	}
+
+	return promotions, nil
+}
+
+func (pm *promotion) Conflicts(ctx context.Context, namespaceID string, target *models.Promotion) ([]string, error) {
+	pipeline := append([]bson.M{{"$match": bson.M{"namespace_id": namespaceID}}}, or(target)...)
+
+	cursor, err := pm.c.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	defer cursor.Close(ctx)
+
+	conflicts := make([]string, 0)
+	for cursor.Next(ctx) {
+		prm := new(models.Promotion)
+
+		if err = cursor.Decode(prm); err != nil {
+			return nil, mapError(err)
+		}
+
+		conflicts = append(conflicts, partialEqual(target, prm)...)
+	}
+
+	return conflicts, nil
+}
+
+func (pm *promotion) Create(ctx context.Context, prm *models.Promotion) (string, error) {
+	prm.ID = "prm_" + ulid.Make().String()
+
+	now := clock.Now()
+	prm.CreatedAt = now
+	prm.UpdatedAt = now
+
+	prm.Used = 0
+
+	if _, err := pm.c.InsertOne(ctx, prm); err != nil {
+		return "", mapError(err)
+	}
+
+	return prm.ID, nil
+}
+
+func (pm *promotion) Update(ctx context.Context, namespaceID, id string, changes *models.PromotionChanges) error {
+	if changes == nil {
+		return nil
+	}
+
+	changes.UpdatedAt = clock.Now()
+
+	res, err := pm.c.UpdateOne(ctx, bson.M{"_id": id, "namespace_id": namespaceID}, bson.M{"$set": changes})
+	if err != nil {
+		return mapError(err)
+	}
+
+	if res.MatchedCount < 1 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (pm *promotion) Delete(ctx context.Context, namespaceID, id string) error {
+	res, err := pm.c.DeleteOne(ctx, bson.M{"_id": id, "namespace_id": namespaceID})
+	if err != nil {
+		return mapError(err)
+	}
+
+	if res.DeletedCount < 1 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (pm *promotion) Redeem(ctx context.Context, namespaceID, id string) error {
+	filter := bson.M{
+		"_id":          id,
+		"namespace_id": namespaceID,
+		"$or": []bson.M{
+			{"usage_limit": 0},
+			{"$expr": bson.M{"$lt": bson.A{"$used", "$usage_limit"}}},
+		},
+	}
+
+	res, err := pm.c.UpdateOne(ctx, filter, bson.M{"$inc": bson.M{"used": 1}})
+	if err != nil {
+		return mapError(err)
+	}
+
+	if res.MatchedCount < 1 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (pm *promotion) Release(ctx context.Context, namespaceID, id string) error {
+	res, err := pm.c.UpdateOne(ctx, bson.M{"_id": id, "namespace_id": namespaceID, "used": bson.M{"$gt": 0}}, bson.M{"$inc": bson.M{"used": -1}})
+	if err != nil {
+		return mapError(err)
+	}
+
+	if res.MatchedCount < 1 {
+		return ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,116 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/money"
+	"github.com/heiytor/invenda/api/store"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestPromotionGetActive(t *testing.T) {
+	type Actual struct {
+		ids []string
+		err error
+	}
+
+	cases := []struct {
+		description string
+		namespaceID string
+		at          time.Time
+		fixtures    []fixture
+		expected    Actual
+	}{
+		{
+			description: "succeeds with no promotions in another namespace",
+			namespaceID: "ns_01HWS7Q0H1JCEMKZADAFMETRZJ",
+			at:          time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC),
+			fixtures:    []fixture{fixturePromotion},
+			expected:    Actual{ids: []string{}, err: nil},
+		},
+		{
+			description: "succeeds to find the active promotions while they are valid",
+			namespaceID: "ns_01HV7FKH5SRB0TGWM7MQ15PYKN",
+			at:          time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC),
+			fixtures:    []fixture{fixturePromotion},
+			expected:    Actual{ids: []string{"prm_01HXE1A2B3C4D5E6F7G8H9J0KM", "prm_01HXE1B2C3D4E5F6G7H8J9K0MN"}, err: nil},
+		},
+		{
+			description: "succeeds to ignore the promotions once they expire",
+			namespaceID: "ns_01HV7FKH5SRB0TGWM7MQ15PYKN",
+			at:          time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC),
+			fixtures:    []fixture{fixturePromotion},
+			expected:    Actual{ids: []string{"prm_01HXE1B2C3D4E5F6G7H8J9K0MN"}, err: nil},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			srv.apply(tc.fixtures...)
+			defer srv.reset()
+
+			ctx := context.Background()
+
+			promotions, err := s.Promotion.GetActive(ctx, tc.namespaceID, tc.at)
+
+			ids := make([]string, 0, len(promotions))
+			for _, prm := range promotions {
+				ids = append(ids, prm.ID)
+			}
+
+			require.Equal(t, tc.expected, Actual{ids, err})
+		})
+	}
+}
+
+func TestPromotionRedeem(t *testing.T) {
+	srv.apply(fixturePromotion)
+	defer srv.reset()
+
+	ctx := context.Background()
+
+	// The coupon can be used twice and was used once.
+	require.NoError(t, s.Promotion.Redeem(ctx, "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", "prm_01HXE1B2C3D4E5F6G7H8J9K0MN"))
+	require.Equal(t, store.ErrNotFound, s.Promotion.Redeem(ctx, "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", "prm_01HXE1B2C3D4E5F6G7H8J9K0MN"))
+
+	// Promotions without a limit can always be used.
+	require.NoError(t, s.Promotion.Redeem(ctx, "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", "prm_01HXE1A2B3C4D5E6F7G8H9J0KM"))
+
+	require.Equal(t, store.ErrNotFound, s.Promotion.Redeem(ctx, "ns_01HWS7Q0H1JCEMKZADAFMETRZJ", "prm_01HXE1A2B3C4D5E6F7G8H9J0KM"))
+
+	prm := new(models.Promotion)
+	require.NoError(t, db.Collection("promotion").FindOne(ctx, bson.M{"_id": "prm_01HXE1B2C3D4E5F6G7H8J9K0MN"}).Decode(prm))
+	require.Equal(t, int64(2), prm.Used)
+
+	require.NoError(t, s.Promotion.Release(ctx, "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", "prm_01HXE1B2C3D4E5F6G7H8J9K0MN"))
+	require.NoError(t, db.Collection("promotion").FindOne(ctx, bson.M{"_id": "prm_01HXE1B2C3D4E5F6G7H8J9K0MN"}).Decode(prm))
+	require.Equal(t, int64(1), prm.Used)
+
+	// Unused promotions cannot be released.
+	require.Equal(t, store.ErrNotFound, s.Promotion.Release(ctx, "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", "prm_01HXE1C3D4E5F6G7H8J9K0MNPQ"))
+}
+
+func TestPromotionCreateCoupon(t *testing.T) {
+	srv.apply(fixturePromotion)
+	defer srv.reset()
+
+	ctx := context.Background()
+
+	// Recreates the unique indexes dropped by previous resets.
+	_, err := store.New(ctx, db.Client(), db.Name())
+	require.NoError(t, err)
+
+	_, err = s.Promotion.Create(ctx, &models.Promotion{NamespaceID: "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", Name: "Copy", Type: models.PromotionFixed, Amount: money.New(100, "BRL"), Coupon: "WELCOME"})
+	require.Equal(t, store.ErrDuplicated, err)
+
+	// Promotions without a coupon never conflict with each other.
+	_, err = s.Promotion.Create(ctx, &models.Promotion{NamespaceID: "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", Name: "Automatic", Type: models.PromotionFixed, Amount: money.New(100, "BRL")})
+	require.NoError(t, err)
+
+	_, err = s.Promotion.Create(ctx, &models.Promotion{NamespaceID: "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", Name: "Automatic", Type: models.PromotionFixed, Amount: money.New(100, "BRL")})
+	require.NoError(t, err)
+}
@@ -0,0 +1,117 @@
+package store
+
+import (
+	"context"
+
+	"github.com/heiytor/invenda/api/pkg/clock"
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/query"
+	"github.com/oklog/ulid/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// PurchaseOrder handles the namespace's purchase orders. Every operation is scoped to a namespace ID.
+type PurchaseOrder interface {
+	Entity
+
+	// Get retrieves a purchase order with the specified ID. It returns the order or an error if any.
+	Get(ctx context.Context, namespaceID, id string) (order *models.PurchaseOrder, err error)
+
+	// GetMany retrieves a list of purchase orders of a namespace. It returns the list of orders, the total count
+	// of the existent documents and an error if any.
+	GetMany(ctx context.Context, namespaceID string, query *query.Query) (orders []models.PurchaseOrder, count int64, err error)
+
+	// Create creates a new purchase order with the provided data. It returns the inserted ID or an error
+	// if any.
+	Create(ctx context.Context, order *models.PurchaseOrder) (insertedID string, err error)
+
+	// Update updates a purchase order with the specified changes and ID. When statuses are provided, the order
+	// is only updated if it is in one of them, which guards status transitions against concurrent changes.
+	// It returns [ErrNotFound] if no matching purchase order is found.
+	Update(ctx context.Context, namespaceID, id string, statuses []models.PurchaseOrderStatus, changes *models.PurchaseOrderChanges) (err error)
+
+	// Delete deletes a purchase order with the specified ID. It returns [ErrNotFound] if no purchase order is found.
+	Delete(ctx context.Context, namespaceID, id string) (err error)
+}
+
+type purchaseOrder struct {
+	c *mongo.Collection // c is the "purchase_order" collection
+}
+
+var _ PurchaseOrder = (*purchaseOrder)(nil)
+
+func (*purchaseOrder) Entity() string {
+	return "purchase_order"
+}
+
+func (p *purchaseOrder) Get(ctx context.Context, namespaceID, id string) (*models.PurchaseOrder, error) {
+	po := new(models.PurchaseOrder)
+	if err := p.c.FindOne(ctx, bson.M{"_id": id, "namespace_id": namespaceID}).Decode(po); err != nil {
+		return nil, mapError(err)
+	}
+
+	return po, nil
+}
+
+func (p *purchaseOrder) GetMany(ctx context.Context, namespaceID string, query *query.Query) ([]models.PurchaseOrder, int64, error) {
+	orders := make([]models.PurchaseOrder, 0)
+	count, err := find(ctx, p.c, namespaceID, query, &orders)
+
+	return orders, count, err
+}
+
+func (p *purchaseOrder) Create(ctx context.Context, po *models.PurchaseOrder) (string, error) {
+	po.ID = "po_" + ulid.Make().String()
+
+	now := clock.Now()
+	po.CreatedAt = now
+	po.UpdatedAt = now
+
+	if po.Lines == nil {
+		po.Lines = []models.PurchaseOrderLine{}
+	}
+
+	if _, err := p.c.InsertOne(ctx, po); err != nil {
+		return "", mapError(err)
+	}
+
+	return po.ID, nil
+}
+
+func (p *purchaseOrder) Update(ctx context.Context, namespaceID, id string, statuses []models.PurchaseOrderStatus, changes *models.PurchaseOrderChanges) error {
+	if changes == nil {
+		return nil
+	}
+
+	changes.UpdatedAt = clock.Now()
+
+	filter := bson.M{"_id": id, "namespace_id": namespaceID}
+	if len(statuses) > 0 {
+		filter["status"] = bson.M{"$in": statuses}
+	}
+
+	res, err := p.c.UpdateOne(ctx, filter, bson.M{"$set": changes})
+	if err != nil {
+		return mapError(err)
+	}
+
+	if res.MatchedCount < 1 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (p *purchaseOrder) Delete(ctx context.Context, namespaceID, id string) error {
+	res, err := p.c.DeleteOne(ctx, bson.M{"_id": id, "namespace_id": namespaceID})
+	if err != nil {
+		return mapError(err)
+	}
+
+	if res.DeletedCount < 1 {
+		return ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/store"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestPurchaseOrderUpdate(t *testing.T) {
+	type Expected struct {
+		err    error
+		status models.PurchaseOrderStatus
+	}
+
+	cases := []struct {
+		description string
+		id          string
+		statuses    []models.PurchaseOrderStatus
+		changes     *models.PurchaseOrderChanges
+		fixtures    []fixture
+		expected    Expected
+	}{
+		{
+			description: "fails when purchase order is not found",
+			id:          "po_00000000000000000000000000",
+			statuses:    []models.PurchaseOrderStatus{},
+			changes:     &models.PurchaseOrderChanges{Status: models.PurchaseOrderSent},
+			fixtures:    []fixture{},
+			expected:    Expected{err: store.ErrNotFound},
+		},
+		{
+			description: "fails when purchase order is not in the expected status",
+			id:          "po_01HXA2C3D4E5F6G7H8J9K0MNPQ",
+			statuses:    []models.PurchaseOrderStatus{models.PurchaseOrderSent},
+			changes:     &models.PurchaseOrderChanges{Status: models.PurchaseOrderReceived},
+			fixtures:    []fixture{fixturePurchase},
+			expected:    Expected{err: store.ErrNotFound, status: models.PurchaseOrderDraft},
+		},
+		{
+			description: "succeeds to transition a purchase order",
+			id:          "po_01HXA2C3D4E5F6G7H8J9K0MNPQ",
+			statuses:    []models.PurchaseOrderStatus{models.PurchaseOrderDraft},
+			changes:     &models.PurchaseOrderChanges{Status: models.PurchaseOrderSent},
+			fixtures:    []fixture{fixturePurchase},
+			expected:    Expected{err: nil, status: models.PurchaseOrderSent},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			srv.apply(tc.fixtures...)
+			defer srv.reset()
+
+			ctx := context.Background()
+
+			err := s.PurchaseOrder.Update(ctx, "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", tc.id, tc.statuses, tc.changes)
+			require.Equal(t, tc.expected.err, err)
+
+			if tc.expected.status == "" {
+				return
+			}
+
+			po := new(models.PurchaseOrder)
+			require.NoError(t, db.Collection("purchase_order").FindOne(ctx, bson.M{"_id": tc.id}).Decode(po))
+			require.Equal(t, tc.expected.status, po.Status)
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package store
+
+import (
+	"context"
+
+	"github.com/heiytor/invenda/api/pkg/clock"
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/query"
+	"github.com/heiytor/invenda/api/store/internal"
+	"github.com/oklog/ulid/v2"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Register handles the namespace's registers. Every operation is scoped to a namespace ID.
+type Register interface {
+	Entity
+
+	// Get retrieves a register with the specified ID. It returns the register or an error if any.
+	Get(ctx context.Context, namespaceID, id string) (register *models.Register, err error)
+
+	// GetMany retrieves a list of registers of a namespace. It returns the list of registers, the total count
+	// of the existent documents and an error if any.
+	GetMany(ctx context.Context, namespaceID string, query *query.Query) (registers []models.Register, count int64, err error)
+
+	// Conflicts reports whether the non-zero fields of the provided target already exist in the namespace.
+	// It returns a list of conflicted fields or an error if any.
+	Conflicts(ctx context.Context, namespaceID string, target *models.Register) (conflicts []string, err error)
+
+	// Create creates a new register with the provided data. It returns the inserted ID or an error
+	// if any.
+	Create(ctx context.Context, register *models.Register) (insertedID string, err error)
+
+	// Update updates a register with the specified changes and ID. It returns [ErrNotFound] if no register is found.
+	Update(ctx context.Context, namespaceID, id string, changes *models.RegisterChanges) (err error)
+
+	// Delete deletes a register with the specified ID. It returns [ErrNotFound] if no register is found.
+	Delete(ctx context.Context, namespaceID, id string) (err error)
+}
+
+type register struct {
+	c *mongo.Collection // c is the "register" collection
+}
+
+var _ Register = (*register)(nil)
+
+func (*register) Entity() string {
+	return "register"
+}
+
+func (rg *register) Get(ctx context.Context, namespaceID, id string) (*models.Register, error) {
+	reg := new(models.Register)
+	if err := rg.c.FindOne(ctx, bson.M{"_id": id, "namespace_id": namespaceID}).Decode(reg); err != nil {
+		return nil, mapError(err)
+	}
+
+	return reg, nil
+}
+
+func (rg *register) GetMany(ctx context.Context, namespaceID string, query *query.Query) ([]models.Register, int64, error) {
+	conditions := []bson.M{
+		{"namespace_id": namespaceID},
+		internal.FromFilter(&query.Filter),
+	}
+
+	if query.Search != "" {
+		conditions = append(conditions, internal.FromPrefixSearch(query.Search, "code", "name"))
+	}
+
+	match := bson.M{"$and": conditions}
+
+	count, err := rg.c.CountDocuments(ctx, match)
+	if err != nil {
+		log.Error().Err(err).Msg("unable to count the total documents")
+	}
+
+	pipeline := make([]bson.M, 0)
+	pipeline = append(pipeline, bson.M{"$match": match})
+	pipeline = append(pipeline, internal.FromSorter(&query.Sorter)...)
+	pipeline = append(pipeline, internal.FromPaginator(&query.Paginator)...)
+
+	cursor, err := rg.c.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, 0, mapError(err)
+	}
+	defer cursor.Close(ctx)
+
+	registers := make([]models.Register, 0)
+	if err := cursor.All(ctx, &registers); err != nil {
+		return nil, 0, mapError(err)
+	}
+
+	return registers, count, nil
+}
+
+func (rg *register) Conflicts(ctx context.Context, namespaceID string, target *models.Register) ([]string, error) {
+	pipeline := append([]bson.M{{"$match": bson.M{"namespace_id": namespaceID}}}, or(target)...)
+
+	cursor, err := rg.c.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	defer cursor.Close(ctx)
+
+	conflicts := make([]string, 0)
+	for cursor.Next(ctx) {
+		reg := new(models.Register)
+
+		if err = cursor.Decode(reg); err != nil {
+			return nil, mapError(err)
+		}
+
+		conflicts = append(conflicts, partialEqual(target, reg)...)
+	}
+
+	return conflicts, nil
+}
+
+func (rg *register) Create(ctx context.Context, reg *models.Register) (string, error) {
+	reg.ID = "reg_" + ulid.Make().String()
+
+	now := clock.Now()
+	reg.CreatedAt = now
+	reg.UpdatedAt = now
+
+	if _, err := rg.c.InsertOne(ctx, reg); err != nil {
+		return "", mapError(err)
+	}
+
+	return reg.ID, nil
+}
+
+func (rg *register) Update(ctx context.Context, namespaceID, id string, changes *models.RegisterChanges) error {
+	if changes == nil {
+		return nil
+	}
+
+	changes.UpdatedAt = clock.Now()
+
+	res, err := rg.c.UpdateOne(ctx, bson.M{"_id": id, "namespace_id": namespaceID}, bson.M{"$set": changes})
+	if err != nil {
+		return mapError(err)
+	}
+
+	if res.MatchedCount < 1 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (rg *register) Delete(ctx context.Context, namespaceID, id string) error {
+	res, err := rg.c.DeleteOne(ctx, bson.M{"_id": id, "namespace_id": namespaceID})
+	if err != nil {
+		return mapError(err)
+	}
+
+	if res.DeletedCount < 1 {
+		return ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterConflicts(t *testing.T) {
+	type Actual struct {
+		conflicts []string
+		err       error
+	}
+
+	cases := []struct {
+		description string
+		namespaceID string
+		target      *models.Register
+		fixtures    []fixture
+		expected    Actual
+	}{
+		{
+			description: "succeeds when none conflicts are found",
+			namespaceID: "ns_01HV7FKH5SRB0TGWM7MQ15PYKN",
+			target:      &models.Register{Code: "TILL-02"},
+			fixtures:    []fixture{fixtureRegister},
+			expected:    Actual{conflicts: []string{}, err: nil},
+		},
+		{
+			description: "succeeds when the code belongs to another namespace",
+			namespaceID: "ns_01HWS7Q0H1JCEMKZADAFMETRZJ",
+			target:      &models.Register{Code: "TILL-01"},
+			fixtures:    []fixture{fixtureRegister},
+			expected:    Actual{conflicts: []string{}, err: nil},
+		},
+		{
+			description: "succeeds when a conflict is found",
+			namespaceID: "ns_01HV7FKH5SRB0TGWM7MQ15PYKN",
+			target:      &models.Register{Code: "TILL-01"},
+			fixtures:    []fixture{fixtureRegister},
+			expected:    Actual{conflicts: []string{"code"}, err: nil},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			srv.apply(tc.fixtures...)
+			defer srv.reset()
+
+			ctx := context.Background()
+
+			conflicts, err := s.Register.Conflicts(ctx, tc.namespaceID, tc.target)
+			require.Equal(t, tc.expected, Actual{conflicts, err})
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/heiytor/invenda/api/pkg/clock"
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/query"
+	"github.com/oklog/ulid/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Reservation handles the namespace's stock reservations. The reserved quantities themselves are kept
+// by the balances, see [Stock.Reserve]. Every operation but [Reservation.Expired] is scoped to a namespace ID.
+type Reservation interface {
+	Entity
+
+	// Get retrieves a reservation with the specified ID. It returns the reservation or an error if any.
+	Get(ctx context.Context, namespaceID, id string) (reservation *models.Reservation, err error)
+
+	// GetMany retrieves a list of reservations of a namespace. It returns the list of reservations, the total
+	// count of the existent documents and an error if any.
+	GetMany(ctx context.Context, namespaceID string, query *query.Query) (reservations []models.Reservation, count int64, err error)
+
+	// Create creates a new active reservation with the provided data. It returns the inserted ID or an
+	// error if any.
+	Create(ctx context.Context, reservation *models.Reservation) (insertedID string, err error)
+
+	// SetStatus moves an active reservation with the specified ID to status. It returns [ErrNotFound] if no
+	// active reservation is found, which guards the transition against concurrent changes.
+	SetStatus(ctx context.Context, namespaceID, id string, status models.ReservationStatus) (err error)
+
+	// Expired retrieves up to limit active reservations of every namespace that expired at now, the
+	// oldest first.
+	Expired(ctx context.Context, now time.Time, limit int64) (reservations []models.Reservation, err error)
+}
+
+type reservation struct {
+	c *mongo.Collection // c is the "reservation" collection
+}
+
+var _ Reservation = (*reservation)(nil)
+
+func (*reservation) Entity() string {
+	return "reservation"
+}
+
+func (r *reservation) Get(ctx context.Context, namespaceID, id string) (*models.Reservation, error) {
+	rsv := new(models.Reservation)
+	if err := r.c.FindOne(ctx, bson.M{"_id": id, "namespace_id": namespaceID}).Decode(rsv); err != nil {
+		return nil, mapError(err)
+	}
+
+	return rsv, nil
+}
+
+func (r *reservation) GetMany(ctx context.Context, namespaceID string, query *query.Query) ([]models.Reservation, int64, error) {
+	reservations := make([]models.Reservation, 0)
+	count, err := find(ctx, r.c, namespaceID, query, &reservations)
+
+	return reservations, count, err
+}
+
+func (r *reservation) Create(ctx context.Context, rsv *models.Reservation) (string, error) {
+	rsv.ID = "rsv_" + ulid.Make().String()
+	rsv.Status = models.ReservationActive
+
+	now := clock.Now()
+	rsv.CreatedAt = now
+	rsv.UpdatedAt = now
+
+	if _, err := r.c.InsertOne(ctx, rsv); err != nil {
+		return "", mapError(err)
+	}
+
+	return rsv.ID, nil
+}
+
+func (r *reservation) SetStatus(ctx context.Context, namespaceID, id string, status models.ReservationStatus) error {
+	res, err := r.c.UpdateOne(
+		ctx,
+		bson.M{"_id": id, "namespace_id": namespaceID, "status": models.ReservationActive},
+		bson.M{"$set": bson.M{"status": status, "updated_at": clock.Now()}},
+	)
+	if err != nil {
+		return mapError(err)
+	}
+
+	if res.MatchedCount < 1 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *reservation) Expired(ctx context.Context, now time.Time, limit int64) ([]models.Reservation, error) {
+	opts := options.Find().SetSort(bson.M{"expires_at": 1}).SetLimit(limit)
+
+	cursor, err := r.c.Find(ctx, bson.M{"status": models.ReservationActive, "expires_at": bson.M{"$lte": now}}, opts)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	defer cursor.Close(ctx)
+
+	reservations := make([]models.Reservation, 0)
+	if err := cursor.All(ctx, &reservations); err != nil {
+		return nil, mapError(err)
+	}
+
+	return reservations, nil
+}
@@ -0,0 +1,59 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/store"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReservationSetStatus(t *testing.T) {
+	cases := []struct {
+		description string
+		id          string
+		expected    error
+	}{
+		{
+			description: "succeeds to end an active reservation",
+			id:          "rsv_01HX5H1A2B3C4D5E6F7G8H9J0K",
+			expected:    nil,
+		},
+		{
+			description: "fails when the reservation is not active",
+			id:          "rsv_01HX5H3Y4Z5A6B7C8D9E0F1G2H",
+			expected:    store.ErrNotFound,
+		},
+		{
+			description: "fails when the reservation does not exist",
+			id:          "rsv_01HX5H9Z9Z9Z9Z9Z9Z9Z9Z9Z9Z",
+			expected:    store.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			srv.apply(fixtureReservation)
+			defer srv.reset()
+
+			ctx := context.Background()
+
+			err := s.Reservation.SetStatus(ctx, "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", tc.id, models.ReservationReleased)
+			require.Equal(t, tc.expected, err)
+		})
+	}
+}
+
+func TestReservationExpired(t *testing.T) {
+	srv.apply(fixtureReservation)
+	defer srv.reset()
+
+	ctx := context.Background()
+
+	reservations, err := s.Reservation.Expired(ctx, time.Date(2023, 1, 5, 0, 0, 0, 0, time.UTC), 10)
+	require.NoError(t, err)
+	require.Len(t, reservations, 1)
+	require.Equal(t, "rsv_01HX5H1A2B3C4D5E6F7G8H9J0K", reservations[0].ID)
+}
@@ -0,0 +1,68 @@
+package store
+
+import (
+	"context"
+
+	"github.com/heiytor/invenda/api/pkg/clock"
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/query"
+	"github.com/oklog/ulid/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Return handles the returns of the namespace's counter sales. Every operation is scoped to a namespace ID.
+type Return interface {
+	Entity
+
+	// Get retrieves a return with the specified ID. It returns the return or an error if any.
+	Get(ctx context.Context, namespaceID, id string) (ret *models.Return, err error)
+
+	// GetMany retrieves a list of returns of a namespace. It returns the list of returns, the total count of
+	// the existent documents and an error if any.
+	GetMany(ctx context.Context, namespaceID string, query *query.Query) (returns []models.Return, count int64, err error)
+
+	// Create creates a new return with the provided data. It returns the inserted ID or an error if any.
+	Create(ctx context.Context, ret *models.Return) (insertedID string, err error)
+}
+
+type saleReturn struct {
+	c *mongo.Collection // c is the "return" collection
+}
+
+var _ Return = (*saleReturn)(nil)
+
+func (*saleReturn) Entity() string {
+	return "return"
+}
+
+func (sr *saleReturn) Get(ctx context.Context, namespaceID, id string) (*models.Return, error) {
+	ret := new(models.Return)
+	if err := sr.c.FindOne(ctx, bson.M{"_id": id, "namespace_id": namespaceID}).Decode(ret); err != nil {
+		return nil, mapError(err)
+	}
+
+	return ret, nil
+}
+
+func (sr *saleReturn) GetMany(ctx context.Context, namespaceID string, query *query.Query) ([]models.Return, int64, error) {
+	returns := make([]models.Return, 0)
+	count, err := find(ctx, sr.c, namespaceID, query, &returns)
+
+	return returns, count, err
+}
+
+func (sr *saleReturn) Create(ctx context.Context, ret *models.Return) (string, error) {
+	ret.ID = "ret_" + ulid.Make().String()
+	ret.CreatedAt = clock.Now()
+
+	if ret.Lines == nil {
+		ret.Lines = []models.ReturnLine{}
+	}
+
+	if _, err := sr.c.InsertOne(ctx, ret); err != nil {
+		return "", mapError(err)
+	}
+
+	return ret.ID, nil
+}
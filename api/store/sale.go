@@ -0,0 +1,142 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/heiytor/invenda/api/pkg/clock"
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/money"
+	"github.com/heiytor/invenda/api/pkg/query"
+	"github.com/oklog/ulid/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Sale handles the namespace's counter sales. Every operation is scoped to a namespace ID.
+type Sale interface {
+	Entity
+
+	// Get retrieves a sale with the specified ID. It returns the sale or an error if any.
+	Get(ctx context.Context, namespaceID, id string) (sale *models.Sale, err error)
+
+	// GetByIdempotencyKey retrieves the sale created by the request with the specified idempotency key. It
+	// returns the sale or an error if any.
+	GetByIdempotencyKey(ctx context.Context, namespaceID, key string) (sale *models.Sale, err error)
+
+	// GetMany retrieves a list of sales of a namespace. It returns the list of sales, the total count of the
+	// existent documents and an error if any.
+	GetMany(ctx context.Context, namespaceID string, query *query.Query) (sales []models.Sale, count int64, err error)
+
+	// Create creates a new sale with the provided data. It returns the inserted ID or an error if any. As
+	// idempotency keys are unique within a namespace, a sale whose key was already used returns
+	// [ErrDuplicated].
+	Create(ctx context.Context, sale *models.Sale) (insertedID string, err error)
+
+	// Return adds the quantities of the returned lines to the quantities returned of the sale's lines and
+	// the refunded amounts to the amounts refunded with each tender. The sale is only updated if no line
+	// would be returned beyond its quantity sold, so concurrent returns cannot return a unit twice. It
+	// returns [ErrNotFound] if no such sale is found.
+	Return(ctx context.Context, namespaceID, id string, lines []models.ReturnLine, refunded map[models.TenderMethod]money.Money) (err error)
+
+	// Taxes sums by rate, currency and exchange rate the taxes of the sales made within [from, until). It
+	// returns the taxes or an error if any.
+	Taxes(ctx context.Context, namespaceID string, from, until time.Time) (taxes []models.TaxTotal, err error)
+}
+
+type sale struct {
+	c *mongo.Collection // c is the "sale" collection
+}
+
+var _ Sale = (*sale)(nil)
+
+func (*sale) Entity() string {
+	return "sale"
+}
+
+func (sl *sale) Get(ctx context.Context, namespaceID, id string) (*models.Sale, error) {
+	sal := new(models.Sale)
+	if err := sl.c.FindOne(ctx, bson.M{"_id": id, "namespace_id": namespaceID}).Decode(sal); err != nil {
+		return nil, mapError(err)
+	}
+
+	return sal, nil
+}
+
+func (sl *sale) GetByIdempotencyKey(ctx context.Context, namespaceID, key string) (*models.Sale, error) {
+	sal := new(models.Sale)
+	if err := sl.c.FindOne(ctx, bson.M{"namespace_id": namespaceID, "idempotency_key": key}).Decode(sal); err != nil {
+		return nil, mapError(err)
+	}
+
+	return sal, nil
+}
+
+func (sl *sale) GetMany(ctx context.Context, namespaceID string, query *query.Query) ([]models.Sale, int64, error) {
+	sales := make([]models.Sale, 0)
+	count, err := find(ctx, sl.c, namespaceID, query, &sales)
+
+	return sales, count, err
+}
+
+func (sl *sale) Create(ctx context.Context, sal *models.Sale) (string, error) {
+	sal.ID = "sal_" + ulid.Make().String()
+
+	now := clock.Now()
+	sal.CreatedAt = now
+	sal.UpdatedAt = now
+
+	if sal.Lines == nil {
+		sal.Lines = []models.SaleLine{}
+	}
+
+	if sal.Payments == nil {
+		sal.Payments = []models.Tender{}
+	}
+
+	if _, err := sl.c.InsertOne(ctx, sal); err != nil {
+		return "", mapError(err)
+	}
+
+	return sal.ID, nil
+}
+
+func (sl *sale) Return(ctx context.Context, namespaceID, id string, lines []models.ReturnLine, refunded map[models.TenderMethod]money.Money) error {
+	inc := bson.M{}
+	set := bson.M{"updated_at": clock.Now()}
+	guards := bson.A{}
+
+	for _, l := range lines {
+		line := bson.M{"$arrayElemAt": bson.A{"$lines", l.Line}}
+		returned := bson.M{"$ifNull": bson.A{bson.M{"$getField": bson.M{"field": "returned", "input": line}}, 0}}
+		quantity := bson.M{"$getField": bson.M{"field": "quantity", "input": line}}
+
+		guards = append(guards, bson.M{"$lte": bson.A{bson.M{"$add": bson.A{returned, l.Quantity}}, quantity}})
+		inc[fmt.Sprintf("lines.%d.returned", l.Line)] = l.Quantity
+	}
+
+	for method, amount := range refunded {
+		incMoney(inc, set, "refunded."+string(method), amount)
+	}
+
+	filter := bson.M{"_id": id, "namespace_id": namespaceID}
+	if len(guards) > 0 {
+		filter["$expr"] = bson.M{"$and": guards}
+	}
+
+	res, err := sl.c.UpdateOne(ctx, filter, bson.M{"$inc": inc, "$set": set})
+	if err != nil {
+		return mapError(err)
+	}
+
+	if res.MatchedCount < 1 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (sl *sale) Taxes(ctx context.Context, namespaceID string, from, until time.Time) ([]models.TaxTotal, error) {
+	return sumTaxes(ctx, sl.c, bson.M{"namespace_id": namespaceID, "created_at": bson.M{"$gte": from, "$lt": until}})
+}
@@ -0,0 +1,158 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/money"
+	"github.com/heiytor/invenda/api/store"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaleGetByIdempotencyKey(t *testing.T) {
+	type Actual struct {
+		id  string
+		err error
+	}
+
+	cases := []struct {
+		description string
+		namespaceID string
+		key         string
+		fixtures    []fixture
+		expected    Actual
+	}{
+		{
+			description: "fails when no sale has the key",
+			namespaceID: "ns_01HV7FKH5SRB0TGWM7MQ15PYKN",
+			key:         "00000000-0000-0000-0000-000000000000",
+			fixtures:    []fixture{fixtureSale},
+			expected:    Actual{id: "", err: store.ErrNotFound},
+		},
+		{
+			description: "fails when the key was used in another namespace",
+			namespaceID: "ns_01HWS7Q0H1JCEMKZADAFMETRZJ",
+			key:         "8c0f6c4e-2f7a-4a59-9a51-3b1a54d3e7a1",
+			fixtures:    []fixture{fixtureSale},
+			expected:    Actual{id: "", err: store.ErrNotFound},
+		},
+		{
+			description: "succeeds to find the sale made with the key",
+			namespaceID: "ns_01HV7FKH5SRB0TGWM7MQ15PYKN",
+			key:         "8c0f6c4e-2f7a-4a59-9a51-3b1a54d3e7a1",
+			fixtures:    []fixture{fixtureSale},
+			expected:    Actual{id: "sal_01HXC2B3C4D5E6F7G8H9J0KMNP", err: nil},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			srv.apply(tc.fixtures...)
+			defer srv.reset()
+
+			ctx := context.Background()
+
+			sal, err := s.Sale.GetByIdempotencyKey(ctx, tc.namespaceID, tc.key)
+
+			var id string
+			if sal != nil {
+				id = sal.ID
+			}
+
+			require.Equal(t, tc.expected, Actual{id, err})
+		})
+	}
+}
+
+func TestSaleCreate(t *testing.T) {
+	ctx := context.Background()
+	defer srv.reset()
+
+	// Recreates the unique indexes dropped by previous resets.
+	_, err := store.New(ctx, db.Client(), db.Name())
+	require.NoError(t, err)
+
+	id, err := s.Sale.Create(ctx, &models.Sale{NamespaceID: "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", IdempotencyKey: "key-1"})
+	require.NoError(t, err)
+	require.NotEmpty(t, id)
+
+	_, err = s.Sale.Create(ctx, &models.Sale{NamespaceID: "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", IdempotencyKey: "key-1"})
+	require.ErrorIs(t, err, store.ErrDuplicated)
+
+	_, err = s.Sale.Create(ctx, &models.Sale{NamespaceID: "ns_01HWS7Q0H1JCEMKZADAFMETRZJ", IdempotencyKey: "key-1"})
+	require.NoError(t, err)
+}
+
+func TestSaleReturn(t *testing.T) {
+	cases := []struct {
+		description string
+		lines       []models.ReturnLine
+		refunded    map[models.TenderMethod]money.Money
+		expected    error
+	}{
+		{
+			description: "fails when a line would be returned beyond its quantity sold",
+			lines:       []models.ReturnLine{{Line: 0, Quantity: 5}},
+			expected:    store.ErrNotFound,
+		},
+		{
+			description: "succeeds to return part of a line",
+			lines:       []models.ReturnLine{{Line: 0, Quantity: 4}},
+			refunded:    map[models.TenderMethod]money.Money{models.TenderCash: money.New(2000, "BRL")},
+			expected:    nil,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			srv.apply(fixtureSale)
+			defer srv.reset()
+
+			ctx := context.Background()
+
+			err := s.Sale.Return(ctx, "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", "sal_01HXC2B3C4D5E6F7G8H9J0KMNP", tc.lines, tc.refunded)
+			require.Equal(t, tc.expected, err)
+
+			if err != nil {
+				return
+			}
+
+			sal, err := s.Sale.Get(ctx, "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", "sal_01HXC2B3C4D5E6F7G8H9J0KMNP")
+			require.NoError(t, err)
+			require.Equal(t, int64(4), sal.Lines[0].Returned)
+			require.Equal(t, tc.refunded, sal.Refunded)
+		})
+	}
+}
+
+func TestSaleTaxes(t *testing.T) {
+	ctx := context.Background()
+	defer srv.reset()
+
+	vat := models.AppliedTax{TaxRateID: "txr_1", Name: "VAT", Rate: 1000}
+
+	sales := []*models.Sale{
+		{NamespaceID: "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", IdempotencyKey: "key-1", Taxes: models.AppliedTaxes{{TaxRateID: "txr_1", Name: "VAT", Rate: 1000, Base: money.New(1000, "BRL"), Amount: money.New(100, "BRL")}}},
+		{NamespaceID: "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", IdempotencyKey: "key-2", Taxes: models.AppliedTaxes{{TaxRateID: "txr_1", Name: "VAT", Rate: 1000, Base: money.New(500, "BRL"), Amount: money.New(50, "BRL")}, {Rate: 500, Base: money.New(200, "BRL"), Amount: money.New(10, "BRL")}}},
+		{NamespaceID: "ns_01HWS7Q0H1JCEMKZADAFMETRZJ", IdempotencyKey: "key-1", Taxes: models.AppliedTaxes{{TaxRateID: "txr_2", Name: "VAT", Rate: 2000, Base: money.New(100, "BRL"), Amount: money.New(20, "BRL")}}},
+	}
+
+	for _, sal := range sales {
+		_, err := s.Sale.Create(ctx, sal)
+		require.NoError(t, err)
+	}
+
+	now := time.Now()
+
+	taxes, err := s.Sale.Taxes(ctx, "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", now.Add(-time.Hour), now.Add(time.Hour))
+	require.NoError(t, err)
+
+	vat.Base, vat.Amount = money.New(1500, "BRL"), money.New(150, "BRL")
+	require.Equal(t, []models.TaxTotal{{AppliedTax: models.AppliedTax{Rate: 500, Base: money.New(200, "BRL"), Amount: money.New(10, "BRL")}}, {AppliedTax: vat}}, taxes)
+
+	taxes, err = s.Sale.Taxes(ctx, "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", now.Add(time.Hour), now.Add(2*time.Hour))
+	require.NoError(t, err)
+	require.Empty(t, taxes)
+}
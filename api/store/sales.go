@@ -0,0 +1,126 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/heiytor/invenda/api/pkg/clock"
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/query"
+	"github.com/oklog/ulid/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// SalesOrder handles the namespace's sales orders. Every operation is scoped to a namespace ID.
+type SalesOrder interface {
+	Entity
+
+	// Get retrieves a sales order with the specified ID. It returns the order or an error if any.
+	Get(ctx context.Context, namespaceID, id string) (order *models.SalesOrder, err error)
+
+	// GetMany retrieves a list of sales orders of a namespace. It returns the list of orders, the total count
+	// of the existent documents and an error if any.
+	GetMany(ctx context.Context, namespaceID string, query *query.Query) (orders []models.SalesOrder, count int64, err error)
+
+	// Create creates a new sales order with the provided data. It returns the inserted ID or an error
+	// if any.
+	Create(ctx context.Context, order *models.SalesOrder) (insertedID string, err error)
+
+	// Update updates a sales order with the specified changes and ID. When statuses are provided, the order
+	// is only updated if it is in one of them, which guards status transitions against concurrent changes.
+	// It returns [ErrNotFound] if no matching sales order is found.
+	Update(ctx context.Context, namespaceID, id string, statuses []models.SalesOrderStatus, changes *models.SalesOrderChanges) (err error)
+
+	// Delete deletes a sales order with the specified ID. It returns [ErrNotFound] if no sales order is found.
+	Delete(ctx context.Context, namespaceID, id string) (err error)
+
+	// Taxes sums by rate, currency and exchange rate the taxes of the orders invoiced within [from, until),
+	// which includes the closed ones. It returns the taxes or an error if any.
+	Taxes(ctx context.Context, namespaceID string, from, until time.Time) (taxes []models.TaxTotal, err error)
+}
+
+type salesOrder struct {
+	c *mongo.Collection // c is the "sales_order" collection
+}
+
+var _ SalesOrder = (*salesOrder)(nil)
+
+func (*salesOrder) Entity() string {
+	return "sales_order"
+}
+
+func (p *salesOrder) Get(ctx context.Context, namespaceID, id string) (*models.SalesOrder, error) {
+	so := new(models.SalesOrder)
+	if err := p.c.FindOne(ctx, bson.M{"_id": id, "namespace_id": namespaceID}).Decode(so); err != nil {
+		return nil, mapError(err)
+	}
+
+	return so, nil
+}
+
+func (p *salesOrder) GetMany(ctx context.Context, namespaceID string, query *query.Query) ([]models.SalesOrder, int64, error) {
+	orders := make([]models.SalesOrder, 0)
+	count, err := find(ctx, p.c, namespaceID, query, &orders)
+
+	return orders, count, err
+}
+
+func (p *salesOrder) Create(ctx context.Context, so *models.SalesOrder) (string, error) {
+	so.ID = "so_" + ulid.Make().String()
+
+	now := clock.Now()
+	so.CreatedAt = now
+	so.UpdatedAt = now
+
+	if so.Lines == nil {
+		so.Lines = []models.SalesOrderLine{}
+	}
+
+	if _, err := p.c.InsertOne(ctx, so); err != nil {
+		return "", mapError(err)
+	}
+
+	return so.ID, nil
+}
+
+func (p *salesOrder) Update(ctx context.Context, namespaceID, id string, statuses []models.SalesOrderStatus, changes *models.SalesOrderChanges) error {
+	if changes == nil {
+		return nil
+	}
+
+	changes.UpdatedAt = clock.Now()
+
+	filter := bson.M{"_id": id, "namespace_id": namespaceID}
+	if len(statuses) > 0 {
+		filter["status"] = bson.M{"$in": statuses}
+	}
+
+	res, err := p.c.UpdateOne(ctx, filter, bson.M{"$set": changes})
+	if err != nil {
+		return mapError(err)
+	}
+
+	if res.MatchedCount < 1 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (p *salesOrder) Delete(ctx context.Context, namespaceID, id string) error {
+	res, err := p.c.DeleteOne(ctx, bson.M{"_id": id, "namespace_id": namespaceID})
+	if err != nil {
+		return mapError(err)
+	}
+
+	if res.DeletedCount < 1 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (p *salesOrder) Taxes(ctx context.Context, namespaceID string, from, until time.Time) ([]models.TaxTotal, error) {
+	return sumTaxes(ctx, p.c, bson.M{"namespace_id": namespaceID, "invoiced_at": bson.M{"$gte": from, "$lt": until}})
+}
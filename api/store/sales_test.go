@@ -0,0 +1,72 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/store"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestSalesOrderUpdate(t *testing.T) {
+	type Expected struct {
+		err    error
+		status models.SalesOrderStatus
+	}
+
+	cases := []struct {
+		description string
+		id          string
+		statuses    []models.SalesOrderStatus
+		changes     *models.SalesOrderChanges
+		fixtures    []fixture
+		expected    Expected
+	}{
+		{
+			description: "fails when sales order is not found",
+			id:          "so_00000000000000000000000000",
+			statuses:    []models.SalesOrderStatus{},
+			changes:     &models.SalesOrderChanges{Status: models.SalesOrderConfirmed},
+			fixtures:    []fixture{},
+			expected:    Expected{err: store.ErrNotFound},
+		},
+		{
+			description: "fails when sales order is not in the expected status",
+			id:          "so_01HXB5G6H7J8K9M0NPQRSTUVWX",
+			statuses:    []models.SalesOrderStatus{models.SalesOrderConfirmed},
+			changes:     &models.SalesOrderChanges{Status: models.SalesOrderShipped},
+			fixtures:    []fixture{fixtureSalesOrder},
+			expected:    Expected{err: store.ErrNotFound, status: models.SalesOrderDraft},
+		},
+		{
+			description: "succeeds to transition a sales order",
+			id:          "so_01HXB5G6H7J8K9M0NPQRSTUVWX",
+			statuses:    []models.SalesOrderStatus{models.SalesOrderDraft},
+			changes:     &models.SalesOrderChanges{Status: models.SalesOrderConfirmed},
+			fixtures:    []fixture{fixtureSalesOrder},
+			expected:    Expected{err: nil, status: models.SalesOrderConfirmed},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			srv.apply(tc.fixtures...)
+			defer srv.reset()
+
+			ctx := context.Background()
+
+			err := s.SalesOrder.Update(ctx, "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", tc.id, tc.statuses, tc.changes)
+			require.Equal(t, tc.expected.err, err)
+
+			if tc.expected.status == "" {
+				return
+			}
+
+			so := new(models.SalesOrder)
+			require.NoError(t, db.Collection("sales_order").FindOne(ctx, bson.M{"_id": tc.id}).Decode(so))
+			require.Equal(t, tc.expected.status, so.Status)
+		})
+	}
+}
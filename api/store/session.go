@@ -53,7 +53,12 @@ func (s *session) Get(ctx context.Context, id string, opts ...GetSessionOption)
 }
 
 func (s *session) List(ctx context.Context, userID string, query *query.Query, opts ...GetSessionOption) ([]models.Session, int64, error) {
-	match := bson.M{"userID": userID}
+	match := bson.M{
+		"$and": []bson.M{
+			{"userID": userID},
+			internal.FromFilter(&query.Filter),
+		},
+	}
 
 	count, err := s.c.CountDocuments(ctx, match)
 	if err != nil {
@@ -62,8 +67,8 @@ func (s *session) List(ctx context.Context, userID string, query *query.Query, o
 
 	pipeline := make([]bson.M, 0)
 	pipeline = append(pipeline, bson.M{"$match": match})
-	pipeline = append(pipeline, internal.FromPaginator(&query.Paginator)...)
 	pipeline = append(pipeline, internal.FromSorter(&query.Sorter)...)
+	pipeline = append(pipeline, internal.FromPaginator(&query.Paginator)...)
 
 	cursor, err := s.c.Aggregate(ctx, pipeline)
 	if err != nil {
@@ -0,0 +1,159 @@
+package store
+
+import (
+	"context"
+
+	"github.com/heiytor/invenda/api/pkg/clock"
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/money"
+	"github.com/heiytor/invenda/api/pkg/query"
+	"github.com/oklog/ulid/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Shift handles the shifts of the namespace's registers. Every operation is scoped to a namespace ID.
+// Only open shifts are ever written, so a closed shift cannot be changed.
+type Shift interface {
+	Entity
+
+	// Get retrieves a shift with the specified ID. It returns the shift or an error if any.
+	Get(ctx context.Context, namespaceID, id string) (shift *models.Shift, err error)
+
+	// GetOpen retrieves the open shift of a register. It returns the shift or [ErrNotFound] if the register
+	// has no open shift.
+	GetOpen(ctx context.Context, namespaceID, registerID string) (shift *models.Shift, err error)
+
+	// GetMany retrieves a list of shifts of a namespace. It returns the list of shifts, the total count of the
+	// existent documents and an error if any.
+	GetMany(ctx context.Context, namespaceID string, query *query.Query) (shifts []models.Shift, count int64, err error)
+
+	// Create opens a new shift with the provided data. It returns the inserted ID or an error if any. As a
+	// register has at most one open shift, opening another one returns [ErrDuplicated].
+	Create(ctx context.Context, shift *models.Shift) (insertedID string, err error)
+
+	// Record adds the delta to the totals of an open shift with the specified ID. It returns [ErrNotFound]
+	// if no open shift is found.
+	Record(ctx context.Context, namespaceID, id string, delta *models.ShiftDelta) (err error)
+
+	// Close closes an open shift with the specified ID on behalf of a user, storing the reconciliation of
+	// its drawer. It returns [ErrNotFound] if no open shift is found.
+	Close(ctx context.Context, namespaceID, id, userID string, report *models.ShiftReport) (err error)
+}
+
+type shift struct {
+	c *mongo.Collection // c is the "shift" collection
+}
+
+var _ Shift = (*shift)(nil)
+
+func (*shift) Entity() string {
+	return "shift"
+}
+
+func (sh *shift) Get(ctx context.Context, namespaceID, id string) (*models.Shift, error) {
+	shf := new(models.Shift)
+	if err := sh.c.FindOne(ctx, bson.M{"_id": id, "namespace_id": namespaceID}).Decode(shf); err != nil {
+		return nil, mapError(err)
+	}
+
+	return shf, nil
+}
+
+func (sh *shift) GetOpen(ctx context.Context, namespaceID, registerID string) (*models.Shift, error) {
+	shf := new(models.Shift)
+	if err := sh.c.FindOne(ctx, bson.M{"namespace_id": namespaceID, "register_id": registerID, "status": models.ShiftOpen}).Decode(shf); err != nil {
+		return nil, mapError(err)
+	}
+
+	return shf, nil
+}
+
+func (sh *shift) GetMany(ctx context.Context, namespaceID string, query *query.Query) ([]models.Shift, int64, error) {
+	shifts := make([]models.Shift, 0)
+	count, err := find(ctx, sh.c, namespaceID, query, &shifts)
+
+	return shifts, count, err
+}
+
+func (sh *shift) Create(ctx context.Context, shf *models.Shift) (string, error) {
+	shf.ID = "shf_" + ulid.Make().String()
+
+	now := clock.Now()
+	shf.CreatedAt = now
+	shf.UpdatedAt = now
+	shf.Status = models.ShiftOpen
+
+	if shf.Takings == nil {
+		shf.Takings = map[models.TenderMethod]money.Money{}
+	}
+
+	if shf.CashMovements == nil {
+		shf.CashMovements = []models.CashMovement{}
+	}
+
+	if _, err := sh.c.InsertOne(ctx, shf); err != nil {
+		return "", mapError(err)
+	}
+
+	return shf.ID, nil
+}
+
+func (sh *shift) Record(ctx context.Context, namespaceID, id string, delta *models.ShiftDelta) error {
+	inc := bson.M{"sales": delta.Sales}
+	set := bson.M{"updated_at": clock.Now()}
+
+	incMoney(inc, set, "cash_refunds", delta.CashRefunds)
+	for method, amount := range delta.Takings {
+		incMoney(inc, set, "takings."+string(method), amount)
+	}
+
+	update := bson.M{"$inc": inc, "$set": set}
+
+	if m := delta.CashMovement; m != nil {
+		switch m.Type {
+		case models.CashPayIn:
+			incMoney(inc, set, "pay_ins", m.Amount)
+		case models.CashPayOut:
+			incMoney(inc, set, "pay_outs", m.Amount)
+		}
+
+		update["$push"] = bson.M{"cash_movements": m}
+	}
+
+	res, err := sh.c.UpdateOne(ctx, bson.M{"_id": id, "namespace_id": namespaceID, "status": models.ShiftOpen}, update)
+	if err != nil {
+		return mapError(err)
+	}
+
+	if res.MatchedCount < 1 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (sh *shift) Close(ctx context.Context, namespaceID, id, userID string, report *models.ShiftReport) error {
+	now := clock.Now()
+
+	update := bson.M{
+		"$set": bson.M{
+			"status":     models.ShiftClosed,
+			"closed_by":  userID,
+			"closed_at":  now,
+			"report":     report,
+			"updated_at": now,
+		},
+	}
+
+	res, err := sh.c.UpdateOne(ctx, bson.M{"_id": id, "namespace_id": namespaceID, "status": models.ShiftOpen}, update)
+	if err != nil {
+		return mapError(err)
+	}
+
+	if res.MatchedCount < 1 {
+		return ErrNotFound
+	}
+
+	return nil
+}
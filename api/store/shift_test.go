@@ -0,0 +1,119 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/money"
+	"github.com/heiytor/invenda/api/store"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestShiftCreate(t *testing.T) {
+	ctx := context.Background()
+	defer srv.reset()
+
+	// Recreates the unique indexes dropped by previous resets.
+	_, err := store.New(ctx, db.Client(), db.Name())
+	require.NoError(t, err)
+
+	id, err := s.Shift.Create(ctx, &models.Shift{NamespaceID: "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", RegisterID: "reg_01HXC1A2B3C4D5E6F7G8H9J0KM", Float: money.New(5000, "BRL")})
+	require.NoError(t, err)
+
+	_, err = s.Shift.Create(ctx, &models.Shift{NamespaceID: "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", RegisterID: "reg_01HXC1A2B3C4D5E6F7G8H9J0KM"})
+	require.ErrorIs(t, err, store.ErrDuplicated)
+
+	_, err = s.Shift.Create(ctx, &models.Shift{NamespaceID: "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", RegisterID: "reg_01HXC1B2C3D4E5F6G7H8J9K0MN"})
+	require.NoError(t, err)
+
+	// Once closed, the register can open another shift.
+	require.NoError(t, s.Shift.Close(ctx, "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", id, "01HNGJ2BTGQAHAZ1XNYZQPG719", &models.ShiftReport{Expected: money.New(5000, "BRL"), Counted: money.New(5000, "BRL")}))
+
+	_, err = s.Shift.Create(ctx, &models.Shift{NamespaceID: "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", RegisterID: "reg_01HXC1A2B3C4D5E6F7G8H9J0KM"})
+	require.NoError(t, err)
+}
+
+func TestShiftRecord(t *testing.T) {
+	cases := []struct {
+		description string
+		id          string
+		delta       *models.ShiftDelta
+		fixtures    []fixture
+		expected    error
+	}{
+		{
+			description: "fails when shift is not found",
+			id:          "shf_00000000000000000000000000",
+			delta:       &models.ShiftDelta{Sales: 1},
+			fixtures:    []fixture{},
+			expected:    store.ErrNotFound,
+		},
+		{
+			description: "fails when shift is closed",
+			id:          "shf_01HXC3D4E5F6G7H8J9K0MNPQRS",
+			delta:       &models.ShiftDelta{Sales: 1},
+			fixtures:    []fixture{fixtureShift},
+			expected:    store.ErrNotFound,
+		},
+		{
+			description: "succeeds to record a sale and a pay-out",
+			id:          "shf_01HXC3C4D5E6F7G8H9J0KMNPQR",
+			delta: &models.ShiftDelta{
+				Sales:        1,
+				Takings:      map[models.TenderMethod]money.Money{models.TenderCash: money.New(500, "BRL"), models.TenderCard: money.New(1200, "BRL")},
+				CashMovement: &models.CashMovement{Type: models.CashPayOut, Amount: money.New(300, "BRL"), Reason: "milk delivery"},
+			},
+			fixtures: []fixture{fixtureShift},
+			expected: nil,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			srv.apply(tc.fixtures...)
+			defer srv.reset()
+
+			ctx := context.Background()
+
+			err := s.Shift.Record(ctx, "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", tc.id, tc.delta)
+			require.Equal(t, tc.expected, err)
+
+			if err != nil {
+				return
+			}
+
+			shf := new(models.Shift)
+			require.NoError(t, db.Collection("shift").FindOne(ctx, bson.M{"_id": tc.id}).Decode(shf))
+			require.Equal(t, int64(2), shf.Sales)
+			require.Equal(t, map[models.TenderMethod]money.Money{models.TenderCash: money.New(2500, "BRL"), models.TenderCard: money.New(1200, "BRL")}, shf.Takings)
+			require.Equal(t, money.New(300, "BRL"), shf.PayOuts)
+			require.Len(t, shf.CashMovements, 1)
+		})
+	}
+}
+
+func TestShiftClose(t *testing.T) {
+	srv.apply(fixtureShift)
+	defer srv.reset()
+
+	ctx := context.Background()
+
+	report := &models.ShiftReport{Expected: money.New(12000, "BRL"), Counted: money.New(11950, "BRL"), Difference: money.New(-50, "BRL")}
+
+	err := s.Shift.Close(ctx, "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", "shf_01HXC3D4E5F6G7H8J9K0MNPQRS", "01HNGJ2BTGQAHAZ1XNYZQPG719", report)
+	require.Equal(t, store.ErrNotFound, err)
+
+	err = s.Shift.Close(ctx, "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", "shf_01HXC3C4D5E6F7G8H9J0KMNPQR", "01HNGJ2BTGQAHAZ1XNYZQPG719", report)
+	require.NoError(t, err)
+
+	shf, err := s.Shift.Get(ctx, "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", "shf_01HXC3C4D5E6F7G8H9J0KMNPQR")
+	require.NoError(t, err)
+	require.Equal(t, models.ShiftClosed, shf.Status)
+	require.Equal(t, report, shf.Report)
+	require.NotNil(t, shf.ClosedAt)
+
+	_, err = s.Shift.GetOpen(ctx, "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", "reg_01HXC1A2B3C4D5E6F7G8H9J0KM")
+	require.Equal(t, store.ErrNotFound, err)
+}
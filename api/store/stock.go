@@ -0,0 +1,488 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/heiytor/invenda/api/pkg/clock"
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/query"
+	"github.com/heiytor/invenda/api/store/internal"
+	"github.com/oklog/ulid/v2"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Stock handles the namespace's stock ledger and the balances materialized from it. Every operation is
+// scoped to a namespace ID.
+type Stock interface {
+	Entity
+
+	// Movements retrieves a list of movements of a namespace. It returns the list of movements, the total count
+	// of the existent documents and an error if any.
+	Movements(ctx context.Context, namespaceID string, query *query.Query) (movements []models.Movement, count int64, err error)
+
+	// Balance retrieves the balance of the specified key. It returns [ErrNotFound] if the key has never
+	// been moved.
+	Balance(ctx context.Context, namespaceID string, key models.StockKey) (balance *models.Balance, err error)
+
+	// Balances retrieves a list of balances of a namespace. It returns the list of balances, the total count
+	// of the existent documents and an error if any.
+	Balances(ctx context.Context, namespaceID string, query *query.Query) (balances []models.Balance, count int64, err error)
+
+	// Post appends the movements to the ledger and applies their quantities to the balances, and to the
+	// balances of their lots when they have one. Unless allowNegative is true, a movement that would bring a
+	// balance's available quantity below zero fails with [ErrInsufficientStock], so reserved stock can
+	// only be taken after releasing its reservation; lots can never go below zero. The serials named
+	// by the movements are moved along: inbound serials fail with [ErrDuplicated] when already in stock and
+	// outbound serials fail with [ErrInsufficientStock] when not in stock at the movement's key. As the
+	// movements are applied one by one, Post must be executed within a transaction.
+	Post(ctx context.Context, allowNegative bool, movements ...*models.Movement) (err error)
+
+	// Lots retrieves a list of lots of a namespace. It returns the list of lots, the total count of the
+	// existent documents and an error if any.
+	Lots(ctx context.Context, namespaceID string, query *query.Query) (lots []models.StockLot, count int64, err error)
+
+	// LotsOf retrieves every lot of the specified key with stock on hand. It returns the list of lots or
+	// an error if any.
+	LotsOf(ctx context.Context, namespaceID string, key models.StockKey) (lots []models.StockLot, err error)
+
+	// Serial retrieves the serialized unit of a product. It returns [ErrNotFound] if the serial has never
+	// been received.
+	Serial(ctx context.Context, namespaceID, productID, serial string) (unit *models.StockSerial, err error)
+
+	// Serials retrieves a list of serialized units of a namespace. It returns the list of units, the total
+	// count of the existent documents and an error if any.
+	Serials(ctx context.Context, namespaceID string, query *query.Query) (units []models.StockSerial, count int64, err error)
+
+	// Reserve increments the reserved quantity of the specified key by delta. An increment only succeeds
+	// when the key's available quantity covers it and a decrement when the reserved quantity covers it;
+	// otherwise it fails with [ErrInsufficientStock]. The check and the write are a single conditional
+	// update, so concurrent reservations cannot both take the last unit.
+	Reserve(ctx context.Context, namespaceID string, key models.StockKey, delta int64) (err error)
+
+	// Transit increments the in-transit quantity of the specified key by delta, creating its balance
+	// when it does not exist.
+	Transit(ctx context.Context, namespaceID string, key models.StockKey, delta int64) (err error)
+
+	// Valuation sums the quantities and values of the movements posted until asOf by product or variant,
+	// only considering the movements of a warehouse when warehouseID is not empty. It returns the lines
+	// sorted by SKU or an error if any.
+	Valuation(ctx context.Context, namespaceID string, asOf time.Time, warehouseID string) (lines []models.ValuationLine, err error)
+
+	// Rebuild recomputes the on-hand quantity of every balance of a namespace from its ledger. It must be
+	// executed within a transaction.
+	Rebuild(ctx context.Context, namespaceID string) (err error)
+}
+
+type stock struct {
+	movements *mongo.Collection // movements is the "movement" collection
+	balances  *mongo.Collection // balances is the "balance" collection
+	lots      *mongo.Collection // lots is the "stock_lot" collection
+	serials   *mongo.Collection // serials is the "stock_serial" collection
+}
+
+var _ Stock = (*stock)(nil)
+
+func (*stock) Entity() string {
+	return "stock"
+}
+
+func (s *stock) Movements(ctx context.Context, namespaceID string, query *query.Query) ([]models.Movement, int64, error) {
+	movements := make([]models.Movement, 0)
+	count, err := find(ctx, s.movements, namespaceID, query, &movements)
+
+	return movements, count, err
+}
+
+func (s *stock) Balance(ctx context.Context, namespaceID string, key models.StockKey) (*models.Balance, error) {
+	balance := new(models.Balance)
+	if err := s.balances.FindOne(ctx, keyFilter(namespaceID, key)).Decode(balance); err != nil {
+		return nil, mapError(err)
+	}
+
+	return balance, nil
+}
+
+func (s *stock) Balances(ctx context.Context, namespaceID string, query *query.Query) ([]models.Balance, int64, error) {
+	balances := make([]models.Balance, 0)
+	count, err := find(ctx, s.balances, namespaceID, query, &balances)
+
+	return balances, count, err
+}
+
+func (s *stock) Post(ctx context.Context, allowNegative bool, movements ...*models.Movement) error {
+	now := clock.Now()
+
+	docs := make([]interface{}, 0, len(movements))
+	for _, m := range movements {
+		m.ID = "mov_" + ulid.Make().String()
+		m.CreatedAt = now
+
+		docs = append(docs, m)
+	}
+
+	if len(docs) == 0 {
+		return nil
+	}
+
+	if _, err := s.movements.InsertMany(ctx, docs); err != nil {
+		return mapError(err)
+	}
+
+	for _, m := range movements {
+		if err := s.apply(ctx, m.NamespaceID, m.StockKey, m.Quantity, allowNegative, now); err != nil {
+			return err
+		}
+
+		if m.Lot != "" {
+			if err := s.applyLot(ctx, m, now); err != nil {
+				return err
+			}
+		}
+
+		if err := s.applySerials(ctx, m, now); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// applyLot increments the on-hand quantity of the movement's lot, creating the lot when it does not exist.
+func (s *stock) applyLot(ctx context.Context, m *models.Movement, now time.Time) error {
+	filter := keyFilter(m.NamespaceID, m.StockKey)
+	filter["lot"] = m.Lot
+
+	update := bson.M{
+		"$inc":         bson.M{"on_hand": m.Quantity},
+		"$set":         bson.M{"updated_at": now},
+		"$setOnInsert": bson.M{"_id": "lot_" + ulid.Make().String(), "expires_at": m.ExpiresAt, "received_at": now},
+	}
+
+	if m.Quantity >= 0 {
+		_, err := s.lots.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+		return mapError(err)
+	}
+
+	filter["on_hand"] = bson.M{"$gte": -m.Quantity}
+
+	res, err := s.lots.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return mapError(err)
+	}
+
+	if res.MatchedCount < 1 {
+		return ErrInsufficientStock
+	}
+
+	return nil
+}
+
+// applySerials moves the movement's serials to its key, or out of it when the movement is outbound.
+func (s *stock) applySerials(ctx context.Context, m *models.Movement, now time.Time) error {
+	for _, serial := range m.Serials {
+		filter := bson.M{"namespace_id": m.NamespaceID, "product_id": m.ProductID, "serial": serial}
+
+		if m.Quantity > 0 {
+			// A serial that is already in stock does not match the filter, so the upsert violates the
+			// serial's unique index.
+			filter["status"] = bson.M{"$ne": models.SerialInStock}
+			update := bson.M{
+				"$set": bson.M{
+					"updated_at":   now,
+					"warehouse_id": m.WarehouseID,
+					"location":     m.Location,
+					"variant_id":   m.VariantID,
+					"status":       models.SerialInStock,
+				},
+				"$setOnInsert": bson.M{"_id": "ser_" + ulid.Make().String(), "created_at": now},
+			}
+
+			if _, err := s.serials.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true)); err != nil {
+				return mapError(err)
+			}
+
+			continue
+		}
+
+		status := models.SerialOut
+		if m.Type == models.MovementTransferOut {
+			status = models.SerialInTransit
+		}
+
+		filter = keyFilter(m.NamespaceID, m.StockKey)
+		filter["serial"] = serial
+		filter["status"] = models.SerialInStock
+
+		res, err := s.serials.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"updated_at": now, "status": status}})
+		if err != nil {
+			return mapError(err)
+		}
+
+		if res.MatchedCount < 1 {
+			return ErrInsufficientStock
+		}
+	}
+
+	return nil
+}
+
+func (s *stock) Lots(ctx context.Context, namespaceID string, query *query.Query) ([]models.StockLot, int64, error) {
+	lots := make([]models.StockLot, 0)
+	count, err := find(ctx, s.lots, namespaceID, query, &lots)
+
+	return lots, count, err
+}
+
+func (s *stock) LotsOf(ctx context.Context, namespaceID string, key models.StockKey) ([]models.StockLot, error) {
+	filter := keyFilter(namespaceID, key)
+	filter["on_hand"] = bson.M{"$gt": 0}
+
+	cursor, err := s.lots.Find(ctx, filter)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	defer cursor.Close(ctx)
+
+	lots := make([]models.StockLot, 0)
+	if err := cursor.All(ctx, &lots); err != nil {
+		return nil, mapError(err)
+	}
+
+	return lots, nil
+}
+
+// apply increments the on-hand quantity of a balance by delta, creating the balance when it does not exist.
+func (s *stock) apply(ctx context.Context, namespaceID string, key models.StockKey, delta int64, allowNegative bool, now time.Time) error {
+	filter := keyFilter(namespaceID, key)
+	update := bson.M{
+		"$inc":         bson.M{"on_hand": delta, "version": 1},
+		"$set":         bson.M{"updated_at": now},
+		"$setOnInsert": bson.M{"_id": "bal_" + ulid.Make().String(), "in_transit": 0, "reserved": 0},
+	}
+
+	if delta >= 0 || allowNegative {
+		_, err := s.balances.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+		return mapError(err)
+	}
+
+	// The decrement only matches when there is enough available stock, which keeps the check and the
+	// write atomic.
+	filter["$expr"] = bson.M{"$gte": bson.A{available, -delta}}
+
+	res, err := s.balances.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return mapError(err)
+	}
+
+	if res.MatchedCount < 1 {
+		return ErrInsufficientStock
+	}
+
+	return nil
+}
+
+func (s *stock) Valuation(ctx context.Context, namespaceID string, asOf time.Time, warehouseID string) ([]models.ValuationLine, error) {
+	match := bson.M{"namespace_id": namespaceID, "created_at": bson.M{"$lte": asOf}}
+	if warehouseID != "" {
+		match["warehouse_id"] = warehouseID
+	}
+
+	// The values are of the namespace's base currency, which the movements posted before the namespace
+	// had one lack, so each line takes the currency of the movements that have it.
+	pipeline := []bson.M{
+		{"$match": match},
+		{
+			"$group": bson.M{
+				"_id":      bson.M{"product_id": "$product_id", "variant_id": "$variant_id"},
+				"quantity": bson.M{"$sum": "$quantity"},
+				"value":    bson.M{"$sum": "$value.amount"},
+				"currency": bson.M{"$max": "$value.currency"},
+			},
+		},
+		{"$match": bson.M{"$or": []bson.M{{"quantity": bson.M{"$ne": 0}}, {"value": bson.M{"$ne": 0}}}}},
+		{"$lookup": bson.M{"from": "product", "localField": "_id.product_id", "foreignField": "_id", "as": "product"}},
+		{"$lookup": bson.M{"from": "variant", "localField": "_id.variant_id", "foreignField": "_id", "as": "variant"}},
+		{
+			"$project": bson.M{
+				"_id":        0,
+				"product_id": "$_id.product_id",
+				"variant_id": "$_id.variant_id",
+				"sku":        bson.M{"$ifNull": []interface{}{bson.M{"$first": "$variant.sku"}, bson.M{"$first": "$product.sku"}}},
+				"name":       bson.M{"$first": "$product.name"},
+				"quantity":   1,
+				"value":      bson.M{"amount": "$value", "currency": "$currency"},
+			},
+		},
+		{"$sort": bson.D{{Key: "sku", Value: 1}, {Key: "variant_id", Value: 1}}},
+	}
+
+	cursor, err := s.movements.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	defer cursor.Close(ctx)
+
+	lines := make([]models.ValuationLine, 0)
+	if err := cursor.All(ctx, &lines); err != nil {
+		return nil, mapError(err)
+	}
+
+	return lines, nil
+}
+
+// available is the aggregation expression of a balance's available quantity.
+var available = bson.M{"$subtract": bson.A{"$on_hand", "$reserved"}}
+
+func (s *stock) Reserve(ctx context.Context, namespaceID string, key models.StockKey, delta int64) error {
+	filter := keyFilter(namespaceID, key)
+	if delta >= 0 {
+		filter["$expr"] = bson.M{"$gte": bson.A{available, delta}}
+	} else {
+		filter["reserved"] = bson.M{"$gte": -delta}
+	}
+
+	res, err := s.balances.UpdateOne(ctx, filter, bson.M{"$inc": bson.M{"reserved": delta}, "$set": bson.M{"updated_at": clock.Now()}})
+	if err != nil {
+		return mapError(err)
+	}
+
+	if res.MatchedCount < 1 {
+		return ErrInsufficientStock
+	}
+
+	return nil
+}
+
+func (s *stock) Serial(ctx context.Context, namespaceID, productID, serial string) (*models.StockSerial, error) {
+	unit := new(models.StockSerial)
+	if err := s.serials.FindOne(ctx, bson.M{"namespace_id": namespaceID, "product_id": productID, "serial": serial}).Decode(unit); err != nil {
+		return nil, mapError(err)
+	}
+
+	return unit, nil
+}
+
+func (s *stock) Serials(ctx context.Context, namespaceID string, query *query.Query) ([]models.StockSerial, int64, error) {
+	units := make([]models.StockSerial, 0)
+	count, err := find(ctx, s.serials, namespaceID, query, &units)
+
+	return units, count, err
+}
+
+func (s *stock) Transit(ctx context.Context, namespaceID string, key models.StockKey, delta int64) error {
+	update := bson.M{
+		"$inc":         bson.M{"in_transit": delta},
+		"$set":         bson.M{"updated_at": clock.Now()},
+		"$setOnInsert": bson.M{"_id": "bal_" + ulid.Make().String(), "on_hand": 0, "reserved": 0, "version": 0},
+	}
+
+	_, err := s.balances.UpdateOne(ctx, keyFilter(namespaceID, key), update, options.Update().SetUpsert(true))
+	return mapError(err)
+}
+
+func (s *stock) Rebuild(ctx context.Context, namespaceID string) error {
+	now := clock.Now()
+
+	// Balances whose movements no longer sum to anything must be zeroed as well.
+	if _, err := s.balances.UpdateMany(ctx, bson.M{"namespace_id": namespaceID}, bson.M{"$set": bson.M{"on_hand": 0, "updated_at": now}, "$inc": bson.M{"version": 1}}); err != nil {
+		return mapError(err)
+	}
+
+	pipeline := []bson.M{
+		{"$match": bson.M{"namespace_id": namespaceID}},
+		{
+			"$group": bson.M{
+				"_id": bson.M{
+					"warehouse_id": "$warehouse_id",
+					"location":     "$location",
+					"product_id":   "$product_id",
+					"variant_id":   "$variant_id",
+				},
+				"on_hand": bson.M{"$sum": "$quantity"},
+			},
+		},
+	}
+
+	cursor, err := s.movements.Aggregate(ctx, pipeline)
+	if err != nil {
+		return mapError(err)
+	}
+	defer cursor.Close(ctx)
+
+	writes := make([]mongo.WriteModel, 0)
+	for cursor.Next(ctx) {
+		sum := new(struct {
+			Key    models.StockKey `bson:"_id"`
+			OnHand int64           `bson:"on_hand"`
+		})
+
+		if err := cursor.Decode(sum); err != nil {
+			return mapError(err)
+		}
+
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(keyFilter(namespaceID, sum.Key)).
+			SetUpdate(bson.M{
+				"$set":         bson.M{"on_hand": sum.OnHand, "updated_at": now},
+				"$inc":         bson.M{"version": 1},
+				"$setOnInsert": bson.M{"_id": "bal_" + ulid.Make().String(), "in_transit": 0, "reserved": 0},
+			}).
+			SetUpsert(true),
+		)
+	}
+
+	if len(writes) == 0 {
+		return nil
+	}
+
+	_, err = s.balances.BulkWrite(ctx, writes)
+	return mapError(err)
+}
+
+// keyFilter returns a filter that matches the documents of the specified stock key.
+func keyFilter(namespaceID string, key models.StockKey) bson.M {
+	return bson.M{
+		"namespace_id": namespaceID,
+		"warehouse_id": key.WarehouseID,
+		"location":     key.Location,
+		"product_id":   key.ProductID,
+		"variant_id":   key.VariantID,
+	}
+}
+
+// find decodes into out the documents of a namespace matching the query. It returns the total count
+// of the matched documents or an error if any.
+func find[T any](ctx context.Context, c *mongo.Collection, namespaceID string, query *query.Query, out *[]T) (int64, error) {
+	match := bson.M{
+		"$and": []bson.M{
+			{"namespace_id": namespaceID},
+			internal.FromFilter(&query.Filter),
+		},
+	}
+
+	count, err := c.CountDocuments(ctx, match)
+	if err != nil {
+		log.Error().Err(err).Msg("unable to count the total documents")
+	}
+
+	pipeline := make([]bson.M, 0)
+	pipeline = append(pipeline, bson.M{"$match": match})
+	pipeline = append(pipeline, internal.FromSorter(&query.Sorter)...)
+	pipeline = append(pipeline, internal.FromPaginator(&query.Paginator)...)
+
+	cursor, err := c.Aggregate(ctx, pipeline)
+	if err != nil {
+		return 0, mapError(err)
+	}
+	defer cursor.Close(ctx)
+
+	if err := cursor.All(ctx, out); err != nil {
+		return 0, mapError(err)
+	}
+
+	return count, nil
+}
@@ -0,0 +1,468 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/money"
+	"github.com/heiytor/invenda/api/store"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestStockPost(t *testing.T) {
+	type Expected struct {
+		err    error
+		onHand int64
+	}
+
+	key := models.StockKey{
+		WarehouseID: "wh_01HX5C1A2B3C4D5E6F7G8H9J0K",
+		ProductID:   "prd_01HX3A1B2C3D4E5F6G7H8J9K0M",
+	}
+
+	cases := []struct {
+		description   string
+		allowNegative bool
+		typ           models.MovementType
+		quantity      int64
+		fixtures      []fixture
+		expected      Expected
+	}{
+		{
+			description:   "succeeds to create the balance of a new key",
+			allowNegative: false,
+			typ:           models.MovementReceipt,
+			quantity:      5,
+			fixtures:      []fixture{},
+			expected:      Expected{err: nil, onHand: 5},
+		},
+		{
+			description:   "succeeds to decrease the balance when there is enough stock",
+			allowNegative: false,
+			typ:           models.MovementIssue,
+			quantity:      -6,
+			fixtures:      []fixture{fixtureStock},
+			expected:      Expected{err: nil, onHand: 0},
+		},
+		{
+			description:   "fails when the balance would go below zero",
+			allowNegative: false,
+			typ:           models.MovementIssue,
+			quantity:      -7,
+			fixtures:      []fixture{fixtureStock},
+			expected:      Expected{err: store.ErrInsufficientStock, onHand: 6},
+		},
+		{
+			description:   "succeeds to go below zero when backorders are allowed",
+			allowNegative: true,
+			typ:           models.MovementIssue,
+			quantity:      -7,
+			fixtures:      []fixture{fixtureStock},
+			expected:      Expected{err: nil, onHand: -1},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			srv.apply(tc.fixtures...)
+			defer srv.reset()
+
+			ctx := context.Background()
+
+			mov := &models.Movement{
+				NamespaceID: "ns_01HV7FKH5SRB0TGWM7MQ15PYKN",
+				StockKey:    key,
+				Type:        tc.typ,
+				Quantity:    tc.quantity,
+				Reason:      "test",
+				UserID:      "01HNGJ2BTGQAHAZ1XNYZQPG719",
+			}
+
+			err := s.WithTransaction(ctx, func(ctx context.Context) error {
+				return s.Stock.Post(ctx, tc.allowNegative, mov)
+			})
+			require.Equal(t, tc.expected.err, err)
+
+			balance, err := s.Stock.Balance(ctx, "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", key)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected.onHand, balance.OnHand)
+
+			// A failed post must not leave its movement in the ledger.
+			count, err := db.Collection("movement").CountDocuments(ctx, bson.M{"reason": "test"})
+			require.NoError(t, err)
+			require.Equal(t, tc.expected.err == nil, count == 1)
+		})
+	}
+}
+
+func TestStockRebuild(t *testing.T) {
+	srv.apply(fixtureStock)
+	defer srv.reset()
+
+	ctx := context.Background()
+
+	key := models.StockKey{
+		WarehouseID: "wh_01HX5C1A2B3C4D5E6F7G8H9J0K",
+		ProductID:   "prd_01HX3A1B2C3D4E5F6G7H8J9K0M",
+	}
+
+	_, err := db.Collection("balance").UpdateMany(ctx, bson.M{}, bson.M{"$set": bson.M{"on_hand": 100}})
+	require.NoError(t, err)
+
+	err = s.WithTransaction(ctx, func(ctx context.Context) error {
+		return s.Stock.Rebuild(ctx, "ns_01HV7FKH5SRB0TGWM7MQ15PYKN")
+	})
+	require.NoError(t, err)
+
+	balance, err := s.Stock.Balance(ctx, "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", key)
+	require.NoError(t, err)
+	require.Equal(t, int64(6), balance.OnHand)
+}
+
+func TestStockTransit(t *testing.T) {
+	srv.apply(fixtureStock)
+	defer srv.reset()
+
+	ctx := context.Background()
+
+	key := models.StockKey{
+		WarehouseID: "wh_01HX5C2M3N4P5Q6R7S8T9V0W1X",
+		ProductID:   "prd_01HX3A1B2C3D4E5F6G7H8J9K0M",
+	}
+
+	require.NoError(t, s.Stock.Transit(ctx, "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", key, 4))
+	require.NoError(t, s.Stock.Transit(ctx, "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", key, -1))
+
+	balance, err := s.Stock.Balance(ctx, "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", key)
+	require.NoError(t, err)
+	require.Equal(t, int64(0), balance.OnHand)
+	require.Equal(t, int64(3), balance.InTransit)
+}
+
+func TestStockPostLot(t *testing.T) {
+	type Expected struct {
+		err    error
+		onHand int64
+	}
+
+	key := models.StockKey{
+		WarehouseID: "wh_01HX5C1A2B3C4D5E6F7G8H9J0K",
+		ProductID:   "prd_01HX3A1B2C3D4E5F6G7H8J9K0M",
+	}
+
+	cases := []struct {
+		description string
+		lot         string
+		quantity    int64
+		expected    Expected
+	}{
+		{
+			description: "succeeds to create a new lot",
+			lot:         "L-003",
+			quantity:    5,
+			expected:    Expected{err: nil, onHand: 5},
+		},
+		{
+			description: "succeeds to decrease a lot with enough stock",
+			lot:         "L-001",
+			quantity:    -4,
+			expected:    Expected{err: nil, onHand: 0},
+		},
+		{
+			description: "fails when the lot would go below zero",
+			lot:         "L-002",
+			quantity:    -3,
+			expected:    Expected{err: store.ErrInsufficientStock, onHand: 2},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			srv.apply(fixtureStock, fixtureLot)
+			defer srv.reset()
+
+			ctx := context.Background()
+
+			mov := &models.Movement{
+				NamespaceID: "ns_01HV7FKH5SRB0TGWM7MQ15PYKN",
+				StockKey:    key,
+				Type:        models.MovementAdjustment,
+				Quantity:    tc.quantity,
+				Reason:      "test",
+				UserID:      "01HNGJ2BTGQAHAZ1XNYZQPG719",
+				Lot:         tc.lot,
+			}
+
+			// Backorders never apply to lots, so only the lot can reject the movement.
+			err := s.WithTransaction(ctx, func(ctx context.Context) error {
+				return s.Stock.Post(ctx, true, mov)
+			})
+			require.Equal(t, tc.expected.err, err)
+
+			lot := new(models.StockLot)
+			require.NoError(t, db.Collection("stock_lot").FindOne(ctx, bson.M{"lot": tc.lot}).Decode(lot))
+			require.Equal(t, tc.expected.onHand, lot.OnHand)
+		})
+	}
+}
+
+func TestStockLotsOf(t *testing.T) {
+	srv.apply(fixtureLot)
+	defer srv.reset()
+
+	ctx := context.Background()
+
+	key := models.StockKey{
+		WarehouseID: "wh_01HX5C1A2B3C4D5E6F7G8H9J0K",
+		ProductID:   "prd_01HX3A1B2C3D4E5F6G7H8J9K0M",
+	}
+
+	_, err := db.Collection("stock_lot").UpdateOne(ctx, bson.M{"lot": "L-001"}, bson.M{"$set": bson.M{"on_hand": 0}})
+	require.NoError(t, err)
+
+	lots, err := s.Stock.LotsOf(ctx, "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", key)
+	require.NoError(t, err)
+	require.Len(t, lots, 1)
+	require.Equal(t, "L-002", lots[0].Lot)
+}
+
+func TestStockPostSerials(t *testing.T) {
+	type Expected struct {
+		err    error
+		status models.SerialStatus
+	}
+
+	key := models.StockKey{
+		WarehouseID: "wh_01HX5C1A2B3C4D5E6F7G8H9J0K",
+		ProductID:   "prd_01HX3A1B2C3D4E5F6G7H8J9K0M",
+	}
+
+	cases := []struct {
+		description string
+		typ         models.MovementType
+		warehouseID string
+		serial      string
+		quantity    int64
+		expected    Expected
+	}{
+		{
+			description: "succeeds to receive a new serial",
+			typ:         models.MovementReceipt,
+			warehouseID: key.WarehouseID,
+			serial:      "SN-003",
+			quantity:    1,
+			expected:    Expected{err: nil, status: models.SerialInStock},
+		},
+		{
+			description: "succeeds to receive a serial that left the stock",
+			typ:         models.MovementReceipt,
+			warehouseID: key.WarehouseID,
+			serial:      "SN-002",
+			quantity:    1,
+			expected:    Expected{err: nil, status: models.SerialInStock},
+		},
+		{
+			description: "fails to receive a serial that is already in stock",
+			typ:         models.MovementReceipt,
+			warehouseID: key.WarehouseID,
+			serial:      "SN-001",
+			quantity:    1,
+			expected:    Expected{err: store.ErrDuplicated, status: models.SerialInStock},
+		},
+		{
+			description: "succeeds to issue a serial in stock",
+			typ:         models.MovementIssue,
+			warehouseID: key.WarehouseID,
+			serial:      "SN-001",
+			quantity:    -1,
+			expected:    Expected{err: nil, status: models.SerialOut},
+		},
+		{
+			description: "succeeds to put a serial in transit",
+			typ:         models.MovementTransferOut,
+			warehouseID: key.WarehouseID,
+			serial:      "SN-001",
+			quantity:    -1,
+			expected:    Expected{err: nil, status: models.SerialInTransit},
+		},
+		{
+			description: "fails to issue a serial from another warehouse",
+			typ:         models.MovementIssue,
+			warehouseID: "wh_01HX5C2M3N4P5Q6R7S8T9V0W1X",
+			serial:      "SN-001",
+			quantity:    -1,
+			expected:    Expected{err: store.ErrInsufficientStock, status: models.SerialInStock},
+		},
+		{
+			description: "fails to issue a serial that left the stock",
+			typ:         models.MovementIssue,
+			warehouseID: key.WarehouseID,
+			serial:      "SN-002",
+			quantity:    -1,
+			expected:    Expected{err: store.ErrInsufficientStock, status: models.SerialOut},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			srv.apply(fixtureStock, fixtureSerial)
+			defer srv.reset()
+
+			ctx := context.Background()
+
+			k := key
+			k.WarehouseID = tc.warehouseID
+
+			mov := &models.Movement{
+				NamespaceID: "ns_01HV7FKH5SRB0TGWM7MQ15PYKN",
+				StockKey:    k,
+				Type:        tc.typ,
+				Quantity:    tc.quantity,
+				Reason:      "test",
+				UserID:      "01HNGJ2BTGQAHAZ1XNYZQPG719",
+				Serials:     []string{tc.serial},
+			}
+
+			err := s.WithTransaction(ctx, func(ctx context.Context) error {
+				return s.Stock.Post(ctx, true, mov)
+			})
+			require.ErrorIs(t, err, tc.expected.err)
+
+			unit, err := s.Stock.Serial(ctx, "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", key.ProductID, tc.serial)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected.status, unit.Status)
+		})
+	}
+}
+
+func TestStockReserve(t *testing.T) {
+	type Expected struct {
+		err      error
+		reserved int64
+	}
+
+	key := models.StockKey{
+		WarehouseID: "wh_01HX5C1A2B3C4D5E6F7G8H9J0K",
+		ProductID:   "prd_01HX3A1B2C3D4E5F6G7H8J9K0M",
+	}
+
+	cases := []struct {
+		description string
+		delta       int64
+		expected    Expected
+	}{
+		{
+			description: "succeeds to reserve the available stock",
+			delta:       4,
+			expected:    Expected{err: nil, reserved: 6},
+		},
+		{
+			description: "fails when the available stock does not cover the reservation",
+			delta:       5,
+			expected:    Expected{err: store.ErrInsufficientStock, reserved: 2},
+		},
+		{
+			description: "succeeds to release reserved stock",
+			delta:       -2,
+			expected:    Expected{err: nil, reserved: 0},
+		},
+		{
+			description: "fails to release more than the reserved stock",
+			delta:       -3,
+			expected:    Expected{err: store.ErrInsufficientStock, reserved: 2},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			srv.apply(fixtureStock)
+			defer srv.reset()
+
+			ctx := context.Background()
+
+			_, err := db.Collection("balance").UpdateMany(ctx, bson.M{}, bson.M{"$set": bson.M{"reserved": 2}})
+			require.NoError(t, err)
+
+			require.Equal(t, tc.expected.err, s.Stock.Reserve(ctx, "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", key, tc.delta))
+
+			balance, err := s.Stock.Balance(ctx, "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", key)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected.reserved, balance.Reserved)
+		})
+	}
+}
+
+func TestStockPostReserved(t *testing.T) {
+	srv.apply(fixtureStock)
+	defer srv.reset()
+
+	ctx := context.Background()
+
+	key := models.StockKey{
+		WarehouseID: "wh_01HX5C1A2B3C4D5E6F7G8H9J0K",
+		ProductID:   "prd_01HX3A1B2C3D4E5F6G7H8J9K0M",
+	}
+
+	require.NoError(t, s.Stock.Reserve(ctx, "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", key, 4))
+
+	// Only the 2 units that are not reserved can be issued.
+	mov := &models.Movement{
+		NamespaceID: "ns_01HV7FKH5SRB0TGWM7MQ15PYKN",
+		StockKey:    key,
+		Type:        models.MovementIssue,
+		Quantity:    -3,
+		Reason:      "test",
+		UserID:      "01HNGJ2BTGQAHAZ1XNYZQPG719",
+	}
+
+	err := s.WithTransaction(ctx, func(ctx context.Context) error {
+		return s.Stock.Post(ctx, false, mov)
+	})
+	require.Equal(t, store.ErrInsufficientStock, err)
+}
+
+func TestStockValuation(t *testing.T) {
+	cases := []struct {
+		description string
+		asOf        time.Time
+		warehouseID string
+		expected    []models.ValuationLine
+	}{
+		{
+			description: "succeeds to value the stock after every movement",
+			asOf:        time.Date(2023, 1, 3, 0, 0, 0, 0, time.UTC),
+			warehouseID: "",
+			expected: []models.ValuationLine{
+				{ProductID: "prd_01HX3A1B2C3D4E5F6G7H8J9K0M", SKU: "COLA-350", Name: "Cola 350ml", Quantity: 6, Value: money.New(900, "BRL")},
+			},
+		},
+		{
+			description: "succeeds to value the stock as of a past date",
+			asOf:        time.Date(2023, 1, 1, 18, 0, 0, 0, time.UTC),
+			warehouseID: "",
+			expected: []models.ValuationLine{
+				{ProductID: "prd_01HX3A1B2C3D4E5F6G7H8J9K0M", SKU: "COLA-350", Name: "Cola 350ml", Quantity: 10, Value: money.New(1500, "BRL")},
+			},
+		},
+		{
+			description: "succeeds to value the stock of a warehouse without movements",
+			asOf:        time.Date(2023, 1, 3, 0, 0, 0, 0, time.UTC),
+			warehouseID: "wh_01HX5C2M3N4P5Q6R7S8T9V0W1X",
+			expected:    []models.ValuationLine{},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			srv.apply(fixtureProduct, fixtureStock)
+			defer srv.reset()
+
+			lines, err := s.Stock.Valuation(context.Background(), "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", tc.asOf, tc.warehouseID)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, lines)
+		})
+	}
+}
@@ -13,9 +13,36 @@ type Store struct {
 	db     *mongodb.Database
 
 	// User handle all user-related operations.
-	User      User
-	Namespace Namespace
-	Session   Session
+	User       User
+	Namespace  Namespace
+	Session    Session
+	Product    Product
+	Variant    Variant
+	Category   Category
+	Warehouse  Warehouse
+	Stock      Stock
+	Transfer   Transfer
+	StockCount StockCount
+	StockLevel StockLevel
+
+	Reservation Reservation
+	Cost        Cost
+
+	Supplier      Supplier
+	PurchaseOrder PurchaseOrder
+	Customer      Customer
+	SalesOrder    SalesOrder
+
+	Register Register
+	Sale     Sale
+	Shift    Shift
+	Return   Return
+
+	PriceList PriceList
+	Promotion Promotion
+	TaxRate   TaxRate
+
+	ExchangeRate ExchangeRate
 }
 
 func Connect(ctx context.Context, uri string) (*mongodb.Client, string, error) {
@@ -45,6 +72,53 @@ func New(ctx context.Context, client *mongodb.Client, db string) (*Store, error)
 	store.User = &user{c: store.db.Collection("user")}
 	store.Namespace = &namespace{c: store.db.Collection("namespace")}
 	store.Session = &session{c: store.db.Collection("session")}
+	store.Product = &product{c: store.db.Collection("product")}
+	store.Variant = &variant{c: store.db.Collection("variant")}
+	store.Category = &category{c: store.db.Collection("category")}
+	store.Warehouse = &warehouse{c: store.db.Collection("warehouse")}
+	store.Stock = &stock{movements: store.db.Collection("movement"), balances: store.db.Collection("balance"), lots: store.db.Collection("stock_lot"), serials: store.db.Collection("stock_serial")}
+	store.Transfer = &transfer{c: store.db.Collection("transfer")}
+	store.StockCount = &stockCount{c: store.db.Collection("stock_count")}
+	store.StockLevel = &stockLevel{c: store.db.Collection("stock_level")}
+	store.Reservation = &reservation{c: store.db.Collection("reservation")}
+	store.Cost = &cost{pools: store.db.Collection("cost_pool"), layers: store.db.Collection("cost_layer")}
+	store.Supplier = &supplier{c: store.db.Collection("supplier")}
+	store.PurchaseOrder = &purchaseOrder{c: store.db.Collection("purchase_order")}
+	store.Customer = &customer{c: store.db.Collection("customer"), orders: store.db.Collection("sales_order"), sales: store.db.Collection("sale")}
+	store.SalesOrder = &salesOrder{c: store.db.Collection("sales_order")}
+	store.Register = &register{c: store.db.Collection("register")}
+	store.Sale = &sale{c: store.db.Collection("sale")}
+	store.Shift = &shift{c: store.db.Collection("shift")}
+	store.Return = &saleReturn{c: store.db.Collection("return")}
+	store.PriceList = &priceList{c: store.db.Collection("price_list")}
+	store.Promotion = &promotion{c: store.db.Collection("promotion")}
+	store.TaxRate = &taxRate{c: store.db.Collection("tax_rate")}
+	store.ExchangeRate = &exchangeRate{c: store.db.Collection("exchange_rate")}
+
+	if err := ensureIndexes(ctx, store.db); err != nil {
+		return nil, err
+	}
+
+	if err := store.Namespace.SyncMemberProfiles(ctx); err != nil {
+		return nil, err
+	}
 
 	return store, nil
 }
+
+// WithTransaction executes fn within a transaction, committing it when fn returns nil and aborting it
+// otherwise. Every store operation that is part of the transaction must use the context passed to fn.
+// It returns the error returned by fn or an error if the transaction cannot be committed.
+func (s *Store) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	session, err := s.client.StartSession()
+	if err != nil {
+		return mapError(err)
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sctx mongodb.SessionContext) (interface{}, error) {
+		return nil, fn(sctx)
+	})
+
+	return err
+}
@@ -2,11 +2,13 @@ package store_test
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
 	"runtime"
 	"testing"
 
+	dockercontainer "github.com/docker/docker/api/types/container"
 	"github.com/heiytor/invenda/api/store"
 	"github.com/shellhub-io/mongotest"
 	"github.com/testcontainers/testcontainers-go"
@@ -26,12 +28,24 @@ var db *mongo.Database
 func TestMain(m *testing.M) {
 	ctx := context.Background()
 
-	container, err := mongodb.RunContainer(ctx, testcontainers.WithImage("mongo:7.0.5"))
+	// Transactions are only supported by replica sets, so the container runs as a single-member one.
+	container, err := mongodb.RunContainer(
+		ctx,
+		testcontainers.WithImage("mongo:7.0.5"),
+		testcontainers.WithConfigModifier(func(config *dockercontainer.Config) {
+			config.Cmd = []string{"--replSet", "rs", "--bind_ip_all"}
+		}),
+	)
 	if err != nil {
 		panic(err)
 	}
 	defer container.Terminate(ctx)
 
+	initiate := []string{"mongosh", "--quiet", "--eval", "rs.initiate({ _id: 'rs', members: [ { _id: 0, host: 'localhost:27017' } ] })"}
+	if code, _, err := container.Exec(ctx, initiate); err != nil || code != 0 {
+		panic(fmt.Sprintf("failed to initiate the replica set: exit code %d: %v", code, err))
+	}
+
 	uri, err := container.ConnectionString(ctx)
 	if err != nil {
 		panic(err)
@@ -44,7 +58,7 @@ func TestMain(m *testing.M) {
 	}
 
 	mongotest.Configure(mongotest.Config{
-		URL:            uri,
+		URL:            uri + "/?directConnection=true",
 		Database:       "test",
 		FixtureRootDir: filepath.Join(filepath.Dir(file), "fixtures"),
 		FixtureFormat:  mongotest.FixtureFormatJSON,
@@ -54,10 +68,67 @@ func TestMain(m *testing.M) {
 			mongotest.SimpleConvertTime("namespace", "created_at"),
 			mongotest.SimpleConvertTime("namespace", "updated_at"),
 			mongotest.SimpleConvertTime("namespace.members.id", "updated_at"),
+			mongotest.SimpleConvertTime("product", "created_at"),
+			mongotest.SimpleConvertTime("product", "updated_at"),
+			mongotest.SimpleConvertTime("variant", "created_at"),
+			mongotest.SimpleConvertTime("variant", "updated_at"),
+			mongotest.SimpleConvertTime("category", "created_at"),
+			mongotest.SimpleConvertTime("category", "updated_at"),
+			mongotest.SimpleConvertTime("warehouse", "created_at"),
+			mongotest.SimpleConvertTime("warehouse", "updated_at"),
+			mongotest.SimpleConvertTime("movement", "created_at"),
+			mongotest.SimpleConvertTime("balance", "updated_at"),
+			mongotest.SimpleConvertTime("transfer", "created_at"),
+			mongotest.SimpleConvertTime("transfer", "updated_at"),
+			mongotest.SimpleConvertTime("stock_count", "created_at"),
+			mongotest.SimpleConvertTime("stock_count", "updated_at"),
+			mongotest.SimpleConvertTime("stock_level", "created_at"),
+			mongotest.SimpleConvertTime("stock_level", "updated_at"),
+			mongotest.SimpleConvertTime("stock_lot", "updated_at"),
+			mongotest.SimpleConvertTime("stock_lot", "expires_at"),
+			mongotest.SimpleConvertTime("stock_lot", "received_at"),
+			mongotest.SimpleConvertTime("stock_serial", "created_at"),
+			mongotest.SimpleConvertTime("stock_serial", "updated_at"),
+			mongotest.SimpleConvertTime("reservation", "created_at"),
+			mongotest.SimpleConvertTime("reservation", "updated_at"),
+			mongotest.SimpleConvertTime("reservation", "expires_at"),
+			mongotest.SimpleConvertTime("cost_pool", "updated_at"),
+			mongotest.SimpleConvertTime("cost_layer", "created_at"),
+			mongotest.SimpleConvertTime("supplier", "created_at"),
+			mongotest.SimpleConvertTime("supplier", "updated_at"),
+			mongotest.SimpleConvertTime("purchase_order", "created_at"),
+			mongotest.SimpleConvertTime("purchase_order", "updated_at"),
+			mongotest.SimpleConvertTime("customer", "created_at"),
+			mongotest.SimpleConvertTime("customer", "updated_at"),
+			mongotest.SimpleConvertTime("sales_order", "created_at"),
+			mongotest.SimpleConvertTime("sales_order", "updated_at"),
+			mongotest.SimpleConvertTime("sales_order", "placed_at"),
+			mongotest.SimpleConvertTime("register", "created_at"),
+			mongotest.SimpleConvertTime("register", "updated_at"),
+			mongotest.SimpleConvertTime("sale", "created_at"),
+			mongotest.SimpleConvertTime("sale", "updated_at"),
+			mongotest.SimpleConvertTime("shift", "created_at"),
+			mongotest.SimpleConvertTime("shift", "updated_at"),
+			mongotest.SimpleConvertTime("shift", "closed_at"),
+			mongotest.SimpleConvertTime("price_list", "created_at"),
+			mongotest.SimpleConvertTime("price_list", "updated_at"),
+			mongotest.SimpleConvertTime("price_list", "valid_from"),
+			mongotest.SimpleConvertTime("price_list", "valid_until"),
+			mongotest.SimpleConvertTime("promotion", "created_at"),
+			mongotest.SimpleConvertTime("promotion", "updated_at"),
+			mongotest.SimpleConvertTime("promotion", "valid_from"),
+			mongotest.SimpleConvertTime("promotion", "valid_until"),
+			mongotest.SimpleConvertTime("tax_rate", "created_at"),
+			mongotest.SimpleConvertTime("tax_rate", "updated_at"),
+			mongotest.SimpleConvertTime("tax_rate", "valid_from"),
+			mongotest.SimpleConvertTime("tax_rate", "valid_until"),
+			mongotest.SimpleConvertTime("exchange_rate", "created_at"),
+			mongotest.SimpleConvertTime("exchange_rate", "updated_at"),
+			mongotest.SimpleConvertTime("exchange_rate", "valid_from"),
 		},
 	})
 
-	srv = &Server{container, uri + "/test"}
+	srv = &Server{container, uri + "/test?directConnection=true"}
 
 	c, preferredDb, err := store.Connect(ctx, srv.URI)
 	if err != nil {
@@ -77,9 +148,34 @@ func TestMain(m *testing.M) {
 type fixture string
 
 const (
-	fixtureUser      fixture = "user"
-	fixtureNamespace fixture = "namespace"
-	fixtureSession   fixture = "session"
+	fixtureUser        fixture = "user"
+	fixtureNamespace   fixture = "namespace"
+	fixtureSession     fixture = "session"
+	fixtureProduct     fixture = "product"
+	fixtureVariant     fixture = "variant"
+	fixtureCategory    fixture = "category"
+	fixtureWarehouse   fixture = "warehouse"
+	fixtureStock       fixture = "stock"
+	fixtureTransfer    fixture = "transfer"
+	fixtureCount       fixture = "stock_count"
+	fixtureLevel       fixture = "stock_level"
+	fixtureLot         fixture = "stock_lot"
+	fixtureSerial      fixture = "stock_serial"
+	fixtureReservation fixture = "reservation"
+	fixtureCost        fixture = "cost"
+	fixtureSupplier    fixture = "supplier"
+	fixturePurchase    fixture = "purchase_order"
+	fixtureCustomer    fixture = "customer"
+	fixtureSalesOrder  fixture = "sales_order"
+	fixtureRegister    fixture = "register"
+	fixtureSale        fixture = "sale"
+	fixtureShift       fixture = "shift"
+	fixturePriceList   fixture = "price_list"
+	fixturePromotion   fixture = "promotion"
+	fixtureTaxRate     fixture = "tax_rate"
+	fixtureExchange    fixture = "exchange_rate"
+
+	fixtureNamespaceSearch fixture = "namespace_search"
 )
 
 func (*Server) apply(fixtures ...fixture) error {
@@ -0,0 +1,168 @@
+package store
+
+import (
+	"context"
+
+	"github.com/heiytor/invenda/api/pkg/clock"
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/query"
+	"github.com/heiytor/invenda/api/store/internal"
+	"github.com/oklog/ulid/v2"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Supplier handles the namespace's suppliers. Every operation is scoped to a namespace ID.
+type Supplier interface {
+	Entity
+
+	// Get retrieves a supplier with the specified ID. It returns the supplier or an error if any.
+	Get(ctx context.Context, namespaceID, id string) (supplier *models.Supplier, err error)
+
+	// GetMany retrieves a list of suppliers of a namespace. It returns the list of suppliers, the total count
+	// of the existent documents and an error if any.
+	GetMany(ctx context.Context, namespaceID string, query *query.Query) (suppliers []models.Supplier, count int64, err error)
+
+	// Conflicts reports whether the non-zero fields of the provided target already exist in the namespace.
+	// It returns a list of conflicted fields or an error if any.
+	Conflicts(ctx context.Context, namespaceID string, target *models.Supplier) (conflicts []string, err error)
+
+	// Create creates a new supplier with the provided data. It returns the inserted ID or an error
+	// if any.
+	Create(ctx context.Context, supplier *models.Supplier) (insertedID string, err error)
+
+	// Update updates a supplier with the specified changes and ID. It returns [ErrNotFound] if no supplier is found.
+	Update(ctx context.Context, namespaceID, id string, changes *models.SupplierChanges) (err error)
+
+	// Delete deletes a supplier with the specified ID. It returns [ErrNotFound] if no supplier is found.
+	Delete(ctx context.Context, namespaceID, id string) (err error)
+}
+
+type supplier struct {
+	c *mongo.Collection // c is the "supplier" collection
+}
+
+var _ Supplier = (*supplier)(nil)
+
+func (*supplier) Entity() string {
+	return "supplier"
+}
+
+func (sp *supplier) Get(ctx context.Context, namespaceID, id string) (*models.Supplier, error) {
+	sup := new(models.Supplier)
+	if err := sp.c.FindOne(ctx, bson.M{"_id": id, "namespace_id": namespaceID}).Decode(sup); err != nil {
+		return nil, mapError(err)
+	}
+
+	return sup, nil
+}
+
+func (sp *supplier) GetMany(ctx context.Context, namespaceID string, query *query.Query) ([]models.Supplier, int64, error) {
+	conditions := []bson.M{
+		{"namespace_id": namespaceID},
+		internal.FromFilter(&query.Filter),
+	}
+
+	if query.Search != "" {
+		conditions = append(conditions, internal.FromPrefixSearch(query.Search, "code", "name"))
+	}
+
+	match := bson.M{"$and": conditions}
+
+	count, err := sp.c.CountDocuments(ctx, match)
+	if err != nil {
+		log.Error().Err(err).Msg("unable to count the total documents")
+	}
+
+	pipeline := make([]bson.M, 0)
+	pipeline = append(pipeline, bson.M{"$match": match})
+	pipeline = append(pipeline, internal.FromSorter(&query.Sorter)...)
+	pipeline = append(pipeline, internal.FromPaginator(&query.Paginator)...)
+
+	cursor, err := sp.c.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, 0, mapError(err)
+	}
+	defer cursor.Close(ctx)
+
+	suppliers := make([]models.Supplier, 0)
+	if err := cursor.All(ctx, &suppliers); err != nil {
+		return nil, 0, mapError(err)
+	}
+
+	return suppliers, count, nil
+}
+
+func (sp *supplier) Conflicts(ctx context.Context, namespaceID string, target *models.Supplier) ([]string, error) {
+	pipeline := append([]bson.M{{"$match": bson.M{"namespace_id": namespaceID}}}, or(target)...)
+
+	cursor, err := sp.c.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	defer cursor.Close(ctx)
+
+	conflicts := make([]string, 0)
+	for cursor.Next(ctx) {
+		sup := new(models.Supplier)
+
+		if err = cursor.Decode(sup); err != nil {
+			return nil, mapError(err)
+		}
+
+		conflicts = append(conflicts, partialEqual(target, sup)...)
+	}
+
+	return conflicts, nil
+}
+
+func (sp *supplier) Create(ctx context.Context, sup *models.Supplier) (string, error) {
+	sup.ID = "sup_" + ulid.Make().String()
+
+	now := clock.Now()
+	sup.CreatedAt = now
+	sup.UpdatedAt = now
+
+	if sup.Products == nil {
+		sup.Products = []models.SupplierProduct{}
+	}
+
+	if _, err := sp.c.InsertOne(ctx, sup); err != nil {
+		return "", mapError(err)
+	}
+
+	return sup.ID, nil
+}
+
+func (sp *supplier) Update(ctx context.Context, namespaceID, id string, changes *models.SupplierChanges) error {
+	if changes == nil {
+		return nil
+	}
+
+	changes.UpdatedAt = clock.Now()
+
+	res, err := sp.c.UpdateOne(ctx, bson.M{"_id": id, "namespace_id": namespaceID}, bson.M{"$set": changes})
+	if err != nil {
+		return mapError(err)
+	}
+
+	if res.MatchedCount < 1 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (sp *supplier) Delete(ctx context.Context, namespaceID, id string) error {
+	res, err := sp.c.DeleteOne(ctx, bson.M{"_id": id, "namespace_id": namespaceID})
+	if err != nil {
+		return mapError(err)
+	}
+
+	if res.DeletedCount < 1 {
+		return ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,126 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/money"
+	"github.com/heiytor/invenda/api/store"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSupplierGet(t *testing.T) {
+	type Actual struct {
+		supplier *models.Supplier
+		err      error
+	}
+
+	cases := []struct {
+		description string
+		namespaceID string
+		id          string
+		fixtures    []fixture
+		expected    Actual
+	}{
+		{
+			description: "fails when supplier is not found",
+			namespaceID: "ns_01HV7FKH5SRB0TGWM7MQ15PYKN",
+			id:          "sup_00000000000000000000000000",
+			fixtures:    []fixture{},
+			expected:    Actual{supplier: nil, err: store.ErrNotFound},
+		},
+		{
+			description: "fails when supplier belongs to another namespace",
+			namespaceID: "ns_01HWS7Q0H1JCEMKZADAFMETRZJ",
+			id:          "sup_01HXA1B2C3D4E5F6G7H8J9K0MN",
+			fixtures:    []fixture{fixtureSupplier},
+			expected:    Actual{supplier: nil, err: store.ErrNotFound},
+		},
+		{
+			description: "succeeds to find a supplier",
+			namespaceID: "ns_01HV7FKH5SRB0TGWM7MQ15PYKN",
+			id:          "sup_01HXA1B2C3D4E5F6G7H8J9K0MN",
+			fixtures:    []fixture{fixtureSupplier},
+			expected: Actual{
+				supplier: &models.Supplier{
+					ID:           "sup_01HXA1B2C3D4E5F6G7H8J9K0MN",
+					NamespaceID:  "ns_01HV7FKH5SRB0TGWM7MQ15PYKN",
+					CreatedAt:    time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC),
+					UpdatedAt:    time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC),
+					Code:         "ACME",
+					Name:         "Acme Corp.",
+					Contact:      models.Contact{Name: "Jane Roe", Email: "jane@acme.test", Phone: "+1 555 0100"},
+					Address:      "2 Industrial Rd",
+					Active:       true,
+					PaymentTerms: "net 30",
+					LeadTimeDays: 7,
+					Products:     []models.SupplierProduct{{ProductID: "prd_01HX3A1B2C3D4E5F6G7H8J9K0M", SKU: "ACME-001", Cost: money.New(450, "BRL")}},
+				},
+				err: nil,
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			srv.apply(tc.fixtures...)
+			defer srv.reset()
+
+			ctx := context.Background()
+
+			supplier, err := s.Supplier.Get(ctx, tc.namespaceID, tc.id)
+			require.Equal(t, tc.expected, Actual{supplier, err})
+		})
+	}
+}
+
+func TestSupplierConflicts(t *testing.T) {
+	type Actual struct {
+		conflicts []string
+		err       error
+	}
+
+	cases := []struct {
+		description string
+		namespaceID string
+		target      *models.Supplier
+		fixtures    []fixture
+		expected    Actual
+	}{
+		{
+			description: "succeeds when none conflicts are found",
+			namespaceID: "ns_01HV7FKH5SRB0TGWM7MQ15PYKN",
+			target:      &models.Supplier{Code: "GLOBEX"},
+			fixtures:    []fixture{fixtureSupplier},
+			expected:    Actual{conflicts: []string{}, err: nil},
+		},
+		{
+			description: "succeeds when the code belongs to another namespace",
+			namespaceID: "ns_01HWS7Q0H1JCEMKZADAFMETRZJ",
+			target:      &models.Supplier{Code: "ACME"},
+			fixtures:    []fixture{fixtureSupplier},
+			expected:    Actual{conflicts: []string{}, err: nil},
+		},
+		{
+			description: "succeeds when a conflict is found",
+			namespaceID: "ns_01HV7FKH5SRB0TGWM7MQ15PYKN",
+			target:      &models.Supplier{Code: "ACME"},
+			fixtures:    []fixture{fixtureSupplier},
+			expected:    Actual{conflicts: []string{"code"}, err: nil},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			srv.apply(tc.fixtures...)
+			defer srv.reset()
+
+			ctx := context.Background()
+
+			conflicts, err := s.Supplier.Conflicts(ctx, tc.namespaceID, tc.target)
+			require.Equal(t, tc.expected, Actual{conflicts, err})
+		})
+	}
+}
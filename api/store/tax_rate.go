@@ -0,0 +1,217 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/heiytor/invenda/api/pkg/clock"
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/query"
+	"github.com/heiytor/invenda/api/store/internal"
+	"github.com/oklog/ulid/v2"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// TaxRate handles the namespace's tax rates. Every operation is scoped to a namespace ID.
+type TaxRate interface {
+	Entity
+
+	// Get retrieves a tax rate with the specified ID. It returns the tax rate or an error if any.
+	Get(ctx context.Context, namespaceID, id string) (taxRate *models.TaxRate, err error)
+
+	// GetMany retrieves a list of tax rates of a namespace. It returns the list of tax rates, the total
+	// count of the existent documents and an error if any.
+	GetMany(ctx context.Context, namespaceID string, query *query.Query) (taxRates []models.TaxRate, count int64, err error)
+
+	// GetEffective retrieves the tax rates of a namespace that are effective at a time, sorted by the
+	// order in which they are applied. It returns the rates or an error if any.
+	GetEffective(ctx context.Context, namespaceID string, at time.Time) (taxRates []models.TaxRate, err error)
+
+	// Create creates a new tax rate with the provided data. It returns the inserted ID or an error if any.
+	Create(ctx context.Context, taxRate *models.TaxRate) (insertedID string, err error)
+
+	// Update updates a tax rate with the specified changes and ID. It returns [ErrNotFound] if no tax rate
+	// is found.
+	Update(ctx context.Context, namespaceID, id string, changes *models.TaxRateChanges) (err error)
+
+	// Delete deletes a tax rate with the specified ID. It returns [ErrNotFound] if no tax rate is found.
+	Delete(ctx context.Context, namespaceID, id string) (err error)
+}
+
+type taxRate struct {
+	c *mongo.Collection // c is the "tax_rate" collection
+}
+
+var _ TaxRate = (*taxRate)(nil)
+
+func (*taxRate) Entity() string {
+	return "tax_rate"
+}
+
+func (tr *taxRate) Get(ctx context.Context, namespaceID, id string) (*models.TaxRate, error) {
+	txr := new(models.TaxRate)
+	if err := tr.c.FindOne(ctx, bson.M{"_id": id, "namespace_id": namespaceID}).Decode(txr); err != nil {
+		return nil, mapError(err)
+	}
+
+	return txr, nil
+}
+
+func (tr *taxRate) GetMany(ctx context.Context, namespaceID string, query *query.Query) ([]models.TaxRate, int64, error) {
+	conditions := []bson.M{
+		{"namespace_id": namespaceID},
+		internal.FromFilter(&query.Filter),
+	}
+
+	if query.Search != "" {
+		conditions = append(conditions, internal.FromPrefixSearch(query.Search, "name", "category"))
+	}
+
+	match := bson.M{"$and": conditions}
+
+	count, err := tr.c.CountDocuments(ctx, match)
+	if err != nil {
+		log.Error().Err(err).Msg("unable to count the total documents")
+	}
+
+	pipeline := make([]bson.M, 0)
+	pipeline = append(pipeline, bson.M{"$match": match})
+	pipeline = append(pipeline, internal.FromSorter(&query.Sorter)...)
+	pipeline = append(pipeline, internal.FromPaginator(&query.Paginator)...)
+
+	cursor, err := tr.c.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, 0, mapError(err)
+	}
+	defer cursor.Close(ctx)
+
+	taxRates := make([]models.TaxRate, 0)
+	if err := cursor.All(ctx, &taxRates); err != nil {
+		return nil, 0, mapError(err)
+	}
+
+	return taxRates, count, nil
+}
+
+func (tr *taxRate) GetEffective(ctx context.Context, namespaceID string, at time.Time) ([]models.TaxRate, error) {
+	filter := bson.M{
+		"namespace_id": namespaceID,
+		"$and": []bson.M{
+			{"$or": []bson.M{{"valid_from": nil}, {"valid_from": bson.M{"$lte": at}}}},
+			{"$or": []bson.M{{"valid_until": nil}, {"valid_until": bson.M{"$gt": at}}}},
+		},
+	}
+
+	cursor, err := tr.c.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "order", Value: 1}, {Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, mapError(err)
+	}
+	defer cursor.Close(ctx)
+
+	taxRates := make([]models.TaxRate, 0)
+	if err := cursor.All(ctx, &taxRates); err != nil {
+		return nil, mapError(err)
+	}
+
+	return taxRates, nil
+}
+
+func (tr *taxRate) Create(ctx context.Context, txr *models.TaxRate) (string, error) {
+	txr.ID = "txr_" + ulid.Make().String()
+
+	now := clock.Now()
+	txr.CreatedAt = now
+	txr.UpdatedAt = now
+
+	if _, err := tr.c.InsertOne(ctx, txr); err != nil {
+		return "", mapError(err)
+	}
+
+	return txr.ID, nil
+}
+
+func (tr *taxRate) Update(ctx context.Context, namespaceID, id string, changes *models.TaxRateChanges) error {
+	if changes == nil {
+		return nil
+	}
+
+	changes.UpdatedAt = clock.Now()
+
+	res, err := tr.c.UpdateOne(ctx, bson.M{"_id": id, "namespace_id": namespaceID}, bson.M{"$set": changes})
+	if err != nil {
+		return mapError(err)
+	}
+
+	if res.MatchedCount < 1 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (tr *taxRate) Delete(ctx context.Context, namespaceID, id string) error {
+	res, err := tr.c.DeleteOne(ctx, bson.M{"_id": id, "namespace_id": namespaceID})
+	if err != nil {
+		return mapError(err)
+	}
+
+	if res.DeletedCount < 1 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// sumTaxes sums by rate, currency and exchange rate the taxes of the documents of the collection that match
+// the filter, as they were stored when the documents were priced.
+func sumTaxes(ctx context.Context, c *mongo.Collection, match bson.M) ([]models.TaxTotal, error) {
+	pipeline := []bson.M{
+		{"$match": match},
+		{"$unwind": "$taxes"},
+		{
+			"$group": bson.M{
+				"_id": bson.M{
+					"tax_rate_id": "$taxes.tax_rate_id",
+					"name":        "$taxes.name",
+					"rate":        "$taxes.rate",
+					"compound":    "$taxes.compound",
+					"currency":    "$currency",
+					"rate_used":   "$exchange_rate",
+					"amounts":     "$taxes.amount.currency",
+				},
+				"base":   bson.M{"$sum": "$taxes.base.amount"},
+				"amount": bson.M{"$sum": "$taxes.amount.amount"},
+			},
+		},
+		{
+			"$project": bson.M{
+				"_id":           0,
+				"tax_rate_id":   "$_id.tax_rate_id",
+				"name":          "$_id.name",
+				"rate":          "$_id.rate",
+				"compound":      "$_id.compound",
+				"currency":      "$_id.currency",
+				"exchange_rate": "$_id.rate_used",
+				"base":          bson.M{"amount": "$base", "currency": "$_id.amounts"},
+				"amount":        bson.M{"amount": "$amount", "currency": "$_id.amounts"},
+			},
+		},
+		{"$sort": bson.D{{Key: "name", Value: 1}, {Key: "rate", Value: 1}, {Key: "tax_rate_id", Value: 1}, {Key: "currency", Value: 1}, {Key: "exchange_rate", Value: 1}}},
+	}
+
+	cursor, err := c.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	defer cursor.Close(ctx)
+
+	taxes := make([]models.TaxTotal, 0)
+	if err := cursor.All(ctx, &taxes); err != nil {
+		return nil, mapError(err)
+	}
+
+	return taxes, nil
+}
@@ -0,0 +1,69 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaxRateGetEffective(t *testing.T) {
+	type Actual struct {
+		ids []string
+		err error
+	}
+
+	cases := []struct {
+		description string
+		namespaceID string
+		at          time.Time
+		fixtures    []fixture
+		expected    Actual
+	}{
+		{
+			description: "succeeds with no rates when the namespace has none",
+			namespaceID: "ns_01HV7FKH5SRB0TGWM7MQ15PYKN",
+			at:          time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+			fixtures:    []fixture{},
+			expected:    Actual{ids: []string{}, err: nil},
+		},
+		{
+			description: "succeeds to find the rates effective before a change of rate",
+			namespaceID: "ns_01HV7FKH5SRB0TGWM7MQ15PYKN",
+			at:          time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC),
+			fixtures:    []fixture{fixtureTaxRate},
+			expected:    Actual{ids: []string{"txr_01HXF1C3D4E5F6G7H8J9K0MNPQ", "txr_01HXF1A2B3C4D5E6F7G8H9J0KM"}, err: nil},
+		},
+		{
+			description: "succeeds to find the rates effective after a change of rate",
+			namespaceID: "ns_01HV7FKH5SRB0TGWM7MQ15PYKN",
+			at:          time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			fixtures:    []fixture{fixtureTaxRate},
+			expected:    Actual{ids: []string{"txr_01HXF1C3D4E5F6G7H8J9K0MNPQ", "txr_01HXF1B2C3D4E5F6G7H8J9K0MN"}, err: nil},
+		},
+		{
+			description: "succeeds to find the rates of another namespace",
+			namespaceID: "ns_01HWS7Q0H1JCEMKZADAFMETRZJ",
+			at:          time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			fixtures:    []fixture{fixtureTaxRate},
+			expected:    Actual{ids: []string{"txr_01HXF1D4E5F6G7H8J9K0MNPQRS"}, err: nil},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			srv.apply(tc.fixtures...)
+			defer srv.reset()
+
+			rates, err := s.TaxRate.GetEffective(context.Background(), tc.namespaceID, tc.at)
+
+			ids := make([]string, 0, len(rates))
+			for _, txr := range rates {
+				ids = append(ids, txr.ID)
+			}
+
+			require.Equal(t, tc.expected, Actual{ids, err})
+		})
+	}
+}
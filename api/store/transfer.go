@@ -0,0 +1,117 @@
+package store
+
+import (
+	"context"
+
+	"github.com/heiytor/invenda/api/pkg/clock"
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/query"
+	"github.com/oklog/ulid/v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Transfer handles the namespace's stock transfers. Every operation is scoped to a namespace ID.
+type Transfer interface {
+	Entity
+
+	// Get retrieves a transfer with the specified ID. It returns the transfer or an error if any.
+	Get(ctx context.Context, namespaceID, id string) (transfer *models.Transfer, err error)
+
+	// GetMany retrieves a list of transfers of a namespace. It returns the list of transfers, the total count
+	// of the existent documents and an error if any.
+	GetMany(ctx context.Context, namespaceID string, query *query.Query) (transfers []models.Transfer, count int64, err error)
+
+	// Create creates a new transfer with the provided data. It returns the inserted ID or an error
+	// if any.
+	Create(ctx context.Context, transfer *models.Transfer) (insertedID string, err error)
+
+	// Update updates a transfer with the specified changes and ID. When statuses are provided, the transfer
+	// is only updated if it is in one of them, which guards status transitions against concurrent changes.
+	// It returns [ErrNotFound] if no matching transfer is found.
+	Update(ctx context.Context, namespaceID, id string, statuses []models.TransferStatus, changes *models.TransferChanges) (err error)
+
+	// Delete deletes a transfer with the specified ID. It returns [ErrNotFound] if no transfer is found.
+	Delete(ctx context.Context, namespaceID, id string) (err error)
+}
+
+type transfer struct {
+	c *mongo.Collection // c is the "transfer" collection
+}
+
+var _ Transfer = (*transfer)(nil)
+
+func (*transfer) Entity() string {
+	return "transfer"
+}
+
+func (t *transfer) Get(ctx context.Context, namespaceID, id string) (*models.Transfer, error) {
+	tr := new(models.Transfer)
+	if err := t.c.FindOne(ctx, bson.M{"_id": id, "namespace_id": namespaceID}).Decode(tr); err != nil {
+		return nil, mapError(err)
+	}
+
+	return tr, nil
+}
+
+func (t *transfer) GetMany(ctx context.Context, namespaceID string, query *query.Query) ([]models.Transfer, int64, error) {
+	transfers := make([]models.Transfer, 0)
+	count, err := find(ctx, t.c, namespaceID, query, &transfers)
+
+	return transfers, count, err
+}
+
+func (t *transfer) Create(ctx context.Context, tr *models.Transfer) (string, error) {
+	tr.ID = "tr_" + ulid.Make().String()
+
+	now := clock.Now()
+	tr.CreatedAt = now
+	tr.UpdatedAt = now
+
+	if tr.Items == nil {
+		tr.Items = []models.TransferItem{}
+	}
+
+	if _, err := t.c.InsertOne(ctx, tr); err != nil {
+		return "", mapError(err)
+	}
+
+	return tr.ID, nil
+}
+
+func (t *transfer) Update(ctx context.Context, namespaceID, id string, statuses []models.TransferStatus, changes *models.TransferChanges) error {
+	if changes == nil {
+		return nil
+	}
+
+	changes.UpdatedAt = clock.Now()
+
+	filter := bson.M{"_id": id, "namespace_id": namespaceID}
+	if len(statuses) > 0 {
+		filter["status"] = bson.M{"$in": statuses}
+	}
+
+	res, err := t.c.UpdateOne(ctx, filter, bson.M{"$set": changes})
+	if err != nil {
+		return mapError(err)
+	}
+
+	if res.MatchedCount < 1 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (t *transfer) Delete(ctx context.Context, namespaceID, id string) error {
+	res, err := t.c.DeleteOne(ctx, bson.M{"_id": id, "namespace_id": namespaceID})
+	if err != nil {
+		return mapError(err)
+	}
+
+	if res.DeletedCount < 1 {
+		return ErrNotFound
+	}
+
+	return nil
+}
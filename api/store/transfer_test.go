@@ -0,0 +1,72 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/store"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestTransferUpdate(t *testing.T) {
+	type Expected struct {
+		err    error
+		status models.TransferStatus
+	}
+
+	cases := []struct {
+		description string
+		id          string
+		statuses    []models.TransferStatus
+		changes     *models.TransferChanges
+		fixtures    []fixture
+		expected    Expected
+	}{
+		{
+			description: "fails when transfer is not found",
+			id:          "tr_00000000000000000000000000",
+			statuses:    []models.TransferStatus{},
+			changes:     &models.TransferChanges{Status: models.TransferDispatched},
+			fixtures:    []fixture{},
+			expected:    Expected{err: store.ErrNotFound},
+		},
+		{
+			description: "fails when transfer is not in the expected status",
+			id:          "tr_01HX6F1A2B3C4D5E6F7G8H9J0K",
+			statuses:    []models.TransferStatus{models.TransferDispatched},
+			changes:     &models.TransferChanges{Status: models.TransferReceived},
+			fixtures:    []fixture{fixtureTransfer},
+			expected:    Expected{err: store.ErrNotFound, status: models.TransferDraft},
+		},
+		{
+			description: "succeeds to transition a transfer",
+			id:          "tr_01HX6F1A2B3C4D5E6F7G8H9J0K",
+			statuses:    []models.TransferStatus{models.TransferDraft},
+			changes:     &models.TransferChanges{Status: models.TransferDispatched},
+			fixtures:    []fixture{fixtureTransfer},
+			expected:    Expected{err: nil, status: models.TransferDispatched},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			srv.apply(tc.fixtures...)
+			defer srv.reset()
+
+			ctx := context.Background()
+
+			err := s.Transfer.Update(ctx, "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", tc.id, tc.statuses, tc.changes)
+			require.Equal(t, tc.expected.err, err)
+
+			if tc.expected.status == "" {
+				return
+			}
+
+			tr := new(models.Transfer)
+			require.NoError(t, db.Collection("transfer").FindOne(ctx, bson.M{"_id": tc.id}).Decode(tr))
+			require.Equal(t, tc.expected.status, tr.Status)
+		})
+	}
+}
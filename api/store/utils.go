@@ -4,6 +4,7 @@ import (
 	"reflect"
 	"strings"
 
+	"github.com/heiytor/invenda/api/pkg/money"
 	"go.mongodb.org/mongo-driver/bson"
 )
 
@@ -28,7 +29,7 @@ func or[T any](target *T) []bson.M {
 		fieldValue := reflect.ValueOf(target).Elem().FieldByName(field.Name)
 
 		if !reflect.DeepEqual(fieldValue.Interface(), reflect.Zero(fieldValue.Type()).Interface()) {
-			conditions = append(conditions, bson.M{field.Tag.Get("bson"): fieldValue.Interface()})
+			conditions = append(conditions, bson.M{bsonName(field): fieldValue.Interface()})
 		}
 	}
 
@@ -55,10 +56,35 @@ func partialEqual[T any](a, b *T) []string {
 		field1 := ra.Field(i)
 		field2 := rb.FieldByName(name)
 
+		if field1.IsZero() {
+			continue
+		}
+
 		if field2.IsValid() && reflect.DeepEqual(field1.Interface(), field2.Interface()) {
-			conflicts = append(conflicts, strings.ToLower(name))
+			conflicts = append(conflicts, bsonName(ra.Type().Field(i)))
 		}
 	}
 
 	return conflicts
 }
+
+// incMoney adds to the $inc and $set operators of an update the increment of the amount of money at path
+// and its currency. Zero amounts are left out, so they neither create the amount nor replace its currency.
+func incMoney(inc, set bson.M, path string, amount money.Money) {
+	if amount.IsZeroAmount() {
+		return
+	}
+
+	inc[path+".amount"] = amount.Amount
+	set[path+".currency"] = amount.Currency
+}
+
+// bsonName returns the name of the field in the BSON document, ignoring the tag's options.
+func bsonName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("bson"), ",")
+	if name == "" {
+		return strings.ToLower(field.Name)
+	}
+
+	return name
+}
@@ -0,0 +1,209 @@
+package store
+
+import (
+	"context"
+
+	"github.com/heiytor/invenda/api/pkg/clock"
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/query"
+	"github.com/heiytor/invenda/api/store/internal"
+	"github.com/oklog/ulid/v2"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Variant handles the variants of the namespace's products. Every operation is scoped to a namespace
+// ID and a product ID.
+type Variant interface {
+	Entity
+
+	// Get retrieves a variant with the specified ID. It returns the variant or an error if any.
+	Get(ctx context.Context, namespaceID, productID, id string) (variant *models.Variant, err error)
+
+	// GetByBarcode retrieves the variant with the specified barcode, of any product of the namespace. It
+	// returns the variant or an error if any.
+	GetByBarcode(ctx context.Context, namespaceID, barcode string) (variant *models.Variant, err error)
+
+	// GetMany retrieves a list of variants of a product. It returns the list of variants, the total count of the
+	// existent documents and an error if any.
+	GetMany(ctx context.Context, namespaceID, productID string, query *query.Query) (variants []models.Variant, count int64, err error)
+
+	// Conflicts reports whether the non-zero fields of the provided target already exist in the namespace.
+	// It returns a list of conflicted fields or an error if any.
+	Conflicts(ctx context.Context, namespaceID string, target *models.Variant) (conflicts []string, err error)
+
+	// Create creates the provided variants. It returns the inserted IDs or an error if any. As the
+	// variants are inserted in order, a failure does not remove the variants already created.
+	Create(ctx context.Context, variants ...*models.Variant) (insertedIDs []string, err error)
+
+	// Update updates a variant with the specified changes and ID. It returns [ErrNotFound] if no variant is found.
+	Update(ctx context.Context, namespaceID, productID, id string, changes *models.VariantChanges) (err error)
+
+	// Delete deletes a variant with the specified ID. It returns [ErrNotFound] if no variant is found.
+	Delete(ctx context.Context, namespaceID, productID, id string) (err error)
+
+	// DeleteMany deletes all variants of a product. It returns the number of deleted variants or an error if any.
+	DeleteMany(ctx context.Context, namespaceID, productID string) (count int64, err error)
+}
+
+type variant struct {
+	c *mongo.Collection // c is the "variant" collection
+}
+
+var _ Variant = (*variant)(nil)
+
+func (*variant) Entity() string {
+	return "variant"
+}
+
+func (v *variant) Get(ctx context.Context, namespaceID, productID, id string) (*models.Variant, error) {
+	vrt := new(models.Variant)
+	if err := v.c.FindOne(ctx, bson.M{"_id": id, "namespace_id": namespaceID, "product_id": productID}).Decode(vrt); err != nil {
+		return nil, mapError(err)
+	}
+
+	return vrt, nil
+}
+
+func (v *variant) GetByBarcode(ctx context.Context, namespaceID, barcode string) (*models.Variant, error) {
+	vrt := new(models.Variant)
+	if err := v.c.FindOne(ctx, bson.M{"namespace_id": namespaceID, "barcode": barcode}).Decode(vrt); err != nil {
+		return nil, mapError(err)
+	}
+
+	return vrt, nil
+}
+
+func (v *variant) GetMany(ctx context.Context, namespaceID, productID string, query *query.Query) ([]models.Variant, int64, error) {
+	match := bson.M{
+		"$and": []bson.M{
+			{"namespace_id": namespaceID, "product_id": productID},
+			internal.FromFilter(&query.Filter),
+		},
+	}
+
+	count, err := v.c.CountDocuments(ctx, match)
+	if err != nil {
+		log.Error().Err(err).Msg("unable to count the total documents")
+	}
+
+	pipeline := make([]bson.M, 0)
+	pipeline = append(pipeline, bson.M{"$match": match})
+	pipeline = append(pipeline, internal.FromSorter(&query.Sorter)...)
+	pipeline = append(pipeline, internal.FromPaginator(&query.Paginator)...)
+
+	cursor, err := v.c.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, 0, mapError(err)
+	}
+	defer cursor.Close(ctx)
+
+	variants := make([]models.Variant, 0)
+	for cursor.Next(ctx) {
+		vrt := new(models.Variant)
+		if err := cursor.Decode(vrt); err != nil {
+			return nil, 0, mapError(err)
+		}
+
+		variants = append(variants, *vrt)
+	}
+
+	return variants, count, nil
+}
+
+func (v *variant) Conflicts(ctx context.Context, namespaceID string, target *models.Variant) ([]string, error) {
+	pipeline := append([]bson.M{{"$match": bson.M{"namespace_id": namespaceID}}}, or(target)...)
+
+	cursor, err := v.c.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	defer cursor.Close(ctx)
+
+	conflicts := make([]string, 0)
+	for cursor.Next(ctx) {
+		vrt := new(models.Variant)
+
+		if err = cursor.Decode(vrt); err != nil {
+			return nil, mapError(err)
+		}
+
+		conflicts = append(conflicts, partialEqual(target, vrt)...)
+	}
+
+	return conflicts, nil
+}
+
+func (v *variant) Create(ctx context.Context, variants ...*models.Variant) ([]string, error) {
+	if len(variants) == 0 {
+		return []string{}, nil
+	}
+
+	now := clock.Now()
+
+	ids := make([]string, 0, len(variants))
+	docs := make([]interface{}, 0, len(variants))
+	for _, vrt := range variants {
+		vrt.ID = "var_" + ulid.Make().String()
+		vrt.CreatedAt = now
+		vrt.UpdatedAt = now
+
+		ids = append(ids, vrt.ID)
+		docs = append(docs, vrt)
+	}
+
+	if _, err := v.c.InsertMany(ctx, docs); err != nil {
+		return nil, mapError(err)
+	}
+
+	return ids, nil
+}
+
+func (v *variant) Update(ctx context.Context, namespaceID, productID, id string, changes *models.VariantChanges) error {
+	if changes == nil {
+		return nil
+	}
+
+	changes.UpdatedAt = clock.Now()
+	update := bson.M{"$set": changes}
+
+	// An empty barcode is removed from the document so it is not indexed as a duplicate.
+	if changes.Barcode != nil && *changes.Barcode == "" {
+		changes.Barcode = nil
+		update["$unset"] = bson.M{"barcode": ""}
+	}
+
+	res, err := v.c.UpdateOne(ctx, bson.M{"_id": id, "namespace_id": namespaceID, "product_id": productID}, update)
+	if err != nil {
+		return mapError(err)
+	}
+
+	if res.MatchedCount < 1 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (v *variant) Delete(ctx context.Context, namespaceID, productID, id string) error {
+	res, err := v.c.DeleteOne(ctx, bson.M{"_id": id, "namespace_id": namespaceID, "product_id": productID})
+	if err != nil {
+		return mapError(err)
+	}
+
+	if res.DeletedCount < 1 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (v *variant) DeleteMany(ctx context.Context, namespaceID, productID string) (int64, error) {
+	res, err := v.c.DeleteMany(ctx, bson.M{"namespace_id": namespaceID, "product_id": productID})
+	if err != nil {
+		return 0, mapError(err)
+	}
+
+	return res.DeletedCount, nil
+}
@@ -0,0 +1,153 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/money"
+	"github.com/heiytor/invenda/api/pkg/query"
+	"github.com/heiytor/invenda/api/store"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVariantGet(t *testing.T) {
+	type Actual struct {
+		variant *models.Variant
+		err     error
+	}
+
+	price := money.New(500, "BRL")
+
+	cases := []struct {
+		description string
+		productID   string
+		id          string
+		fixtures    []fixture
+		expected    Actual
+	}{
+		{
+			description: "fails when variant is not found",
+			productID:   "prd_01HX3A1B2C3D4E5F6G7H8J9K0M",
+			id:          "var_00000000000000000000000000",
+			fixtures:    []fixture{},
+			expected:    Actual{variant: nil, err: store.ErrNotFound},
+		},
+		{
+			description: "fails when variant belongs to another product",
+			productID:   "prd_01HX3A2N3P4Q5R6S7T8V9W0X1Y",
+			id:          "var_01HX4B2P3Q4R5S6T7V8W9X0Y1Z",
+			fixtures:    []fixture{fixtureVariant},
+			expected:    Actual{variant: nil, err: store.ErrNotFound},
+		},
+		{
+			description: "succeeds to find a variant",
+			productID:   "prd_01HX3A1B2C3D4E5F6G7H8J9K0M",
+			id:          "var_01HX4B2P3Q4R5S6T7V8W9X0Y1Z",
+			fixtures:    []fixture{fixtureVariant},
+			expected: Actual{
+				variant: &models.Variant{
+					ID:          "var_01HX4B2P3Q4R5S6T7V8W9X0Y1Z",
+					NamespaceID: "ns_01HV7FKH5SRB0TGWM7MQ15PYKN",
+					ProductID:   "prd_01HX3A1B2C3D4E5F6G7H8J9K0M",
+					CreatedAt:   time.Date(2023, 1, 2, 12, 0, 0, 0, time.UTC),
+					UpdatedAt:   time.Date(2023, 1, 2, 12, 0, 0, 0, time.UTC),
+					SKU:         "COLA-350-REGULAR",
+					Attributes:  map[string]string{"Flavour": "Regular"},
+					Price:       &price,
+				},
+				err: nil,
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			srv.apply(tc.fixtures...)
+			defer srv.reset()
+
+			ctx := context.Background()
+
+			variant, err := s.Variant.Get(ctx, "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", tc.productID, tc.id)
+			require.Equal(t, tc.expected, Actual{variant, err})
+		})
+	}
+}
+
+func TestVariantGetMany(t *testing.T) {
+	srv.apply(fixtureVariant)
+	defer srv.reset()
+
+	ctx := context.Background()
+
+	variants, count, err := s.Variant.GetMany(
+		ctx,
+		"ns_01HV7FKH5SRB0TGWM7MQ15PYKN",
+		"prd_01HX3A1B2C3D4E5F6G7H8J9K0M",
+		&query.Query{Sorter: query.Sorter{By: "created_at", Order: query.OrderDesc}},
+	)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), count)
+	require.Equal(t, "var_01HX4B2P3Q4R5S6T7V8W9X0Y1Z", variants[0].ID)
+	require.Equal(t, "var_01HX4B1C2D3E4F5G6H7J8K9M0N", variants[1].ID)
+}
+
+func TestVariantCreate(t *testing.T) {
+	ctx := context.Background()
+	defer srv.reset()
+
+	// Recreates the unique indexes dropped by previous resets.
+	_, err := store.New(ctx, db.Client(), db.Name())
+	require.NoError(t, err)
+
+	ids, err := s.Variant.Create(
+		ctx,
+		&models.Variant{NamespaceID: "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", ProductID: "prd_01HX3A1B2C3D4E5F6G7H8J9K0M", SKU: "TSHIRT-S"},
+		&models.Variant{NamespaceID: "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", ProductID: "prd_01HX3A1B2C3D4E5F6G7H8J9K0M", SKU: "TSHIRT-M"},
+	)
+	require.NoError(t, err)
+	require.Len(t, ids, 2)
+
+	_, err = s.Variant.Create(ctx, &models.Variant{NamespaceID: "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", ProductID: "prd_01HX3A2N3P4Q5R6S7T8V9W0X1Y", SKU: "TSHIRT-M"})
+	require.ErrorIs(t, err, store.ErrDuplicated)
+
+	_, err = s.Variant.Create(ctx, &models.Variant{NamespaceID: "ns_01HWS7Q0H1JCEMKZADAFMETRZJ", ProductID: "prd_01HX3A3Z4A5B6C7D8E9F0G1H2J", SKU: "TSHIRT-M"})
+	require.NoError(t, err)
+}
+
+func TestVariantConflicts(t *testing.T) {
+	srv.apply(fixtureVariant)
+	defer srv.reset()
+
+	ctx := context.Background()
+
+	conflicts, err := s.Variant.Conflicts(ctx, "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", &models.Variant{SKU: "COLA-350-ZERO", Barcode: "0000000000000"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"sku"}, conflicts)
+}
+
+func TestVariantDeleteMany(t *testing.T) {
+	srv.apply(fixtureVariant)
+	defer srv.reset()
+
+	ctx := context.Background()
+
+	count, err := s.Variant.DeleteMany(ctx, "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", "prd_01HX3A1B2C3D4E5F6G7H8J9K0M")
+	require.NoError(t, err)
+	require.Equal(t, int64(2), count)
+}
+
+func TestVariantGetByBarcode(t *testing.T) {
+	srv.apply(fixtureVariant)
+	defer srv.reset()
+
+	ctx := context.Background()
+
+	vrt, err := s.Variant.GetByBarcode(ctx, "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", "7891000100110")
+	require.NoError(t, err)
+	require.Equal(t, "var_01HX4B1C2D3E4F5G6H7J8K9M0N", vrt.ID)
+
+	_, err = s.Variant.GetByBarcode(ctx, "ns_01HV7FKH5SRB0TGWM7MQ15PYKN", "7891000100103")
+	require.Equal(t, store.ErrNotFound, err)
+}
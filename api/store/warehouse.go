@@ -0,0 +1,168 @@
+package store
+
+import (
+	"context"
+
+	"github.com/heiytor/invenda/api/pkg/clock"
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/pkg/query"
+	"github.com/heiytor/invenda/api/store/internal"
+	"github.com/oklog/ulid/v2"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Warehouse handles the namespace's warehouses. Every operation is scoped to a namespace ID.
+type Warehouse interface {
+	Entity
+
+	// Get retrieves a warehouse with the specified ID. It returns the warehouse or an error if any.
+	Get(ctx context.Context, namespaceID, id string) (warehouse *models.Warehouse, err error)
+
+	// GetMany retrieves a list of warehouses of a namespace. It returns the list of warehouses, the total count
+	// of the existent documents and an error if any.
+	GetMany(ctx context.Context, namespaceID string, query *query.Query) (warehouses []models.Warehouse, count int64, err error)
+
+	// Conflicts reports whether the non-zero fields of the provided target already exist in the namespace.
+	// It returns a list of conflicted fields or an error if any.
+	Conflicts(ctx context.Context, namespaceID string, target *models.Warehouse) (conflicts []string, err error)
+
+	// Create creates a new warehouse with the provided data. It returns the inserted ID or an error
+	// if any.
+	Create(ctx context.Context, warehouse *models.Warehouse) (insertedID string, err error)
+
+	// Update updates a warehouse with the specified changes and ID. It returns [ErrNotFound] if no warehouse is found.
+	Update(ctx context.Context, namespaceID, id string, changes *models.WarehouseChanges) (err error)
+
+	// Delete deletes a warehouse with the specified ID. It returns [ErrNotFound] if no warehouse is found.
+	Delete(ctx context.Context, namespaceID, id string) (err error)
+}
+
+type warehouse struct {
+	c *mongo.Collection // c is the "warehouse" collection
+}
+
+var _ Warehouse = (*warehouse)(nil)
+
+func (*warehouse) Entity() string {
+	return "warehouse"
+}
+
+func (w *warehouse) Get(ctx context.Context, namespaceID, id string) (*models.Warehouse, error) {
+	wh := new(models.Warehouse)
+	if err := w.c.FindOne(ctx, bson.M{"_id": id, "namespace_id": namespaceID}).Decode(wh); err != nil {
+		return nil, mapError(err)
+	}
+
+	return wh, nil
+}
+
+func (w *warehouse) GetMany(ctx context.Context, namespaceID string, query *query.Query) ([]models.Warehouse, int64, error) {
+	conditions := []bson.M{
+		{"namespace_id": namespaceID},
+		internal.FromFilter(&query.Filter),
+	}
+
+	if query.Search != "" {
+		conditions = append(conditions, internal.FromPrefixSearch(query.Search, "code", "name"))
+	}
+
+	match := bson.M{"$and": conditions}
+
+	count, err := w.c.CountDocuments(ctx, match)
+	if err != nil {
+		log.Error().Err(err).Msg("unable to count the total documents")
+	}
+
+	pipeline := make([]bson.M, 0)
+	pipeline = append(pipeline, bson.M{"$match": match})
+	pipeline = append(pipeline, internal.FromSorter(&query.Sorter)...)
+	pipeline = append(pipeline, internal.FromPaginator(&query.Paginator)...)
+
+	cursor, err := w.c.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, 0, mapError(err)
+	}
+	defer cursor.Close(ctx)
+
+	warehouses := make([]models.Warehouse, 0)
+	if err := cursor.All(ctx, &warehouses); err != nil {
+		return nil, 0, mapError(err)
+	}
+
+	return warehouses, count, nil
+}
+
+func (w *warehouse) Conflicts(ctx context.Context, namespaceID string, target *models.Warehouse) ([]string, error) {
+	pipeline := append([]bson.M{{"$match": bson.M{"namespace_id": namespaceID}}}, or(target)...)
+
+	cursor, err := w.c.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	defer cursor.Close(ctx)
+
+	conflicts := make([]string, 0)
+	for cursor.Next(ctx) {
+		wh := new(models.Warehouse)
+
+		if err = cursor.Decode(wh); err != nil {
+			return nil, mapError(err)
+		}
+
+		conflicts = append(conflicts, partialEqual(target, wh)...)
+	}
+
+	return conflicts, nil
+}
+
+func (w *warehouse) Create(ctx context.Context, wh *models.Warehouse) (string, error) {
+	wh.ID = "wh_" + ulid.Make().String()
+
+	now := clock.Now()
+	wh.CreatedAt = now
+	wh.UpdatedAt = now
+
+	if wh.Locations == nil {
+		wh.Locations = []models.Location{}
+	}
+
+	if _, err := w.c.InsertOne(ctx, wh); err != nil {
+		return "", mapError(err)
+	}
+
+	return wh.ID, nil
+}
+
+func (w *warehouse) Update(ctx context.Context, namespaceID, id string, changes *models.WarehouseChanges) error {
+	if changes == nil {
+		return nil
+	}
+
+	changes.UpdatedAt = clock.Now()
+
+	res, err := w.c.UpdateOne(ctx, bson.M{"_id": id, "namespace_id": namespaceID}, bson.M{"$set": changes})
+	if err != nil {
+		return mapError(err)
+	}
+
+	if res.MatchedCount < 1 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (w *warehouse) Delete(ctx context.Context, namespaceID, id string) error {
+	res, err := w.c.DeleteOne(ctx, bson.M{"_id": id, "namespace_id": namespaceID})
+	if err != nil {
+		return mapError(err)
+	}
+
+	if res.DeletedCount < 1 {
+		return ErrNotFound
+	}
+
+	return nil
+}
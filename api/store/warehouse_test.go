@@ -0,0 +1,122 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/heiytor/invenda/api/pkg/models"
+	"github.com/heiytor/invenda/api/store"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWarehouseGet(t *testing.T) {
+	type Actual struct {
+		warehouse *models.Warehouse
+		err       error
+	}
+
+	cases := []struct {
+		description string
+		namespaceID string
+		id          string
+		fixtures    []fixture
+		expected    Actual
+	}{
+		{
+			description: "fails when warehouse is not found",
+			namespaceID: "ns_01HV7FKH5SRB0TGWM7MQ15PYKN",
+			id:          "wh_00000000000000000000000000",
+			fixtures:    []fixture{},
+			expected:    Actual{warehouse: nil, err: store.ErrNotFound},
+		},
+		{
+			description: "fails when warehouse belongs to another namespace",
+			namespaceID: "ns_01HWS7Q0H1JCEMKZADAFMETRZJ",
+			id:          "wh_01HX5C1A2B3C4D5E6F7G8H9J0K",
+			fixtures:    []fixture{fixtureWarehouse},
+			expected:    Actual{warehouse: nil, err: store.ErrNotFound},
+		},
+		{
+			description: "succeeds to find a warehouse",
+			namespaceID: "ns_01HV7FKH5SRB0TGWM7MQ15PYKN",
+			id:          "wh_01HX5C1A2B3C4D5E6F7G8H9J0K",
+			fixtures:    []fixture{fixtureWarehouse},
+			expected: Actual{
+				warehouse: &models.Warehouse{
+					ID:          "wh_01HX5C1A2B3C4D5E6F7G8H9J0K",
+					NamespaceID: "ns_01HV7FKH5SRB0TGWM7MQ15PYKN",
+					CreatedAt:   time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC),
+					UpdatedAt:   time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC),
+					Code:        "MAIN",
+					Name:        "Main warehouse",
+					Address:     "1 Market St",
+					Active:      true,
+					Locations:   []models.Location{{Code: "A1", Name: "Aisle 1"}},
+				},
+				err: nil,
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			srv.apply(tc.fixtures...)
+			defer srv.reset()
+
+			ctx := context.Background()
+
+			warehouse, err := s.Warehouse.Get(ctx, tc.namespaceID, tc.id)
+			require.Equal(t, tc.expected, Actual{warehouse, err})
+		})
+	}
+}
+
+func TestWarehouseConflicts(t *testing.T) {
+	type Actual struct {
+		conflicts []string
+		err       error
+	}
+
+	cases := []struct {
+		description string
+		namespaceID string
+		target      *models.Warehouse
+		fixtures    []fixture
+		expected    Actual
+	}{
+		{
+			description: "succeeds when none conflicts are found",
+			namespaceID: "ns_01HV7FKH5SRB0TGWM7MQ15PYKN",
+			target:      &models.Warehouse{Code: "BACKUP"},
+			fixtures:    []fixture{fixtureWarehouse},
+			expected:    Actual{conflicts: []string{}, err: nil},
+		},
+		{
+			description: "succeeds when the code belongs to another namespace",
+			namespaceID: "ns_01HWS7Q0H1JCEMKZADAFMETRZJ",
+			target:      &models.Warehouse{Code: "MAIN"},
+			fixtures:    []fixture{fixtureWarehouse},
+			expected:    Actual{conflicts: []string{}, err: nil},
+		},
+		{
+			description: "succeeds when a conflict is found",
+			namespaceID: "ns_01HV7FKH5SRB0TGWM7MQ15PYKN",
+			target:      &models.Warehouse{Code: "MAIN"},
+			fixtures:    []fixture{fixtureWarehouse},
+			expected:    Actual{conflicts: []string{"code"}, err: nil},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			srv.apply(tc.fixtures...)
+			defer srv.reset()
+
+			ctx := context.Background()
+
+			conflicts, err := s.Warehouse.Conflicts(ctx, tc.namespaceID, tc.target)
+			require.Equal(t, tc.expected, Actual{conflicts, err})
+		})
+	}
+}